@@ -2,10 +2,17 @@ package evm
 
 import (
 	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -16,14 +23,32 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-// ResolveClientsFromConfig provisions a map of [chainID] => ethclient.Client based on the config.
+// Client is the subset of the Ethereum RPC API used by the indexer.
+// It's satisfied by *ethclient.Client and by *Pool.
+type Client interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+var _ Client = (*ethclient.Client)(nil)
+
+// ResolveClientsFromConfig provisions a map of [chainID] => Pool based on the config.
+// metrics is optional.
 func ResolveClientsFromConfig(
 	ctx context.Context,
 	cfg config.Config,
+	metrics *Metrics,
 	logger zerolog.Logger,
-) (map[uint64]*ethclient.Client, error) {
+) (map[uint64]*Pool, error) {
 	var (
-		clients             = make(map[uint64]*ethclient.Client, len(cfg.ChainConfigs))
+		pools               = make(map[uint64]*Pool, len(cfg.ChainConfigs))
 		mu                  = sync.Mutex{}
 		errGroup, ctxShared = errgroup.WithContext(ctx)
 	)
@@ -31,13 +56,13 @@ func ResolveClientsFromConfig(
 	for chainID := range cfg.ChainConfigs {
 		chain := *cfg.ChainConfigs[chainID]
 		errGroup.Go(func() error {
-			client, err := NewFromConfig(ctxShared, chain, logger)
+			pool, err := NewFromConfig(ctxShared, chain, metrics, logger)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create client for chain %d", chain.ChainID)
 			}
 
 			mu.Lock()
-			clients[chain.ChainID] = client
+			pools[chain.ChainID] = pool
 			mu.Unlock()
 
 			return nil
@@ -48,65 +73,117 @@ func ResolveClientsFromConfig(
 		return nil, err
 	}
 
-	return clients, nil
+	return pools, nil
 }
 
-// NewFromConfig creates a new ethclient.Client from a chain configuration.
+// NewFromConfig creates a provider pool from a chain configuration.
+// Endpoints that can't be dialed are skipped, it fails only if none of them works.
 func NewFromConfig(
 	ctx context.Context,
 	chain config.ChainConfig,
+	metrics *Metrics,
+	logger zerolog.Logger,
+) (*Pool, error) {
+	urls := chain.RPCURLs
+	if len(urls) == 0 && chain.RPCURL != "" {
+		urls = []string{chain.RPCURL}
+	}
+
+	if len(urls) == 0 {
+		return nil, errors.New("no RPC URL configured")
+	}
+
+	providers := make([]Provider, 0, len(urls))
+	for i, endpoint := range urls {
+		name := providerName(endpoint)
+		if slices.ContainsFunc(providers, func(p Provider) bool { return p.Name == name }) {
+			name = fmt.Sprintf("%s#%d", name, i)
+		}
+
+		limiter := NewLimiter(Limits{
+			RequestsPerSecond: chain.RPCRateLimit,
+			Burst:             chain.RPCBurst,
+			MaxConcurrency:    chain.RPCMaxConcurrency,
+		})
+
+		providerLogger := logger.With().Str("provider", name).Logger()
+
+		client, isWebSocket, err := Dial(ctx, chain.ChainID, endpoint, chain.IngestionMode, limiter, providerLogger)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Uint64(logging.FieldChain, chain.ChainID).
+				Str("provider", name).
+				Msg("Skipping RPC provider")
+			continue
+		}
+
+		providers = append(providers, Provider{Name: name, Client: client, WebSocket: isWebSocket, Limiter: limiter})
+	}
+
+	if len(providers) == 0 {
+		return nil, errors.Errorf("none of the %d RPC providers is reachable", len(urls))
+	}
+
+	return NewPool(chain.ChainID, metrics, logger, providers...), nil
+}
+
+// Dial creates a new ethclient.Client for one RPC endpoint of a chain
+// and returns whether it supports subscriptions over WebSocket.
+// Chains polled over HTTP never use WebSocket, and hybrid chains fall back to HTTP
+// when a WebSocket endpoint doesn't support eth_subscribe.
+// limiter is optional, it receives the Retry-After of rate limited HTTP responses.
+func Dial(
+	ctx context.Context,
+	chainID uint64,
+	endpoint string,
+	mode config.IngestionMode,
+	limiter *Limiter,
 	logger zerolog.Logger,
-) (*ethclient.Client, error) {
+) (*ethclient.Client, bool, error) {
 	logger = logger.With().
-		Uint64(logging.FieldChain, chain.ChainID).
+		Uint64(logging.FieldChain, chainID).
 		Str(logging.FieldModule, "evm_client").
 		Logger()
 
-	isWebSocket := isWebSocketURL(chain.RPCURL)
+	isWebSocket := isWebSocketURL(endpoint)
 
 	var evmClient *ethclient.Client
 
 	switch {
-	case chain.ChainID == config.ZetachainMainnetChainID && isWebSocket:
-		logger.Info().Msg("For ZetaChain, forcing HTTP connection instead of WebSocket")
+	case mode == config.IngestionPolling && isWebSocket:
+		logger.Info().Msg("Chain is polled, forcing HTTP connection instead of WebSocket")
 
-		// Convert WebSocket URL to HTTP if necessary
-		httpURL := chain.RPCURL
-		httpURL = strings.Replace(httpURL, "wss://", "https://", 1)
-		httpURL = strings.Replace(httpURL, "ws://", "http://", 1)
-
-		client, err := ethclient.Dial(httpURL)
+		client, err := dialHTTP(ctx, httpURL(endpoint), limiter)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to connect to ZetaChain with HTTP")
+			return nil, false, errors.Wrap(err, "failed to connect to chain with HTTP")
 		}
 
 		evmClient = client
 		isWebSocket = false
-	case chain.ChainID == config.ZetachainMainnetChainID:
-		client, err := ethclient.Dial(chain.RPCURL)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to connect to ZetaChain")
-		}
-
-		evmClient = client
 	case isWebSocket:
-		rpcClient, err := rpc.DialWebsocket(ctx, chain.RPCURL, "")
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to create WebSocket RPC client")
-		}
+		client, err := dialWebSocket(ctx, endpoint, logger)
+		if err != nil && mode == config.IngestionHybrid {
+			logger.Warn().Err(err).Msg("WebSocket endpoint unusable, falling back to HTTP polling")
 
-		evmClient = ethclient.NewClient(rpcClient)
-
-		if err := verifyWebsocketSubscription(ctx, evmClient, logger); err != nil {
-			return nil, errors.Wrap(err, "failed to verify WebSocket subscription")
+			client, err = dialHTTP(ctx, httpURL(endpoint), limiter)
+			if err != nil {
+				return nil, false, errors.Wrap(err, "failed to connect to chain with HTTP")
+			}
+			isWebSocket = false
+		} else if err != nil {
+			return nil, false, err
 		}
 
-		logger.Info().Msg("Successfully created WebSocket client")
+		evmClient = client
 	default:
-		logger.Warn().Msg("Using HTTP RPC. Real-time subscriptions may not work. Consider using WebSockets")
-		client, err := ethclient.Dial(chain.RPCURL)
+		if mode == config.IngestionSubscription {
+			logger.Warn().Msg("Using HTTP RPC. Real-time subscriptions may not work. Consider using WebSockets")
+		}
+
+		client, err := dialHTTP(ctx, endpoint, limiter)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to connect to chain")
+			return nil, false, errors.Wrap(err, "failed to connect to chain")
 		}
 
 		evmClient = client
@@ -114,7 +191,7 @@ func NewFromConfig(
 
 	// should not happen
 	if evmClient == nil {
-		return nil, errors.New("evmClient is nil")
+		return nil, false, errors.New("evmClient is nil")
 	}
 
 	// verify that the client works
@@ -123,7 +200,7 @@ func NewFromConfig(
 
 	bn, err := evmClient.BlockNumber(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get block number")
+		return nil, false, errors.Wrap(err, "failed to get block number")
 	}
 
 	logger.Info().
@@ -131,7 +208,7 @@ func NewFromConfig(
 		Uint64(logging.FieldBlock, bn).
 		Msg("Successfully created EVM client")
 
-	return evmClient, nil
+	return evmClient, isWebSocket, nil
 }
 
 // verifyWebsocketSubscription tests if a client supports subscriptions by attempting to subscribe to new heads
@@ -193,3 +270,55 @@ func verifyWebsocketSubscription(
 func isWebSocketURL(url string) bool {
 	return strings.HasPrefix(url, "wss://") || strings.HasPrefix(url, "ws://")
 }
+
+// httpURL converts a WebSocket URL to HTTP
+func httpURL(endpoint string) string {
+	endpoint = strings.Replace(endpoint, "wss://", "https://", 1)
+	return strings.Replace(endpoint, "ws://", "http://", 1)
+}
+
+// dialWebSocket creates a WebSocket client and verifies that it supports subscriptions
+func dialWebSocket(ctx context.Context, endpoint string, logger zerolog.Logger) (*ethclient.Client, error) {
+	rpcClient, err := rpc.DialWebsocket(ctx, endpoint, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create WebSocket RPC client")
+	}
+
+	client := ethclient.NewClient(rpcClient)
+
+	if err := verifyWebsocketSubscription(ctx, client, logger); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "failed to verify WebSocket subscription")
+	}
+
+	logger.Info().Msg("Successfully created WebSocket client")
+
+	return client, nil
+}
+
+// dialHTTP creates an HTTP client recording the Retry-After of rate limited responses in the limiter
+func dialHTTP(ctx context.Context, endpoint string, limiter *Limiter) (*ethclient.Client, error) {
+	if limiter == nil {
+		return ethclient.DialContext(ctx, endpoint)
+	}
+
+	httpClient := &http.Client{
+		Transport: &retryAfterTransport{base: http.DefaultTransport, limiter: limiter},
+	}
+
+	rpcClient, err := rpc.DialOptions(ctx, endpoint, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+
+	return ethclient.NewClient(rpcClient), nil
+}
+
+// providerName returns the host of an RPC URL, used in logs and metrics since the URL may contain an API key
+func providerName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
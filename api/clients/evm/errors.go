@@ -0,0 +1,34 @@
+package evm
+
+import "strings"
+
+// rangeTooLargeMessages are the errors of the providers rejecting a FilterLogs query
+// because of its block range or of the number of logs it would return
+var rangeTooLargeMessages = []string{
+	"query returned more than",
+	"block range too large",
+	"block range is too large",
+	"block range is too wide",
+	"exceed maximum block range",
+	"range too large",
+	"too many results",
+	"response size exceeded",
+	"log response size exceeded",
+}
+
+// IsRangeTooLarge returns whether a provider rejected a FilterLogs query as too large,
+// the query succeeds with a smaller block range
+func IsRangeTooLarge(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, tooLarge := range rangeTooLargeMessages {
+		if strings.Contains(msg, tooLarge) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package evm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRangeTooLarge(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("connection refused"), false},
+		{errors.New("query returned more than 10000 results"), true},
+		{errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"), true},
+		{errors.New("block range is too wide"), true},
+		{rpcError{code: rpcLimitExceeded, msg: "query returned more than 10000 results"}, true},
+	} {
+		assert.Equal(t, tt.want, IsRangeTooLarge(tt.err), "%v", tt.err)
+	}
+
+	// shares the limit exceeded code with rate limiting but must not pause the provider
+	assert.False(t, isRateLimited(rpcError{code: rpcLimitExceeded, msg: "query returned more than 10000 results"}))
+	assert.True(t, isRateLimited(rpcError{code: rpcLimitExceeded, msg: "limit exceeded"}))
+}
@@ -0,0 +1,262 @@
+package evm
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// rateLimitBackoff is the first pause of a provider that rate limited a request without Retry-After,
+	// it doubles on every further rate limited request
+	rateLimitBackoff    = time.Second
+	rateLimitMaxBackoff = 30 * time.Second
+
+	// maxRetryAfter caps the Retry-After honored from a provider
+	maxRetryAfter = 5 * time.Minute
+)
+
+// ErrRateLimited is returned when a request can't get a slot of the provider budget before its context expires
+var ErrRateLimited = errors.New("RPC request budget exceeded")
+
+// Limits is the request budget of an RPC provider, zero values mean unlimited
+type Limits struct {
+	RequestsPerSecond float64
+	Burst             int
+	MaxConcurrency    int
+}
+
+// Limiter budgets the requests sent to an RPC provider: a token bucket caps the request rate,
+// a semaphore caps the concurrent requests and the provider is paused after it rate limited a request.
+// A nil Limiter doesn't limit anything.
+type Limiter struct {
+	rate  float64
+	burst float64
+	slots chan struct{}
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	retryAfter  time.Duration // Retry-After of the last rate limited response, consumed by Backoff
+	backoff     time.Duration
+}
+
+// NewLimiter creates a limiter. With unlimited limits it only pauses the provider after a rate limited request.
+func NewLimiter(limits Limits) *Limiter {
+	burst := float64(limits.Burst)
+	if burst < 1 {
+		burst = math.Max(1, math.Ceil(limits.RequestsPerSecond))
+	}
+
+	l := &Limiter{
+		rate:   max(limits.RequestsPerSecond, 0),
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+
+	if limits.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrency)
+	}
+
+	return l
+}
+
+// Acquire waits for a request slot and returns the function releasing it.
+// throttled is true if the request had to wait. ErrRateLimited is returned without waiting
+// if the context expires before a slot would be available.
+func (l *Limiter) Acquire(ctx context.Context) (release func(), throttled bool, err error) {
+	if l == nil {
+		return func() {}, false, nil
+	}
+
+	wait := l.reserve(time.Now())
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		l.cancel()
+		return nil, false, ErrRateLimited
+	}
+
+	if wait > 0 {
+		throttled = true
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			l.cancel()
+			return nil, true, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if l.slots == nil {
+		return func() {}, throttled, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		throttled = true
+
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+
+	return func() { <-l.slots }, throttled, nil
+}
+
+// Paused returns whether the provider is paused after rate limiting a request
+func (l *Limiter) Paused(now time.Time) bool {
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return now.Before(l.pausedUntil)
+}
+
+// Backoff pauses the provider after it rate limited a request, for its Retry-After if it sent one
+// and for an exponential backoff otherwise. Returns the pause.
+func (l *Limiter) Backoff() time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pause := l.retryAfter
+	l.retryAfter = 0
+
+	if pause == 0 {
+		l.backoff = min(max(2*l.backoff, rateLimitBackoff), rateLimitMaxBackoff)
+		pause = l.backoff
+	}
+
+	if until := time.Now().Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+
+	return pause
+}
+
+// Reset clears the exponential backoff after a successful request
+func (l *Limiter) Reset() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.backoff = 0
+}
+
+// setRetryAfter records the Retry-After of a rate limited response
+func (l *Limiter) setRetryAfter(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.retryAfter = min(retryAfter, maxRetryAfter)
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+
+	if l.rate > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		l.tokens--
+
+		if l.tokens < 0 {
+			wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+
+	return max(wait, l.pausedUntil.Sub(now))
+}
+
+// cancel gives back a reserved token
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+1)
+	}
+}
+
+// isRateLimited returns whether the provider rejected a request because of its rate limit.
+// Some providers share the limit exceeded code with queries returning too many logs.
+func isRateLimited(err error) bool {
+	var (
+		httpErr rpc.HTTPError
+		rpcErr  rpc.Error
+	)
+
+	switch {
+	case IsRangeTooLarge(err):
+		return false
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	case errors.As(err, &rpcErr):
+		return rpcErr.ErrorCode() == rpcLimitExceeded || rpcErr.ErrorCode() == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// retryAfterTransport records the Retry-After header of rate limited HTTP responses in the limiter,
+// the RPC client only returns the status and body of failed responses
+type retryAfterTransport struct {
+	base    http.RoundTripper
+	limiter *Limiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusTooManyRequests {
+		return res, err
+	}
+
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+		t.limiter.setRetryAfter(retryAfter)
+	}
+
+	return res, nil
+}
+
+// parseRetryAfter parses a Retry-After header, either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
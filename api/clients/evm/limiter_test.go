@@ -0,0 +1,118 @@
+package evm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Run("throttles requests over the rate", func(t *testing.T) {
+		limiter := NewLimiter(Limits{RequestsPerSecond: 20, Burst: 2})
+
+		for i := range 3 {
+			release, throttled, err := limiter.Acquire(context.Background())
+			require.NoError(t, err)
+			release()
+			assert.Equal(t, i == 2, throttled, "request %d", i)
+		}
+	})
+
+	t.Run("rejects requests that can't be served before their deadline", func(t *testing.T) {
+		limiter := NewLimiter(Limits{RequestsPerSecond: 0.1, Burst: 1})
+
+		_, _, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, _, err = limiter.Acquire(ctx)
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("caps concurrent requests", func(t *testing.T) {
+		limiter := NewLimiter(Limits{MaxConcurrency: 1})
+
+		release, _, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, throttled, err := limiter.Acquire(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, throttled)
+
+		release()
+		release, _, err = limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("backs off exponentially without Retry-After", func(t *testing.T) {
+		limiter := NewLimiter(Limits{})
+
+		assert.Equal(t, time.Second, limiter.Backoff())
+		assert.Equal(t, 2*time.Second, limiter.Backoff())
+		assert.True(t, limiter.Paused(time.Now()))
+
+		limiter.Reset()
+		assert.Equal(t, time.Second, limiter.Backoff())
+	})
+
+	t.Run("nil limiter does not limit", func(t *testing.T) {
+		var limiter *Limiter
+
+		release, throttled, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		release()
+		assert.False(t, throttled)
+		assert.Zero(t, limiter.Backoff())
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	limiter := NewLimiter(Limits{})
+	client, err := dialHTTP(context.Background(), server.URL, limiter)
+	require.NoError(t, err)
+
+	_, err = client.BlockNumber(context.Background())
+	require.Error(t, err)
+	assert.True(t, isRateLimited(err))
+
+	// the pause is the Retry-After of the response, not the exponential backoff
+	assert.Equal(t, 7*time.Second, limiter.Backoff())
+	assert.Equal(t, time.Second, limiter.Backoff())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{"Wed, 01 Jan 2025 00:01:00 GMT", time.Minute, true},
+		{"Tue, 31 Dec 2024 00:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(tt.value, now)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+}
@@ -0,0 +1,145 @@
+package evm
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the per-provider RPC metrics of the pools
+type Metrics struct {
+	requests        *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	latency         *prometheus.HistogramVec
+	providerHealthy *prometheus.GaugeVec
+	throttled       *prometheus.CounterVec
+	rejected        *prometheus.CounterVec
+	rateLimited     *prometheus.CounterVec
+}
+
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// NewMetrics creates the RPC provider metrics, register them with a prometheus registry to export them
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "speedrun_rpc_requests_total",
+				Help: "Total number of RPC requests per provider",
+			},
+			[]string{"chain_id", "provider", "method"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "speedrun_rpc_errors_total",
+				Help: "Total number of failed RPC requests per provider, request errors returned by the node are excluded",
+			},
+			[]string{"chain_id", "provider", "method"},
+		),
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "speedrun_rpc_request_duration_seconds",
+				Help:    "Latency of RPC requests per provider",
+				Buckets: []float64{0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"chain_id", "provider", "method"},
+		),
+		providerHealthy: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "speedrun_rpc_provider_healthy",
+				Help: "Whether the RPC provider is used for requests (1 = healthy, 0 = cooling down or lagging)",
+			},
+			[]string{"chain_id", "provider"},
+		),
+		throttled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "speedrun_rpc_throttled_total",
+				Help: "Total number of RPC requests delayed by the request budget of the provider",
+			},
+			[]string{"chain_id", "provider"},
+		),
+		rejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "speedrun_rpc_rejected_total",
+				Help: "Total number of RPC requests rejected because the budget of the provider was exhausted before their deadline",
+			},
+			[]string{"chain_id", "provider"},
+		),
+		rateLimited: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "speedrun_rpc_rate_limited_total",
+				Help: "Total number of RPC requests rate limited by the provider (HTTP 429 or limit exceeded)",
+			},
+			[]string{"chain_id", "provider"},
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.latency.Describe(ch)
+	m.providerHealthy.Describe(ch)
+	m.throttled.Describe(ch)
+	m.rejected.Describe(ch)
+	m.rateLimited.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.latency.Collect(ch)
+	m.providerHealthy.Collect(ch)
+	m.throttled.Collect(ch)
+	m.rejected.Collect(ch)
+	m.rateLimited.Collect(ch)
+}
+
+func (m *Metrics) observeRequest(chainID uint64, provider, method string, latency time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+
+	chain := strconv.FormatUint(chainID, 10)
+	m.requests.WithLabelValues(chain, provider, method).Inc()
+	m.latency.WithLabelValues(chain, provider, method).Observe(latency.Seconds())
+	if failed {
+		m.errors.WithLabelValues(chain, provider, method).Inc()
+	}
+}
+
+func (m *Metrics) setHealthy(chainID uint64, provider string, healthy bool) {
+	if m == nil {
+		return
+	}
+
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	m.providerHealthy.WithLabelValues(strconv.FormatUint(chainID, 10), provider).Set(value)
+}
+
+func (m *Metrics) observeThrottled(chainID uint64, provider string) {
+	if m == nil {
+		return
+	}
+	m.throttled.WithLabelValues(strconv.FormatUint(chainID, 10), provider).Inc()
+}
+
+func (m *Metrics) observeRejected(chainID uint64, provider string) {
+	if m == nil {
+		return
+	}
+	m.rejected.WithLabelValues(strconv.FormatUint(chainID, 10), provider).Inc()
+}
+
+func (m *Metrics) observeRateLimited(chainID uint64, provider string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(strconv.FormatUint(chainID, 10), provider).Inc()
+}
@@ -0,0 +1,506 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/logging"
+)
+
+const (
+	// HealthCheckInterval is how often the pool probes the head block of its providers
+	HealthCheckInterval = 15 * time.Second
+
+	// healthCheckTimeout bounds the probe of a single provider
+	healthCheckTimeout = 5 * time.Second
+
+	// providerFailureThreshold is the number of consecutive failures after which a provider is put on cooldown
+	providerFailureThreshold = 3
+
+	// providerCooldown is the first cooldown of a failing provider, it doubles on every further failure
+	providerCooldown    = 30 * time.Second
+	providerMaxCooldown = 5 * time.Minute
+
+	// providerMaxLag is the number of blocks a provider can be behind the best head before it's skipped
+	providerMaxLag = 10
+
+	// latencyWeight is the weight of the latest sample in the moving average of a provider latency
+	latencyWeight = 0.2
+
+	// rpcLimitExceeded is the JSON-RPC error code returned by most providers when rate limiting
+	rpcLimitExceeded = -32005
+
+	// maxRateLimitedRounds is the number of times the providers are tried in a row when they all rate limit
+	maxRateLimitedRounds = 3
+)
+
+// ErrNoProvider is returned when a pool has no provider to send a request to
+var ErrNoProvider = errors.New("no RPC provider available")
+
+// Provider is an RPC endpoint of a pool
+type Provider struct {
+	// Name identifies the provider in logs and metrics, it must not contain secrets
+	Name      string
+	Client    Client
+	WebSocket bool
+
+	// Limiter budgets the requests sent to the provider, nil means unlimited
+	Limiter *Limiter
+}
+
+// Pool is a Client spreading the requests of a chain over several RPC providers.
+// Requests go to the healthy provider with the lowest latency and fail over to the next one on error.
+// Providers failing repeatedly are put on cooldown, providers lagging behind the best head are skipped
+// until they catch up.
+type Pool struct {
+	chainID   uint64
+	providers []*providerState
+	metrics   *Metrics
+	logger    zerolog.Logger
+}
+
+type providerState struct {
+	Provider
+
+	mu        sync.Mutex
+	latency   time.Duration // moving average, zero until the first response
+	failures  int           // consecutive failures
+	downUntil time.Time
+	lagging   bool
+}
+
+var _ Client = (*Pool)(nil)
+
+// NewPool creates a pool of RPC providers for a chain. metrics is optional.
+func NewPool(chainID uint64, metrics *Metrics, logger zerolog.Logger, providers ...Provider) *Pool {
+	pool := &Pool{
+		chainID:   chainID,
+		providers: make([]*providerState, 0, len(providers)),
+		metrics:   metrics,
+		logger: logger.With().
+			Uint64(logging.FieldChain, chainID).
+			Str(logging.FieldModule, "rpc_pool").
+			Logger(),
+	}
+
+	for _, provider := range providers {
+		pool.providers = append(pool.providers, &providerState{Provider: provider})
+		metrics.setHealthy(chainID, provider.Name, true)
+	}
+
+	return pool
+}
+
+// ChainID returns the chain ID of the pool
+func (p *Pool) ChainID() uint64 {
+	return p.chainID
+}
+
+// BlockNumber implements Client
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(ctx, p, "eth_blockNumber", false, func(s *providerState) (uint64, error) {
+		return s.Client.BlockNumber(ctx)
+	})
+}
+
+// HeaderByNumber implements Client
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(ctx, p, "eth_getBlockByNumber", false, func(s *providerState) (*types.Header, error) {
+		return s.Client.HeaderByNumber(ctx, number)
+	})
+}
+
+// BlockByNumber implements Client
+func (p *Pool) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return call(ctx, p, "eth_getBlockByNumber", false, func(s *providerState) (*types.Block, error) {
+		return s.Client.BlockByNumber(ctx, number)
+	})
+}
+
+// TransactionByHash implements Client
+func (p *Pool) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx      *types.Transaction
+		pending bool
+	}
+
+	res, err := call(ctx, p, "eth_getTransactionByHash", false, func(s *providerState) (result, error) {
+		tx, pending, err := s.Client.TransactionByHash(ctx, hash)
+		return result{tx, pending}, err
+	})
+
+	return res.tx, res.pending, err
+}
+
+// TransactionReceipt implements Client
+func (p *Pool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(ctx, p, "eth_getTransactionReceipt", false, func(s *providerState) (*types.Receipt, error) {
+		return s.Client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// FilterLogs implements Client
+func (p *Pool) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return call(ctx, p, "eth_getLogs", false, func(s *providerState) ([]types.Log, error) {
+		return s.Client.FilterLogs(ctx, q)
+	})
+}
+
+// CallContract implements Client
+func (p *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, p, "eth_call", false, func(s *providerState) ([]byte, error) {
+		return s.Client.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+// SubscribeFilterLogs implements Client, the subscription is made with a WebSocket provider if there is one
+func (p *Pool) SubscribeFilterLogs(
+	ctx context.Context,
+	q ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return p.subscribe(ctx, "eth_subscribe_logs", func(s *providerState) (ethereum.Subscription, error) {
+		return s.Client.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
+
+// SubscribeNewHead implements Client, the subscription is made with a WebSocket provider if there is one
+func (p *Pool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return p.subscribe(ctx, "eth_subscribe_newHeads", func(s *providerState) (ethereum.Subscription, error) {
+		return s.Client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// Run probes the providers periodically until the context is done
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth fetches the head block of every provider. Providers on cooldown recover once they respond,
+// providers more than providerMaxLag blocks behind the best head are skipped until they catch up.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	var (
+		heads = make([]uint64, len(p.providers))
+		wg    sync.WaitGroup
+	)
+
+	for i, provider := range p.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			head, err := provider.Client.BlockNumber(ctx)
+			p.observe(provider, "eth_blockNumber", time.Since(start), err)
+			if err == nil {
+				heads[i] = head
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var best uint64
+	for _, head := range heads {
+		best = max(best, head)
+	}
+
+	now := time.Now()
+	for i, provider := range p.providers {
+		// keep the previous state of providers that failed to respond
+		if heads[i] != 0 {
+			lagging := best-heads[i] > providerMaxLag
+
+			provider.mu.Lock()
+			if lagging && !provider.lagging {
+				p.logger.Warn().
+					Str("provider", provider.Name).
+					Uint64(logging.FieldBlock, heads[i]).
+					Uint64("best_block", best).
+					Msg("RPC provider is lagging behind")
+			}
+			provider.lagging = lagging
+			provider.mu.Unlock()
+		}
+
+		p.metrics.setHealthy(p.chainID, provider.Name, provider.healthy(now))
+	}
+}
+
+// subscribe makes a subscription and watches it, so that a dropped subscription counts as a provider failure
+func (p *Pool) subscribe(
+	ctx context.Context,
+	method string,
+	fn func(*providerState) (ethereum.Subscription, error),
+) (ethereum.Subscription, error) {
+	var provider *providerState
+
+	sub, err := call(ctx, p, method, true, func(s *providerState) (ethereum.Subscription, error) {
+		provider = s
+		return fn(s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	watched := &poolSubscription{Subscription: sub, errc: make(chan error, 1)}
+
+	go func() {
+		defer close(watched.errc)
+
+		// the channel receives at most one error and is closed on unsubscribe
+		for err := range sub.Err() {
+			if isProviderError(err) {
+				p.recordFailure(provider, method, err)
+			}
+			watched.errc <- err
+		}
+	}()
+
+	return watched, nil
+}
+
+// call sends a request to the providers in order of preference until one of them answers.
+// If all of them rate limited the request, it's retried once their backoff is over.
+func call[T any](ctx context.Context, p *Pool, method string, subscription bool, fn func(*providerState) (T, error)) (T, error) {
+	var (
+		zero    T
+		lastErr error = ErrNoProvider
+	)
+
+	for round := 0; round < maxRateLimitedRounds; round++ {
+		// retry only if every provider rate limited the request, their budget has to be waited for
+		retry := true
+
+		for _, provider := range p.candidates(subscription) {
+			release, throttled, err := provider.Limiter.Acquire(ctx)
+			if throttled {
+				p.metrics.observeThrottled(p.chainID, provider.Name)
+			}
+			if errors.Is(err, ErrRateLimited) {
+				p.metrics.observeRejected(p.chainID, provider.Name)
+				lastErr = err
+				retry = false
+				continue
+			}
+			if err != nil {
+				return zero, err
+			}
+
+			start := time.Now()
+			res, err := fn(provider)
+			release()
+			p.observe(provider, method, time.Since(start), err)
+
+			if !isProviderError(err) {
+				return res, err
+			}
+
+			lastErr = err
+			retry = retry && isRateLimited(err)
+
+			if ctx.Err() != nil {
+				return zero, lastErr
+			}
+
+			p.logger.Debug().
+				Err(err).
+				Str("provider", provider.Name).
+				Str("method", method).
+				Msg("RPC request failed, trying next provider")
+		}
+
+		if !retry {
+			break
+		}
+	}
+
+	return zero, lastErr
+}
+
+// candidates returns the providers in the order they're tried: healthy ones first, then the ones
+// not paused by a rate limit, then by latency. Unhealthy providers are kept as a last resort.
+// Subscriptions prefer WebSocket providers.
+func (p *Pool) candidates(subscription bool) []*providerState {
+	type candidate struct {
+		provider *providerState
+		healthy  bool
+		paused   bool
+		latency  time.Duration
+	}
+
+	var (
+		now  = time.Now()
+		list = make([]candidate, 0, len(p.providers))
+	)
+
+	for _, provider := range p.providers {
+		if subscription && !provider.WebSocket {
+			continue
+		}
+
+		provider.mu.Lock()
+		healthy, latency := provider.isHealthy(now), provider.latency
+		provider.mu.Unlock()
+
+		list = append(list, candidate{provider, healthy, provider.Limiter.Paused(now), latency})
+	}
+
+	if subscription && len(list) == 0 {
+		return p.candidates(false)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].healthy != list[j].healthy {
+			return list[i].healthy
+		}
+		if list[i].paused != list[j].paused {
+			return !list[i].paused
+		}
+		return list[i].latency < list[j].latency
+	})
+
+	providers := make([]*providerState, len(list))
+	for i := range list {
+		providers[i] = list[i].provider
+	}
+
+	return providers
+}
+
+// observe records the outcome of a request. Rate limited requests pause the provider
+// instead of counting as failures.
+func (p *Pool) observe(provider *providerState, method string, latency time.Duration, err error) {
+	failed := isProviderError(err)
+	p.metrics.observeRequest(p.chainID, provider.Name, method, latency, failed)
+
+	if isRateLimited(err) {
+		pause := provider.Limiter.Backoff()
+		p.metrics.observeRateLimited(p.chainID, provider.Name)
+		p.logger.Warn().
+			Err(err).
+			Str("provider", provider.Name).
+			Str("method", method).
+			Dur("pause", pause).
+			Msg("RPC provider rate limited the request")
+		return
+	}
+
+	if failed {
+		p.recordFailure(provider, method, err)
+		return
+	}
+
+	provider.Limiter.Reset()
+
+	provider.mu.Lock()
+	recovered := provider.failures >= providerFailureThreshold
+	provider.failures = 0
+	provider.downUntil = time.Time{}
+	if provider.latency == 0 {
+		provider.latency = latency
+	} else {
+		provider.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(provider.latency))
+	}
+	healthy := provider.isHealthy(time.Now())
+	provider.mu.Unlock()
+
+	if recovered {
+		p.logger.Info().Str("provider", provider.Name).Msg("RPC provider recovered")
+		p.metrics.setHealthy(p.chainID, provider.Name, healthy)
+	}
+}
+
+func (p *Pool) recordFailure(provider *providerState, method string, err error) {
+	now := time.Now()
+
+	provider.mu.Lock()
+	provider.failures++
+	failures := provider.failures
+	cooldown := time.Duration(0)
+	if failures >= providerFailureThreshold {
+		cooldown = providerCooldown
+		for i := providerFailureThreshold; i < failures && cooldown < providerMaxCooldown; i++ {
+			cooldown *= 2
+		}
+		cooldown = min(cooldown, providerMaxCooldown)
+		provider.downUntil = now.Add(cooldown)
+	}
+	provider.mu.Unlock()
+
+	if cooldown == 0 {
+		return
+	}
+
+	p.logger.Warn().
+		Err(err).
+		Str("provider", provider.Name).
+		Str("method", method).
+		Int("failures", failures).
+		Dur("cooldown", cooldown).
+		Msg("RPC provider put on cooldown")
+
+	p.metrics.setHealthy(p.chainID, provider.Name, false)
+}
+
+// healthy returns whether the provider is neither on cooldown nor lagging
+func (s *providerState) healthy(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isHealthy(now)
+}
+
+// isHealthy is healthy for callers holding the lock
+func (s *providerState) isHealthy(now time.Time) bool {
+	return !s.lagging && !now.Before(s.downUntil)
+}
+
+// isProviderError returns whether an error is attributed to the provider. Answers of the node like
+// "not found", a JSON-RPC error or a query too large would be the same with any other provider, except rate limiting.
+func isProviderError(err error) bool {
+	var rpcErr rpc.Error
+
+	switch {
+	case err == nil, errors.Is(err, ethereum.NotFound), errors.Is(err, context.Canceled), IsRangeTooLarge(err):
+		return false
+	case errors.As(err, &rpcErr):
+		return isRateLimited(err)
+	default:
+		return true
+	}
+}
+
+// poolSubscription forwards the error of a subscription watched by the pool
+type poolSubscription struct {
+	ethereum.Subscription
+	errc chan error
+}
+
+// Err implements ethereum.Subscription
+func (s *poolSubscription) Err() <-chan error {
+	return s.errc
+}
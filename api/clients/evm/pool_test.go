@@ -0,0 +1,269 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	errConnection := errors.New("connection refused")
+
+	t.Run("prefers the fastest healthy provider", func(t *testing.T) {
+		slow := &fakeClient{head: 100}
+		fast := &fakeClient{head: 100}
+		pool := newTestPool(t, nil, slow, fast)
+		setLatency(pool, 0, 300*time.Millisecond)
+		setLatency(pool, 1, 50*time.Millisecond)
+
+		head, err := pool.BlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), head)
+		assert.Equal(t, 0, slow.callCount())
+		assert.Equal(t, 1, fast.callCount())
+	})
+
+	t.Run("fails over and puts a failing provider on cooldown", func(t *testing.T) {
+		failing := &fakeClient{err: errConnection}
+		backup := &fakeClient{head: 42}
+		metrics := NewMetrics()
+		pool := newTestPool(t, metrics, failing, backup)
+
+		for range providerFailureThreshold {
+			head, err := pool.BlockNumber(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, uint64(42), head)
+		}
+
+		assert.False(t, pool.providers[0].healthy(time.Now()))
+		assert.Equal(t, float64(providerFailureThreshold),
+			testutil.ToFloat64(metrics.errors.WithLabelValues("1", "provider-0", "eth_blockNumber")))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.providerHealthy.WithLabelValues("1", "provider-0")))
+
+		// the provider on cooldown is skipped
+		_, err := pool.BlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, providerFailureThreshold, failing.callCount())
+	})
+
+	t.Run("returns the last error if all providers fail", func(t *testing.T) {
+		pool := newTestPool(t, nil, &fakeClient{err: errConnection}, &fakeClient{err: errConnection})
+
+		_, err := pool.BlockNumber(context.Background())
+		require.ErrorIs(t, err, errConnection)
+	})
+
+	t.Run("does not fail over on answers of the node", func(t *testing.T) {
+		first := &fakeClient{err: ethereum.NotFound}
+		second := &fakeClient{}
+		pool := newTestPool(t, nil, first, second)
+
+		_, err := pool.TransactionReceipt(context.Background(), common.Hash{})
+		require.ErrorIs(t, err, ethereum.NotFound)
+		assert.Equal(t, 0, second.callCount())
+		assert.True(t, pool.providers[0].healthy(time.Now()))
+	})
+
+	t.Run("fails over when rate limited", func(t *testing.T) {
+		limited := &fakeClient{err: rpcError{code: rpcLimitExceeded}}
+		backup := &fakeClient{head: 7}
+		pool := newTestPool(t, nil, limited, backup)
+
+		head, err := pool.BlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), head)
+	})
+
+	t.Run("pauses a rate limited provider instead of failing it", func(t *testing.T) {
+		limited := &fakeClient{err: rpcError{code: rpcLimitExceeded}}
+		backup := &fakeClient{head: 7}
+		metrics := NewMetrics()
+		pool := NewPool(1, metrics, logging.NewTesting(t),
+			Provider{Name: "limited", Client: limited, Limiter: NewLimiter(Limits{})},
+			Provider{Name: "backup", Client: backup, Limiter: NewLimiter(Limits{})},
+		)
+		setLatency(pool, 1, time.Second)
+
+		_, err := pool.BlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.True(t, pool.providers[0].Limiter.Paused(time.Now()))
+		assert.True(t, pool.providers[0].healthy(time.Now()))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.rateLimited.WithLabelValues("1", "limited")))
+
+		// the paused provider is tried after the slower one
+		_, err = pool.BlockNumber(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, limited.callCount())
+	})
+
+	t.Run("health check skips lagging providers and recovers failed ones", func(t *testing.T) {
+		lagging := &fakeClient{head: 100}
+		synced := &fakeClient{head: 100 + providerMaxLag + 1}
+		pool := newTestPool(t, nil, lagging, synced)
+		pool.providers[1].failures = providerFailureThreshold
+		pool.providers[1].downUntil = time.Now().Add(time.Hour)
+
+		pool.CheckHealth(context.Background())
+
+		assert.False(t, pool.providers[0].healthy(time.Now()))
+		assert.True(t, pool.providers[1].healthy(time.Now()))
+
+		lagging.setHead(100 + providerMaxLag + 1)
+		pool.CheckHealth(context.Background())
+		assert.True(t, pool.providers[0].healthy(time.Now()))
+	})
+
+	t.Run("subscribes with a websocket provider and records dropped subscriptions", func(t *testing.T) {
+		httpClient := &fakeClient{}
+		ws := &fakeClient{sub: newFakeSubscription()}
+		pool := NewPool(1, nil, logging.NewTesting(t),
+			Provider{Name: "http", Client: httpClient},
+			Provider{Name: "ws", Client: ws, WebSocket: true},
+		)
+
+		sub, err := pool.SubscribeNewHead(context.Background(), make(chan *types.Header))
+		require.NoError(t, err)
+		assert.Equal(t, 0, httpClient.callCount())
+
+		ws.sub.errc <- errConnection
+		require.ErrorIs(t, <-sub.Err(), errConnection)
+
+		pool.providers[1].mu.Lock()
+		defer pool.providers[1].mu.Unlock()
+		assert.Equal(t, 1, pool.providers[1].failures)
+	})
+}
+
+func TestProviderCooldown(t *testing.T) {
+	pool := newTestPool(t, nil, &fakeClient{})
+	provider := pool.providers[0]
+
+	var cooldowns []time.Duration
+	for range providerFailureThreshold + 5 {
+		pool.recordFailure(provider, "eth_blockNumber", errors.New("timeout"))
+		if !provider.downUntil.IsZero() {
+			cooldowns = append(cooldowns, time.Until(provider.downUntil).Round(time.Second))
+		}
+	}
+
+	assert.Equal(t, []time.Duration{
+		30 * time.Second,
+		time.Minute,
+		2 * time.Minute,
+		4 * time.Minute,
+		5 * time.Minute,
+		5 * time.Minute,
+	}, cooldowns)
+}
+
+func TestProviderName(t *testing.T) {
+	assert.Equal(t, "eth-mainnet.example.com", providerName("wss://eth-mainnet.example.com/v2/secret-key"))
+	assert.Equal(t, "localhost:8545", providerName("http://localhost:8545"))
+	assert.Equal(t, "unknown", providerName("not a url"))
+}
+
+func newTestPool(t *testing.T, metrics *Metrics, clients ...*fakeClient) *Pool {
+	providers := make([]Provider, len(clients))
+	for i, client := range clients {
+		providers[i] = Provider{Name: fmt.Sprintf("provider-%d", i), Client: client}
+	}
+
+	return NewPool(1, metrics, logging.NewTesting(t), providers...)
+}
+
+func setLatency(pool *Pool, i int, latency time.Duration) {
+	pool.providers[i].mu.Lock()
+	defer pool.providers[i].mu.Unlock()
+	pool.providers[i].latency = latency
+}
+
+// fakeClient answers every request with its head or its error
+type fakeClient struct {
+	Client
+
+	mu    sync.Mutex
+	head  uint64
+	err   error
+	calls int
+	sub   *fakeSubscription
+}
+
+func (c *fakeClient) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func (c *fakeClient) setHead(head uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head = head
+}
+
+func (c *fakeClient) BlockNumber(context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return c.head, c.err
+}
+
+func (c *fakeClient) TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return &types.Receipt{}, c.err
+}
+
+func (c *fakeClient) SubscribeNewHead(context.Context, chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.sub == nil {
+		return nil, errors.New("notifications not supported")
+	}
+	return c.sub, nil
+}
+
+type fakeSubscription struct {
+	errc chan error
+	once sync.Once
+}
+
+func newFakeSubscription() *fakeSubscription {
+	return &fakeSubscription{errc: make(chan error, 1)}
+}
+
+func (s *fakeSubscription) Unsubscribe() {
+	s.once.Do(func() { close(s.errc) })
+}
+
+func (s *fakeSubscription) Err() <-chan error {
+	return s.errc
+}
+
+type rpcError struct {
+	code int
+	msg  string
+}
+
+func (e rpcError) Error() string {
+	if e.msg == "" {
+		return "rpc error"
+	}
+	return e.msg
+}
+
+func (e rpcError) ErrorCode() int {
+	return e.code
+}
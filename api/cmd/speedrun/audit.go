@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/services"
+	"github.com/speedrun-hq/speedrun/api/utils"
+)
+
+const auditSkipsCommand = "audit-skips"
+
+// runAuditSkips audits the block ranges the catchup skipped as empty against the block headers, e.g.
+//
+//	speedrun audit-skips --chain 1 --limit 100
+//
+// Every chain with skipped ranges is audited if no chain is given. Blocks found to have events are logged
+// and the command exits with status 1, they can be re-indexed with the backfill command.
+func runAuditSkips(args []string) {
+	var (
+		chainID  uint64
+		limit    int
+		logJSON  bool
+		logLevel string
+	)
+
+	fs := flag.NewFlagSet(auditSkipsCommand, flag.ExitOnError)
+	fs.Uint64Var(&chainID, "chain", 0, "Chain ID to audit, all supported chains if not set")
+	fs.IntVar(&limit, "limit", services.DefaultAuditLimit, "Maximum number of skipped ranges audited per chain")
+	fs.BoolVar(&logJSON, "log-json", false, "Output logs in JSON format")
+	fs.StringVar(&logLevel, "log-level", "info", "Set log level (debug, info, warn, error)")
+
+	// ExitOnError: Parse exits on invalid flags
+	_ = fs.Parse(args)
+
+	log := logging.New(os.Stdout, parseLogLevel(logLevel), logJSON)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	chainIDs := cfg.SupportedChains
+	if chainID != 0 {
+		if _, ok := cfg.ChainConfigs[chainID]; !ok {
+			log.Fatal().Uint64(logging.FieldChain, chainID).Msg("Chain is not supported")
+		}
+		chainIDs = []uint64{chainID}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.NewPostgresDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize database")
+	}
+
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close database")
+		}
+	}()
+
+	pools, err := evm.ResolveClientsFromConfig(ctx, *cfg, nil, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize Ethereum clients")
+	}
+
+	intentServices, _, _, err := createServices(utils.MapMap(pools, castClientsMap), database, cfg, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create services")
+	}
+
+	auditor := services.NewSkipAuditor(intentServices, database, log)
+
+	var audited, missed int
+	for _, id := range chainIDs {
+		ranges, err := auditor.Audit(ctx, id, limit)
+		if err != nil {
+			log.Fatal().Err(err).Uint64(logging.FieldChain, id).Msg("Audit failed")
+		}
+
+		for _, skipped := range ranges {
+			if len(skipped.MissedBlocks) > 0 {
+				missed++
+			}
+		}
+		audited += len(ranges)
+	}
+
+	log.Info().
+		Int("audited_ranges", audited).
+		Int("ranges_with_misses", missed).
+		Msg("Audit of skipped block ranges completed")
+
+	if missed > 0 {
+		os.Exit(1)
+	}
+}
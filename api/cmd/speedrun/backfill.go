@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/services"
+	"github.com/speedrun-hq/speedrun/api/utils"
+)
+
+const backfillCommand = "backfill"
+
+// runBackfill re-indexes a block range of a chain, e.g.
+//
+//	speedrun backfill --chain 8453 --from 123 --to 456 --events intent,fulfillment,settlement
+//
+// Already indexed events are skipped and the checkpoints of the live server are left untouched,
+// so it's safe to run while the server is running.
+func runBackfill(args []string) {
+	var (
+		chainID   uint64
+		fromBlock uint64
+		toBlock   uint64
+		events    string
+		logJSON   bool
+		logLevel  string
+	)
+
+	fs := flag.NewFlagSet(backfillCommand, flag.ExitOnError)
+	fs.Uint64Var(&chainID, "chain", 0, "Chain ID to backfill")
+	fs.Uint64Var(&fromBlock, "from", 0, "First block to re-index")
+	fs.Uint64Var(&toBlock, "to", 0, "Last block to re-index")
+	fs.StringVar(&events, "events", strings.Join(services.BackfillEventTypes, ","), "Comma-separated event types")
+	fs.BoolVar(&logJSON, "log-json", false, "Output logs in JSON format")
+	fs.StringVar(&logLevel, "log-level", "info", "Set log level (debug, info, warn, error)")
+
+	// ExitOnError: Parse exits on invalid flags
+	_ = fs.Parse(args)
+
+	log := logging.New(os.Stdout, parseLogLevel(logLevel), logJSON)
+
+	eventTypes, err := services.ParseBackfillEventTypes(events)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid --events")
+	}
+
+	if chainID == 0 || toBlock == 0 {
+		fmt.Fprintln(os.Stderr, "--chain and --to are required")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if fromBlock > toBlock {
+		log.Fatal().Uint64("from_block", fromBlock).Uint64("to_block", toBlock).Msg("--from must not be after --to")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	chainConfig, ok := cfg.ChainConfigs[chainID]
+	if !ok {
+		log.Fatal().Uint64(logging.FieldChain, chainID).Msg("Chain is not supported")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.NewPostgresDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize database")
+	}
+
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close database")
+		}
+	}()
+
+	// Services of all chains are needed to resolve the destination chain of fulfillments and settlements
+	pools, err := evm.ResolveClientsFromConfig(ctx, *cfg, nil, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize Ethereum clients")
+	}
+
+	intentServices, fulfillmentServices, settlementServices, err := createServices(
+		utils.MapMap(pools, castClientsMap),
+		database,
+		cfg,
+		log,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create services")
+	}
+
+	backfillService := services.NewBackfillService(
+		intentServices,
+		fulfillmentServices,
+		settlementServices,
+		database,
+		log,
+	)
+
+	req := services.BackfillRequest{
+		ChainID:         chainID,
+		ContractAddress: common.HexToAddress(chainConfig.ContractAddr),
+		FromBlock:       fromBlock,
+		ToBlock:         toBlock,
+		EventTypes:      eventTypes,
+	}
+
+	err = backfillService.Run(ctx, req, func(p services.BackfillProgress) {
+		log.Info().
+			Uint64(logging.FieldChain, p.ChainID).
+			Str("event_type", p.EventType).
+			Uint64(logging.FieldBlock, p.Block).
+			Uint64("to_block", p.ToBlock).
+			Str("progress", fmt.Sprintf("%.1f%%", p.Percent())).
+			Msg("Backfill progress")
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Backfill failed")
+	}
+
+	log.Info().
+		Uint64(logging.FieldChain, chainID).
+		Uint64("from_block", fromBlock).
+		Uint64("to_block", toBlock).
+		Strs("event_types", eventTypes).
+		Msg("Backfill completed")
+}
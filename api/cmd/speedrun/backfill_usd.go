@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/services"
+	"github.com/speedrun-hq/speedrun/api/utils"
+)
+
+const backfillUSDCommand = "backfill-usd"
+
+// runBackfillUSD stores the USD value of the intents indexed without one, e.g. before PRICE_FILE was set
+//
+//	speedrun backfill-usd --chain 8453
+//
+// Intents are valued with the configured prices at their creation time, like new intents. Every chain is
+// backfilled if no chain is given. Intents that already have a USD value are left untouched, so it's safe
+// to run again, e.g. after adding prices.
+func runBackfillUSD(args []string) {
+	var (
+		chainID   uint64
+		batchSize int
+		logJSON   bool
+		logLevel  string
+	)
+
+	fs := flag.NewFlagSet(backfillUSDCommand, flag.ExitOnError)
+	fs.Uint64Var(&chainID, "chain", 0, "Source chain ID of the intents to value, all supported chains if not set")
+	fs.IntVar(&batchSize, "batch-size", services.DefaultUSDBackfillBatchSize, "Number of intents valued per batch")
+	fs.BoolVar(&logJSON, "log-json", false, "Output logs in JSON format")
+	fs.StringVar(&logLevel, "log-level", "info", "Set log level (debug, info, warn, error)")
+
+	// ExitOnError: Parse exits on invalid flags
+	_ = fs.Parse(args)
+
+	log := logging.New(os.Stdout, parseLogLevel(logLevel), logJSON)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	if cfg.PriceFile == "" {
+		log.Fatal().Msg("PRICE_FILE is not set, intents can't be valued")
+	}
+
+	prices, err := services.LoadPriceFile(cfg.PriceFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load token prices")
+	}
+
+	chainIDs := cfg.SupportedChains
+	if chainID != 0 {
+		if _, ok := cfg.ChainConfigs[chainID]; !ok {
+			log.Fatal().Uint64(logging.FieldChain, chainID).Msg("Chain is not supported")
+		}
+		chainIDs = []uint64{chainID}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.NewPostgresDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize database")
+	}
+
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close database")
+		}
+	}()
+
+	pools, err := evm.ResolveClientsFromConfig(ctx, *cfg, nil, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize Ethereum clients")
+	}
+
+	intentServices, _, _, err := createServices(utils.MapMap(pools, castClientsMap), database, cfg, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create services")
+	}
+
+	// tokens missing from the chain registry are looked up on their chain, as when intents are indexed
+	tokenRegistry, err := services.NewTokenRegistry(cfg.ChainConfigs, utils.MapMap(pools, castClientsMap), log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create token registry")
+	}
+
+	var scanned, valued int
+	for _, id := range chainIDs {
+		intentService, ok := intentServices[id]
+		if !ok {
+			log.Fatal().Uint64(logging.FieldChain, id).Msg("No intent service for chain")
+		}
+		intentService.SetTokenRegistry(tokenRegistry)
+		intentService.SetPriceSource(prices)
+
+		chainScanned, chainValued, err := intentService.BackfillUSDValues(ctx, batchSize)
+		if err != nil {
+			log.Fatal().Err(err).Uint64(logging.FieldChain, id).Msg("USD value backfill failed")
+		}
+
+		log.Info().
+			Uint64(logging.FieldChain, id).
+			Int("scanned", chainScanned).
+			Int("valued", chainValued).
+			Msg("Backfilled USD values of chain")
+
+		scanned += chainScanned
+		valued += chainValued
+	}
+
+	log.Info().
+		Int("scanned", scanned).
+		Int("valued", valued).
+		Msg("USD value backfill completed")
+}
@@ -0,0 +1,51 @@
+package httpjson
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	web "github.com/speedrun-hq/speedrun/api/http"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+func (h *handler) setupChainRoutes(rg *gin.RouterGroup) {
+	rg.GET("/chains", h.listChains)
+}
+
+// listChains handles listing the configured chains with their indexing progress
+func (h *handler) listChains(c *gin.Context) {
+	chains, err := h.deps.Chains.ListChains(c.Request.Context())
+	if err != nil {
+		web.ErrInternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chains)
+}
+
+// annotateIntentLinks sets the explorer links of the intents if the chains are known
+func (h *handler) annotateIntentLinks(intents ...*models.Intent) {
+	if h.deps.Chains == nil {
+		return
+	}
+
+	h.deps.Chains.AnnotateIntents(intents...)
+}
+
+// annotateFulfillmentLinks sets the explorer links of the fulfillments if the chains are known
+func (h *handler) annotateFulfillmentLinks(fulfillments ...*models.Fulfillment) {
+	if h.deps.Chains == nil {
+		return
+	}
+
+	h.deps.Chains.AnnotateFulfillments(fulfillments...)
+}
+
+// annotateSettlementLinks sets the explorer links of the settlements if the chains are known
+func (h *handler) annotateSettlementLinks(settlements ...*models.Settlement) {
+	if h.deps.Chains == nil {
+		return
+	}
+
+	h.deps.Chains.AnnotateSettlements(settlements...)
+}
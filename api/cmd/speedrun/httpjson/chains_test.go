@@ -0,0 +1,94 @@
+package httpjson
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChains(t *testing.T) {
+	const contract = "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB"
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name           string
+			expectedStatus int
+			setup          func(ts *testSuite)
+		}{
+			{
+				name:           "ChainsWithProgress",
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.On("ListEventCheckpoints", mock.Anything).Return([]*models.EventCheckpoint{
+						{ChainID: 1, EventType: "intent", ContractAddress: contract, BlockNumber: 120},
+						{ChainID: 1, EventType: "fulfillment", ContractAddress: contract, BlockNumber: 110},
+						{ChainID: 1, EventType: "settlement", ContractAddress: contract, BlockNumber: 130},
+					}, nil)
+				},
+			},
+			{
+				name:           "DatabaseError",
+				expectedStatus: http.StatusInternalServerError,
+				setup: func(ts *testSuite) {
+					ts.Database.On("ListEventCheckpoints", mock.Anything).Return(nil, errors.New("connection refused"))
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+				tt.setup(ts)
+
+				// ACT
+				res, err := ts.Client.Get().AddPath("/api/v1/chains").Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode, res.String())
+
+				if tt.expectedStatus == http.StatusOK {
+					assertResponseContainsJSON(t, res, "0.name", "Ethereum")
+					assertResponseContainsJSON(t, res, "0.explorer_url", "https://etherscan.io")
+					assertResponseContainsJSON(t, res, "0.ingestion_mode", "subscription")
+					assertResponseContainsJSON(t, res, "0.last_indexed_block", "110")
+				}
+			})
+		}
+	})
+
+	t.Run("ExplorerLinks", func(t *testing.T) {
+		t.Parallel()
+
+		const validID = "0x1234567890123456789012345678901234567890123456789012345678901234"
+
+		// ARRANGE
+		ts := newTestSuite(t)
+		ts.SettlementServices[1].On("GetSettlement", mock.Anything, validID).Return(&models.Settlement{
+			ID:        validID,
+			ChainID:   1,
+			TxHash:    "0xaa",
+			Fulfiller: "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B",
+		}, nil)
+
+		// ACT
+		res, err := ts.Client.Get().AddPath("/api/v1/settlements/" + validID).Do()
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode, res.String())
+		assertResponseContainsJSON(t, res, "explorer.tx", "https://etherscan.io/tx/0xaa")
+		assertResponseContainsJSON(t, res, "explorer.fulfiller",
+			"https://etherscan.io/address/0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B")
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	web "github.com/speedrun-hq/speedrun/api/http"
 	"github.com/speedrun-hq/speedrun/api/models"
 )
@@ -34,7 +35,7 @@ func (h *handler) createFulfillment(c *gin.Context) {
 
 	err = service.CreateFulfillment(ctx, req.IntentID, req.TxHash)
 	if err != nil {
-		web.ErrInternalServerError(c, err)
+		web.ErrFrom(c, err)
 		return
 	}
 
@@ -58,10 +59,18 @@ func (h *handler) getFulfillment(c *gin.Context) {
 
 	fulfillment, err := service.GetFulfillment(ctx, id)
 	if err != nil {
-		web.ErrNotFound(c, err)
+		if errors.Is(err, errs.ErrNotFound) {
+			web.ErrNotFound(c, errors.Wrap(ErrNotFound, "fulfillment"))
+			return
+		}
+
+		web.ErrFrom(c, err)
 		return
 	}
 
+	h.annotateFulfillmentLinks(fulfillment)
+	h.annotateFulfillmentTokens(ctx, fulfillment)
+
 	c.JSON(http.StatusOK, fulfillment)
 }
 
@@ -80,6 +89,9 @@ func (h *handler) listFulfillments(c *gin.Context) {
 		return
 	}
 
+	h.annotateFulfillmentLinks(fulfillments...)
+	h.annotateFulfillmentTokens(ctx, fulfillments...)
+
 	res := models.NewPaginatedResponse(fulfillments, pag.Page, pag.PageSize, totalCount)
 
 	c.JSON(http.StatusOK, res)
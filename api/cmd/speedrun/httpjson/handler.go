@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	web "github.com/speedrun-hq/speedrun/api/http"
 	"github.com/speedrun-hq/speedrun/api/http/timeout"
 	"github.com/speedrun-hq/speedrun/api/logging"
@@ -20,8 +21,9 @@ import (
 type handler struct {
 	*gin.Engine
 
-	deps   Dependencies
-	logger zerolog.Logger
+	deps       Dependencies
+	adminToken string
+	logger     zerolog.Logger
 }
 
 type Config struct {
@@ -29,6 +31,7 @@ type Config struct {
 
 	Addr           string
 	AllowedOrigins string
+	AdminToken     string // bearer token of the webhook management routes, they reject every request if empty
 	LogRequests    bool
 
 	Logger zerolog.Logger
@@ -38,6 +41,13 @@ type Dependencies struct {
 	Database            db.Database
 	IntentServices      map[uint64]IntentService
 	FulfillmentServices map[uint64]FulfillmentService
+	SettlementServices  map[uint64]SettlementService
+	Leaderboard         LeaderboardService
+	Confirmations       ConfirmationStatus
+	Stream              IntentEventStream
+	Webhooks            WebhookService
+	Chains              *services.ChainService
+	Tokens              *services.TokenRegistry
 	Metrics             *services.MetricsService
 }
 
@@ -62,6 +72,33 @@ type FulfillmentService interface {
 	GetFulfillment(ctx context.Context, id string) (*models.Fulfillment, error)
 }
 
+type SettlementService interface {
+	GetSettlement(ctx context.Context, id string) (*models.Settlement, error)
+}
+
+// ConfirmationStatus sets the confirmation state of intents from their source chain head
+type ConfirmationStatus interface {
+	Annotate(intents ...*models.Intent)
+}
+
+// IntentEventStream subscribes stream clients to intent events
+type IntentEventStream interface {
+	Subscribe(filter models.IntentEventFilter) (*services.IntentEventSubscription, error)
+}
+
+// WebhookService registers webhook subscriptions
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, req models.CreateWebhookRequest) (*models.WebhookSubscription, error)
+}
+
+// LeaderboardService defines the interface for leaderboard operations
+type LeaderboardService interface {
+	GetLeaderboard(
+		ctx context.Context,
+		query models.LeaderboardQuery,
+	) ([]*models.LeaderboardResponse, int, error)
+}
+
 const (
 	requestTimeout = 10 * time.Second
 	rwTimeout      = 15 * time.Second
@@ -69,8 +106,8 @@ const (
 )
 
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrParamRequired = errors.New("param required")
+	ErrNotFound      = errs.ErrNotFound
+	ErrParamRequired = errs.Validation("param required")
 )
 
 func New(cfg Config) *http.Server {
@@ -94,9 +131,10 @@ func New(cfg Config) *http.Server {
 
 func newHandler(cfg Config, router *gin.Engine) *handler {
 	h := &handler{
-		Engine: router,
-		deps:   cfg.Dependencies,
-		logger: cfg.Logger.With().Str(logging.FieldModule, "api").Logger(),
+		Engine:     router,
+		deps:       cfg.Dependencies,
+		adminToken: cfg.AdminToken,
+		logger:     cfg.Logger.With().Str(logging.FieldModule, "api").Logger(),
 	}
 
 	logLevel := zerolog.DebugLevel
@@ -107,7 +145,7 @@ func newHandler(cfg Config, router *gin.Engine) *handler {
 	h.Use(
 		gin.Recovery(),
 		web.Zerolog(cfg.Logger, logLevel),
-		timeout.New(requestTimeout, cfg.Logger),
+		skipStreams(timeout.New(requestTimeout, cfg.Logger)),
 		web.CORS(cfg.AllowedOrigins),
 	)
 
@@ -117,11 +155,41 @@ func newHandler(cfg Config, router *gin.Engine) *handler {
 	return h
 }
 
+// skipStreams bypasses the middleware for long-lived streams,
+// e.g. the timeout middleware buffers the whole response
+func skipStreams(middleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == streamPath {
+			c.Next()
+			return
+		}
+
+		middleware(c)
+	}
+}
+
 func (h *handler) setupAPIRoutes() {
 	v1 := h.Group("/api/v1")
 
 	h.setupIntentRoutes(v1)
 	h.setupFulfillmentRoutes(v1)
+	h.setupSettlementRoutes(v1)
+
+	if h.deps.Leaderboard != nil {
+		h.setupLeaderboardRoutes(v1)
+	}
+
+	if h.deps.Stream != nil {
+		h.setupStreamRoutes(v1)
+	}
+
+	if h.deps.Webhooks != nil {
+		h.setupWebhookRoutes(v1)
+	}
+
+	if h.deps.Chains != nil {
+		h.setupChainRoutes(v1)
+	}
 }
 
 func (h *handler) setupObservabilityRoutes() {
@@ -152,19 +220,14 @@ type paginationParams struct {
 var errPageSize = errors.Errorf("invalid page_size parameter (must be between 1 and %d)", maxPageSize)
 
 func resolvePagination(c *gin.Context) (paginationParams, error) {
-	var (
-		pageRaw     = c.DefaultQuery("page", "1")
-		pageSizeRaw = c.DefaultQuery("page_size", "20")
-	)
-
-	page, err := strconv.Atoi(pageRaw)
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
 		return paginationParams{}, errors.New("invalid page parameter")
 	}
 
-	pageSize, err := strconv.Atoi(pageSizeRaw)
-	if err != nil || pageSize < 1 || pageSize > maxPageSize {
-		return paginationParams{}, errPageSize
+	pageSize, err := resolvePageSize(c)
+	if err != nil {
+		return paginationParams{}, err
 	}
 
 	return paginationParams{
@@ -172,3 +235,13 @@ func resolvePagination(c *gin.Context) (paginationParams, error) {
 		PageSize: pageSize,
 	}, nil
 }
+
+// resolvePageSize parses the page_size query param, for both offset and keyset pagination
+func resolvePageSize(c *gin.Context) (int, error) {
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		return 0, errPageSize
+	}
+
+	return pageSize, nil
+}
@@ -2,6 +2,7 @@ package httpjson
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/config"
 	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/services"
 	"github.com/speedrun-hq/speedrun/api/testing/mocks"
 	"github.com/speedrun-hq/speedrun/api/utils"
 	"github.com/stretchr/testify/assert"
@@ -28,10 +31,19 @@ type testSuite struct {
 	Database            *mocks.DatabaseMock
 	IntentServices      map[uint64]*mocks.IntentServiceMock
 	FulfillmentServices map[uint64]*mocks.FulfillmentServiceMock
+	SettlementServices  map[uint64]*mocks.SettlementServiceMock
+	Leaderboard         *mocks.LeaderboardServiceMock
+	Confirmations       *mocks.ConfirmationStatusMock
+	Stream              *services.IntentEventBroker
+	Chains              *services.ChainService
+	Server              *httptest.Server
 
 	Logger zerolog.Logger
 }
 
+// testAdminToken is the admin token of the test server
+const testAdminToken = "test-admin-token"
+
 var once sync.Once
 
 func newTestSuite(t *testing.T) *testSuite {
@@ -47,11 +59,35 @@ func newTestSuite(t *testing.T) *testSuite {
 		database           = mocks.NewDatabaseMock(t)
 		ethIntentMock      = mocks.NewIntentServiceMock(t)
 		ethFulfillmentMock = mocks.NewFulfillmentServiceMock(t)
+		ethSettlementMock  = mocks.NewSettlementServiceMock(t)
+		leaderboardMock    = mocks.NewLeaderboardServiceMock(t)
+		confirmationsMock  = mocks.NewConfirmationStatusMock(t)
+		stream             = services.NewIntentEventBroker(database, logger)
+		webhooks           = services.NewWebhookService(database, 3, logger)
+		chainConfigs       = map[uint64]*config.ChainConfig{
+			1: {
+				Name:          "Ethereum",
+				ExplorerURL:   "https://etherscan.io",
+				ContractAddr:  "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
+				IngestionMode: config.IngestionSubscription,
+				Tokens: []config.TokenEntry{
+					{Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Symbol: "USDC", Decimals: 6, Asset: "usdc"},
+				},
+			},
+		}
 	)
 
+	// subscription hosts resolve to a public documentation address
+	webhooks.SetResolver(publicResolver{})
+
+	chains := services.NewChainService(database, chainConfigs, nil)
+	tokens, err := services.NewTokenRegistry(chainConfigs, nil, logger)
+	require.NoError(t, err)
+
 	cfg := Config{
 		Logger:      logger,
 		LogRequests: true,
+		AdminToken:  testAdminToken,
 		Dependencies: Dependencies{
 			Database: database,
 			IntentServices: map[uint64]IntentService{
@@ -60,7 +96,16 @@ func newTestSuite(t *testing.T) *testSuite {
 			FulfillmentServices: map[uint64]FulfillmentService{
 				1: FulfillmentService(ethFulfillmentMock),
 			},
-			Metrics: nil,
+			SettlementServices: map[uint64]SettlementService{
+				1: SettlementService(ethSettlementMock),
+			},
+			Leaderboard:   leaderboardMock,
+			Confirmations: confirmationsMock,
+			Stream:        stream,
+			Webhooks:      webhooks,
+			Chains:        chains,
+			Tokens:        tokens,
+			Metrics:       nil,
 		},
 	}
 
@@ -90,6 +135,14 @@ func newTestSuite(t *testing.T) *testSuite {
 		FulfillmentServices: map[uint64]*mocks.FulfillmentServiceMock{
 			1: ethFulfillmentMock,
 		},
+		SettlementServices: map[uint64]*mocks.SettlementServiceMock{
+			1: ethSettlementMock,
+		},
+		Leaderboard:   leaderboardMock,
+		Confirmations: confirmationsMock,
+		Stream:        stream,
+		Chains:        chains,
+		Server:        server,
 	}
 }
 
@@ -114,6 +167,13 @@ func assertResponseContainsJSON(t *testing.T, res *gentleman.Response, path stri
 	assert.Contains(t, r.String(), contains, res.String())
 }
 
+// publicResolver resolves every host to a public address
+type publicResolver struct{}
+
+func (publicResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}}, nil
+}
+
 func numOfArgs(v uint) []any {
 	vals := make([]any, 0, v)
 
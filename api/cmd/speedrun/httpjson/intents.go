@@ -1,11 +1,14 @@
 package httpjson
 
 import (
+	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	web "github.com/speedrun-hq/speedrun/api/http"
 	"github.com/speedrun-hq/speedrun/api/logging"
 	"github.com/speedrun-hq/speedrun/api/models"
@@ -17,7 +20,9 @@ func (h *handler) setupIntentRoutes(rg *gin.RouterGroup) {
 
 	intents.GET("", h.listIntents)
 	intents.POST("", h.createIntent)
+	intents.GET("/search", h.searchIntents)
 	intents.GET(":id", h.getIntent)
+	intents.GET(":id/history", h.getIntentHistory)
 	intents.GET("/sender/:sender", h.getIntentsBySender)
 	intents.GET("/recipient/:recipient", h.getIntentsByRecipient)
 }
@@ -50,13 +55,7 @@ func (h *handler) createIntent(c *gin.Context) {
 	)
 
 	if err != nil {
-		// "check if it's a validation error" (ported code)
-		if strings.Contains(err.Error(), "invalid") {
-			web.ErrBadRequest(c, err)
-			return
-		}
-
-		web.ErrInternalServerError(c, err)
+		web.ErrFrom(c, err)
 		return
 	}
 
@@ -90,21 +89,57 @@ func (h *handler) getIntent(c *gin.Context) {
 	if err != nil {
 		h.logger.Debug().Err(err).Str(logging.FieldIntent, id).Msgf("Error getting intent")
 
-		// "check if it's a not found error" (ported code)
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, errs.ErrNotFound) {
 			web.ErrNotFound(c, errors.Wrap(ErrNotFound, "intent"))
 			return
 		}
 
+		web.ErrFrom(c, err)
+		return
+	}
+
+	fulfillments, err := h.deps.Database.ListIntentFulfillments(ctx, id)
+	if err != nil {
 		web.ErrInternalServerError(c, err)
 		return
 	}
+	intent.SetFulfillments(fulfillments)
 
 	h.logger.Debug().Str(logging.FieldIntent, id).Msg("Successfully retrieved intent")
 
+	h.annotateConfirmations(intent)
+	h.annotateIntentLinks(intent)
+	h.annotateIntentTokens(ctx, intent)
+
 	c.JSON(http.StatusOK, intent)
 }
 
+// getIntentHistory handles retrieving the status transitions of an intent
+func (h *handler) getIntentHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	if !utils.ValidateBytes32(id) {
+		web.ErrBadRequest(c, errors.Wrap(ErrParamRequired, "intent id"))
+		return
+	}
+
+	history, err := h.deps.Database.ListIntentStatusHistory(ctx, id)
+	if err != nil {
+		web.ErrInternalServerError(c, err)
+		return
+	}
+
+	// every indexed intent has at least one status recorded, intents indexed before the history was
+	// recorded have their status at the migration
+	if len(history) == 0 {
+		web.ErrNotFound(c, errors.Wrap(ErrNotFound, "intent"))
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *handler) listIntents(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -123,6 +158,10 @@ func (h *handler) listIntents(c *gin.Context) {
 		return
 	}
 
+	h.annotateConfirmations(intents...)
+	h.annotateIntentLinks(intents...)
+	h.annotateIntentTokens(ctx, intents...)
+
 	response := make([]*models.IntentResponse, 0, len(intents))
 	for _, intent := range intents {
 		response = append(response, intent.ToResponse())
@@ -131,6 +170,141 @@ func (h *handler) listIntents(c *gin.Context) {
 	c.JSON(http.StatusOK, models.NewPaginatedResponse(response, pag.Page, pag.PageSize, totalCount))
 }
 
+// searchIntents handles searching intents by any combination of filters, with keyset pagination
+func (h *handler) searchIntents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query, err := parseIntentQuery(c)
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	intents, hasMore, err := h.deps.Database.SearchIntents(ctx, query)
+	if err != nil {
+		web.ErrFrom(c, err)
+		return
+	}
+
+	h.annotateConfirmations(intents...)
+	h.annotateIntentLinks(intents...)
+	h.annotateIntentTokens(ctx, intents...)
+
+	response := make([]*models.IntentResponse, 0, len(intents))
+	for _, intent := range intents {
+		response = append(response, intent.ToResponse())
+	}
+
+	res := &models.CursorPaginatedResponse{
+		Data:     response,
+		PageSize: query.PageSize,
+		HasMore:  hasMore,
+	}
+	if hasMore {
+		res.NextCursor = models.NewIntentCursor(intents[len(intents)-1], query.SortBy).Encode()
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// parseIntentQuery parses the filters, sort and page of an intent search from the query params
+func parseIntentQuery(c *gin.Context) (models.IntentQuery, error) {
+	var (
+		query models.IntentQuery
+		err   error
+	)
+
+	if query.SourceChain, err = parseChainQuery(c, "source_chain"); err != nil {
+		return query, err
+	}
+	if query.DestinationChain, err = parseChainQuery(c, "destination_chain"); err != nil {
+		return query, err
+	}
+
+	for key, value := range map[string]*string{
+		"token":     &query.Token,
+		"sender":    &query.Sender,
+		"recipient": &query.Recipient,
+	} {
+		*value = c.Query(key)
+		if *value != "" && !utils.IsValidAddress(*value) {
+			return query, errors.Errorf("invalid %s address format", key)
+		}
+	}
+
+	if raw := c.Query("status"); raw != "" {
+		for _, status := range strings.Split(raw, ",") {
+			status := models.IntentStatus(strings.TrimSpace(status))
+			if !status.IsValid() {
+				return query, errors.Errorf("invalid status: %s", status)
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+
+	if raw := c.Query("is_call"); raw != "" {
+		isCall, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("invalid is_call parameter (must be true or false)")
+		}
+		query.IsCall = &isCall
+	}
+
+	if query.MinAmount, err = parseAmountQuery(c, "min_amount"); err != nil {
+		return query, err
+	}
+	if query.MaxAmount, err = parseAmountQuery(c, "max_amount"); err != nil {
+		return query, err
+	}
+	if query.MinAmount != nil && query.MaxAmount != nil && query.MinAmount.Cmp(query.MaxAmount) > 0 {
+		return query, errors.New("min_amount is greater than max_amount")
+	}
+
+	if query.Since, err = parseTimeQuery(c, "since"); err != nil {
+		return query, err
+	}
+	if query.Until, err = parseTimeQuery(c, "until"); err != nil {
+		return query, err
+	}
+
+	query.SortBy = models.IntentSort(c.DefaultQuery("sort_by", string(models.IntentSortCreatedAt)))
+	if !query.SortBy.IsValid() {
+		return query, errors.New("invalid sort_by parameter (must be created_at or amount)")
+	}
+
+	query.Order = models.SortOrder(strings.ToLower(c.DefaultQuery("order", string(models.SortOrderDesc))))
+	if !query.Order.IsValid() {
+		return query, errors.New("invalid order parameter (must be asc or desc)")
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		if query.After, err = models.ParseIntentCursor(raw, query.SortBy); err != nil {
+			return query, err
+		}
+	}
+
+	if query.PageSize, err = resolvePageSize(c); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// parseAmountQuery parses an optional amount query param in base units. Returns nil if absent.
+func parseAmountQuery(c *gin.Context, key string) (*big.Int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, errors.Errorf("invalid %s parameter (must be an integer amount in base units)", key)
+	}
+
+	return amount, nil
+}
+
 // GetIntentsBySender handles retrieving intents by sender
 func (h *handler) getIntentsBySender(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -164,6 +338,10 @@ func (h *handler) getIntentsBySender(c *gin.Context) {
 		return
 	}
 
+	h.annotateConfirmations(intents...)
+	h.annotateIntentLinks(intents...)
+	h.annotateIntentTokens(ctx, intents...)
+
 	response := make([]*models.IntentResponse, 0, len(intents))
 	for _, intent := range intents {
 		response = append(response, intent.ToResponse())
@@ -204,6 +382,10 @@ func (h *handler) getIntentsByRecipient(c *gin.Context) {
 		return
 	}
 
+	h.annotateConfirmations(intents...)
+	h.annotateIntentLinks(intents...)
+	h.annotateIntentTokens(ctx, intents...)
+
 	response := make([]*models.IntentResponse, 0, len(intents))
 	for _, intent := range intents {
 		response = append(response, intent.ToResponse())
@@ -214,6 +396,15 @@ func (h *handler) getIntentsByRecipient(c *gin.Context) {
 	c.JSON(http.StatusOK, paginatedResponse)
 }
 
+// annotateConfirmations sets the confirmation state of the intents if it's tracked
+func (h *handler) annotateConfirmations(intents ...*models.Intent) {
+	if h.deps.Confirmations == nil || len(intents) == 0 {
+		return
+	}
+
+	h.deps.Confirmations.Annotate(intents...)
+}
+
 func (h *handler) resolveIntentService(chainID uint64) (IntentService, error) {
 	s, ok := h.deps.IntentServices[chainID]
 	if !ok {
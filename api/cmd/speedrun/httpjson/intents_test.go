@@ -3,12 +3,16 @@ package httpjson
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gentleman.v2"
 )
 
 func TestIntents(t *testing.T) {
@@ -21,24 +25,27 @@ func TestIntents(t *testing.T) {
 	t.Run("Create", func(t *testing.T) {
 		t.Parallel()
 
+		validRequest := models.CreateIntentRequest{
+			ID:               validID,
+			SourceChain:      1,
+			DestinationChain: 2,
+			Token:            "ETH",
+			Amount:           "1.0",
+			Recipient:        validRecipient,
+			Sender:           validSender,
+			IntentFee:        "0.1",
+		}
+
 		tests := []struct {
 			name           string
 			request        any
 			expectedStatus int
+			expectedCode   string
 			setup          func(ts *testSuite)
 		}{
 			{
-				name: "ValidCreation",
-				request: models.CreateIntentRequest{
-					ID:               validID,
-					SourceChain:      1,
-					DestinationChain: 2,
-					Token:            "ETH",
-					Amount:           "1.0",
-					Recipient:        validRecipient,
-					Sender:           validSender,
-					IntentFee:        "0.1",
-				},
+				name:    "ValidCreation",
+				request: validRequest,
 				setup: func(ts *testSuite) {
 					out := &models.Intent{
 						ID:               validID,
@@ -60,6 +67,40 @@ func TestIntents(t *testing.T) {
 				name:           "InvalidRequest",
 				request:        "invalid json",
 				expectedStatus: http.StatusBadRequest,
+				expectedCode:   "invalid_argument",
+			},
+			{
+				name:           "ValidationError",
+				request:        validRequest,
+				expectedStatus: http.StatusBadRequest,
+				expectedCode:   "invalid_argument",
+				setup: func(ts *testSuite) {
+					ts.IntentServices[1].
+						On("CreateIntent", numOfArgs(9)...).
+						Return(nil, errs.Validation("invalid token address"))
+				},
+			},
+			{
+				name:           "DuplicateIntent",
+				request:        validRequest,
+				expectedStatus: http.StatusConflict,
+				expectedCode:   "duplicate",
+				setup: func(ts *testSuite) {
+					ts.IntentServices[1].
+						On("CreateIntent", numOfArgs(9)...).
+						Return(nil, errors.Wrap(db.ErrDuplicate, "failed to create intent"))
+				},
+			},
+			{
+				name:           "DatabaseError",
+				request:        validRequest,
+				expectedStatus: http.StatusInternalServerError,
+				expectedCode:   "internal",
+				setup: func(ts *testSuite) {
+					ts.IntentServices[1].
+						On("CreateIntent", numOfArgs(9)...).
+						Return(nil, errors.New("connection refused"))
+				},
 			},
 		}
 
@@ -80,6 +121,10 @@ func TestIntents(t *testing.T) {
 				// ASSERT
 				require.NoError(t, err)
 				assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+				if tt.expectedCode != "" {
+					assertResponseContainsJSON(t, res, "code", tt.expectedCode)
+				}
 			})
 		}
 	})
@@ -111,6 +156,20 @@ func TestIntents(t *testing.T) {
 				expectedStatus: http.StatusOK,
 				setup: func(ts *testSuite) {
 					ts.IntentServices[1].On("GetIntent", mock.Anything, validID).Return(mockIntent, nil)
+					ts.Database.On("ListIntentFulfillments", mock.Anything, validID).Return([]*models.Fulfillment{
+						{ID: validID, Amount: "300", TxHash: "0xaa", LogIndex: 1},
+						{ID: validID, Amount: "200", TxHash: "0xbb", LogIndex: 4},
+					}, nil)
+					ts.Confirmations.On("Annotate", mock.Anything).Return()
+				},
+			},
+			{
+				name:           "DatabaseError",
+				intentID:       validID,
+				expectedStatus: http.StatusInternalServerError,
+				setup: func(ts *testSuite) {
+					ts.IntentServices[1].On("GetIntent", mock.Anything, validID).Return(&models.Intent{ID: validID}, nil)
+					ts.Database.On("ListIntentFulfillments", mock.Anything, validID).Return(nil, assert.AnError)
 				},
 			},
 			{
@@ -120,7 +179,7 @@ func TestIntents(t *testing.T) {
 				setup: func(ts *testSuite) {
 					ts.IntentServices[1].
 						On("GetIntent", mock.Anything, mock.Anything).
-						Return(nil, errors.New("not found"))
+						Return(nil, errs.NotFound("intent not found"))
 				},
 			},
 		}
@@ -146,9 +205,14 @@ func TestIntents(t *testing.T) {
 				require.NoError(t, err)
 				assert.Equal(t, tt.expectedStatus, res.StatusCode, res.String())
 
-				if tt.expectedStatus == http.StatusOK {
+				switch tt.expectedStatus {
+				case http.StatusOK:
 					assertResponseContainsJSON(t, res, "id", mockIntent.ID)
 					assertResponseContainsJSON(t, res, "token", mockIntent.Token)
+					assertResponseContainsJSON(t, res, "filled_amount", "500")
+					assert.Contains(t, res.String(), `"log_index":4`)
+				case http.StatusNotFound:
+					assertResponseContainsJSON(t, res, "code", "not_found")
 				}
 			})
 		}
@@ -199,6 +263,7 @@ func TestIntents(t *testing.T) {
 				expectedStatus: http.StatusOK,
 				setup: func(ts *testSuite) {
 					ts.Database.On("ListIntentsPaginatedOptimized", numOfArgs(4)...).Return(mockIntents, 2, nil)
+					ts.Confirmations.On("Annotate", mock.Anything).Return()
 				},
 			},
 			{
@@ -281,6 +346,7 @@ func TestIntents(t *testing.T) {
 					ts.Database.
 						On("ListIntentsBySenderPaginatedOptimized", numOfArgs(4)...).
 						Return(mockIntents, 2, nil)
+					ts.Confirmations.On("Annotate", mock.Anything).Return()
 				},
 			},
 			{
@@ -379,6 +445,7 @@ func TestIntents(t *testing.T) {
 					ts.Database.
 						On("ListIntentsByRecipientPaginatedOptimized", numOfArgs(4)...).
 						Return(mockIntents, 2, nil)
+					ts.Confirmations.On("Annotate", mock.Anything).Return()
 				},
 			},
 			{
@@ -465,4 +532,225 @@ func TestIntents(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("History", func(t *testing.T) {
+		t.Parallel()
+
+		history := []*models.IntentStatusTransition{
+			{IntentID: validID, ToStatus: models.IntentStatusPending},
+			{IntentID: validID, FromStatus: models.IntentStatusPending, ToStatus: models.IntentStatusFulfilled},
+		}
+
+		tests := []struct {
+			name           string
+			intentID       string
+			expectedStatus int
+			setup          func(ts *testSuite)
+		}{
+			{
+				name:           "ValidHistoryRetrieval",
+				intentID:       validID,
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.On("ListIntentStatusHistory", mock.Anything, validID).Return(history, nil)
+				},
+			},
+			{
+				name:           "IntentNotFound",
+				intentID:       validID,
+				expectedStatus: http.StatusNotFound,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("ListIntentStatusHistory", mock.Anything, validID).
+						Return([]*models.IntentStatusTransition{}, nil)
+				},
+			},
+			{
+				name:           "InvalidIntentID",
+				intentID:       "0x1234",
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "DatabaseError",
+				intentID:       validID,
+				expectedStatus: http.StatusInternalServerError,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("ListIntentStatusHistory", mock.Anything, validID).
+						Return(nil, assert.AnError)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				if tt.setup != nil {
+					tt.setup(ts)
+				}
+
+				// ACT
+				res, err := ts.Client.Get().
+					AddPath("/api/v1/intents/:id/history").
+					Param("id", tt.intentID).
+					Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+
+		createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		mockIntents := []*models.Intent{
+			{
+				ID:               validID,
+				SourceChain:      1,
+				DestinationChain: 2,
+				Token:            validRecipient,
+				Amount:           "1000000",
+				Recipient:        validRecipient,
+				Sender:           validSender,
+				IntentFee:        "100",
+				Status:           models.IntentStatusSettled,
+				CreatedAt:        createdAt,
+			},
+		}
+		nextCursor := models.NewIntentCursor(mockIntents[0], models.IntentSortCreatedAt).Encode()
+
+		tests := []struct {
+			name           string
+			queryParams    map[string]string
+			expectedStatus int
+			setup          func(ts *testSuite)
+			assert         func(t *testing.T, res *gentleman.Response)
+		}{
+			{
+				name: "Filters",
+				queryParams: map[string]string{
+					"source_chain": "1",
+					"sender":       validSender,
+					"status":       "settled,settled_without_fulfillment",
+					"is_call":      "false",
+					"min_amount":   "1000",
+					"max_amount":   "2000000",
+					"since":        "2025-01-01T00:00:00Z",
+					"page_size":    "1",
+				},
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.On("SearchIntents", mock.Anything, mock.MatchedBy(func(q models.IntentQuery) bool {
+						return q.SourceChain == 1 &&
+							q.Sender == validSender &&
+							len(q.Statuses) == 2 &&
+							q.IsCall != nil && !*q.IsCall &&
+							q.MinAmount.String() == "1000" &&
+							q.MaxAmount.String() == "2000000" &&
+							q.Since.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+							q.SortBy == models.IntentSortCreatedAt &&
+							q.Order == models.SortOrderDesc &&
+							q.After == nil &&
+							q.PageSize == 1
+					})).Return(mockIntents, true, nil)
+					ts.Confirmations.On("Annotate", mock.Anything).Return()
+				},
+				assert: func(t *testing.T, res *gentleman.Response) {
+					assertResponseContainsJSON(t, res, "data.0.id", validID)
+					assertResponseContainsJSON(t, res, "has_more", "true")
+					assertResponseContainsJSON(t, res, "next_cursor", nextCursor)
+				},
+			},
+			{
+				name: "NextPage",
+				queryParams: map[string]string{
+					"cursor": nextCursor,
+				},
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.On("SearchIntents", mock.Anything, mock.MatchedBy(func(q models.IntentQuery) bool {
+						return q.After != nil && q.After.ID == validID && q.After.CreatedAt.Equal(createdAt)
+					})).Return([]*models.Intent{}, false, nil)
+				},
+				assert: func(t *testing.T, res *gentleman.Response) {
+					assertResponseContainsJSON(t, res, "has_more", "false")
+					assert.NotContains(t, res.String(), "next_cursor")
+				},
+			},
+			{
+				name:           "CursorOfAnotherSort",
+				queryParams:    map[string]string{"cursor": nextCursor, "sort_by": "amount"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidStatus",
+				queryParams:    map[string]string{"status": "settled,bogus"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidAddress",
+				queryParams:    map[string]string{"recipient": "invalid-address"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidAmountRange",
+				queryParams:    map[string]string{"min_amount": "2000", "max_amount": "1000"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidOrder",
+				queryParams:    map[string]string{"order": "sideways"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "DatabaseError",
+				expectedStatus: http.StatusInternalServerError,
+				setup: func(ts *testSuite) {
+					ts.Database.On("SearchIntents", mock.Anything, mock.Anything).Return(nil, false, assert.AnError)
+				},
+			},
+			{
+				name:           "DatabaseTimeout",
+				expectedStatus: http.StatusGatewayTimeout,
+				setup: func(ts *testSuite) {
+					ts.Database.On("SearchIntents", mock.Anything, mock.Anything).
+						Return(nil, false, errs.New(errs.KindTimeout, "failed to query intents"))
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				if tt.setup != nil {
+					tt.setup(ts)
+				}
+
+				// ACT
+				res, err := ts.Client.Get().
+					AddPath("/api/v1/intents/search").
+					SetQueryParams(tt.queryParams).
+					Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedStatus, res.StatusCode, res.String())
+
+				if tt.assert != nil {
+					tt.assert(t, res)
+				}
+			})
+		}
+	})
 }
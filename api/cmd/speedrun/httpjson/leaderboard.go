@@ -0,0 +1,78 @@
+package httpjson
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	web "github.com/speedrun-hq/speedrun/api/http"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+func (h *handler) setupLeaderboardRoutes(rg *gin.RouterGroup) {
+	rg.GET("/leaderboard/:chainId", h.getLeaderboard)
+}
+
+func (h *handler) getLeaderboard(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	chainID, err := strconv.ParseUint(c.Param("chainId"), 10, 64)
+	if err != nil || chainID == 0 {
+		web.ErrBadRequest(c, errors.New("invalid chainId parameter"))
+		return
+	}
+
+	pag, err := resolvePagination(c)
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	since, err := parseTimeQuery(c, "since")
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	until, err := parseTimeQuery(c, "until")
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	query := models.LeaderboardQuery{
+		ChainID:  chainID,
+		SortBy:   models.LeaderboardSort(c.DefaultQuery("sort_by", string(models.LeaderboardSortVolume))),
+		Since:    since,
+		Until:    until,
+		Page:     pag.Page,
+		PageSize: pag.PageSize,
+	}
+
+	runners, totalCount, err := h.deps.Leaderboard.GetLeaderboard(ctx, query)
+	if err != nil {
+		web.ErrFrom(c, err)
+		return
+	}
+
+	res := models.NewPaginatedResponse(runners, pag.Page, pag.PageSize, totalCount)
+
+	c.JSON(http.StatusOK, res)
+}
+
+// parseTimeQuery parses an optional RFC3339 query param. Returns zero time if absent.
+func parseTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid %s parameter (must be RFC3339)", key)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,141 @@
+package httpjson
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboard(t *testing.T) {
+	const validAddress = "0x0987654321098765432109876543210987654321"
+
+	runners := []*models.LeaderboardResponse{
+		{
+			Rank:           1,
+			Address:        validAddress,
+			ChainID:        8453,
+			Score:          "3000",
+			TotalTransfers: 3,
+			TotalVolume:    "3000",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		query          map[string]string
+		expectedStatus int
+		setup          func(ts *testSuite)
+	}{
+		{
+			name:           "DefaultsToVolume",
+			path:           "/api/v1/leaderboard/8453",
+			expectedStatus: http.StatusOK,
+			setup: func(ts *testSuite) {
+				query := models.LeaderboardQuery{
+					ChainID:  8453,
+					SortBy:   models.LeaderboardSortVolume,
+					Page:     1,
+					PageSize: 20,
+				}
+
+				ts.Leaderboard.On("GetLeaderboard", mock.Anything, query).Return(runners, 1, nil)
+			},
+		},
+		{
+			name: "SortAndTimeWindow",
+			path: "/api/v1/leaderboard/8453",
+			query: map[string]string{
+				"sort_by":   "fastest",
+				"since":     "2025-01-01T00:00:00Z",
+				"until":     "2025-02-01T00:00:00Z",
+				"page":      "2",
+				"page_size": "10",
+			},
+			expectedStatus: http.StatusOK,
+			setup: func(ts *testSuite) {
+				matcher := mock.MatchedBy(func(q models.LeaderboardQuery) bool {
+					return q.SortBy == models.LeaderboardSortFastest &&
+						q.Page == 2 && q.PageSize == 10 &&
+						q.Since.Year() == 2025 && q.Until.Month() == 2
+				})
+
+				ts.Leaderboard.On("GetLeaderboard", mock.Anything, matcher).Return(runners, 11, nil)
+			},
+		},
+		{
+			name:           "InvalidChainID",
+			path:           "/api/v1/leaderboard/base",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "InvalidTimeWindow",
+			path:           "/api/v1/leaderboard/8453",
+			query:          map[string]string{"since": "yesterday"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "InvalidPageSize",
+			path:           "/api/v1/leaderboard/8453",
+			query:          map[string]string{"page_size": "1000"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "InvalidSort",
+			path:           "/api/v1/leaderboard/8453",
+			query:          map[string]string{"sort_by": "bogus"},
+			expectedStatus: http.StatusBadRequest,
+			setup: func(ts *testSuite) {
+				ts.Leaderboard.
+					On("GetLeaderboard", numOfArgs(2)...).
+					Return(nil, 0, errs.Validation("invalid sort_by: bogus"))
+			},
+		},
+		{
+			name:           "DatabaseError",
+			path:           "/api/v1/leaderboard/8453",
+			expectedStatus: http.StatusInternalServerError,
+			setup: func(ts *testSuite) {
+				ts.Leaderboard.
+					On("GetLeaderboard", numOfArgs(2)...).
+					Return(nil, 0, errors.New("connection refused"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// ARRANGE
+			ts := newTestSuite(t)
+
+			if tt.setup != nil {
+				tt.setup(ts)
+			}
+
+			req := ts.Client.Get().AddPath(tt.path)
+			for k, v := range tt.query {
+				req.AddQuery(k, v)
+			}
+
+			// ACT
+			res, err := req.Do()
+
+			// ASSERT
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, res.StatusCode, res.String())
+
+			if tt.expectedStatus == http.StatusOK {
+				assertResponseContainsJSON(t, res, "data.0.address", validAddress)
+				assertResponseContainsJSON(t, res, "data.0.rank", "1")
+			}
+		})
+	}
+}
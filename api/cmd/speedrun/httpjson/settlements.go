@@ -0,0 +1,131 @@
+package httpjson
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	web "github.com/speedrun-hq/speedrun/api/http"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/utils"
+)
+
+func (h *handler) setupSettlementRoutes(rg *gin.RouterGroup) {
+	settlements := rg.Group("/settlements")
+
+	settlements.GET("", h.listSettlements)
+	settlements.GET("/:id", h.getSettlement)
+	settlements.GET("/fulfiller/:fulfiller", h.getSettlementsByFulfiller)
+}
+
+func (h *handler) getSettlement(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	if id == "" {
+		web.ErrBadRequest(c, errors.Wrap(ErrParamRequired, "settlement id"))
+		return
+	}
+
+	if !utils.ValidateBytes32(id) {
+		web.ErrBadRequest(c, errors.New("invalid settlement id format"))
+		return
+	}
+
+	service, err := h.resolveFirstSettlementService()
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	settlement, err := service.GetSettlement(ctx, id)
+	if err != nil {
+		h.logger.Debug().Err(err).Str(logging.FieldIntent, id).Msg("Error getting settlement")
+
+		if errors.Is(err, errs.ErrNotFound) {
+			web.ErrNotFound(c, errors.Wrap(ErrNotFound, "settlement"))
+			return
+		}
+
+		web.ErrFrom(c, err)
+		return
+	}
+
+	h.annotateSettlementLinks(settlement)
+	h.annotateSettlementTokens(ctx, settlement)
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+func (h *handler) listSettlements(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	pag, err := resolvePagination(c)
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	settlements, totalCount, err := h.deps.Database.ListSettlementsPaginatedOptimized(ctx, pag.Page, pag.PageSize)
+	if err != nil {
+		web.ErrInternalServerError(c, err)
+		return
+	}
+
+	h.annotateSettlementLinks(settlements...)
+	h.annotateSettlementTokens(ctx, settlements...)
+
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(settlements, pag.Page, pag.PageSize, totalCount))
+}
+
+func (h *handler) getSettlementsByFulfiller(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	fulfiller := c.Param("fulfiller")
+	if fulfiller == "" {
+		web.ErrBadRequest(c, errors.Wrap(ErrParamRequired, "fulfiller address"))
+		return
+	}
+
+	if !utils.IsValidAddress(fulfiller) {
+		web.ErrBadRequest(c, errors.New("invalid fulfiller address format"))
+		return
+	}
+
+	pag, err := resolvePagination(c)
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	// fulfillers are stored in checksum format
+	fulfiller = common.HexToAddress(fulfiller).Hex()
+
+	settlements, totalCount, err := h.deps.Database.ListSettlementsByFulfillerPaginatedOptimized(
+		ctx,
+		fulfiller,
+		pag.Page,
+		pag.PageSize,
+	)
+	if err != nil {
+		web.ErrInternalServerError(c, err)
+		return
+	}
+
+	h.annotateSettlementLinks(settlements...)
+	h.annotateSettlementTokens(ctx, settlements...)
+
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(settlements, pag.Page, pag.PageSize, totalCount))
+}
+
+// just resolve any settlement service.
+func (h *handler) resolveFirstSettlementService() (SettlementService, error) {
+	for _, s := range h.deps.SettlementServices {
+		return s, nil
+	}
+
+	return nil, errors.Wrap(ErrNotFound, "settlement service")
+}
@@ -0,0 +1,175 @@
+package httpjson
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettlements(t *testing.T) {
+	const (
+		validID        = "0x1234567890123456789012345678901234567890123456789012345678901234"
+		validFulfiller = "0xAb5801a7D398351b8bE11C439e05C5B3259aeC9B"
+	)
+
+	mockSettlement := &models.Settlement{
+		ID:           validID,
+		Asset:        "0x1234567890123456789012345678901234567890",
+		Amount:       "1000",
+		Receiver:     "0x0987654321098765432109876543210987654321",
+		Fulfilled:    true,
+		Fulfiller:    validFulfiller,
+		ActualAmount: "990",
+		PaidTip:      "10",
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name           string
+			settlementID   string
+			expectedStatus int
+			setup          func(ts *testSuite)
+		}{
+			{
+				name:           "ValidSettlementRetrieval",
+				settlementID:   validID,
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.SettlementServices[1].On("GetSettlement", mock.Anything, validID).Return(mockSettlement, nil)
+				},
+			},
+			{
+				name:           "SettlementNotFound",
+				settlementID:   validID,
+				expectedStatus: http.StatusNotFound,
+				setup: func(ts *testSuite) {
+					ts.SettlementServices[1].
+						On("GetSettlement", mock.Anything, validID).
+						Return(nil, errors.Wrap(db.ErrNotFound, "failed to get settlement"))
+				},
+			},
+			{
+				name:           "InvalidID",
+				settlementID:   "0x123",
+				expectedStatus: http.StatusBadRequest,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				if tt.setup != nil {
+					tt.setup(ts)
+				}
+
+				// ACT
+				res, err := ts.Client.Get().AddPath("/api/v1/settlements/" + tt.settlementID).Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+				if tt.expectedStatus == http.StatusOK {
+					assertResponseContainsJSON(t, res, "actual_amount", "990")
+					assertResponseContainsJSON(t, res, "paid_tip", "10")
+				}
+			})
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name           string
+			path           string
+			query          map[string]string
+			expectedStatus int
+			setup          func(ts *testSuite)
+		}{
+			{
+				name:           "ListAll",
+				path:           "/api/v1/settlements",
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("ListSettlementsPaginatedOptimized", mock.Anything, 1, 20).
+						Return([]*models.Settlement{mockSettlement}, 1, nil)
+				},
+			},
+			{
+				name:           "ListAllInvalidPageSize",
+				path:           "/api/v1/settlements",
+				query:          map[string]string{"page_size": "101"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "ByFulfillerNormalizesAddress",
+				path:           "/api/v1/settlements/fulfiller/0xab5801a7d398351b8be11c439e05c5b3259aec9b",
+				query:          map[string]string{"page": "2", "page_size": "5"},
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("ListSettlementsByFulfillerPaginatedOptimized", mock.Anything, validFulfiller, 2, 5).
+						Return([]*models.Settlement{mockSettlement}, 6, nil)
+				},
+			},
+			{
+				name:           "ByFulfillerInvalidAddress",
+				path:           "/api/v1/settlements/fulfiller/0x123",
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "ByFulfillerDatabaseError",
+				path:           "/api/v1/settlements/fulfiller/" + validFulfiller,
+				expectedStatus: http.StatusInternalServerError,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("ListSettlementsByFulfillerPaginatedOptimized", numOfArgs(4)...).
+						Return(nil, 0, errors.New("connection refused"))
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				if tt.setup != nil {
+					tt.setup(ts)
+				}
+
+				req := ts.Client.Get().AddPath(tt.path)
+				for k, v := range tt.query {
+					req.AddQuery(k, v)
+				}
+
+				// ACT
+				res, err := req.Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode, res.String())
+
+				if tt.expectedStatus == http.StatusOK {
+					assertResponseContainsJSON(t, res, "data.0.fulfiller", validFulfiller)
+				}
+			})
+		}
+	})
+}
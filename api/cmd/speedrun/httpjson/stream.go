@@ -0,0 +1,174 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	web "github.com/speedrun-hq/speedrun/api/http"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/utils"
+)
+
+const (
+	streamPath = "/api/v1/stream"
+
+	// streamHeartbeat keeps idle streams open through proxies
+	streamHeartbeat = 15 * time.Second
+)
+
+func (h *handler) setupStreamRoutes(rg *gin.RouterGroup) {
+	rg.GET("/stream", h.streamIntentEvents)
+}
+
+// streamIntentEvents streams intent events as Server-Sent Events.
+// Clients resume after a disconnect with the `cursor` query param or the Last-Event-ID header.
+func (h *handler) streamIntentEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter, err := parseIntentEventFilter(c)
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	cursor, resume, err := parseStreamCursor(c)
+	if err != nil {
+		web.ErrBadRequest(c, err)
+		return
+	}
+
+	sub, err := h.deps.Stream.Subscribe(filter)
+	if err != nil {
+		web.ErrFrom(c, err)
+		return
+	}
+	defer sub.Close()
+
+	// the stream outlives the server write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug().Err(err).Msg("Failed to clear write deadline of stream")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	send := func(event *models.IntentEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Cursor, event.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+
+		return nil
+	}
+
+	if resume {
+		if err := sub.Replay(ctx, cursor, send); err != nil {
+			h.logger.Debug().Err(err).Int64("cursor", cursor).Msg("Failed to replay intent events")
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// dropped by the broker, the client resumes from its last event
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// parseIntentEventFilter parses the optional stream filters
+func parseIntentEventFilter(c *gin.Context) (models.IntentEventFilter, error) {
+	filter := models.IntentEventFilter{
+		IntentID:  c.Query("intent_id"),
+		Sender:    c.Query("sender"),
+		Recipient: c.Query("recipient"),
+		Token:     c.Query("token"),
+	}
+
+	if filter.IntentID != "" && !utils.ValidateBytes32(filter.IntentID) {
+		return filter, errors.New("invalid intent_id parameter")
+	}
+
+	for key, address := range map[string]string{
+		"sender":    filter.Sender,
+		"recipient": filter.Recipient,
+		"token":     filter.Token,
+	} {
+		if address != "" && !utils.IsValidAddress(address) {
+			return filter, errors.Errorf("invalid %s parameter", key)
+		}
+	}
+
+	var err error
+	if filter.SourceChain, err = parseChainQuery(c, "source_chain"); err != nil {
+		return filter, err
+	}
+	if filter.DestinationChain, err = parseChainQuery(c, "destination_chain"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseChainQuery parses an optional chain ID query param. Returns 0 if absent.
+func parseChainQuery(c *gin.Context, key string) (uint64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	chainID, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || chainID == 0 {
+		return 0, errors.Errorf("invalid %s parameter", key)
+	}
+
+	return chainID, nil
+}
+
+// parseStreamCursor returns the cursor to resume from, the query param takes precedence over Last-Event-ID
+func parseStreamCursor(c *gin.Context) (int64, bool, error) {
+	raw := c.Query("cursor")
+	if raw == "" {
+		raw = c.GetHeader("Last-Event-ID")
+	}
+	if raw == "" {
+		return 0, false, nil
+	}
+
+	cursor, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, false, errors.New("invalid cursor")
+	}
+
+	return cursor, true, nil
+}
@@ -0,0 +1,161 @@
+package httpjson
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	const (
+		validSender = "0x0987654321098765432109876543210987654321"
+		otherSender = "0x1234567890123456789012345678901234567890"
+	)
+
+	t.Run("InvalidRequests", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name           string
+			queryParams    map[string]string
+			expectedStatus int
+		}{
+			{
+				name:           "InvalidSender",
+				queryParams:    map[string]string{"sender": "0x123"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidChain",
+				queryParams:    map[string]string{"source_chain": "abc"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidCursor",
+				queryParams:    map[string]string{"cursor": "-1"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "StreamNotReady",
+				expectedStatus: http.StatusServiceUnavailable,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				// ACT
+				res, err := ts.Client.Get().AddPath("/api/v1/stream").SetQueryParams(tt.queryParams).Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			})
+		}
+	})
+
+	t.Run("ResumeAndLiveEvents", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		ts := newTestSuite(t)
+
+		event := func(cursor int64, sender string, from, to models.IntentStatus) *models.IntentEvent {
+			return &models.IntentEvent{
+				Cursor:     cursor,
+				Type:       models.EventType(from, to),
+				IntentID:   "0x1234567890123456789012345678901234567890123456789012345678901234",
+				Status:     to,
+				FromStatus: from,
+				Sender:     sender,
+			}
+		}
+
+		ts.Database.On("GetLatestIntentEventCursor", mock.Anything).Return(int64(2), nil)
+		ts.Database.On("ListIntentEvents", mock.Anything, int64(0), services.StreamBatchSize).
+			Return([]*models.IntentEvent{
+				event(1, validSender, "", models.IntentStatusPending),
+				event(2, otherSender, "", models.IntentStatusPending),
+			}, nil).
+			Once()
+		ts.Database.On("ListIntentEvents", mock.Anything, int64(2), services.StreamBatchSize).
+			Return([]*models.IntentEvent{
+				event(3, validSender, models.IntentStatusPending, models.IntentStatusFulfilled),
+			}, nil).
+			Once()
+		ts.Database.On("ListIntentEvents", mock.Anything, int64(3), services.StreamBatchSize).
+			Return(nil, nil).
+			Maybe()
+
+		go ts.Stream.Run(ts.Ctx)
+
+		require.Eventually(t, func() bool {
+			sub, err := ts.Stream.Subscribe(models.IntentEventFilter{})
+			if err != nil {
+				return false
+			}
+			sub.Close()
+			return true
+		}, 5*time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(ts.Ctx, 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodGet,
+			ts.Server.URL+"/api/v1/stream?sender="+validSender,
+			nil,
+		)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", "0")
+
+		// ACT
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		// ASSERT
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		scanner := bufio.NewScanner(res.Body)
+		readEvent := func() []string {
+			var lines []string
+			for scanner.Scan() {
+				if scanner.Text() == "" {
+					return lines
+				}
+				lines = append(lines, scanner.Text())
+			}
+			return lines
+		}
+
+		// replayed event of the sender, the other sender's event is filtered out
+		replayed := readEvent()
+		require.Len(t, replayed, 3)
+		assert.Equal(t, "id: 1", replayed[0])
+		assert.Equal(t, "event: created", replayed[1])
+
+		ts.Stream.Notify()
+
+		live := readEvent()
+		require.Len(t, live, 3)
+		assert.Equal(t, "id: 3", live[0])
+		assert.Equal(t, "event: fulfilled", live[1])
+		assert.True(t, strings.HasPrefix(live[2], "data: "))
+	})
+}
@@ -0,0 +1,34 @@
+package httpjson
+
+import (
+	"context"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// annotateIntentTokens sets the token and formatted amounts of the intents if the tokens are known
+func (h *handler) annotateIntentTokens(ctx context.Context, intents ...*models.Intent) {
+	if h.deps.Tokens == nil {
+		return
+	}
+
+	h.deps.Tokens.AnnotateIntents(ctx, intents...)
+}
+
+// annotateFulfillmentTokens sets the asset and formatted amount of the fulfillments if the assets are known
+func (h *handler) annotateFulfillmentTokens(ctx context.Context, fulfillments ...*models.Fulfillment) {
+	if h.deps.Tokens == nil {
+		return
+	}
+
+	h.deps.Tokens.AnnotateFulfillments(ctx, fulfillments...)
+}
+
+// annotateSettlementTokens sets the asset and formatted amounts of the settlements if the assets are known
+func (h *handler) annotateSettlementTokens(ctx context.Context, settlements ...*models.Settlement) {
+	if h.deps.Tokens == nil {
+		return
+	}
+
+	h.deps.Tokens.AnnotateSettlements(ctx, settlements...)
+}
@@ -0,0 +1,44 @@
+package httpjson
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens(t *testing.T) {
+	const (
+		validID = "0x1234567890123456789012345678901234567890123456789012345678901234"
+		usdc    = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	)
+
+	t.Run("FormattedAmounts", func(t *testing.T) {
+		t.Parallel()
+
+		// ARRANGE
+		ts := newTestSuite(t)
+		ts.SettlementServices[1].On("GetSettlement", mock.Anything, validID).Return(&models.Settlement{
+			ID:           validID,
+			ChainID:      1,
+			Asset:        usdc,
+			Amount:       "2500000",
+			ActualAmount: "2499000",
+			PaidTip:      "1000",
+		}, nil)
+
+		// ACT
+		res, err := ts.Client.Get().AddPath("/api/v1/settlements/" + validID).Do()
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode, res.String())
+		assertResponseContainsJSON(t, res, "asset_info.symbol", "USDC")
+		assertResponseContainsJSON(t, res, "asset_info.decimals", "6")
+		assertResponseContainsJSON(t, res, "amount_formatted", "2.5")
+		assertResponseContainsJSON(t, res, "actual_amount_formatted", "2.499")
+		assertResponseContainsJSON(t, res, "paid_tip_formatted", "0.001")
+	})
+}
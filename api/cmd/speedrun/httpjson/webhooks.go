@@ -0,0 +1,146 @@
+package httpjson
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	web "github.com/speedrun-hq/speedrun/api/http"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+const defaultDeliveriesLimit = 50
+
+// setupWebhookRoutes registers the webhook management routes, they're restricted to the admin token
+// as subscriptions make the server send requests to their URL and list other subscribers
+func (h *handler) setupWebhookRoutes(rg *gin.RouterGroup) {
+	webhooks := rg.Group("/webhooks", web.AdminAuth(h.adminToken))
+
+	webhooks.POST("", h.createWebhook)
+	webhooks.GET("", h.listWebhooks)
+	webhooks.GET(":id", h.getWebhook)
+	webhooks.DELETE(":id", h.deleteWebhook)
+	webhooks.GET(":id/deliveries", h.listWebhookDeliveries)
+}
+
+// createWebhook registers a webhook subscription, the response is the only time the signing secret is returned
+func (h *handler) createWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.ErrBadRequest(c, errors.Wrap(err, "invalid request"))
+		return
+	}
+
+	sub, err := h.deps.Webhooks.CreateSubscription(ctx, req)
+	if err != nil {
+		web.ErrFrom(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+func (h *handler) listWebhooks(c *gin.Context) {
+	subs, err := h.deps.Database.ListWebhookSubscriptions(c.Request.Context())
+	if err != nil {
+		web.ErrInternalServerError(c, err)
+		return
+	}
+
+	if subs == nil {
+		subs = []*models.WebhookSubscription{}
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+func (h *handler) getWebhook(c *gin.Context) {
+	id, ok := h.webhookID(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.deps.Database.GetWebhookSubscription(c.Request.Context(), id)
+	if err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *handler) deleteWebhook(c *gin.Context) {
+	id, ok := h.webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deps.Database.DeleteWebhookSubscription(c.Request.Context(), id); err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// listWebhookDeliveries returns the delivery logs of a subscription, optionally filtered by status
+func (h *handler) listWebhookDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, ok := h.webhookID(c)
+	if !ok {
+		return
+	}
+
+	status := models.WebhookDeliveryStatus(c.Query("status"))
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryDead:
+	default:
+		web.ErrBadRequest(c, errors.New("invalid status parameter"))
+		return
+	}
+
+	limit := defaultDeliveriesLimit
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxPageSize {
+			web.ErrBadRequest(c, errors.Errorf("invalid limit parameter (must be between 1 and %d)", maxPageSize))
+			return
+		}
+	}
+
+	if _, err := h.deps.Database.GetWebhookSubscription(ctx, id); err != nil {
+		h.webhookError(c, err)
+		return
+	}
+
+	deliveries, err := h.deps.Database.ListWebhookDeliveries(ctx, id, status, limit)
+	if err != nil {
+		web.ErrInternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// webhookID parses the subscription id param, responding with 400 if it's invalid
+func (h *handler) webhookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		web.ErrBadRequest(c, errors.Wrap(ErrParamRequired, "webhook id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *handler) webhookError(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrNotFound) {
+		web.ErrNotFound(c, errors.Wrap(ErrNotFound, "webhook"))
+		return
+	}
+	web.ErrFrom(c, err)
+}
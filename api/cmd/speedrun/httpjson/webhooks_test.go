@@ -0,0 +1,284 @@
+package httpjson
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestWebhooks(t *testing.T) {
+	const validSender = "0x0987654321098765432109876543210987654321"
+
+	mockSubscription := &models.WebhookSubscription{
+		ID:        7,
+		URL:       "https://partner.example.com/hooks",
+		Sender:    validSender,
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name           string
+			request        map[string]any
+			expectedStatus int
+			setup          func(ts *testSuite)
+		}{
+			{
+				name: "ValidSubscription",
+				request: map[string]any{
+					"url":          "https://partner.example.com/hooks",
+					"sender":       validSender,
+					"source_chain": 8453,
+				},
+				expectedStatus: http.StatusCreated,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("CreateWebhookSubscription", mock.Anything, mock.MatchedBy(func(sub *models.WebhookSubscription) bool {
+							return len(sub.Secret) == 64 && sub.Sender == validSender && sub.SourceChain == 8453
+						})).
+						Run(func(args mock.Arguments) {
+							args.Get(1).(*models.WebhookSubscription).ID = 7
+						}).
+						Return(nil)
+				},
+			},
+			{
+				name:           "MissingURL",
+				request:        map[string]any{"sender": validSender},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidURL",
+				request:        map[string]any{"url": "ftp://partner.example.com"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "InvalidToken",
+				request:        map[string]any{"url": "https://partner.example.com", "token": "0x123"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "PrivateAddress",
+				request:        map[string]any{"url": "http://169.254.169.254/latest/meta-data"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "DatabaseError",
+				request:        map[string]any{"url": "https://partner.example.com"},
+				expectedStatus: http.StatusInternalServerError,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("CreateWebhookSubscription", mock.Anything, mock.Anything).
+						Return(errors.New("connection refused"))
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				if tt.setup != nil {
+					tt.setup(ts)
+				}
+
+				// ACT
+				res, err := ts.Client.Post().
+					AddPath("/api/v1/webhooks").
+					SetHeader("Authorization", "Bearer "+testAdminToken).
+					JSON(tt.request).
+					Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+				if tt.expectedStatus == http.StatusCreated {
+					assertResponseContainsJSON(t, res, "id", "7")
+					// the signing secret is only returned on creation
+					assert.Len(t, gjson.GetBytes(res.Bytes(), "secret").String(), 64)
+				}
+			})
+		}
+	})
+
+	t.Run("Manage", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name           string
+			method         string
+			path           string
+			query          map[string]string
+			expectedStatus int
+			setup          func(ts *testSuite)
+		}{
+			{
+				name:           "List",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks",
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.
+						On("ListWebhookSubscriptions", mock.Anything).
+						Return([]*models.WebhookSubscription{mockSubscription}, nil)
+				},
+			},
+			{
+				name:           "Get",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks/7",
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.On("GetWebhookSubscription", mock.Anything, int64(7)).Return(mockSubscription, nil)
+				},
+			},
+			{
+				name:           "GetNotFound",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks/8",
+				expectedStatus: http.StatusNotFound,
+				setup: func(ts *testSuite) {
+					ts.Database.On("GetWebhookSubscription", mock.Anything, int64(8)).Return(nil, db.ErrNotFound)
+				},
+			},
+			{
+				name:           "GetInvalidID",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks/abc",
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "Delete",
+				method:         http.MethodDelete,
+				path:           "/api/v1/webhooks/7",
+				expectedStatus: http.StatusNoContent,
+				setup: func(ts *testSuite) {
+					ts.Database.On("DeleteWebhookSubscription", mock.Anything, int64(7)).Return(nil)
+				},
+			},
+			{
+				name:           "DeleteNotFound",
+				method:         http.MethodDelete,
+				path:           "/api/v1/webhooks/8",
+				expectedStatus: http.StatusNotFound,
+				setup: func(ts *testSuite) {
+					ts.Database.On("DeleteWebhookSubscription", mock.Anything, int64(8)).Return(db.ErrNotFound)
+				},
+			},
+			{
+				name:           "DeadDeliveries",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks/7/deliveries",
+				query:          map[string]string{"status": "dead", "limit": "10"},
+				expectedStatus: http.StatusOK,
+				setup: func(ts *testSuite) {
+					ts.Database.On("GetWebhookSubscription", mock.Anything, int64(7)).Return(mockSubscription, nil)
+					ts.Database.
+						On("ListWebhookDeliveries", mock.Anything, int64(7), models.WebhookDeliveryDead, 10).
+						Return([]*models.WebhookDelivery{}, nil)
+				},
+			},
+			{
+				name:           "DeliveriesInvalidStatus",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks/7/deliveries",
+				query:          map[string]string{"status": "unknown"},
+				expectedStatus: http.StatusBadRequest,
+			},
+			{
+				name:           "DeliveriesInvalidLimit",
+				method:         http.MethodGet,
+				path:           "/api/v1/webhooks/7/deliveries",
+				query:          map[string]string{"limit": "101"},
+				expectedStatus: http.StatusBadRequest,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				if tt.setup != nil {
+					tt.setup(ts)
+				}
+
+				// ACT
+				res, err := ts.Client.Request().
+					Method(tt.method).
+					AddPath(tt.path).
+					SetHeader("Authorization", "Bearer "+testAdminToken).
+					SetQueryParams(tt.query).
+					Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name          string
+			method        string
+			path          string
+			authorization string
+		}{
+			{name: "CreateWithoutToken", method: http.MethodPost, path: "/api/v1/webhooks"},
+			{
+				name:          "CreateWithWrongToken",
+				method:        http.MethodPost,
+				path:          "/api/v1/webhooks",
+				authorization: "Bearer wrong",
+			},
+			{name: "ListWithoutToken", method: http.MethodGet, path: "/api/v1/webhooks"},
+			{
+				name:          "DeleteWithoutBearer",
+				method:        http.MethodDelete,
+				path:          "/api/v1/webhooks/7",
+				authorization: testAdminToken,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				// ARRANGE
+				ts := newTestSuite(t)
+
+				req := ts.Client.Request().Method(tt.method).AddPath(tt.path)
+				if tt.authorization != "" {
+					req.SetHeader("Authorization", tt.authorization)
+				}
+
+				// ACT
+				res, err := req.JSON(map[string]any{"url": "https://partner.example.com"}).Do()
+
+				// ASSERT
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+				assertResponseContainsJSON(t, res, "code", "unauthorized")
+			})
+		}
+	})
+}
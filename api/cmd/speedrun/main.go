@@ -9,7 +9,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/speedrun-hq/speedrun/api/clients/evm"
@@ -27,6 +26,20 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case backfillCommand:
+			runBackfill(os.Args[2:])
+			return
+		case auditSkipsCommand:
+			runAuditSkips(os.Args[2:])
+			return
+		case backfillUSDCommand:
+			runBackfillUSD(os.Args[2:])
+			return
+		}
+	}
+
 	flags := parseFlags()
 	log := logging.New(os.Stdout, flags.LogLevel, flags.LogJSON)
 
@@ -53,32 +66,105 @@ func main() {
 
 	log.Info().Msg("Database connection established successfully")
 
-	// Initialize Ethereum clients
-	clients, err := evm.ResolveClientsFromConfig(ctx, *cfg, log)
+	// Initialize a pool of RPC providers for every chain
+	rpcMetrics := evm.NewMetrics()
+	pools, err := evm.ResolveClientsFromConfig(ctx, *cfg, rpcMetrics, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize Ethereum clients")
 	}
 
 	// Create services for all chains
-	intentServices, fulfillmentServices, settlementServices, err := createServices(clients, database, cfg, log)
+	intentServices, fulfillmentServices, settlementServices, err := createServices(
+		utils.MapMap(pools, castClientsMap),
+		database,
+		cfg,
+		log,
+	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create services")
 	}
 
+	// Know the tokens of the chain registry, the others are looked up on their chain when first seen
+	tokenRegistry, err := services.NewTokenRegistry(cfg.ChainConfigs, utils.MapMap(pools, castClientsMap), log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create token registry")
+	}
+	for _, intentService := range intentServices {
+		intentService.SetTokenRegistry(tokenRegistry)
+	}
+
+	// Store the USD value of the intents when they're created if token prices are provided
+	if cfg.PriceFile != "" {
+		prices, err := services.LoadPriceFile(cfg.PriceFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load token prices")
+		}
+		for _, intentService := range intentServices {
+			intentService.SetPriceSource(prices)
+		}
+	} else {
+		log.Warn().Msg("PRICE_FILE is not set, USD values of intents won't be stored")
+	}
+
+	// Stage events until their block is confirmed according to the chain config
+	confirmationTrackers := createConfirmationTrackers(
+		database,
+		cfg,
+		intentServices,
+		fulfillmentServices,
+		settlementServices,
+		log,
+	)
+
+	// Replay the fulfillments and settlements indexed before their intent once it is
+	orphanReconciler := services.NewOrphanReconciler(database, log)
+	for chainID := range intentServices {
+		fulfillmentServices[chainID].SetOrphanReconciler(orphanReconciler)
+		settlementServices[chainID].SetOrphanReconciler(orphanReconciler)
+	}
+
+	// Publish intent events to stream clients and webhooks through the outbox the services write them to,
+	// webhook deliveries are queued in the dispatch transaction and new intents also wake up the orphan reconciler
+	eventBroker := services.NewIntentEventBroker(database, log)
+	webhookService := services.NewWebhookService(database, cfg.WebhookMaxAttempts, log)
+	if cfg.AdminToken == "" {
+		log.Warn().Msg("ADMIN_TOKEN is not set, the webhook management routes reject every request")
+	}
+	outboxDispatcher := services.NewOutboxDispatcher(
+		database,
+		services.EventNotifiers{eventBroker, webhookService, orphanReconciler},
+		log,
+	)
+	outboxDispatcher.SetConsumers(webhookService)
+	for chainID := range intentServices {
+		intentServices[chainID].SetEventNotifier(outboxDispatcher)
+		fulfillmentServices[chainID].SetEventNotifier(outboxDispatcher)
+		settlementServices[chainID].SetEventNotifier(outboxDispatcher)
+	}
+
 	// Create metrics service
 	metricsService := services.NewMetricsService(log)
+	metricsService.SetChainNames(cfg.ChainNames())
 
-	// Register all services with the metrics service
-	for chainID, intentService := range intentServices {
-		metricsService.RegisterIntentService(chainID, intentService)
+	if err := metricsService.RegisterCollector(rpcMetrics); err != nil {
+		log.Fatal().Err(err).Msg("Failed to register RPC metrics")
 	}
 
-	for chainID, fulfillmentService := range fulfillmentServices {
-		metricsService.RegisterFulfillmentService(chainID, fulfillmentService)
+	if err := metricsService.RegisterCollector(orphanReconciler); err != nil {
+		log.Fatal().Err(err).Msg("Failed to register orphan event metrics")
 	}
 
-	for chainID, settlementService := range settlementServices {
-		metricsService.RegisterSettlementService(chainID, settlementService)
+	if err := metricsService.RegisterCollector(confirmationTrackers); err != nil {
+		log.Fatal().Err(err).Msg("Failed to register staged event metrics")
+	}
+
+	// Serve the chains with the state of their ingestion
+	chainService := services.NewChainService(database, cfg.ChainConfigs, confirmationTrackers)
+
+	// Register all services with the metrics service
+	for chainID, intentService := range intentServices {
+		metricsService.RegisterIntentService(chainID, intentService)
+		chainService.RegisterIngestion(chainID, intentService)
 	}
 
 	// Start the metrics updater
@@ -97,6 +183,22 @@ func main() {
 	// Register EventCatchupService with metrics service
 	metricsService.RegisterEventCatchupService(eventCatchupService)
 
+	eventCatchupService.StartRPCHealthChecks(pools)
+
+	eventCatchupService.StartConfirmationTrackers(ctx, confirmationTrackers)
+
+	eventCatchupService.StartIntentEventBroker(eventBroker)
+
+	eventCatchupService.StartOutboxDispatcher(outboxDispatcher)
+
+	eventCatchupService.StartOrphanReconciler(orphanReconciler)
+
+	eventCatchupService.StartWebhooks(webhookService)
+
+	if cfg.IntentExpiry > 0 {
+		eventCatchupService.StartIntentExpiry(services.NewIntentExpiryService(database, cfg.IntentExpiry, log))
+	}
+
 	err = eventCatchupService.StartListening(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to start event catchup service")
@@ -112,12 +214,20 @@ func main() {
 	server := httpjson.New(httpjson.Config{
 		Addr:           fmt.Sprintf(":%s", cfg.Port),
 		AllowedOrigins: os.Getenv("ALLOWED_ORIGINS"),
+		AdminToken:     cfg.AdminToken,
 		Logger:         log,
 		LogRequests:    true,
 		Dependencies: httpjson.Dependencies{
 			Database:            database,
 			IntentServices:      utils.MapMap(intentServices, castIntentsMap),
 			FulfillmentServices: utils.MapMap(fulfillmentServices, castFulfillmentServicesMap),
+			SettlementServices:  utils.MapMap(settlementServices, castSettlementServicesMap),
+			Leaderboard:         services.NewLeaderboardService(database, log),
+			Confirmations:       confirmationTrackers,
+			Stream:              eventBroker,
+			Webhooks:            webhookService,
+			Chains:              chainService,
+			Tokens:              tokenRegistry,
 			Metrics:             metricsService,
 		},
 	})
@@ -138,40 +248,13 @@ func main() {
 	// Shutdown all services gracefully
 	var shutdownErrors []error
 
-	// Shutdown event catchup service
+	// Shutdown event catchup service, with the ingestion engines it started
 	log.Info().Msg("Shutting down event catchup service...")
 	if err := eventCatchupService.Shutdown(shutdownTimeout); err != nil {
 		err = errors.Wrap(err, "failed to shutdown event catchup service")
 		shutdownErrors = append(shutdownErrors, err)
 	}
 
-	// Shutdown intent services
-	for chainID, intentService := range intentServices {
-		log.Info().Uint64(logging.FieldChain, chainID).Msg("Shutting down intent service")
-		if err := intentService.Shutdown(shutdownTimeout); err != nil {
-			err = errors.Wrap(err, "failed to shutdown intent service")
-			shutdownErrors = append(shutdownErrors, err)
-		}
-	}
-
-	// Shutdown fulfillment services
-	for chainID, fulfillmentService := range fulfillmentServices {
-		log.Info().Uint64(logging.FieldChain, chainID).Msg("Shutting down fulfillment service")
-		if err := fulfillmentService.Shutdown(shutdownTimeout); err != nil {
-			err = errors.Wrap(err, "failed to shutdown fulfillment service")
-			shutdownErrors = append(shutdownErrors, err)
-		}
-	}
-
-	// Shutdown settlement services
-	for chainID, settlementService := range settlementServices {
-		log.Info().Uint64(logging.FieldChain, chainID).Msg("Shutting down settlement service")
-		if err := settlementService.Shutdown(shutdownTimeout); err != nil {
-			err = errors.Wrap(err, "failed to shutdown settlement service")
-			shutdownErrors = append(shutdownErrors, err)
-		}
-	}
-
 	// Log any shutdown errors
 	if len(shutdownErrors) > 0 {
 		log.Error().Int("errors_count", len(shutdownErrors)).Msg("Encountered errors during shutdown")
@@ -186,7 +269,7 @@ func main() {
 
 // createServices creates and returns the intent and fulfillment services for each chain
 func createServices(
-	clients map[uint64]*ethclient.Client,
+	clients map[uint64]evm.Client,
 	db db.Database,
 	cfg *config.Config,
 	logger zerolog.Logger,
@@ -245,11 +328,53 @@ func createServices(
 			return nil, nil, nil, fmt.Errorf("failed to create settlement service for chain %d: %v", chainID, err)
 		}
 		settlementServices[chainID] = settlementService
+
+		// Each event type has its own density, so every service adapts its own block range
+		chain := cfg.ChainConfigs[chainID]
+		intentService.SetLogRange(services.NewLogRange(chain.LogRange, chain.LogRangeMin, chain.LogRangeMax))
+		fulfillmentService.SetLogRange(services.NewLogRange(chain.LogRange, chain.LogRangeMin, chain.LogRangeMax))
+		settlementService.SetLogRange(services.NewLogRange(chain.LogRange, chain.LogRangeMin, chain.LogRangeMax))
+		intentService.SetSkipEmptyRanges(chain.SkipEmptyRanges)
 	}
 
 	return intentServices, fulfillmentServices, settlementServices, nil
 }
 
+// createConfirmationTrackers creates a confirmation tracker for each chain and attaches it to the chain's services
+func createConfirmationTrackers(
+	db db.Database,
+	cfg *config.Config,
+	intentServices map[uint64]*services.IntentService,
+	fulfillmentServices map[uint64]*services.FulfillmentService,
+	settlementServices map[uint64]*services.SettlementService,
+	logger zerolog.Logger,
+) services.ConfirmationTrackers {
+	trackers := make(services.ConfirmationTrackers)
+
+	for chainID, intentService := range intentServices {
+		chainConfig, ok := cfg.ChainConfigs[chainID]
+		if !ok {
+			continue
+		}
+
+		tracker := services.NewConfirmationTracker(
+			db,
+			chainID,
+			chainConfig.Confirmations,
+			chainConfig.FinalityTag,
+			logger,
+		)
+
+		intentService.SetConfirmationTracker(tracker)
+		fulfillmentServices[chainID].SetConfirmationTracker(tracker)
+		settlementServices[chainID].SetConfirmationTracker(tracker)
+
+		trackers[chainID] = tracker
+	}
+
+	return trackers
+}
+
 type flagSet struct {
 	LogJSON  bool
 	LogLevel zerolog.Level
@@ -257,9 +382,8 @@ type flagSet struct {
 
 func parseFlags() flagSet {
 	var (
-		logJSON        bool
-		logLevel       string
-		logLevelParsed zerolog.Level
+		logJSON  bool
+		logLevel string
 	)
 
 	flag.BoolVar(&logJSON, "log-json", false, "Output logs in JSON format")
@@ -267,21 +391,27 @@ func parseFlags() flagSet {
 
 	flag.Parse()
 
+	return flagSet{
+		LogJSON:  logJSON,
+		LogLevel: parseLogLevel(logLevel),
+	}
+}
+
+func parseLogLevel(logLevel string) zerolog.Level {
 	switch logLevel {
 	case "debug":
-		logLevelParsed = zerolog.DebugLevel
+		return zerolog.DebugLevel
 	case "warn":
-		logLevelParsed = zerolog.WarnLevel
+		return zerolog.WarnLevel
 	case "error":
-		logLevelParsed = zerolog.ErrorLevel
+		return zerolog.ErrorLevel
 	default:
-		logLevelParsed = zerolog.InfoLevel
+		return zerolog.InfoLevel
 	}
+}
 
-	return flagSet{
-		LogJSON:  logJSON,
-		LogLevel: logLevelParsed,
-	}
+func castClientsMap(_ uint64, v *evm.Pool) evm.Client {
+	return evm.Client(v)
 }
 
 func castIntentsMap(_ uint64, v *services.IntentService) httpjson.IntentService {
@@ -291,3 +421,7 @@ func castIntentsMap(_ uint64, v *services.IntentService) httpjson.IntentService
 func castFulfillmentServicesMap(_ uint64, v *services.FulfillmentService) httpjson.FulfillmentService {
 	return httpjson.FulfillmentService(v)
 }
+
+func castSettlementServicesMap(_ uint64, v *services.SettlementService) httpjson.SettlementService {
+	return httpjson.SettlementService(v)
+}
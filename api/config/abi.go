@@ -169,3 +169,23 @@ const IntentSettledEventABI = `[
 		"type": "event"
 	}
 ]`
+
+// ERC20MetadataABI is the ABI of the symbol and decimals functions of ERC-20 tokens
+const ERC20MetadataABI = `[
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "symbol",
+		"outputs": [{"name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
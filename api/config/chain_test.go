@@ -2,35 +2,153 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
-func TestChainNameFromID(t *testing.T) {
+func TestDefaultChainRegistry(t *testing.T) {
+	registry, err := LoadChainRegistry("")
+	require.NoError(t, err)
+
 	tests := []struct {
 		chainID uint64
-		name    string
-		wantErr bool
+		prefix  string
+		mode    IngestionMode
 	}{
-		{arbitrumMainnetChainID, arbitrumName, false},
-		{baseMainnetChainID, baseName, false},
-		{ZetachainMainnetChainID, zetachainName, false},
-		{polygonMainnetChainID, polygonName, false},
-		{ethereumMainnetChainID, ethereumName, false},
-		{bscMainnetChainID, bscName, false},
-		{avalancheMainnetChainID, avalancheName, false},
+		{42161, "ARBITRUM", IngestionSubscription},
+		{8453, "BASE", IngestionSubscription},
+		{7000, "ZETACHAIN", IngestionPolling},
+		{137, "POLYGON", IngestionSubscription},
+		{1, "ETHEREUM", IngestionSubscription},
+		{56, "BSC", IngestionSubscription},
+		{43114, "AVALANCHE", IngestionSubscription},
+		{11155111, "ETHEREUM", IngestionSubscription},
+		{7001, "ZETACHAIN", IngestionPolling},
 	}
 
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("chainID=%d", tt.chainID), func(t *testing.T) {
-			got, err := chainNameFromID(tt.chainID)
-			if tt.wantErr {
-				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
-				require.Equal(t, tt.name, got)
-			}
+			chain, ok := registry.Chain(tt.chainID)
+			require.True(t, ok)
+			require.Equal(t, tt.prefix, chain.EnvPrefix)
+			require.Equal(t, tt.mode, chain.Mode)
 		})
 	}
+
+	require.Equal(t, []uint64{42161, 8453, 137, 1, 43114, 56, 7000}, registry.DefaultChains())
+
+	_, ok := registry.Chain(10)
+	require.False(t, ok)
+
+	ethereum, _ := registry.Chain(1)
+	require.Contains(t, ethereum.Tokens, TokenEntry{
+		Address:  "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Symbol:   "USDC",
+		Decimals: 6,
+		Asset:    "usdc",
+	})
+}
+
+func TestParseChainRegistry(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		registry, err := ParseChainRegistry([]byte(`{"chains": [{
+			"chain_id": 10,
+			"name": "optimism",
+			"env_prefix": "OPTIMISM",
+			"contracts": {"intent": "0x0000000000000000000000000000000000000001"},
+			"log_range": 2000,
+			"explorer_url": "https://optimistic.etherscan.io"
+		}]}`))
+		require.NoError(t, err)
+
+		chain, ok := registry.Chain(10)
+		require.True(t, ok)
+		require.Equal(t, "optimism", chain.Name)
+		require.Equal(t, uint64(2000), chain.LogRange)
+		require.Equal(t, IngestionSubscription, chain.Mode)
+	})
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no chains", `chains: []`, "has no chains"},
+		{"unknown field", `chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM, rpc: x}]`, "field rpc not found"},
+		{"missing chain ID", `chains: [{name: optimism, env_prefix: OPTIMISM}]`, "has no chain ID"},
+		{"invalid env prefix", `chains: [{chain_id: 10, name: optimism, env_prefix: optimism}]`, "invalid env prefix"},
+		{"invalid contract", `chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM, contracts: {intent: "0x1"}}]`, "invalid intent contract"},
+		{"invalid mode", `chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM, mode: push}]`, "invalid ingestion mode"},
+		{"invalid explorer", `chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM, explorer_url: optimism}]`, "invalid explorer URL"},
+		{
+			"invalid token",
+			`chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM, tokens: [{address: "0x1", symbol: USDC}]}]`,
+			"invalid token address",
+		},
+		{
+			"invalid token max amount",
+			`chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM, tokens: [
+				{address: "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", symbol: USDC, decimals: 6, max_amount: "1e6"}]}]`,
+			"invalid max amount",
+		},
+		{
+			"duplicate chain",
+			`chains: [{chain_id: 10, name: optimism, env_prefix: OPTIMISM}, {chain_id: 10, name: op, env_prefix: OP}]`,
+			"registered twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseChainRegistry([]byte(tt.yaml))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_ChainRegistryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chains.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+chains:
+  - chain_id: 59144
+    name: linea
+    env_prefix: LINEA
+    contracts:
+      intent: "0x0000000000000000000000000000000000000002"
+    confirmations: 5
+    explorer_url: https://lineascan.build
+`), 0o600))
+
+	t.Setenv("CHAIN_REGISTRY_FILE", path)
+	t.Setenv("SUPPORTED_CHAINS", "")
+	t.Setenv("LINEA_RPC_URL", "https://rpc.linea.build")
+	t.Setenv("LINEA_LOG_RANGE", "300")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{59144}, cfg.SupportedChains)
+
+	chain := cfg.ChainConfigs[59144]
+	require.Equal(t, "linea", chain.Name)
+	require.Equal(t, "https://rpc.linea.build", chain.RPCURL)
+	require.Equal(t, uint64(300), chain.LogRange)
+	require.Equal(t, 5, chain.Confirmations)
+	require.Equal(t, IngestionSubscription, chain.IngestionMode)
+
+	t.Setenv("LINEA_INGESTION_MODE", "hybrid")
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+	require.Equal(t, IngestionHybrid, cfg.ChainConfigs[59144].IngestionMode)
+
+	t.Setenv("LINEA_INGESTION_MODE", "push")
+	_, err = LoadConfig()
+	require.ErrorContains(t, err, "invalid ingestion mode")
+
+	t.Setenv("LINEA_INGESTION_MODE", "")
+	t.Setenv("SUPPORTED_CHAINS", "1")
+	_, err = LoadConfig()
+	require.ErrorContains(t, err, "not in the chain registry")
 }
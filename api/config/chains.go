@@ -1,66 +1,223 @@
 package config
 
-import "fmt"
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
 
 const (
-	ZetachainMainnetChainID = 7000
+	// initialLogRange is the initial block range of FilterLogs requests of chains without configured range
+	initialLogRange = 5000
+
+	// defaultLogRangeMin and defaultLogRangeMax bound the adaptive block range of FilterLogs requests
+	defaultLogRangeMin = 10
+	defaultLogRangeMax = 10000
 )
 
+// IngestionMode is how the events of a chain are received
+type IngestionMode string
+
 const (
-	ethereumMainnetChainID  = 1
-	bscMainnetChainID       = 56
-	polygonMainnetChainID   = 137
-	arbitrumMainnetChainID  = 42161
-	baseMainnetChainID      = 8453
-	avalancheMainnetChainID = 43114
-
-	ethereumSepoliaChainID  = 11155111
-	bscTestnetChainID       = 97
-	polygonAmoyChainID      = 80002
-	arbitrumSepoliaChainID  = 421614
-	baseSepoliaChainID      = 84532
-	zetachainTestnetChainID = 7001
-	avalancheFujiChainID    = 43113
-
-	ethereumName  = "ETHEREUM"
-	bscName       = "BSC"
-	polygonName   = "POLYGON"
-	arbitrumName  = "ARBITRUM"
-	baseName      = "BASE"
-	zetachainName = "ZETACHAIN"
-	avalancheName = "AVALANCHE"
-
-	mainnetDefaultChains = "42161,8453,137,1,43114,56,7000"
-	testnetDefaultChains = "421614,84532,80002,11155111,43113,97,7001"
+	// IngestionSubscription receives the events from a websocket subscription
+	IngestionSubscription IngestionMode = "subscription"
+
+	// IngestionPolling polls the events over HTTP, for providers without eth_subscribe
+	IngestionPolling IngestionMode = "polling"
+
+	// IngestionHybrid subscribes when the providers support eth_subscribe and falls back to polling otherwise
+	IngestionHybrid IngestionMode = "hybrid"
 )
 
-var intentAddressByChain = map[uint64]string{
-	ethereumMainnetChainID:  "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
-	bscMainnetChainID:       "0x68282fa70a32E52711d437b6c5984B714Eec3ED0",
-	polygonMainnetChainID:   "0x4017717c550E4B6E61048D412a718D6A8078d264",
-	arbitrumMainnetChainID:  "0xD6B0E2a8D115cCA2823c5F80F8416644F3970dD2",
-	baseMainnetChainID:      "0x999fce149FD078DCFaa2C681e060e00F528552f4",
-	ZetachainMainnetChainID: "0x986e2db1aF08688dD3C9311016026daD15969e09",
-	avalancheMainnetChainID: "0x9a22A7d337aF1801BEEcDBE7f4f04BbD09F9E5bb",
+// Valid returns whether the mode is a known ingestion mode
+func (m IngestionMode) Valid() bool {
+	switch m {
+	case IngestionSubscription, IngestionPolling, IngestionHybrid:
+		return true
+	default:
+		return false
+	}
+}
+
+// defaultRegistry is the built-in chain registry, used when CHAIN_REGISTRY_FILE is not set
+//
+//go:embed chains.yaml
+var defaultRegistry []byte
+
+var (
+	envPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	amountPattern    = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
+
+// ChainRegistry lists the chains the indexer can support with their defaults
+type ChainRegistry struct {
+	Chains []ChainEntry `yaml:"chains"`
+}
+
+// ChainEntry describes a chain of the registry, the per-chain environment variables override its values
+type ChainEntry struct {
+	ChainID         uint64         `yaml:"chain_id"`
+	Name            string         `yaml:"name"`
+	EnvPrefix       string         `yaml:"env_prefix"`
+	Testnet         bool           `yaml:"testnet"`
+	Contracts       ChainContracts `yaml:"contracts"`
+	Mode            IngestionMode  `yaml:"mode"`
+	LogRange        uint64         `yaml:"log_range"`
+	LogRangeMin     uint64         `yaml:"log_range_min"`
+	LogRangeMax     uint64         `yaml:"log_range_max"`
+	SkipEmptyRanges bool           `yaml:"skip_empty_ranges"`
+	Confirmations   int            `yaml:"confirmations"`
+	FinalityTag     string         `yaml:"finality_tag"`
+	ExplorerURL     string         `yaml:"explorer_url"`
+	Tokens          []TokenEntry   `yaml:"tokens"`
+}
+
+// ChainContracts are the addresses of the contracts indexed on a chain
+type ChainContracts struct {
+	Intent string `yaml:"intent"`
+}
+
+// TokenEntry describes a token of a chain, tokens that aren't listed are looked up on the chain when first seen
+type TokenEntry struct {
+	Address  string `yaml:"address"`
+	Symbol   string `yaml:"symbol"`
+	Decimals uint8  `yaml:"decimals"`
+	LogoURL  string `yaml:"logo_url"`
+	Asset    string `yaml:"asset"`
+	// MaxAmount is the largest amount of an intent in whole tokens, e.g. "1000000"
+	MaxAmount string `yaml:"max_amount"`
+}
+
+// LoadChainRegistry loads a YAML or JSON chain registry file, or the built-in registry if path is empty
+func LoadChainRegistry(path string) (*ChainRegistry, error) {
+	if path == "" {
+		return ParseChainRegistry(defaultRegistry)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain registry: %v", err)
+	}
+
+	registry, err := ParseChainRegistry(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain registry %s: %w", path, err)
+	}
+
+	return registry, nil
+}
+
+// ParseChainRegistry parses and validates a YAML or JSON chain registry
+func ParseChainRegistry(data []byte) (*ChainRegistry, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var registry ChainRegistry
+	if err := decoder.Decode(&registry); err != nil {
+		return nil, fmt.Errorf("failed to parse chain registry: %v", err)
+	}
+
+	for i := range registry.Chains {
+		if registry.Chains[i].Mode == "" {
+			registry.Chains[i].Mode = IngestionSubscription
+		}
+	}
+
+	if err := registry.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &registry, nil
+}
+
+// Validate checks the entries of the registry
+func (r *ChainRegistry) Validate() error {
+	if len(r.Chains) == 0 {
+		return fmt.Errorf("chain registry has no chains")
+	}
+
+	seen := make(map[uint64]bool, len(r.Chains))
+	for _, chain := range r.Chains {
+		if err := chain.validate(); err != nil {
+			return err
+		}
+		if seen[chain.ChainID] {
+			return fmt.Errorf("chain %d is registered twice", chain.ChainID)
+		}
+		seen[chain.ChainID] = true
+	}
+
+	return nil
+}
+
+func (c *ChainEntry) validate() error {
+	switch {
+	case c.ChainID == 0:
+		return fmt.Errorf("chain %q has no chain ID", c.Name)
+	case c.Name == "":
+		return fmt.Errorf("chain %d has no name", c.ChainID)
+	case !envPrefixPattern.MatchString(c.EnvPrefix):
+		return fmt.Errorf("chain %d has an invalid env prefix %q", c.ChainID, c.EnvPrefix)
+	case c.Contracts.Intent != "" && !common.IsHexAddress(c.Contracts.Intent):
+		return fmt.Errorf("chain %d has an invalid intent contract address %q", c.ChainID, c.Contracts.Intent)
+	case !c.Mode.Valid():
+		return fmt.Errorf("chain %d has an invalid ingestion mode %q", c.ChainID, c.Mode)
+	case c.LogRangeMin > 0 && c.LogRangeMax > 0 && c.LogRangeMin > c.LogRangeMax:
+		return fmt.Errorf("chain %d has a log range minimum above its maximum", c.ChainID)
+	case c.Confirmations < 0:
+		return fmt.Errorf("chain %d has negative confirmations", c.ChainID)
+	case c.FinalityTag != "" && c.FinalityTag != FinalityTagFinalized && c.FinalityTag != FinalityTagSafe:
+		return fmt.Errorf("chain %d has an invalid finality tag %q", c.ChainID, c.FinalityTag)
+	}
+
+	if c.ExplorerURL != "" {
+		if u, err := url.Parse(c.ExplorerURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("chain %d has an invalid explorer URL %q", c.ChainID, c.ExplorerURL)
+		}
+	}
+
+	seen := make(map[string]bool, len(c.Tokens))
+	for _, token := range c.Tokens {
+		switch {
+		case !common.IsHexAddress(token.Address):
+			return fmt.Errorf("chain %d has an invalid token address %q", c.ChainID, token.Address)
+		case token.Symbol == "":
+			return fmt.Errorf("token %s of chain %d has no symbol", token.Address, c.ChainID)
+		case token.MaxAmount != "" && !amountPattern.MatchString(token.MaxAmount):
+			return fmt.Errorf("token %s of chain %d has an invalid max amount %q", token.Address, c.ChainID, token.MaxAmount)
+		case seen[strings.ToLower(token.Address)]:
+			return fmt.Errorf("token %s of chain %d is registered twice", token.Address, c.ChainID)
+		}
+		seen[strings.ToLower(token.Address)] = true
+	}
+
+	return nil
+}
+
+// Chain returns the registry entry of a chain
+func (r *ChainRegistry) Chain(chainID uint64) (*ChainEntry, bool) {
+	for i := range r.Chains {
+		if r.Chains[i].ChainID == chainID {
+			return &r.Chains[i], true
+		}
+	}
+	return nil, false
 }
 
-// chainNameFromID returns the chain name based on the chain ID
-func chainNameFromID(chainID uint64) (string, error) {
-	switch chainID {
-	case arbitrumMainnetChainID, arbitrumSepoliaChainID:
-		return arbitrumName, nil
-	case baseMainnetChainID, baseSepoliaChainID:
-		return baseName, nil
-	case ZetachainMainnetChainID, zetachainTestnetChainID:
-		return zetachainName, nil
-	case polygonMainnetChainID, polygonAmoyChainID:
-		return polygonName, nil
-	case ethereumMainnetChainID, ethereumSepoliaChainID:
-		return ethereumName, nil
-	case bscMainnetChainID, bscTestnetChainID:
-		return bscName, nil
-	case avalancheMainnetChainID, avalancheFujiChainID:
-		return avalancheName, nil
+// DefaultChains returns the mainnets of the registry, in registry order
+func (r *ChainRegistry) DefaultChains() []uint64 {
+	var chainIDs []uint64
+	for _, chain := range r.Chains {
+		if !chain.Testnet {
+			chainIDs = append(chainIDs, chain.ChainID)
+		}
 	}
-	return "", fmt.Errorf("unsupported chain ID: %d", chainID)
+	return chainIDs
 }
@@ -3,25 +3,47 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
-	"github.com/pkg/errors"
 )
 
 // ChainConfig represents configuration for a specific chain
 type ChainConfig struct {
-	RPCURL        string
-	ContractAddr  string
-	ChainID       uint64
-	BlockInterval int64
-	MaxRetries    int
-	RetryDelay    int
-	Confirmations int
-	DefaultBlock  uint64
+	Name              string // lowercase chain name from the registry, e.g. "ethereum"
+	ExplorerURL       string
+	IngestionMode     IngestionMode // how the live events are received, see IngestionMode
+	RPCURL            string
+	RPCURLs           []string // all RPC endpoints of the chain, RPCURL is the first one
+	RPCRateLimit      float64  // requests per second sent to each RPC endpoint, 0 is unlimited
+	RPCBurst          int      // requests that can exceed the rate limit at once, defaults to the rate limit
+	RPCMaxConcurrency int      // concurrent requests sent to each RPC endpoint, 0 is unlimited
+	LogRange          uint64   // initial block range of FilterLogs requests, adapted at runtime
+	LogRangeMin       uint64   // lower bound of the adaptive block range
+	LogRangeMax       uint64   // upper bound of the adaptive block range
+	SkipEmptyRanges   bool     // probe the blocks ahead of the catchup and fast-forward through the empty ranges
+	ContractAddr      string
+	ChainID           uint64
+	BlockInterval     int64
+	MaxRetries        int
+	RetryDelay        int
+	Confirmations     int
+	FinalityTag       string
+	DefaultBlock      uint64
+	Tokens            []TokenEntry // known tokens of the chain, see TokenEntry
 }
 
+const (
+	// FinalityTagFinalized waits for the block to be finalized by the chain's consensus
+	FinalityTagFinalized = "finalized"
+
+	// FinalityTagSafe waits for the block to be considered safe from reorgs
+	FinalityTagSafe = "safe"
+)
+
 // Config holds all configuration values
 type Config struct {
 	Port                    string
@@ -31,6 +53,10 @@ type Config struct {
 	IntentFulfilledEventABI string
 	IntentInitiatedEventABI string
 	IntentSettledEventABI   string
+	IntentExpiry            time.Duration // pending intents older than this are expired, 0 disables expiry
+	WebhookMaxAttempts      int           // webhook deliveries failing this many times are moved to dead letters
+	PriceFile               string        // static YAML, JSON or CSV token prices, USD values aren't stored if empty
+	AdminToken              string        // bearer token of the admin routes, they're disabled if empty
 }
 
 // LoadConfig loads configuration from environment variables
@@ -38,15 +64,23 @@ func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	// Get supported chains
-	supportedChainsStr := strings.Split(getEnvOrDefault("SUPPORTED_CHAINS", mainnetDefaultChains), ",")
-	supportedChains := make([]uint64, len(supportedChainsStr))
-	for i, chain := range supportedChainsStr {
-		chainID, err := strconv.ParseUint(chain, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid chain ID for %s: %v", chain, err)
+	registry, err := LoadChainRegistry(getEnvOrDefault("CHAIN_REGISTRY_FILE", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chain registry: %w", err)
+	}
+
+	// Get supported chains, the mainnets of the registry by default
+	var supportedChains []uint64
+	if value := getEnvOrDefault("SUPPORTED_CHAINS", ""); value != "" {
+		for _, chain := range strings.Split(value, ",") {
+			chainID, err := strconv.ParseUint(strings.TrimSpace(chain), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chain ID for %s: %v", chain, err)
+			}
+			supportedChains = append(supportedChains, chainID)
 		}
-		supportedChains[i] = chainID
+	} else {
+		supportedChains = registry.DefaultChains()
 	}
 
 	// Create chain configs map
@@ -54,28 +88,69 @@ func LoadConfig() (*Config, error) {
 
 	// Load configurations for each chain
 	for _, chainID := range supportedChains {
-		prefix, err := chainNameFromID(chainID)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get chain name for chain ID %d", chainID)
+		chain, ok := registry.Chain(chainID)
+		if !ok {
+			return nil, fmt.Errorf("chain ID %d is not in the chain registry", chainID)
 		}
+		prefix := chain.EnvPrefix
 
-		intentAddr, ok := intentAddressByChain[chainID]
-		if !ok {
+		if chain.Contracts.Intent == "" {
 			return nil, fmt.Errorf("no intent address configured for chain ID %d", chainID)
 		}
 
+		finalityTag := getEnvOrDefault(fmt.Sprintf("%s_FINALITY_TAG", prefix), chain.FinalityTag)
+		if finalityTag != "" && finalityTag != FinalityTagFinalized && finalityTag != FinalityTagSafe {
+			return nil, fmt.Errorf("invalid finality tag for chain ID %d: %s", chainID, finalityTag)
+		}
+
+		mode := IngestionMode(getEnvOrDefault(fmt.Sprintf("%s_INGESTION_MODE", prefix), string(chain.Mode)))
+		if !mode.Valid() {
+			return nil, fmt.Errorf("invalid ingestion mode for chain ID %d: %s", chainID, mode)
+		}
+
+		rpcURLs := parseRPCURLs(
+			getEnvOrDefault(fmt.Sprintf("%s_RPC_URLS", prefix), ""),
+			getEnvOrDefault(fmt.Sprintf("%s_RPC_URL", prefix), ""),
+		)
+
+		logRange := getEnvUint64OrDefault(fmt.Sprintf("%s_LOG_RANGE", prefix), orDefault(chain.LogRange, initialLogRange))
+		logRangeMax := orDefault(chain.LogRangeMax, max(logRange, defaultLogRangeMax))
+
 		chainConfigs[chainID] = &ChainConfig{
-			RPCURL:        getEnvOrDefault(fmt.Sprintf("%s_RPC_URL", prefix), ""),
-			ContractAddr:  intentAddr,
-			ChainID:       chainID,
-			BlockInterval: int64(getEnvIntOrDefault(fmt.Sprintf("%s_BLOCK_INTERVAL", prefix), 1)),
-			MaxRetries:    getEnvIntOrDefault(fmt.Sprintf("%s_MAX_RETRIES", prefix), 3),
-			RetryDelay:    getEnvIntOrDefault(fmt.Sprintf("%s_RETRY_DELAY", prefix), 5),
-			Confirmations: getEnvIntOrDefault(fmt.Sprintf("%s_CONFIRMATIONS", prefix), 1),
-			DefaultBlock:  getEnvUint64OrDefault(fmt.Sprintf("%s_DEFAULT_BLOCK", prefix), 0),
+			Name:              chain.Name,
+			ExplorerURL:       chain.ExplorerURL,
+			IngestionMode:     mode,
+			RPCURL:            firstOrEmpty(rpcURLs),
+			RPCURLs:           rpcURLs,
+			RPCRateLimit:      getEnvFloatOrDefault(fmt.Sprintf("%s_RPC_RATE_LIMIT", prefix), 0),
+			RPCBurst:          getEnvIntOrDefault(fmt.Sprintf("%s_RPC_BURST", prefix), 0),
+			RPCMaxConcurrency: getEnvIntOrDefault(fmt.Sprintf("%s_RPC_MAX_CONCURRENCY", prefix), 0),
+			LogRange:          logRange,
+			LogRangeMin:       getEnvUint64OrDefault(fmt.Sprintf("%s_LOG_RANGE_MIN", prefix), orDefault(chain.LogRangeMin, defaultLogRangeMin)),
+			LogRangeMax:       getEnvUint64OrDefault(fmt.Sprintf("%s_LOG_RANGE_MAX", prefix), logRangeMax),
+			SkipEmptyRanges:   getEnvBoolOrDefault(fmt.Sprintf("%s_SKIP_EMPTY_RANGES", prefix), chain.SkipEmptyRanges),
+			ContractAddr:      chain.Contracts.Intent,
+			ChainID:           chainID,
+			BlockInterval:     int64(getEnvIntOrDefault(fmt.Sprintf("%s_BLOCK_INTERVAL", prefix), 1)),
+			MaxRetries:        getEnvIntOrDefault(fmt.Sprintf("%s_MAX_RETRIES", prefix), 3),
+			RetryDelay:        getEnvIntOrDefault(fmt.Sprintf("%s_RETRY_DELAY", prefix), 5),
+			Confirmations:     getEnvIntOrDefault(fmt.Sprintf("%s_CONFIRMATIONS", prefix), orDefault(chain.Confirmations, 1)),
+			FinalityTag:       finalityTag,
+			DefaultBlock:      getEnvUint64OrDefault(fmt.Sprintf("%s_DEFAULT_BLOCK", prefix), 0),
+			Tokens:            chain.Tokens,
 		}
 	}
 
+	intentExpiry, err := time.ParseDuration(getEnvOrDefault("INTENT_EXPIRY", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid intent expiry: %v", err)
+	}
+
+	webhookMaxAttempts := getEnvIntOrDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	if webhookMaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid webhook max attempts: %d", webhookMaxAttempts)
+	}
+
 	return &Config{
 		Port: getEnvOrDefault("PORT", "8080"),
 		DatabaseURL: getEnvOrDefault(
@@ -87,9 +162,54 @@ func LoadConfig() (*Config, error) {
 		IntentFulfilledEventABI: IntentFulfilledEventABI,
 		IntentInitiatedEventABI: IntentInitiatedEventABI,
 		IntentSettledEventABI:   IntentSettledEventABI,
+		IntentExpiry:            intentExpiry,
+		WebhookMaxAttempts:      webhookMaxAttempts,
+		PriceFile:               getEnvOrDefault("PRICE_FILE", ""),
+		AdminToken:              getEnvOrDefault("ADMIN_TOKEN", ""),
 	}, nil
 }
 
+// ChainNames returns the names of the supported chains
+func (c *Config) ChainNames() map[uint64]string {
+	names := make(map[uint64]string, len(c.ChainConfigs))
+	for chainID, chain := range c.ChainConfigs {
+		names[chainID] = chain.Name
+	}
+	return names
+}
+
+// parseRPCURLs parses a comma-separated list of RPC URLs, falling back to the single URL if the list is empty
+func parseRPCURLs(list, single string) []string {
+	var urls []string
+	for _, url := range strings.Split(list, ",") {
+		if url = strings.TrimSpace(url); url != "" && !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+
+	if len(urls) == 0 && single != "" {
+		urls = append(urls, single)
+	}
+
+	return urls
+}
+
+// orDefault returns value, or defaultValue if value is zero
+func orDefault[T comparable](value, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+	return value
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
 // getEnvOrDefault gets an environment variable or returns a default value
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -117,3 +237,23 @@ func getEnvUint64OrDefault(key string, defaultValue uint64) uint64 {
 	}
 	return defaultValue
 }
+
+// getEnvFloatOrDefault gets an environment variable as a float or returns a default value
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBoolOrDefault gets an environment variable as a bool or returns a default value
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRPCURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		list   string
+		single string
+		want   []string
+	}{
+		{"empty", "", "", nil},
+		{"single url", "", "wss://a", []string{"wss://a"}},
+		{"list takes precedence", "wss://a, https://b", "wss://c", []string{"wss://a", "https://b"}},
+		{"skips blanks and duplicates", "wss://a,,wss://a, https://b ", "", []string{"wss://a", "https://b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseRPCURLs(tt.list, tt.single))
+		})
+	}
+}
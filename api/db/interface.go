@@ -3,15 +3,17 @@ package db
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"time"
 
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/models"
 )
 
-// Common database errors
+// Common database errors, they match the domain errors of their kind with errors.Is
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound                = errs.ErrNotFound
+	ErrDuplicate               = errs.ErrDuplicate
+	ErrInvalidStatusTransition = errs.Validation("invalid intent status transition")
 )
 
 // Database interface defines the methods that a database implementation must provide
@@ -26,6 +28,9 @@ type Database interface {
 	// Prepared statements
 	PrepareStatements(ctx context.Context) error
 
+	// Transactions
+	WithTx(ctx context.Context, fn func(tx Database) error) error
+
 	// Intent operations
 	CreateIntent(ctx context.Context, intent *models.Intent) error
 	GetIntent(ctx context.Context, id string) (*models.Intent, error)
@@ -39,7 +44,15 @@ type Database interface {
 		recipient string,
 		page, pageSize int,
 	) ([]*models.Intent, int, error)
-	UpdateIntentStatus(ctx context.Context, id string, status models.IntentStatus) error
+	UpdateIntentStatus(ctx context.Context, transition *models.IntentStatusTransition) error
+	ListIntentStatusHistory(ctx context.Context, intentID string) ([]*models.IntentStatusTransition, error)
+	ExpireIntents(ctx context.Context, createdBefore time.Time) (int64, error)
+	ListUnvaluedIntents(ctx context.Context, sourceChain uint64, afterID string, limit int) ([]*models.Intent, error)
+	UpdateIntentUSDValue(ctx context.Context, id, amountUSD, intentFeeUSD string) error
+
+	// Intent event stream
+	ListIntentEvents(ctx context.Context, afterCursor int64, limit int) ([]*models.IntentEvent, error)
+	GetLatestIntentEventCursor(ctx context.Context) (int64, error)
 
 	// Optimized intent operations
 	ListIntentsPaginatedOptimized(ctx context.Context, page, pageSize int, status string) ([]*models.Intent, int, error)
@@ -62,6 +75,7 @@ type Database interface {
 		pageSize int,
 		status string,
 	) ([]*models.Intent, bool, error)
+	SearchIntents(ctx context.Context, query models.IntentQuery) ([]*models.Intent, bool, error)
 
 	// Fulfillment operations
 	CreateFulfillment(ctx context.Context, fulfillment *models.Fulfillment) error
@@ -69,6 +83,7 @@ type Database interface {
 	ListFulfillments(ctx context.Context) ([]*models.Fulfillment, error)
 	ListFulfillmentsPaginated(ctx context.Context, page, pageSize int) ([]*models.Fulfillment, int, error)
 	ListFulfillmentsPaginatedOptimized(ctx context.Context, page, pageSize int) ([]*models.Fulfillment, int, error)
+	ListIntentFulfillments(ctx context.Context, intentID string) ([]*models.Fulfillment, error)
 	GetTotalFulfilledAmount(ctx context.Context, intentID string) (string, error)
 
 	// Settlement operations
@@ -77,10 +92,95 @@ type Database interface {
 	ListSettlements(ctx context.Context) ([]*models.Settlement, error)
 	ListSettlementsPaginated(ctx context.Context, page, pageSize int) ([]*models.Settlement, int, error)
 	ListSettlementsPaginatedOptimized(ctx context.Context, page, pageSize int) ([]*models.Settlement, int, error)
+	ListSettlementsByFulfillerPaginatedOptimized(
+		ctx context.Context,
+		fulfiller string,
+		page, pageSize int,
+	) ([]*models.Settlement, int, error)
+
+	// Leaderboard operations
+	ListLeaderboardPaginated(
+		ctx context.Context,
+		query models.LeaderboardQuery,
+	) ([]*models.LeaderboardEntry, int, error)
 
 	// Block tracking operations
-	GetLastProcessedBlock(ctx context.Context, chainID uint64) (uint64, error)
-	UpdateLastProcessedBlock(ctx context.Context, chainID uint64, blockNumber uint64) error
+	GetEventCheckpoint(ctx context.Context, chainID uint64, eventType, contractAddress string) (uint64, error)
+	UpdateEventCheckpoint(
+		ctx context.Context,
+		chainID uint64,
+		eventType, contractAddress string,
+		blockNumber uint64,
+	) error
+	AdvanceEventCheckpoint(
+		ctx context.Context,
+		chainID uint64,
+		eventType, contractAddress string,
+		blockNumber uint64,
+	) error
+	ListEventCheckpoints(ctx context.Context) ([]*models.EventCheckpoint, error)
+
+	// Reorg tracking operations
+	RecordBlockHash(ctx context.Context, chainID, blockNumber uint64, blockHash string) error
+	ListBlockHashes(ctx context.Context, chainID, fromBlock uint64) ([]*models.ProcessedBlock, error)
+	PruneBlockHashes(ctx context.Context, chainID, belowBlock uint64) error
+	RollbackFromBlock(
+		ctx context.Context,
+		chainID, fromBlock uint64,
+		orphanedHashes []string,
+	) (*models.RollbackResult, error)
+
+	// Confirmation staging operations
+	StageUnconfirmedEvent(ctx context.Context, event *models.UnconfirmedEvent) error
+	ListUnconfirmedEvents(
+		ctx context.Context,
+		chainID, upToBlock uint64,
+		maxAttempts int,
+	) ([]*models.UnconfirmedEvent, error)
+	RecordUnconfirmedEventFailure(
+		ctx context.Context,
+		chainID uint64,
+		blockHash string,
+		logIndex uint,
+		reason string,
+	) error
+	CountStuckUnconfirmedEvents(ctx context.Context, chainID uint64, maxAttempts int) (map[string]int, error)
+	DeleteUnconfirmedEvent(ctx context.Context, chainID uint64, blockHash string, logIndex uint) error
+
+	// Orphan event operations
+	EnqueueOrphanEvent(ctx context.Context, event *models.OrphanEvent) error
+	ListReconcilableOrphanEvents(ctx context.Context, maxAttempts, limit int) ([]*models.OrphanEvent, error)
+	RecordOrphanEventFailure(ctx context.Context, id int64, reason string) error
+	DeleteOrphanEvent(ctx context.Context, id int64) error
+	GetOrphanEventStats(ctx context.Context) ([]*models.OrphanEventStats, error)
+
+	// Skipped range operations
+	RecordSkippedRange(ctx context.Context, skipped *models.SkippedRange) error
+	ListUnverifiedSkippedRanges(ctx context.Context, chainID uint64, limit int) ([]*models.SkippedRange, error)
+	MarkSkippedRangeVerified(ctx context.Context, id int64, missedBlocks []uint64) error
+
+	// Outbox operations
+	EnqueueOutboxMessage(ctx context.Context, message *models.OutboxMessage) error
+	DispatchOutboxMessages(ctx context.Context, limit int) ([]*models.IntentEvent, int, error)
+
+	// Webhook operations
+	CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	RecordWebhookAttempt(
+		ctx context.Context,
+		delivery *models.WebhookDelivery,
+		attempt *models.WebhookDeliveryAttempt,
+	) error
+	ListWebhookDeliveries(
+		ctx context.Context,
+		subscriptionID int64,
+		status models.WebhookDeliveryStatus,
+		limit int,
+	) ([]*models.WebhookDelivery, error)
 
 	// Database initialization
 	InitDB(ctx context.Context) error
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// EnqueueOrphanEvent stores a log referring to an intent that isn't indexed yet.
+// Logs already queued are left untouched.
+func (p *PostgresDB) EnqueueOrphanEvent(ctx context.Context, event *models.OrphanEvent) error {
+	query := `
+		INSERT INTO orphan_events (
+			chain_id, event_type, intent_id, block_number, block_hash, tx_hash, log_index, log, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (chain_id, block_hash, log_index) DO NOTHING
+	`
+
+	_, err := p.conn().ExecContext(ctx, query,
+		event.ChainID,
+		event.EventType,
+		event.IntentID,
+		event.BlockNumber,
+		event.BlockHash,
+		event.TxHash,
+		event.LogIndex,
+		event.Log,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue orphan event: %v", err)
+	}
+	return nil
+}
+
+// ListReconcilableOrphanEvents retrieves the orphan events whose intent is now indexed and that failed
+// less than maxAttempts times, in chain order
+func (p *PostgresDB) ListReconcilableOrphanEvents(
+	ctx context.Context,
+	maxAttempts, limit int,
+) ([]*models.OrphanEvent, error) {
+	query := `
+		SELECT o.id, o.chain_id, o.event_type, o.intent_id, o.block_number, o.block_hash, o.tx_hash,
+			o.log_index, o.log, o.attempts, o.last_error, o.created_at
+		FROM orphan_events o
+		WHERE o.attempts < $1
+			AND EXISTS (SELECT 1 FROM intents i WHERE i.id = o.intent_id)
+		ORDER BY o.chain_id ASC, o.block_number ASC, o.log_index ASC
+		LIMIT $2
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphan events: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListReconcilableOrphanEvents: failed to close: %v", err)
+		}
+	}()
+
+	var events []*models.OrphanEvent
+	for rows.Next() {
+		var (
+			e         models.OrphanEvent
+			lastError sql.NullString
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.ChainID,
+			&e.EventType,
+			&e.IntentID,
+			&e.BlockNumber,
+			&e.BlockHash,
+			&e.TxHash,
+			&e.LogIndex,
+			&e.Log,
+			&e.Attempts,
+			&lastError,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan orphan event: %v", err)
+		}
+		e.LastError = lastError.String
+		events = append(events, &e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orphan events: %v", err)
+	}
+
+	return events, nil
+}
+
+// RecordOrphanEventFailure records a failed attempt at replaying an orphan event
+func (p *PostgresDB) RecordOrphanEventFailure(ctx context.Context, id int64, reason string) error {
+	query := `
+		UPDATE orphan_events
+		SET attempts = attempts + 1,
+			last_error = $2
+		WHERE id = $1
+	`
+
+	_, err := p.conn().ExecContext(ctx, query, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record orphan event failure: %v", err)
+	}
+	return nil
+}
+
+// DeleteOrphanEvent removes an orphan event once it has been replayed
+func (p *PostgresDB) DeleteOrphanEvent(ctx context.Context, id int64) error {
+	_, err := p.conn().ExecContext(ctx, `DELETE FROM orphan_events WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete orphan event: %v", err)
+	}
+	return nil
+}
+
+// GetOrphanEventStats counts the orphan events per chain and event type, with the creation time of the oldest
+func (p *PostgresDB) GetOrphanEventStats(ctx context.Context) ([]*models.OrphanEventStats, error) {
+	query := `
+		SELECT chain_id, event_type, COUNT(*), MIN(created_at)
+		FROM orphan_events
+		GROUP BY chain_id, event_type
+		ORDER BY chain_id ASC, event_type ASC
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphan event stats: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("GetOrphanEventStats: failed to close: %v", err)
+		}
+	}()
+
+	var stats []*models.OrphanEventStats
+	for rows.Next() {
+		var s models.OrphanEventStats
+		if err := rows.Scan(&s.ChainID, &s.EventType, &s.Count, &s.OldestCreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan orphan event stats: %v", err)
+		}
+		stats = append(stats, &s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orphan event stats: %v", err)
+	}
+
+	return stats, nil
+}
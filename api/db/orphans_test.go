@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueOrphanEvent(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	event := &models.OrphanEvent{
+		ChainID:     8453,
+		EventType:   "settlement",
+		IntentID:    "0x01",
+		BlockNumber: 100,
+		BlockHash:   "0xb1",
+		TxHash:      "0xc1",
+		LogIndex:    2,
+		Log:         []byte(`{}`),
+	}
+
+	// Setup expectations, logs already queued are ignored
+	mock.ExpectExec(`INSERT INTO orphan_events .* ON CONFLICT \(chain_id, block_hash, log_index\) DO NOTHING`).
+		WithArgs(uint64(8453), "settlement", "0x01", uint64(100), "0xb1", "0xc1", uint(2), []byte(`{}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Run test
+	err := postgresDB.EnqueueOrphanEvent(context.Background(), event)
+	assert.NoError(t, err)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListReconcilableOrphanEvents(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	createdAt := time.Now().Add(-time.Hour)
+
+	// Setup expectations, only the events whose intent is indexed are listed
+	rows := sqlmock.NewRows([]string{
+		"id", "chain_id", "event_type", "intent_id", "block_number", "block_hash", "tx_hash",
+		"log_index", "log", "attempts", "last_error", "created_at",
+	}).
+		AddRow(int64(1), uint64(8453), "fulfillment", "0x01", uint64(100), "0xb1", "0xc1", 0, []byte(`{}`), 0, nil, createdAt).
+		AddRow(int64(2), uint64(8453), "settlement", "0x01", uint64(101), "0xb2", "0xc2", 1, []byte(`{}`), 2, "timeout", createdAt)
+	mock.ExpectQuery(`FROM orphan_events o\s+WHERE o.attempts < \$1\s+AND EXISTS \(SELECT 1 FROM intents i WHERE i.id = o.intent_id\)`).
+		WithArgs(10, 100).
+		WillReturnRows(rows)
+
+	// Run test
+	events, err := postgresDB.ListReconcilableOrphanEvents(context.Background(), 10, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "fulfillment", events[0].EventType)
+	assert.Empty(t, events[0].LastError)
+	assert.Equal(t, 2, events[1].Attempts)
+	assert.Equal(t, "timeout", events[1].LastError)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
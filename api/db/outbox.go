@@ -0,0 +1,206 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// outboxDispatchLock is the key of the advisory lock serializing the outbox dispatches, so that the dispatch
+// cursors are committed in order and a reader never skips a cursor committed after a larger one
+const outboxDispatchLock = 0x5350454544 // "SPEED"
+
+// intentEventColumns are the columns of the intent event of an outbox message o, whose payload is the status
+// transition t of the intent i, scanned by intentEventFields
+const intentEventColumns = `
+	o.dispatch_cursor, t.intent_id, t.to_status, COALESCE(t.from_status, ''),
+	COALESCE(i.source_chain, 0), COALESCE(i.destination_chain, 0), COALESCE(i.token, ''), COALESCE(i.amount, ''),
+	COALESCE(i.sender, ''), COALESCE(i.recipient, ''),
+	COALESCE(t.chain_id, 0), COALESCE(t.block_number, 0), COALESCE(t.tx_hash, ''), COALESCE(t.created_at, o.created_at)
+`
+
+// intentEventPayload expands the status transition of the payload of an outbox message o
+const intentEventPayload = `
+	CROSS JOIN LATERAL jsonb_to_record(o.payload) AS t(
+		intent_id TEXT, from_status TEXT, to_status TEXT, chain_id BIGINT, block_number BIGINT, tx_hash TEXT,
+		created_at TIMESTAMPTZ
+	)
+`
+
+// EnqueueOutboxMessage records a notification in the outbox and sets its ID and creation time.
+// It's meant to be called in the transaction persisting the event the message notifies.
+func (p *PostgresDB) EnqueueOutboxMessage(ctx context.Context, message *models.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox_messages (topic, payload, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, created_at
+	`
+
+	err := p.conn().QueryRowContext(ctx, query, message.Topic, []byte(message.Payload)).
+		Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox message: %v", err)
+	}
+	return nil
+}
+
+// DispatchOutboxMessages marks a batch of pending outbox messages dispatched, oldest first, and assigns them
+// their dispatch cursor. Returns the intent events of the messages and the number of dispatched messages,
+// messages of intents that no longer exist (e.g. rolled back by a reorg) are dispatched without event.
+// It's meant to be called in the transaction handing the events to their consumers, so that the messages
+// are dispatched only if the consumers succeed.
+// Dispatches are serialized so that the cursors of the events increase in commit order.
+func (p *PostgresDB) DispatchOutboxMessages(ctx context.Context, limit int) ([]*models.IntentEvent, int, error) {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("DispatchOutboxMessages: failed to rollback transaction: %v", err)
+		}
+	}()
+
+	// sequence values are assigned before commit, dispatches holding the lock until they commit
+	// hand out their cursors in commit order
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, outboxDispatchLock); err != nil {
+		return nil, 0, fmt.Errorf("failed to lock outbox dispatch: %v", err)
+	}
+
+	query := `
+		WITH pending AS (
+			SELECT id
+			FROM outbox_messages
+			WHERE dispatched_at IS NULL
+			ORDER BY id ASC
+			LIMIT $1
+			FOR UPDATE
+		), numbered AS (
+			SELECT id, nextval('outbox_dispatch_cursor_seq') AS dispatch_cursor
+			FROM pending
+		), dispatched AS (
+			UPDATE outbox_messages m
+			SET dispatched_at = NOW(),
+				dispatch_cursor = n.dispatch_cursor
+			FROM numbered n
+			WHERE m.id = n.id
+			RETURNING m.dispatch_cursor, m.payload, m.created_at
+		)
+		SELECT i.id IS NOT NULL, ` + intentEventColumns + `
+		FROM dispatched o
+		` + intentEventPayload + `
+		LEFT JOIN intents i ON i.id = t.intent_id
+		ORDER BY o.dispatch_cursor ASC
+	`
+
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to dispatch outbox messages: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("DispatchOutboxMessages: failed to close: %v", err)
+		}
+	}()
+
+	var (
+		events     []*models.IntentEvent
+		dispatched int
+	)
+	for rows.Next() {
+		var (
+			event models.IntentEvent
+			found bool
+		)
+		if err := rows.Scan(append([]any{&found}, intentEventFields(&event)...)...); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan intent event: %v", err)
+		}
+
+		dispatched++
+		if found {
+			event.Type = models.EventType(event.FromStatus, event.Status)
+			events = append(events, &event)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating dispatched outbox messages: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit outbox dispatch: %v", err)
+	}
+
+	return events, dispatched, nil
+}
+
+// ListIntentEvents lists the intent events of the outbox messages dispatched after the given cursor, oldest first
+func (p *PostgresDB) ListIntentEvents(ctx context.Context, afterCursor int64, limit int) ([]*models.IntentEvent, error) {
+	query := `
+		SELECT ` + intentEventColumns + `
+		FROM outbox_messages o
+		` + intentEventPayload + `
+		JOIN intents i ON i.id = t.intent_id
+		WHERE o.dispatch_cursor > $1
+		ORDER BY o.dispatch_cursor ASC
+		LIMIT $2
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, afterCursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intent events: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListIntentEvents: failed to close: %v", err)
+		}
+	}()
+
+	var events []*models.IntentEvent
+	for rows.Next() {
+		var event models.IntentEvent
+		if err := rows.Scan(intentEventFields(&event)...); err != nil {
+			return nil, fmt.Errorf("failed to scan intent event: %v", err)
+		}
+		event.Type = models.EventType(event.FromStatus, event.Status)
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating intent events: %v", err)
+	}
+
+	return events, nil
+}
+
+// GetLatestIntentEventCursor returns the cursor of the last dispatched intent event, 0 if there is none
+func (p *PostgresDB) GetLatestIntentEventCursor(ctx context.Context) (int64, error) {
+	var cursor int64
+	err := p.conn().QueryRowContext(ctx, `SELECT COALESCE(MAX(dispatch_cursor), 0) FROM outbox_messages`).Scan(&cursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest intent event cursor: %v", err)
+	}
+	return cursor, nil
+}
+
+// intentEventFields returns the scan destinations of intentEventColumns
+func intentEventFields(event *models.IntentEvent) []any {
+	return []any{
+		&event.Cursor,
+		&event.IntentID,
+		&event.Status,
+		&event.FromStatus,
+		&event.SourceChain,
+		&event.DestinationChain,
+		&event.Token,
+		&event.Amount,
+		&event.Sender,
+		&event.Recipient,
+		&event.ChainID,
+		&event.BlockNumber,
+		&event.TxHash,
+		&event.CreatedAt,
+	}
+}
@@ -7,10 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
-	//nolint:revive // uses PG init() internally
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/models"
 )
 
@@ -20,15 +21,17 @@ var schemaFS embed.FS
 // PostgresDB implements the Database interface using PostgreSQL
 type PostgresDB struct {
 	db *sql.DB
+	tx *sql.Tx // transaction of a WithTx callback, nil outside of transactions
 
 	// Prepared statements
-	listIntentsStmt            *sql.Stmt
-	listIntentsWithStatusStmt  *sql.Stmt
-	listIntentsBySenderStmt    *sql.Stmt
-	listIntentsByRecipientStmt *sql.Stmt
-	listFulfillmentsStmt       *sql.Stmt
-	listSettlementsStmt        *sql.Stmt
-	getIntentStmt              *sql.Stmt
+	listIntentsStmt                *sql.Stmt
+	listIntentsWithStatusStmt      *sql.Stmt
+	listIntentsBySenderStmt        *sql.Stmt
+	listIntentsByRecipientStmt     *sql.Stmt
+	listFulfillmentsStmt           *sql.Stmt
+	listSettlementsStmt            *sql.Stmt
+	listSettlementsByFulfillerStmt *sql.Stmt
+	getIntentStmt                  *sql.Stmt
 }
 
 // NewPostgresDB creates a new PostgreSQL database connection
@@ -76,17 +79,17 @@ func (p *PostgresDB) Ping() error {
 
 // Exec executes a query without returning any rows
 func (p *PostgresDB) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	return p.db.ExecContext(ctx, query, args...)
+	return p.conn().ExecContext(ctx, query, args...)
 }
 
 // QueryRow executes a query that is expected to return at most one row
 func (p *PostgresDB) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	return p.db.QueryRowContext(ctx, query, args...)
+	return p.conn().QueryRowContext(ctx, query, args...)
 }
 
 // Query executes a query that returns rows
 func (p *PostgresDB) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	return p.db.QueryContext(ctx, query, args...)
+	return p.conn().QueryContext(ctx, query, args...)
 }
 
 // InitDB initializes the database schema
@@ -98,7 +101,7 @@ func (p *PostgresDB) InitDB(ctx context.Context) error {
 	}
 
 	// Execute schema
-	_, err = p.db.ExecContext(ctx, string(schemaBytes))
+	_, err = p.conn().ExecContext(ctx, string(schemaBytes))
 	if err != nil {
 		return fmt.Errorf("failed to initialize database schema: %v", err)
 	}
@@ -109,13 +112,15 @@ func (p *PostgresDB) InitDB(ctx context.Context) error {
 // GetIntent retrieves an intent by ID
 func (p *PostgresDB) GetIntent(ctx context.Context, id string) (*models.Intent, error) {
 	query := `
-		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, intent_fee, status, created_at, updated_at
+		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, intent_fee, status, created_at, updated_at,
+			COALESCE(block_number, 0), COALESCE(block_hash, ''), COALESCE(tx_hash, ''), log_index, COALESCE(contract_address, ''),
+			COALESCE(amount_usd::TEXT, ''), COALESCE(intent_fee_usd::TEXT, '')
 		FROM intents
 		WHERE id = $1
 	`
 
 	var intent models.Intent
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := p.conn().QueryRowContext(ctx, query, id).Scan(
 		&intent.ID,
 		&intent.SourceChain,
 		&intent.DestinationChain,
@@ -127,6 +132,13 @@ func (p *PostgresDB) GetIntent(ctx context.Context, id string) (*models.Intent,
 		&intent.Status,
 		&intent.CreatedAt,
 		&intent.UpdatedAt,
+		&intent.BlockNumber,
+		&intent.BlockHash,
+		&intent.TxHash,
+		&intent.LogIndex,
+		&intent.Contract,
+		&intent.AmountUSD,
+		&intent.IntentFeeUSD,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -140,10 +152,21 @@ func (p *PostgresDB) GetIntent(ctx context.Context, id string) (*models.Intent,
 
 // CreateIntent creates a new intent
 func (p *PostgresDB) CreateIntent(ctx context.Context, intent *models.Intent) error {
+	// the initial status is recorded as the first entry of the status history
 	query := `
+	WITH inserted AS (
 		INSERT INTO intents (
-			id, source_chain, destination_chain, token, amount, recipient, sender, intent_fee, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			id, source_chain, destination_chain, token, amount, recipient, sender, intent_fee, status, created_at, updated_at,
+			block_number, block_hash, tx_hash, log_index, contract_address, amount_usd, intent_fee_usd
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+			NULLIF($12, 0), NULLIF($13, ''), NULLIF($14, ''), $15, NULLIF($16, ''),
+			NULLIF($17, '')::NUMERIC, NULLIF($18, '')::NUMERIC
+		)
+		RETURNING id, status, source_chain, block_number, block_hash, tx_hash, created_at
+	)
+	INSERT INTO intent_status_history (intent_id, to_status, chain_id, block_number, block_hash, tx_hash, created_at)
+	SELECT id, status, source_chain, block_number, block_hash, tx_hash, created_at FROM inserted
 	`
 
 	// Ensure created_at and updated_at are set
@@ -156,7 +179,7 @@ func (p *PostgresDB) CreateIntent(ctx context.Context, intent *models.Intent) er
 		intent.UpdatedAt = time.Now()
 	}
 
-	_, err := p.db.ExecContext(ctx, query,
+	_, err := p.conn().ExecContext(ctx, query,
 		intent.ID,
 		intent.SourceChain,
 		intent.DestinationChain,
@@ -168,53 +191,260 @@ func (p *PostgresDB) CreateIntent(ctx context.Context, intent *models.Intent) er
 		intent.Status,
 		intent.CreatedAt,
 		intent.UpdatedAt,
+		intent.BlockNumber,
+		intent.BlockHash,
+		intent.TxHash,
+		intent.LogIndex,
+		intent.Contract,
+		intent.AmountUSD,
+		intent.IntentFeeUSD,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create intent: %v", err)
+		return classifyError(err, "failed to create intent")
 	}
 	return nil
 }
 
-// UpdateIntentStatus updates the status of an intent
-func (p *PostgresDB) UpdateIntentStatus(ctx context.Context, id string, status models.IntentStatus) error {
+// ListUnvaluedIntents retrieves the intents of a source chain without USD value, after the given ID in ID order
+func (p *PostgresDB) ListUnvaluedIntents(
+	ctx context.Context,
+	sourceChain uint64,
+	afterID string,
+	limit int,
+) ([]*models.Intent, error) {
+	query := `
+		SELECT id, source_chain, token, amount, intent_fee, created_at
+		FROM intents
+		WHERE source_chain = $1 AND amount_usd IS NULL AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, sourceChain, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unvalued intents: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListUnvaluedIntents: failed to close: %v", err)
+		}
+	}()
+
+	var intents []*models.Intent
+	for rows.Next() {
+		var intent models.Intent
+		if err := rows.Scan(
+			&intent.ID,
+			&intent.SourceChain,
+			&intent.Token,
+			&intent.Amount,
+			&intent.IntentFee,
+			&intent.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan intent: %v", err)
+		}
+		intents = append(intents, &intent)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unvalued intents: %v", err)
+	}
+
+	return intents, nil
+}
+
+// UpdateIntentUSDValue sets the USD value of the amount and fee of an intent that has none
+func (p *PostgresDB) UpdateIntentUSDValue(ctx context.Context, id, amountUSD, intentFeeUSD string) error {
 	query := `
+		UPDATE intents
+		SET amount_usd = NULLIF($2, '')::NUMERIC,
+			intent_fee_usd = NULLIF($3, '')::NUMERIC
+		WHERE id = $1 AND amount_usd IS NULL
+	`
+
+	_, err := p.conn().ExecContext(ctx, query, id, amountUSD, intentFeeUSD)
+	if err != nil {
+		return fmt.Errorf("failed to update intent USD value: %v", err)
+	}
+	return nil
+}
+
+// UpdateIntentStatus moves an intent to the status of the transition and records it in the status history.
+// The from status of an applied transition is set to the previous status of the intent.
+// Moving an intent to its current status is a no-op, transitions the state machine
+// doesn't allow return ErrInvalidStatusTransition.
+func (p *PostgresDB) UpdateIntentStatus(ctx context.Context, transition *models.IntentStatusTransition) error {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("UpdateIntentStatus: failed to rollback transaction: %v", err)
+		}
+	}()
+
+	var current models.IntentStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM intents WHERE id = $1 FOR UPDATE`, transition.IntentID).
+		Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get intent status: %v", err)
+	}
+
+	if current == transition.ToStatus {
+		return nil
+	}
+
+	if !current.CanTransitionTo(transition.ToStatus) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, current, transition.ToStatus)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE intents
 		SET status = $1,
 			updated_at = NOW()
 		WHERE id = $2
-	`
-	result, err := p.db.ExecContext(ctx, query, string(status), id)
+	`, string(transition.ToStatus), transition.IntentID)
 	if err != nil {
 		return fmt.Errorf("failed to update intent status: %v", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	transition.FromStatus = current
+	if transition.CreatedAt.IsZero() {
+		transition.CreatedAt = time.Now()
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO intent_status_history (
+			intent_id, from_status, to_status, chain_id, block_number, block_hash, tx_hash, created_at
+		) VALUES ($1, $2, $3, NULLIF($4, 0), NULLIF($5, 0), NULLIF($6, ''), NULLIF($7, ''), $8)
+	`,
+		transition.IntentID,
+		string(transition.FromStatus),
+		string(transition.ToStatus),
+		transition.ChainID,
+		transition.BlockNumber,
+		transition.BlockHash,
+		transition.TxHash,
+		transition.CreatedAt,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %v", err)
+		return fmt.Errorf("failed to record intent status transition: %v", err)
 	}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit intent status update: %v", err)
 	}
 
 	return nil
 }
 
-// GetFulfillment retrieves a fulfillment by ID
+// ListIntentStatusHistory lists the status transitions of an intent, oldest first
+func (p *PostgresDB) ListIntentStatusHistory(
+	ctx context.Context,
+	intentID string,
+) ([]*models.IntentStatusTransition, error) {
+	query := `
+		SELECT intent_id, COALESCE(from_status, ''), to_status, COALESCE(chain_id, 0),
+			COALESCE(block_number, 0), COALESCE(block_hash, ''), COALESCE(tx_hash, ''), created_at
+		FROM intent_status_history
+		WHERE intent_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, intentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intent status history: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListIntentStatusHistory: failed to close: %v", err)
+		}
+	}()
+
+	var transitions []*models.IntentStatusTransition
+	for rows.Next() {
+		var transition models.IntentStatusTransition
+		if err := rows.Scan(
+			&transition.IntentID,
+			&transition.FromStatus,
+			&transition.ToStatus,
+			&transition.ChainID,
+			&transition.BlockNumber,
+			&transition.BlockHash,
+			&transition.TxHash,
+			&transition.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan intent status transition: %v", err)
+		}
+		transitions = append(transitions, &transition)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating intent status history: %v", err)
+	}
+
+	return transitions, nil
+}
+
+// ExpireIntents moves the intents still pending since before createdBefore to expired,
+// with the outbox messages of the status changes. Returns the number of expired intents.
+func (p *PostgresDB) ExpireIntents(ctx context.Context, createdBefore time.Time) (int64, error) {
+	query := `
+		WITH expired AS (
+			UPDATE intents
+			SET status = 'expired',
+				updated_at = NOW()
+			WHERE status = 'pending' AND created_at < $1
+			RETURNING id
+		), history AS (
+			INSERT INTO intent_status_history (intent_id, from_status, to_status, created_at)
+			SELECT id, 'pending', 'expired', NOW() FROM expired
+		)
+		INSERT INTO outbox_messages (topic, payload, created_at)
+		SELECT $2, jsonb_build_object(
+			'intent_id', id, 'from_status', 'pending', 'to_status', 'expired', 'created_at', NOW()
+		), NOW()
+		FROM expired
+	`
+
+	result, err := p.conn().ExecContext(ctx, query, createdBefore, models.OutboxTopicIntentExpired)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire intents: %v", err)
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+
+	return expired, nil
+}
+
+// GetFulfillment retrieves the first fill of an intent
 func (p *PostgresDB) GetFulfillment(ctx context.Context, id string) (*models.Fulfillment, error) {
 	query := `
-		SELECT id, asset, amount, receiver, tx_hash, created_at, updated_at
+		SELECT id, asset, amount, receiver, COALESCE(chain_id, 0), COALESCE(block_number, 0), COALESCE(block_hash, ''),
+			tx_hash, log_index, COALESCE(contract_address, ''), created_at, updated_at
 		FROM fulfillments
 		WHERE id = $1
+		ORDER BY created_at ASC, log_index ASC
+		LIMIT 1
 	`
 
 	var fulfillment models.Fulfillment
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := p.conn().QueryRowContext(ctx, query, id).Scan(
 		&fulfillment.ID,
 		&fulfillment.Asset,
 		&fulfillment.Amount,
 		&fulfillment.Receiver,
+		&fulfillment.ChainID,
+		&fulfillment.BlockNumber,
+		&fulfillment.BlockHash,
 		&fulfillment.TxHash,
+		&fulfillment.LogIndex,
+		&fulfillment.Contract,
 		&fulfillment.CreatedAt,
 		&fulfillment.UpdatedAt,
 	)
@@ -227,12 +457,38 @@ func (p *PostgresDB) GetFulfillment(ctx context.Context, id string) (*models.Ful
 	return &fulfillment, nil
 }
 
-// CreateFulfillment creates a new fulfillment
+// CreateFulfillment creates a new fulfillment. A fill indexed before log indexes were recorded
+// (log index 0 without contract) is completed with the metadata of its log when it's indexed again,
+// instead of being stored twice.
 func (p *PostgresDB) CreateFulfillment(ctx context.Context, fulfillment *models.Fulfillment) error {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("CreateFulfillment: failed to rollback transaction: %v", err)
+		}
+	}()
+
+	if fulfillment.Contract != "" && fulfillment.LogIndex != 0 {
+		completed, err := completeLegacyFulfillment(ctx, tx, fulfillment)
+		if err != nil {
+			return err
+		}
+		if completed {
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("failed to commit fulfillment: %v", err)
+			}
+			return nil
+		}
+	}
+
 	query := `
 		INSERT INTO fulfillments (
-			id, asset, amount, receiver, tx_hash, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			id, asset, amount, receiver, tx_hash, log_index, created_at, updated_at, block_number, block_hash,
+			chain_id, contract_address
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, 0), NULLIF($10, ''), NULLIF($11, 0), NULLIF($12, ''))
 	`
 
 	// Ensure timestamps are set
@@ -245,21 +501,64 @@ func (p *PostgresDB) CreateFulfillment(ctx context.Context, fulfillment *models.
 		fulfillment.UpdatedAt = time.Now()
 	}
 
-	_, err := p.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		fulfillment.ID,
 		fulfillment.Asset,
 		fulfillment.Amount,
 		fulfillment.Receiver,
 		fulfillment.TxHash,
+		fulfillment.LogIndex,
 		fulfillment.CreatedAt,
 		fulfillment.UpdatedAt,
+		fulfillment.BlockNumber,
+		fulfillment.BlockHash,
+		fulfillment.ChainID,
+		fulfillment.Contract,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create fulfillment: %v", err)
+		return classifyError(err, "failed to create fulfillment")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fulfillment: %v", err)
 	}
 	return nil
 }
 
+// completeLegacyFulfillment sets the log metadata of the legacy fill of the same intent, transaction and amount
+// as a fulfillment. Returns whether there was one.
+func completeLegacyFulfillment(ctx context.Context, tx *txn, fulfillment *models.Fulfillment) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE fulfillments
+		SET log_index = $3,
+			block_number = NULLIF($4, 0),
+			block_hash = NULLIF($5, ''),
+			chain_id = NULLIF($6, 0),
+			contract_address = $7,
+			updated_at = $8
+		WHERE id = $1 AND tx_hash = $2 AND amount = $9 AND log_index = 0 AND contract_address IS NULL
+	`,
+		fulfillment.ID,
+		fulfillment.TxHash,
+		fulfillment.LogIndex,
+		fulfillment.BlockNumber,
+		fulfillment.BlockHash,
+		fulfillment.ChainID,
+		fulfillment.Contract,
+		fulfillment.UpdatedAt,
+		fulfillment.Amount,
+	)
+	if err != nil {
+		return false, classifyError(err, "failed to complete legacy fulfillment")
+	}
+
+	completed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to complete legacy fulfillment: %v", err)
+	}
+	return completed > 0, nil
+}
+
 // ListFulfillments retrieves all fulfillments
 func (p *PostgresDB) ListFulfillments(ctx context.Context) ([]*models.Fulfillment, error) {
 	query := `
@@ -268,7 +567,7 @@ func (p *PostgresDB) ListFulfillments(ctx context.Context) ([]*models.Fulfillmen
 		ORDER BY created_at DESC
 	`
 
-	rows, err := p.db.QueryContext(ctx, query)
+	rows, err := p.conn().QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query fulfillments: %v", err)
 	}
@@ -301,44 +600,84 @@ func (p *PostgresDB) ListFulfillments(ctx context.Context) ([]*models.Fulfillmen
 	return fulfillments, nil
 }
 
-// GetTotalFulfilledAmount gets the total amount fulfilled for an intent
-func (p *PostgresDB) GetTotalFulfilledAmount(ctx context.Context, intentID string) (string, error) {
-	// First, check if there's a completed fulfillment for this intent
+// ListIntentFulfillments retrieves the fills of an intent, in chain order
+func (p *PostgresDB) ListIntentFulfillments(ctx context.Context, intentID string) ([]*models.Fulfillment, error) {
 	query := `
-		SELECT COUNT(*)
+		SELECT id, asset, amount, receiver, tx_hash, log_index, COALESCE(contract_address, ''), COALESCE(chain_id, 0),
+			COALESCE(block_number, 0), COALESCE(block_hash, ''), is_call, COALESCE(call_data, ''),
+			created_at, updated_at
 		FROM fulfillments
 		WHERE id = $1
+		ORDER BY created_at ASC, block_number ASC, log_index ASC
 	`
 
-	var count int
-	err := p.db.QueryRowContext(ctx, query, intentID).Scan(&count)
+	rows, err := p.conn().QueryContext(ctx, query, intentID)
 	if err != nil {
-		return "0", fmt.Errorf("failed to check fulfillments: %v", err)
+		return nil, fmt.Errorf("failed to query fulfillments: %v", err)
 	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListIntentFulfillments: failed to close: %v", err)
+		}
+	}()
 
-	// If there's at least one completed fulfillment, return the intent amount
-	if count > 0 {
-		intent, err := p.GetIntent(ctx, intentID)
+	var fulfillments []*models.Fulfillment
+	for rows.Next() {
+		var f models.Fulfillment
+		err := rows.Scan(
+			&f.ID,
+			&f.Asset,
+			&f.Amount,
+			&f.Receiver,
+			&f.TxHash,
+			&f.LogIndex,
+			&f.Contract,
+			&f.ChainID,
+			&f.BlockNumber,
+			&f.BlockHash,
+			&f.IsCall,
+			&f.CallData,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
 		if err != nil {
-			return "0", fmt.Errorf("failed to get intent: %v", err)
+			return nil, fmt.Errorf("failed to scan fulfillment: %v", err)
 		}
-		return intent.Amount, nil
+		fulfillments = append(fulfillments, &f)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating fulfillments: %v", err)
+	}
+	return fulfillments, nil
+}
+
+// GetTotalFulfilledAmount gets the cumulative amount of the fills of an intent
+func (p *PostgresDB) GetTotalFulfilledAmount(ctx context.Context, intentID string) (string, error) {
+	query := `
+		SELECT COALESCE(SUM(amount::NUMERIC), 0)::TEXT
+		FROM fulfillments
+		WHERE id = $1
+	`
 
-	// Otherwise, return 0
-	return "0", nil
+	var total string
+	err := p.conn().QueryRowContext(ctx, query, intentID).Scan(&total)
+	if err != nil {
+		return "0", fmt.Errorf("failed to sum fulfillments: %v", err)
+	}
+	return total, nil
 }
 
 // GetSettlement retrieves a settlement by ID
 func (p *PostgresDB) GetSettlement(ctx context.Context, id string) (*models.Settlement, error) {
 	query := `
-		SELECT id, asset, amount, receiver, fulfilled, fulfiller, actual_amount, paid_tip, tx_hash, is_call, call_data, created_at, updated_at
+		SELECT id, asset, amount, receiver, fulfilled, fulfiller, actual_amount, paid_tip, tx_hash, is_call, call_data, created_at, updated_at,
+			COALESCE(chain_id, 0), COALESCE(block_number, 0), COALESCE(block_hash, ''), log_index, COALESCE(contract_address, '')
 		FROM settlements
 		WHERE id = $1
 	`
 
 	var settlement models.Settlement
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := p.conn().QueryRowContext(ctx, query, id).Scan(
 		&settlement.ID,
 		&settlement.Asset,
 		&settlement.Amount,
@@ -352,6 +691,11 @@ func (p *PostgresDB) GetSettlement(ctx context.Context, id string) (*models.Sett
 		&settlement.CallData,
 		&settlement.CreatedAt,
 		&settlement.UpdatedAt,
+		&settlement.ChainID,
+		&settlement.BlockNumber,
+		&settlement.BlockHash,
+		&settlement.LogIndex,
+		&settlement.Contract,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
@@ -370,7 +714,7 @@ func (p *PostgresDB) ListSettlements(ctx context.Context) ([]*models.Settlement,
 		ORDER BY created_at DESC
 	`
 
-	rows, err := p.db.QueryContext(ctx, query)
+	rows, err := p.conn().QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query settlements: %v", err)
 	}
@@ -413,8 +757,12 @@ func (p *PostgresDB) ListSettlements(ctx context.Context) ([]*models.Settlement,
 func (p *PostgresDB) CreateSettlement(ctx context.Context, settlement *models.Settlement) error {
 	query := `
 		INSERT INTO settlements (
-			id, asset, amount, receiver, fulfilled, fulfiller, actual_amount, paid_tip, tx_hash, is_call, call_data, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			id, asset, amount, receiver, fulfilled, fulfiller, actual_amount, paid_tip, tx_hash, is_call, call_data, created_at, updated_at,
+			block_number, block_hash, chain_id, log_index, contract_address
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+			NULLIF($14, 0), NULLIF($15, ''), NULLIF($16, 0), $17, NULLIF($18, '')
+		)
 		ON CONFLICT (id) DO NOTHING
 	`
 
@@ -428,7 +776,7 @@ func (p *PostgresDB) CreateSettlement(ctx context.Context, settlement *models.Se
 		settlement.UpdatedAt = time.Now()
 	}
 
-	_, err := p.db.ExecContext(ctx, query,
+	_, err := p.conn().ExecContext(ctx, query,
 		settlement.ID,
 		settlement.Asset,
 		settlement.Amount,
@@ -442,9 +790,14 @@ func (p *PostgresDB) CreateSettlement(ctx context.Context, settlement *models.Se
 		settlement.CallData,
 		settlement.CreatedAt,
 		settlement.UpdatedAt,
+		settlement.BlockNumber,
+		settlement.BlockHash,
+		settlement.ChainID,
+		settlement.LogIndex,
+		settlement.Contract,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create settlement: %v", err)
+		return classifyError(err, "failed to create settlement")
 	}
 	return nil
 }
@@ -457,7 +810,7 @@ func (p *PostgresDB) ListIntents(ctx context.Context) ([]*models.Intent, error)
 		ORDER BY created_at DESC
 	`
 
-	rows, err := p.db.QueryContext(ctx, query)
+	rows, err := p.conn().QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query intents: %v", err)
 	}
@@ -494,47 +847,123 @@ func (p *PostgresDB) ListIntents(ctx context.Context) ([]*models.Intent, error)
 	return intents, nil
 }
 
-// GetLastProcessedBlock gets the last processed block number for a chain
-func (p *PostgresDB) GetLastProcessedBlock(ctx context.Context, chainID uint64) (uint64, error) {
-	query := `
-		SELECT block_number
+// GetEventCheckpoint gets the last processed block of an event type emitted by a contract on a chain.
+// The first time a cursor is read for an event type, it's seeded from the legacy per-chain checkpoint.
+func (p *PostgresDB) GetEventCheckpoint(
+	ctx context.Context,
+	chainID uint64,
+	eventType, contractAddress string,
+) (uint64, error) {
+	contractAddress = strings.ToLower(contractAddress)
+
+	seedQuery := `
+		INSERT INTO event_checkpoints (chain_id, event_type, contract_address, block_number, updated_at)
+		SELECT chain_id, $2, $3, block_number, NOW()
 		FROM last_processed_blocks
 		WHERE chain_id = $1
+			AND NOT EXISTS (SELECT 1 FROM event_checkpoints WHERE chain_id = $1 AND event_type = $2)
+		ON CONFLICT (chain_id, event_type, contract_address) DO NOTHING
+	`
+
+	if _, err := p.conn().ExecContext(ctx, seedQuery, chainID, eventType, contractAddress); err != nil {
+		return 0, fmt.Errorf("failed to seed event checkpoint: %v", err)
+	}
+
+	query := `
+		SELECT block_number
+		FROM event_checkpoints
+		WHERE chain_id = $1 AND event_type = $2 AND contract_address = $3
 	`
 
 	var blockNumber uint64
-	err := p.db.QueryRowContext(ctx, query, chainID).Scan(&blockNumber)
+	err := p.conn().QueryRowContext(ctx, query, chainID, eventType, contractAddress).Scan(&blockNumber)
 	if errors.Is(err, sql.ErrNoRows) {
-		// If no record exists, create one with a default value of 0
-		err = p.UpdateLastProcessedBlock(ctx, chainID, 0)
-		if err != nil {
-			return 0, fmt.Errorf("failed to create default last processed block: %v", err)
-		}
 		return 0, nil
 	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to get last processed block: %v", err)
+		return 0, fmt.Errorf("failed to get event checkpoint: %v", err)
 	}
 	return blockNumber, nil
 }
 
-// UpdateLastProcessedBlock updates the last processed block number for a chain
-func (p *PostgresDB) UpdateLastProcessedBlock(ctx context.Context, chainID uint64, blockNumber uint64) error {
+// UpdateEventCheckpoint updates the last processed block of an event type emitted by a contract on a chain
+func (p *PostgresDB) UpdateEventCheckpoint(
+	ctx context.Context,
+	chainID uint64,
+	eventType, contractAddress string,
+	blockNumber uint64,
+) error {
 	query := `
-		INSERT INTO last_processed_blocks (chain_id, block_number, updated_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (chain_id) DO UPDATE
-		SET block_number = $2,
+		INSERT INTO event_checkpoints (chain_id, event_type, contract_address, block_number, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (chain_id, event_type, contract_address) DO UPDATE
+		SET block_number = $4,
 			updated_at = NOW()
 	`
 
-	_, err := p.db.ExecContext(ctx, query, chainID, blockNumber)
+	_, err := p.conn().ExecContext(ctx, query, chainID, eventType, strings.ToLower(contractAddress), blockNumber)
 	if err != nil {
-		return fmt.Errorf("failed to update last processed block: %v", err)
+		return fmt.Errorf("failed to update event checkpoint: %v", err)
 	}
 	return nil
 }
 
+// AdvanceEventCheckpoint moves the checkpoint of an event type emitted by a contract on a chain
+// forward to a block, checkpoints already past the block are left untouched
+func (p *PostgresDB) AdvanceEventCheckpoint(
+	ctx context.Context,
+	chainID uint64,
+	eventType, contractAddress string,
+	blockNumber uint64,
+) error {
+	query := `
+		INSERT INTO event_checkpoints (chain_id, event_type, contract_address, block_number, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (chain_id, event_type, contract_address) DO UPDATE
+		SET block_number = $4,
+			updated_at = NOW()
+		WHERE event_checkpoints.block_number < $4
+	`
+
+	_, err := p.conn().ExecContext(ctx, query, chainID, eventType, strings.ToLower(contractAddress), blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to advance event checkpoint: %v", err)
+	}
+	return nil
+}
+
+// ListEventCheckpoints retrieves the checkpoints of every event type, contract and chain
+func (p *PostgresDB) ListEventCheckpoints(ctx context.Context) ([]*models.EventCheckpoint, error) {
+	query := `
+		SELECT chain_id, event_type, contract_address, block_number, updated_at
+		FROM event_checkpoints
+		ORDER BY chain_id, event_type, contract_address
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event checkpoints: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListEventCheckpoints: failed to close: %v", err)
+		}
+	}()
+
+	var checkpoints []*models.EventCheckpoint
+	for rows.Next() {
+		var c models.EventCheckpoint
+		if err := rows.Scan(&c.ChainID, &c.EventType, &c.ContractAddress, &c.BlockNumber, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event checkpoint: %v", err)
+		}
+		checkpoints = append(checkpoints, &c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event checkpoints: %v", err)
+	}
+	return checkpoints, nil
+}
+
 // ListIntentsBySender retrieves all intents for a specific sender address
 func (p *PostgresDB) ListIntentsBySender(ctx context.Context, sender string) ([]*models.Intent, error) {
 	query := `
@@ -544,7 +973,7 @@ func (p *PostgresDB) ListIntentsBySender(ctx context.Context, sender string) ([]
 		ORDER BY created_at DESC
 	`
 
-	rows, err := p.db.QueryContext(ctx, query, sender)
+	rows, err := p.conn().QueryContext(ctx, query, sender)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query intents for sender %s: %v", sender, err)
 	}
@@ -590,7 +1019,7 @@ func (p *PostgresDB) ListIntentsByRecipient(ctx context.Context, recipient strin
 		ORDER BY created_at DESC
 	`
 
-	rows, err := p.db.QueryContext(ctx, query, recipient)
+	rows, err := p.conn().QueryContext(ctx, query, recipient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query intents for recipient %s: %v", recipient, err)
 	}
@@ -644,7 +1073,7 @@ func (p *PostgresDB) ListIntentsPaginated(
 		countArgs = append(countArgs, status)
 	}
 	var totalCount int
-	err := p.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount)
+	err := p.conn().QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count intents: %v", err)
 	}
@@ -668,7 +1097,7 @@ func (p *PostgresDB) ListIntentsPaginated(
 		args = append(args, pageSize, offset)
 	}
 
-	rows, err := p.db.QueryContext(ctx, query, args...)
+	rows, err := p.conn().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query intents: %v", err)
 	}
@@ -753,6 +1182,10 @@ func (p *PostgresDB) ListIntentsPaginatedOptimized(
 			&intent.Status,
 			&intent.CreatedAt,
 			&intent.UpdatedAt,
+			&intent.BlockNumber,
+			&intent.TxHash,
+			&intent.AmountUSD,
+			&intent.IntentFeeUSD,
 			&totalCount,
 		)
 		if err != nil {
@@ -780,7 +1213,7 @@ func (p *PostgresDB) ListIntentsBySenderPaginated(
 	// Get total count first
 	countQuery := `SELECT COUNT(*) FROM intents WHERE sender = $1`
 	var totalCount int
-	err := p.db.QueryRowContext(ctx, countQuery, sender).Scan(&totalCount)
+	err := p.conn().QueryRowContext(ctx, countQuery, sender).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count intents: %v", err)
 	}
@@ -794,7 +1227,7 @@ func (p *PostgresDB) ListIntentsBySenderPaginated(
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := p.db.QueryContext(ctx, query, sender, pageSize, offset)
+	rows, err := p.conn().QueryContext(ctx, query, sender, pageSize, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query intents: %v", err)
 	}
@@ -869,6 +1302,10 @@ func (p *PostgresDB) ListIntentsBySenderPaginatedOptimized(
 			&intent.Status,
 			&intent.CreatedAt,
 			&intent.UpdatedAt,
+			&intent.BlockNumber,
+			&intent.TxHash,
+			&intent.AmountUSD,
+			&intent.IntentFeeUSD,
 			&totalCount,
 		)
 		if err != nil {
@@ -896,7 +1333,7 @@ func (p *PostgresDB) ListIntentsByRecipientPaginated(
 	// Get total count first
 	countQuery := `SELECT COUNT(*) FROM intents WHERE recipient = $1`
 	var totalCount int
-	err := p.db.QueryRowContext(ctx, countQuery, recipient).Scan(&totalCount)
+	err := p.conn().QueryRowContext(ctx, countQuery, recipient).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count intents: %v", err)
 	}
@@ -910,7 +1347,7 @@ func (p *PostgresDB) ListIntentsByRecipientPaginated(
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := p.db.QueryContext(ctx, query, recipient, pageSize, offset)
+	rows, err := p.conn().QueryContext(ctx, query, recipient, pageSize, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query intents: %v", err)
 	}
@@ -985,6 +1422,10 @@ func (p *PostgresDB) ListIntentsByRecipientPaginatedOptimized(
 			&intent.Status,
 			&intent.CreatedAt,
 			&intent.UpdatedAt,
+			&intent.BlockNumber,
+			&intent.TxHash,
+			&intent.AmountUSD,
+			&intent.IntentFeeUSD,
 			&totalCount,
 		)
 		if err != nil {
@@ -1011,7 +1452,7 @@ func (p *PostgresDB) ListFulfillmentsPaginated(
 	// Get total count first
 	countQuery := `SELECT COUNT(*) FROM fulfillments`
 	var totalCount int
-	err := p.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+	err := p.conn().QueryRowContext(ctx, countQuery).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count fulfillments: %v", err)
 	}
@@ -1024,7 +1465,7 @@ func (p *PostgresDB) ListFulfillmentsPaginated(
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := p.db.QueryContext(ctx, query, pageSize, offset)
+	rows, err := p.conn().QueryContext(ctx, query, pageSize, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query fulfillments: %v", err)
 	}
@@ -1069,7 +1510,7 @@ func (p *PostgresDB) ListSettlementsPaginated(
 	// Get total count first
 	countQuery := `SELECT COUNT(*) FROM settlements`
 	var totalCount int
-	err := p.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+	err := p.conn().QueryRowContext(ctx, countQuery).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count settlements: %v", err)
 	}
@@ -1082,7 +1523,7 @@ func (p *PostgresDB) ListSettlementsPaginated(
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := p.db.QueryContext(ctx, query, pageSize, offset)
+	rows, err := p.conn().QueryContext(ctx, query, pageSize, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query settlements: %v", err)
 	}
@@ -1151,6 +1592,7 @@ func (p *PostgresDB) ListFulfillmentsPaginatedOptimized(
 			&f.Asset,
 			&f.Amount,
 			&f.Receiver,
+			&f.ChainID,
 			&f.TxHash,
 			&f.CreatedAt,
 			&f.UpdatedAt,
@@ -1202,6 +1644,62 @@ func (p *PostgresDB) ListSettlementsPaginatedOptimized(
 			&s.Fulfiller,
 			&s.ActualAmount,
 			&s.PaidTip,
+			&s.ChainID,
+			&s.TxHash,
+			&s.IsCall,
+			&s.CallData,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+			&totalCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan settlement: %v", err)
+		}
+		settlements = append(settlements, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating settlements: %v", err)
+	}
+
+	return settlements, totalCount, nil
+}
+
+// ListSettlementsByFulfillerPaginatedOptimized retrieves settlements for a fulfiller with pagination using a single query
+func (p *PostgresDB) ListSettlementsByFulfillerPaginatedOptimized(
+	ctx context.Context,
+	fulfiller string,
+	page, pageSize int,
+) ([]*models.Settlement, int, error) {
+	// Calculate offset
+	offset := (page - 1) * pageSize
+
+	// Use prepared statement
+	rows, err := p.listSettlementsByFulfillerStmt.QueryContext(ctx, fulfiller, pageSize, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query settlements: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListSettlementsByFulfillerPaginatedOptimized: failed to close: %v", err)
+		}
+	}()
+
+	var settlements []*models.Settlement
+	var totalCount int
+
+	for rows.Next() {
+		var s models.Settlement
+		err := rows.Scan(
+			&s.ID,
+			&s.Asset,
+			&s.Amount,
+			&s.Receiver,
+			&s.Fulfilled,
+			&s.Fulfiller,
+			&s.ActualAmount,
+			&s.PaidTip,
+			&s.ChainID,
 			&s.TxHash,
 			&s.IsCall,
 			&s.CallData,
@@ -1230,14 +1728,16 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 	p.listIntentsStmt, err = p.db.PrepareContext(ctx, `
 		WITH data AS (
 			SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-				   intent_fee, status, created_at, updated_at,
+				   intent_fee, status, created_at, updated_at, COALESCE(block_number, 0) AS block_number,
+				   COALESCE(tx_hash, '') AS tx_hash,
+				   COALESCE(amount_usd::TEXT, '') AS amount_usd, COALESCE(intent_fee_usd::TEXT, '') AS intent_fee_usd,
 				   COUNT(*) OVER() AS total_count
 			FROM intents
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
 		)
 		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-			   intent_fee, status, created_at, updated_at, 
+			   intent_fee, status, created_at, updated_at, block_number, tx_hash, amount_usd, intent_fee_usd,
 			   total_count 
 		FROM data
 	`)
@@ -1249,7 +1749,9 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 	p.listIntentsWithStatusStmt, err = p.db.PrepareContext(ctx, `
 		WITH data AS (
 			SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-				   intent_fee, status, created_at, updated_at,
+				   intent_fee, status, created_at, updated_at, COALESCE(block_number, 0) AS block_number,
+				   COALESCE(tx_hash, '') AS tx_hash,
+				   COALESCE(amount_usd::TEXT, '') AS amount_usd, COALESCE(intent_fee_usd::TEXT, '') AS intent_fee_usd,
 				   COUNT(*) OVER() AS total_count
 			FROM intents
 			WHERE status = $1
@@ -1257,7 +1759,7 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 			LIMIT $2 OFFSET $3
 		)
 		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-			   intent_fee, status, created_at, updated_at, 
+			   intent_fee, status, created_at, updated_at, block_number, tx_hash, amount_usd, intent_fee_usd,
 			   total_count
 		FROM data
 	`)
@@ -1269,7 +1771,9 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 	p.listIntentsBySenderStmt, err = p.db.PrepareContext(ctx, `
 		WITH data AS (
 			SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-				   intent_fee, status, created_at, updated_at,
+				   intent_fee, status, created_at, updated_at, COALESCE(block_number, 0) AS block_number,
+				   COALESCE(tx_hash, '') AS tx_hash,
+				   COALESCE(amount_usd::TEXT, '') AS amount_usd, COALESCE(intent_fee_usd::TEXT, '') AS intent_fee_usd,
 				   COUNT(*) OVER() AS total_count
 			FROM intents
 			WHERE sender = $1
@@ -1277,7 +1781,7 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 			LIMIT $2 OFFSET $3
 		)
 		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-			   intent_fee, status, created_at, updated_at, 
+			   intent_fee, status, created_at, updated_at, block_number, tx_hash, amount_usd, intent_fee_usd,
 			   total_count
 		FROM data
 	`)
@@ -1289,7 +1793,9 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 	p.listIntentsByRecipientStmt, err = p.db.PrepareContext(ctx, `
 		WITH data AS (
 			SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-				   intent_fee, status, created_at, updated_at,
+				   intent_fee, status, created_at, updated_at, COALESCE(block_number, 0) AS block_number,
+				   COALESCE(tx_hash, '') AS tx_hash,
+				   COALESCE(amount_usd::TEXT, '') AS amount_usd, COALESCE(intent_fee_usd::TEXT, '') AS intent_fee_usd,
 				   COUNT(*) OVER() AS total_count
 			FROM intents
 			WHERE recipient = $1
@@ -1297,7 +1803,7 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 			LIMIT $2 OFFSET $3
 		)
 		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-			   intent_fee, status, created_at, updated_at, 
+			   intent_fee, status, created_at, updated_at, block_number, tx_hash, amount_usd, intent_fee_usd,
 			   total_count
 		FROM data
 	`)
@@ -1308,13 +1814,13 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 	// Prepare statement for listing fulfillments
 	p.listFulfillmentsStmt, err = p.db.PrepareContext(ctx, `
 		WITH data AS (
-			SELECT id, asset, amount, receiver, tx_hash, created_at, updated_at,
+			SELECT id, asset, amount, receiver, COALESCE(chain_id, 0) AS chain_id, tx_hash, created_at, updated_at,
 				   COUNT(*) OVER() AS total_count
 			FROM fulfillments
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
 		)
-		SELECT id, asset, amount, receiver, tx_hash, created_at, updated_at,
+		SELECT id, asset, amount, receiver, chain_id, tx_hash, created_at, updated_at,
 			   total_count
 		FROM data
 	`)
@@ -1326,14 +1832,14 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 	p.listSettlementsStmt, err = p.db.PrepareContext(ctx, `
 		WITH data AS (
 			SELECT id, asset, amount, receiver, fulfilled, fulfiller, actual_amount, 
-				   paid_tip, tx_hash, is_call, call_data, created_at, updated_at,
+				   paid_tip, COALESCE(chain_id, 0) AS chain_id, tx_hash, is_call, call_data, created_at, updated_at,
 				   COUNT(*) OVER() AS total_count
 			FROM settlements
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
 		)
 		SELECT id, asset, amount, receiver, fulfilled, fulfiller, actual_amount,
-			   paid_tip, tx_hash, is_call, call_data, created_at, updated_at,
+			   paid_tip, chain_id, tx_hash, is_call, call_data, created_at, updated_at,
 			   total_count
 		FROM data
 	`)
@@ -1341,6 +1847,26 @@ func (p *PostgresDB) PrepareStatements(ctx context.Context) error {
 		return fmt.Errorf("failed to prepare listSettlementsStmt: %v", err)
 	}
 
+	// Prepare statement for listing settlements by fulfiller
+	p.listSettlementsByFulfillerStmt, err = p.db.PrepareContext(ctx, `
+		WITH data AS (
+			SELECT id, asset, amount, receiver, fulfilled, fulfiller, actual_amount, 
+				   paid_tip, COALESCE(chain_id, 0) AS chain_id, tx_hash, is_call, call_data, created_at, updated_at,
+				   COUNT(*) OVER() AS total_count
+			FROM settlements
+			WHERE fulfiller = $1
+			ORDER BY created_at DESC
+			LIMIT $2 OFFSET $3
+		)
+		SELECT id, asset, amount, receiver, fulfilled, fulfiller, actual_amount,
+			   paid_tip, chain_id, tx_hash, is_call, call_data, created_at, updated_at,
+			   total_count
+		FROM data
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare listSettlementsByFulfillerStmt: %v", err)
+	}
+
 	// Prepare statement for getting a single intent
 	p.getIntentStmt, err = p.db.PrepareContext(ctx, `
 		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
@@ -1363,50 +1889,109 @@ func (p *PostgresDB) ListIntentsKeysetPaginated(
 	pageSize int,
 	status string,
 ) ([]*models.Intent, bool, error) {
-	whereClause := ""
-	var args []interface{}
-	argIndex := 1
-
-	// Add status filter if provided
+	query := models.IntentQuery{PageSize: pageSize}
 	if status != "" {
-		whereClause = "WHERE status = $1"
-		args = append(args, status)
-		argIndex++
+		query.Statuses = []models.IntentStatus{models.IntentStatus(status)}
 	}
-
-	// Add keyset condition if not first page
 	if !lastTimestamp.IsZero() {
-		if whereClause == "" {
-			whereClause = "WHERE "
+		query.After = &models.IntentCursor{SortBy: models.IntentSortCreatedAt, CreatedAt: lastTimestamp, ID: lastID}
+	}
+
+	return p.SearchIntents(ctx, query)
+}
+
+// SearchIntents retrieves the intents matching the filters of a query with keyset pagination,
+// and whether there are more intents after the page
+func (p *PostgresDB) SearchIntents(ctx context.Context, query models.IntentQuery) ([]*models.Intent, bool, error) {
+	b := &queryBuilder{}
+
+	if query.SourceChain != 0 {
+		b.where("source_chain = ?", query.SourceChain)
+	}
+	if query.DestinationChain != 0 {
+		b.where("destination_chain = ?", query.DestinationChain)
+	}
+	if query.Token != "" {
+		b.where("token = ?", query.Token)
+	}
+	if query.Sender != "" {
+		b.where("sender = ?", query.Sender)
+	}
+	if query.Recipient != "" {
+		b.where("recipient = ?", query.Recipient)
+	}
+	if len(query.Statuses) > 0 {
+		statuses := make([]string, 0, len(query.Statuses))
+		for _, status := range query.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		b.where("status = ANY(?)", pq.Array(statuses))
+	}
+	if query.IsCall != nil {
+		b.where("is_call = ?", *query.IsCall)
+	}
+	if query.MinAmount != nil {
+		b.where("amount::NUMERIC >= ?::NUMERIC", query.MinAmount.String())
+	}
+	if query.MaxAmount != nil {
+		b.where("amount::NUMERIC <= ?::NUMERIC", query.MaxAmount.String())
+	}
+	if !query.Since.IsZero() {
+		b.where("created_at >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		b.where("created_at < ?", query.Until)
+	}
+
+	// the id breaks the ties of the sort so that the keyset of each intent is unique
+	sortBy, sortColumn := models.IntentSortCreatedAt, "created_at"
+	switch query.SortBy {
+	case models.IntentSortAmount:
+		sortBy, sortColumn = models.IntentSortAmount, "amount::NUMERIC"
+	case models.IntentSortCreatedAt, "":
+	default:
+		return nil, false, errs.Validation("invalid intent sort: %s", query.SortBy)
+	}
+
+	direction, comparison := "DESC", "<"
+	switch query.Order {
+	case models.SortOrderAsc:
+		direction, comparison = "ASC", ">"
+	case models.SortOrderDesc, "":
+	default:
+		return nil, false, errs.Validation("invalid sort order: %s", query.Order)
+	}
+
+	if after := query.After; after != nil {
+		if after.SortBy != sortBy {
+			return nil, false, errs.Validation("cursor of sort %s used with sort %s", after.SortBy, sortBy)
+		}
+		if sortBy == models.IntentSortAmount {
+			b.where(fmt.Sprintf("(%s, id) %s (?::NUMERIC, ?)", sortColumn, comparison), after.Amount, after.ID)
 		} else {
-			whereClause += " AND "
+			b.where(fmt.Sprintf("(%s, id) %s (?, ?)", sortColumn, comparison), after.CreatedAt, after.ID)
 		}
-		whereClause += fmt.Sprintf("(created_at, id) < ($%d, $%d)", argIndex, argIndex+1)
-		args = append(args, lastTimestamp, lastID)
-		argIndex += 2
 	}
 
-	// Build the query
-	query := fmt.Sprintf(`
-		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, 
-			   intent_fee, status, created_at, updated_at
+	// Request one extra record to determine if there are more pages
+	sqlQuery := fmt.Sprintf(`
+		SELECT id, source_chain, destination_chain, token, amount, recipient, sender, intent_fee, status,
+			created_at, updated_at, is_call, COALESCE(call_data, ''),
+			COALESCE(block_number, 0), COALESCE(tx_hash, ''),
+			COALESCE(amount_usd::TEXT, ''), COALESCE(intent_fee_usd::TEXT, '')
 		FROM intents
 		%s
-		ORDER BY created_at DESC, id DESC
-		LIMIT $%d
-	`, whereClause, argIndex)
+		ORDER BY %s %s, id %s
+		LIMIT %s
+	`, b.whereClause(), sortColumn, direction, direction, b.arg(query.PageSize+1))
 
-	// Request one extra record to determine if there are more pages
-	args = append(args, pageSize+1)
-
-	// Execute the query
-	rows, err := p.db.QueryContext(ctx, query, args...)
+	rows, err := p.conn().QueryContext(ctx, sqlQuery, b.args...)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to query intents: %v", err)
+		return nil, false, classifyError(err, "failed to query intents")
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			log.Printf("ListIntentsKeysetPaginated: failed to close: %v", err)
+			log.Printf("SearchIntents: failed to close: %v", err)
 		}
 	}()
 
@@ -1425,6 +2010,12 @@ func (p *PostgresDB) ListIntentsKeysetPaginated(
 			&intent.Status,
 			&intent.CreatedAt,
 			&intent.UpdatedAt,
+			&intent.IsCall,
+			&intent.CallData,
+			&intent.BlockNumber,
+			&intent.TxHash,
+			&intent.AmountUSD,
+			&intent.IntentFeeUSD,
 		)
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to scan intent: %v", err)
@@ -1438,10 +2029,564 @@ func (p *PostgresDB) ListIntentsKeysetPaginated(
 
 	// Determine if there are more pages by checking if we got more records than requested
 	hasMore := false
-	if len(intents) > pageSize {
-		intents = intents[:pageSize] // Remove the extra record
+	if len(intents) > query.PageSize {
+		intents = intents[:query.PageSize] // Remove the extra record
 		hasMore = true
 	}
 
 	return intents, hasMore, nil
 }
+
+// leaderboardWindowSource mirrors leaderboard_view but lets the caller restrict
+// the aggregated intents to a time window, which the view itself can't do
+const leaderboardWindowSource = `
+	SELECT
+		sender AS address,
+		source_chain AS chain_id,
+		COUNT(*) AS total_transfers,
+		SUM(amount_usd) AS total_volume_usd,
+		AVG(EXTRACT(EPOCH FROM (updated_at - created_at))) AS avg_completion_time_seconds,
+		MIN(EXTRACT(EPOCH FROM (updated_at - created_at))) AS fastest_completion_time_seconds,
+		MAX(updated_at) AS last_transfer_time
+	FROM intents
+	WHERE %s
+	GROUP BY sender, source_chain
+`
+
+// ListLeaderboardPaginated retrieves ranked runners for a chain with pagination
+func (p *PostgresDB) ListLeaderboardPaginated(
+	ctx context.Context,
+	query models.LeaderboardQuery,
+) ([]*models.LeaderboardEntry, int, error) {
+	var orderBy string
+	switch query.SortBy {
+	case models.LeaderboardSortTransfers:
+		orderBy = "total_transfers DESC, total_volume_usd DESC NULLS LAST"
+	case models.LeaderboardSortFastest:
+		orderBy = "fastest_completion_time_seconds ASC, total_volume_usd DESC NULLS LAST"
+	case models.LeaderboardSortVolume, "":
+		orderBy = "total_volume_usd DESC NULLS LAST, total_transfers DESC"
+	default:
+		return nil, 0, fmt.Errorf("invalid leaderboard sort: %s", query.SortBy)
+	}
+
+	args := []interface{}{query.ChainID}
+
+	// Use the view unless a time window is requested
+	source := "SELECT * FROM leaderboard_view WHERE chain_id = $1"
+	if query.HasTimeWindow() {
+		conditions := "status IN ('settled', 'settled_without_fulfillment') AND source_chain = $1"
+		if !query.Since.IsZero() {
+			args = append(args, query.Since)
+			conditions += fmt.Sprintf(" AND created_at >= $%d", len(args))
+		}
+		if !query.Until.IsZero() {
+			args = append(args, query.Until)
+			conditions += fmt.Sprintf(" AND created_at < $%d", len(args))
+		}
+		source = fmt.Sprintf(leaderboardWindowSource, conditions)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	args = append(args, query.PageSize, offset)
+
+	sqlQuery := fmt.Sprintf(`
+		WITH leaderboard AS (%s)
+		SELECT address, chain_id, total_transfers,
+			   COALESCE(total_volume_usd, 0)::TEXT,
+			   COALESCE(avg_completion_time_seconds, 0),
+			   COALESCE(fastest_completion_time_seconds, 0),
+			   last_transfer_time,
+			   COUNT(*) OVER() AS total_count
+		FROM leaderboard
+		ORDER BY %s, address ASC
+		LIMIT $%d OFFSET $%d
+	`, source, orderBy, len(args)-1, len(args))
+
+	rows, err := p.conn().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query leaderboard: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListLeaderboardPaginated: failed to close: %v", err)
+		}
+	}()
+
+	var entries []*models.LeaderboardEntry
+	var totalCount int
+
+	for rows.Next() {
+		var e models.LeaderboardEntry
+		err := rows.Scan(
+			&e.Address,
+			&e.ChainID,
+			&e.TotalTransfers,
+			&e.TotalVolume,
+			&e.AvgCompletionTimeSeconds,
+			&e.FastestCompletionTimeSeconds,
+			&e.LastTransferTime,
+			&totalCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan leaderboard entry: %v", err)
+		}
+		entries = append(entries, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating leaderboard: %v", err)
+	}
+
+	return entries, totalCount, nil
+}
+
+// RecordBlockHash stores the hash of a processed block, replacing any previous hash at that height
+func (p *PostgresDB) RecordBlockHash(ctx context.Context, chainID, blockNumber uint64, blockHash string) error {
+	query := `
+		INSERT INTO processed_blocks (chain_id, block_number, block_hash, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (chain_id, block_number) DO UPDATE
+		SET block_hash = $3,
+			created_at = NOW()
+	`
+
+	_, err := p.conn().ExecContext(ctx, query, chainID, blockNumber, blockHash)
+	if err != nil {
+		return fmt.Errorf("failed to record block hash: %v", err)
+	}
+	return nil
+}
+
+// ListBlockHashes retrieves processed block hashes for a chain starting at fromBlock, newest first
+func (p *PostgresDB) ListBlockHashes(ctx context.Context, chainID, fromBlock uint64) ([]*models.ProcessedBlock, error) {
+	query := `
+		SELECT chain_id, block_number, block_hash
+		FROM processed_blocks
+		WHERE chain_id = $1 AND block_number >= $2
+		ORDER BY block_number DESC
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, chainID, fromBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block hashes: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListBlockHashes: failed to close: %v", err)
+		}
+	}()
+
+	var blocks []*models.ProcessedBlock
+	for rows.Next() {
+		var b models.ProcessedBlock
+		if err := rows.Scan(&b.ChainID, &b.BlockNumber, &b.BlockHash); err != nil {
+			return nil, fmt.Errorf("failed to scan block hash: %v", err)
+		}
+		blocks = append(blocks, &b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating block hashes: %v", err)
+	}
+
+	return blocks, nil
+}
+
+// PruneBlockHashes removes processed block hashes below the given block number
+func (p *PostgresDB) PruneBlockHashes(ctx context.Context, chainID, belowBlock uint64) error {
+	query := `DELETE FROM processed_blocks WHERE chain_id = $1 AND block_number < $2`
+
+	_, err := p.conn().ExecContext(ctx, query, chainID, belowBlock)
+	if err != nil {
+		return fmt.Errorf("failed to prune block hashes: %v", err)
+	}
+	return nil
+}
+
+// StageUnconfirmedEvent stores a log until its block reaches the required confirmations
+func (p *PostgresDB) StageUnconfirmedEvent(ctx context.Context, event *models.UnconfirmedEvent) error {
+	query := `
+		INSERT INTO unconfirmed_events (
+			chain_id, event_type, block_number, block_hash, tx_hash, log_index, log, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (chain_id, block_hash, log_index) DO NOTHING
+	`
+
+	_, err := p.conn().ExecContext(ctx, query,
+		event.ChainID,
+		event.EventType,
+		event.BlockNumber,
+		event.BlockHash,
+		event.TxHash,
+		event.LogIndex,
+		event.Log,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to stage unconfirmed event: %v", err)
+	}
+	return nil
+}
+
+// ListUnconfirmedEvents retrieves the staged events of a chain up to the given block that failed to be promoted
+// less than maxAttempts times, in chain order
+func (p *PostgresDB) ListUnconfirmedEvents(
+	ctx context.Context,
+	chainID, upToBlock uint64,
+	maxAttempts int,
+) ([]*models.UnconfirmedEvent, error) {
+	query := `
+		SELECT chain_id, event_type, block_number, block_hash, tx_hash, log_index, log, attempts, last_error, created_at
+		FROM unconfirmed_events
+		WHERE chain_id = $1 AND block_number <= $2 AND attempts < $3
+		ORDER BY block_number ASC, log_index ASC
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, chainID, upToBlock, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unconfirmed events: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListUnconfirmedEvents: failed to close: %v", err)
+		}
+	}()
+
+	var events []*models.UnconfirmedEvent
+	for rows.Next() {
+		var (
+			e         models.UnconfirmedEvent
+			lastError sql.NullString
+		)
+		if err := rows.Scan(
+			&e.ChainID,
+			&e.EventType,
+			&e.BlockNumber,
+			&e.BlockHash,
+			&e.TxHash,
+			&e.LogIndex,
+			&e.Log,
+			&e.Attempts,
+			&lastError,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan unconfirmed event: %v", err)
+		}
+		e.LastError = lastError.String
+		events = append(events, &e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unconfirmed events: %v", err)
+	}
+
+	return events, nil
+}
+
+// RecordUnconfirmedEventFailure records a failed attempt at promoting a staged event
+func (p *PostgresDB) RecordUnconfirmedEventFailure(
+	ctx context.Context,
+	chainID uint64,
+	blockHash string,
+	logIndex uint,
+	reason string,
+) error {
+	query := `
+		UPDATE unconfirmed_events
+		SET attempts = attempts + 1,
+			last_error = $4
+		WHERE chain_id = $1 AND block_hash = $2 AND log_index = $3
+	`
+
+	_, err := p.conn().ExecContext(ctx, query, chainID, blockHash, logIndex, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record unconfirmed event failure: %v", err)
+	}
+	return nil
+}
+
+// CountStuckUnconfirmedEvents counts the staged events of a chain that failed to be promoted maxAttempts times,
+// by event type
+func (p *PostgresDB) CountStuckUnconfirmedEvents(
+	ctx context.Context,
+	chainID uint64,
+	maxAttempts int,
+) (map[string]int, error) {
+	query := `
+		SELECT event_type, COUNT(*)
+		FROM unconfirmed_events
+		WHERE chain_id = $1 AND attempts >= $2
+		GROUP BY event_type
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, chainID, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count stuck unconfirmed events: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("CountStuckUnconfirmedEvents: failed to close: %v", err)
+		}
+	}()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			eventType string
+			count     int
+		)
+		if err := rows.Scan(&eventType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan stuck unconfirmed events: %v", err)
+		}
+		counts[eventType] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stuck unconfirmed events: %v", err)
+	}
+
+	return counts, nil
+}
+
+// DeleteUnconfirmedEvent removes a staged event once it has been promoted
+func (p *PostgresDB) DeleteUnconfirmedEvent(ctx context.Context, chainID uint64, blockHash string, logIndex uint) error {
+	query := `DELETE FROM unconfirmed_events WHERE chain_id = $1 AND block_hash = $2 AND log_index = $3`
+
+	_, err := p.conn().ExecContext(ctx, query, chainID, blockHash, logIndex)
+	if err != nil {
+		return fmt.Errorf("failed to delete unconfirmed event: %v", err)
+	}
+	return nil
+}
+
+// RollbackFromBlock removes every intent, fulfillment and settlement that was indexed from
+// an orphaned block of the given chain at or above fromBlock. orphanedHashes may contain extra
+// hashes known to be orphaned (e.g. from removed logs) that were never recorded.
+// Status transitions caused by the orphaned blocks are deleted and the statuses of surviving intents
+// are re-derived from their remaining history, or from their fulfillments and settlements if they have none.
+// Staged unconfirmed events and orphan events of the orphaned blocks are dropped,
+// and the chain checkpoints are moved back so the canonical blocks get re-indexed.
+func (p *PostgresDB) RollbackFromBlock(
+	ctx context.Context,
+	chainID, fromBlock uint64,
+	orphanedHashes []string,
+) (*models.RollbackResult, error) {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin rollback transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("RollbackFromBlock: failed to rollback transaction: %v", err)
+		}
+	}()
+
+	hashes, err := collectOrphanedHashes(ctx, tx, chainID, fromBlock, orphanedHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RollbackResult{ChainID: chainID, FromBlock: fromBlock}
+
+	var affected []string
+	if len(hashes) > 0 {
+		settled, err := deleteByBlockHash(ctx, tx, "settlements", hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		fulfilled, err := deleteByBlockHash(ctx, tx, "fulfillments", hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		initiated, err := deleteByBlockHash(ctx, tx, "intents", hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Settlements = int64(len(settled))
+		result.Fulfillments = int64(len(fulfilled))
+		result.Intents = int64(len(initiated))
+
+		affected = append(settled, fulfilled...)
+
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM intent_status_history WHERE block_hash = ANY($1)`,
+			pq.Array(hashes),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete orphaned intent status history: %v", err)
+		}
+	}
+
+	if len(affected) > 0 {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE intents i
+			SET status = COALESCE(
+					(
+						SELECT h.to_status FROM intent_status_history h
+						WHERE h.intent_id = i.id
+						ORDER BY h.id DESC
+						LIMIT 1
+					),
+					CASE
+						WHEN EXISTS (SELECT 1 FROM settlements s WHERE s.id = i.id AND s.fulfilled) THEN 'settled'
+						WHEN EXISTS (SELECT 1 FROM settlements s WHERE s.id = i.id) THEN 'settled_without_fulfillment'
+						WHEN (SELECT SUM(f.amount::NUMERIC) FROM fulfillments f WHERE f.id = i.id) >= i.amount::NUMERIC
+							THEN 'fulfilled'
+						WHEN EXISTS (SELECT 1 FROM fulfillments f WHERE f.id = i.id) THEN 'partially_fulfilled'
+						ELSE 'pending'
+					END
+				),
+				updated_at = NOW()
+			WHERE i.id = ANY($1)
+		`, pq.Array(affected))
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-derive intent statuses: %v", err)
+		}
+
+		if result.RederivedIntents, err = res.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %v", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM processed_blocks WHERE chain_id = $1 AND block_number >= $2`,
+		chainID, fromBlock,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete orphaned block hashes: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM unconfirmed_events WHERE chain_id = $1 AND (block_number >= $2 OR block_hash = ANY($3))`,
+		chainID, fromBlock, pq.Array(hashes),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete orphaned unconfirmed events: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM orphan_events WHERE chain_id = $1 AND (block_number >= $2 OR block_hash = ANY($3))`,
+		chainID, fromBlock, pq.Array(hashes),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete orphan events of orphaned blocks: %v", err)
+	}
+
+	if fromBlock > 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE event_checkpoints
+			SET block_number = $2,
+				updated_at = NOW()
+			WHERE chain_id = $1 AND block_number >= $2
+		`, chainID, fromBlock-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind event checkpoints: %v", err)
+		}
+
+		// cursors that aren't seeded yet still resume from the legacy checkpoint
+		_, err = tx.ExecContext(ctx, `
+			UPDATE last_processed_blocks
+			SET block_number = $2,
+				updated_at = NOW()
+			WHERE chain_id = $1 AND block_number >= $2
+		`, chainID, fromBlock-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind last processed block: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rollback: %v", err)
+	}
+
+	return result, nil
+}
+
+// collectOrphanedHashes merges the recorded hashes at or above fromBlock with the provided ones
+func collectOrphanedHashes(
+	ctx context.Context,
+	tx querier,
+	chainID, fromBlock uint64,
+	extra []string,
+) ([]string, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT block_hash FROM processed_blocks WHERE chain_id = $1 AND block_number >= $2`,
+		chainID, fromBlock,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query orphaned block hashes: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("collectOrphanedHashes: failed to close: %v", err)
+		}
+	}()
+
+	seen := make(map[string]struct{})
+	hashes := make([]string, 0, len(extra))
+
+	for _, h := range extra {
+		if _, ok := seen[h]; !ok && h != "" {
+			seen[h] = struct{}{}
+			hashes = append(hashes, h)
+		}
+	}
+
+	for rows.Next() {
+		var h string
+		if err := rows.Scan(&h); err != nil {
+			return nil, fmt.Errorf("failed to scan block hash: %v", err)
+		}
+		if _, ok := seen[h]; !ok {
+			seen[h] = struct{}{}
+			hashes = append(hashes, h)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating block hashes: %v", err)
+	}
+
+	return hashes, nil
+}
+
+// deleteByBlockHash deletes rows of the given table indexed from any of the hashes and returns their ids
+func deleteByBlockHash(ctx context.Context, tx querier, table string, hashes []string) ([]string, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE block_hash = ANY($1) RETURNING id`, table)
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(hashes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete orphaned %s: %v", table, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("deleteByBlockHash: failed to close: %v", err)
+		}
+	}()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan orphaned %s id: %v", table, err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orphaned %s: %v", table, err)
+	}
+
+	return ids, nil
+}
+
+// classifyError wraps a driver error into the domain error of its kind
+func classifyError(err error, msg string) error {
+	var pqErr *pq.Error
+	switch {
+	case errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation":
+		return errs.Wrap(errs.KindDuplicate, err, msg)
+	case errors.Is(err, context.DeadlineExceeded):
+		return errs.Wrap(errs.KindTimeout, err, msg)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -2,11 +2,16 @@ package db
 
 import (
 	"context"
+	"database/sql/driver"
 	"log"
+	"math/big"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,6 +48,13 @@ func TestCreateIntent(t *testing.T) {
 		Status:           models.IntentStatusPending,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		BlockNumber:      100,
+		BlockHash:        "0xb1",
+		TxHash:           "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		LogIndex:         2,
+		Contract:         "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
+		AmountUSD:        "2500",
+		IntentFeeUSD:     "250",
 	}
 
 	// Setup expectations
@@ -59,6 +71,13 @@ func TestCreateIntent(t *testing.T) {
 			string(intent.Status),
 			intent.CreatedAt,
 			intent.UpdatedAt,
+			intent.BlockNumber,
+			intent.BlockHash,
+			intent.TxHash,
+			intent.LogIndex,
+			intent.Contract,
+			intent.AmountUSD,
+			intent.IntentFeeUSD,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -66,6 +85,13 @@ func TestCreateIntent(t *testing.T) {
 	err := postgresDB.CreateIntent(context.Background(), intent)
 	assert.NoError(t, err)
 
+	// Indexing the same log again is a duplicate
+	mock.ExpectExec(`INSERT INTO intents`).
+		WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+
+	err = postgresDB.CreateIntent(context.Background(), intent)
+	assert.ErrorIs(t, err, ErrDuplicate)
+
 	// Verify expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -93,18 +119,28 @@ func TestGetIntent(t *testing.T) {
 		Status:           models.IntentStatusPending,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		BlockNumber:      12345,
+		BlockHash:        "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		TxHash:           "0x1111111111111111111111111111111111111111111111111111111111111111",
+		LogIndex:         7,
+		Contract:         "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
+		AmountUSD:        "2500.5",
 	}
 
 	// Setup the expected rows
 	rows := sqlmock.NewRows([]string{
 		"id", "source_chain", "destination_chain", "token", "amount",
 		"recipient", "sender", "intent_fee", "status", "created_at", "updated_at",
+		"block_number", "block_hash", "tx_hash", "log_index", "contract_address", "amount_usd", "intent_fee_usd",
 	}).
 		AddRow(
 			expectedIntent.ID, expectedIntent.SourceChain, expectedIntent.DestinationChain,
 			expectedIntent.Token, expectedIntent.Amount, expectedIntent.Recipient,
 			expectedIntent.Sender, expectedIntent.IntentFee, string(expectedIntent.Status),
 			expectedIntent.CreatedAt, expectedIntent.UpdatedAt,
+			expectedIntent.BlockNumber, expectedIntent.BlockHash,
+			expectedIntent.TxHash, expectedIntent.LogIndex, expectedIntent.Contract,
+			expectedIntent.AmountUSD, "",
 		)
 
 	// Setup expectations
@@ -120,12 +156,124 @@ func TestGetIntent(t *testing.T) {
 	assert.Equal(t, expectedIntent.Amount, intent.Amount)
 	assert.Equal(t, expectedIntent.Status, intent.Status)
 	assert.Equal(t, expectedIntent.CreatedAt, intent.CreatedAt)
+	assert.Equal(t, expectedIntent.BlockNumber, intent.BlockNumber)
+	assert.Equal(t, expectedIntent.TxHash, intent.TxHash)
+	assert.Equal(t, expectedIntent.LogIndex, intent.LogIndex)
+	assert.Equal(t, expectedIntent.Contract, intent.Contract)
+	assert.Equal(t, expectedIntent.AmountUSD, intent.AmountUSD)
+	assert.Empty(t, intent.IntentFeeUSD)
 
 	// Verify expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUpdateIntentStatus(t *testing.T) {
+	intentID := "0x1234567890123456789012345678901234567890123456789012345678901234"
+	txHash := "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	t.Run("records transition", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		transition := &models.IntentStatusTransition{
+			IntentID:    intentID,
+			ToStatus:    models.IntentStatusSettled,
+			ChainID:     8453,
+			BlockNumber: 100,
+			TxHash:      txHash,
+			CreatedAt:   now,
+		}
+
+		// Setup expectations
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM intents WHERE id = \$1 FOR UPDATE`).
+			WithArgs(intentID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("fulfilled"))
+		mock.ExpectExec(`UPDATE intents SET status = \$1, updated_at = NOW\(\) WHERE id = \$2`).
+			WithArgs(string(models.IntentStatusSettled), intentID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO intent_status_history`).
+			WithArgs(
+				intentID,
+				string(models.IntentStatusFulfilled),
+				string(models.IntentStatusSettled),
+				uint64(8453),
+				uint64(100),
+				"",
+				txHash,
+				now,
+			).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		// Run test
+		err := postgresDB.UpdateIntentStatus(context.Background(), transition)
+		assert.NoError(t, err)
+		assert.Equal(t, models.IntentStatusFulfilled, transition.FromStatus)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("same status is a no-op", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM intents WHERE id = \$1 FOR UPDATE`).
+			WithArgs(intentID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("fulfilled"))
+		mock.ExpectRollback()
+
+		// Run test
+		err := postgresDB.UpdateIntentStatus(context.Background(), &models.IntentStatusTransition{
+			IntentID: intentID,
+			ToStatus: models.IntentStatusFulfilled,
+		})
+		assert.NoError(t, err)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejects invalid transition", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status FROM intents WHERE id = \$1 FOR UPDATE`).
+			WithArgs(intentID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("settled"))
+		mock.ExpectRollback()
+
+		// Run test
+		err := postgresDB.UpdateIntentStatus(context.Background(), &models.IntentStatusTransition{
+			IntentID: intentID,
+			ToStatus: models.IntentStatusPending,
+		})
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExpireIntents(t *testing.T) {
 	postgresDB, mock := setupTestDB(t)
 	defer func() {
 		if err := postgresDB.Close(); err != nil {
@@ -133,20 +281,154 @@ func TestUpdateIntentStatus(t *testing.T) {
 		}
 	}()
 
-	intentID := "0x1234567890123456789012345678901234567890123456789012345678901234"
-	status := models.IntentStatusSettled
+	createdBefore := time.Now().Add(-24 * time.Hour)
 
-	// Setup expectations
-	mock.ExpectExec(`UPDATE intents SET status = \$1, updated_at = NOW\(\) WHERE id = \$2`).
+	// Setup expectations, the status changes are published through the outbox
+	mock.ExpectExec(`UPDATE intents SET status = 'expired'.* INSERT INTO intent_status_history.* INSERT INTO outbox_messages`).
+		WithArgs(createdBefore, models.OutboxTopicIntentExpired).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Run test
+	expired, err := postgresDB.ExpireIntents(context.Background(), createdBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateFulfillment(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	fulfillment := &models.Fulfillment{
+		ID:          "0x1234567890123456789012345678901234567890123456789012345678901234",
+		Asset:       "0x1234567890123456789012345678901234567890",
+		Amount:      "400000000000000000",
+		Receiver:    "0x9876543210987654321098765432109876543210",
+		ChainID:     2,
+		TxHash:      "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		LogIndex:    3,
+		Contract:    "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
+		BlockNumber: 100,
+		BlockHash:   "0xb1",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// Setup expectations, fills are identified by their transaction and log index
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE fulfillments .* log_index = 0 AND contract_address IS NULL`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO fulfillments`).
+		WithArgs(
+			fulfillment.ID,
+			fulfillment.Asset,
+			fulfillment.Amount,
+			fulfillment.Receiver,
+			fulfillment.TxHash,
+			fulfillment.LogIndex,
+			fulfillment.CreatedAt,
+			fulfillment.UpdatedAt,
+			fulfillment.BlockNumber,
+			fulfillment.BlockHash,
+			fulfillment.ChainID,
+			fulfillment.Contract,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Run test
+	err := postgresDB.CreateFulfillment(context.Background(), fulfillment)
+	assert.NoError(t, err)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateFulfillment_ReindexesLegacyFill(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// the fill was indexed before log indexes were recorded, as log index 0 without contract
+	fulfillment := &models.Fulfillment{
+		ID:          "0x1234567890123456789012345678901234567890123456789012345678901234",
+		Amount:      "400000000000000000",
+		ChainID:     2,
+		TxHash:      "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		LogIndex:    3,
+		Contract:    "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
+		BlockNumber: 100,
+		BlockHash:   "0xb1",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// Setup expectations, the legacy row is completed instead of a second row being inserted
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE fulfillments .* WHERE id = \$1 AND tx_hash = \$2 AND amount = \$9 AND log_index = 0 AND contract_address IS NULL`).
 		WithArgs(
-			string(status),
-			intentID,
+			fulfillment.ID,
+			fulfillment.TxHash,
+			fulfillment.LogIndex,
+			fulfillment.BlockNumber,
+			fulfillment.BlockHash,
+			fulfillment.ChainID,
+			fulfillment.Contract,
+			fulfillment.UpdatedAt,
+			fulfillment.Amount,
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// the filled amount only counts the completed row
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount::NUMERIC\), 0\)::TEXT FROM fulfillments WHERE id = \$1`).
+		WithArgs(fulfillment.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow("400000000000000000"))
 
 	// Run test
-	err := postgresDB.UpdateIntentStatus(context.Background(), intentID, status)
+	err := postgresDB.CreateFulfillment(context.Background(), fulfillment)
+	assert.NoError(t, err)
+
+	total, err := postgresDB.GetTotalFulfilledAmount(context.Background(), fulfillment.ID)
 	assert.NoError(t, err)
+	assert.Equal(t, fulfillment.Amount, total)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTotalFulfilledAmount(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	intentID := "0x1234567890123456789012345678901234567890123456789012345678901234"
+
+	// Setup expectations
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount::NUMERIC\), 0\)::TEXT FROM fulfillments WHERE id = \$1`).
+		WithArgs(intentID).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("700000000000000000"))
+
+	// Run test
+	total, err := postgresDB.GetTotalFulfilledAmount(context.Background(), intentID)
+	assert.NoError(t, err)
+	assert.Equal(t, "700000000000000000", total)
 
 	// Verify expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -171,7 +453,12 @@ func TestCreateSettlement(t *testing.T) {
 		Fulfiller:    "0x5678901234567890123456789012345678901234",
 		ActualAmount: "900000000000000000", // 0.9 ETH
 		PaidTip:      "100000000000000000", // 0.1 ETH
+		ChainID:      2,
+		BlockNumber:  100,
+		BlockHash:    "0xb1",
 		TxHash:       "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		LogIndex:     5,
+		Contract:     "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
 		IsCall:       false,
 		CallData:     "",
 		CreatedAt:    now,
@@ -194,6 +481,11 @@ func TestCreateSettlement(t *testing.T) {
 			settlement.CallData,
 			settlement.CreatedAt,
 			settlement.UpdatedAt,
+			settlement.BlockNumber,
+			settlement.BlockHash,
+			settlement.ChainID,
+			settlement.LogIndex,
+			settlement.Contract,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -225,7 +517,12 @@ func TestGetSettlement(t *testing.T) {
 		Fulfiller:    "0x5678901234567890123456789012345678901234",
 		ActualAmount: "900000000000000000", // 0.9 ETH
 		PaidTip:      "100000000000000000", // 0.1 ETH
+		ChainID:      2,
+		BlockNumber:  100,
+		BlockHash:    "0xb1",
 		TxHash:       "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
+		LogIndex:     5,
+		Contract:     "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB",
 		IsCall:       false,
 		CallData:     "",
 		CreatedAt:    now,
@@ -236,6 +533,7 @@ func TestGetSettlement(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "asset", "amount", "receiver", "fulfilled", "fulfiller",
 		"actual_amount", "paid_tip", "tx_hash", "is_call", "call_data", "created_at", "updated_at",
+		"chain_id", "block_number", "block_hash", "log_index", "contract_address",
 	}).
 		AddRow(
 			expectedSettlement.ID, expectedSettlement.Asset, expectedSettlement.Amount,
@@ -243,6 +541,8 @@ func TestGetSettlement(t *testing.T) {
 			expectedSettlement.ActualAmount, expectedSettlement.PaidTip, expectedSettlement.TxHash,
 			expectedSettlement.IsCall, expectedSettlement.CallData,
 			expectedSettlement.CreatedAt, expectedSettlement.UpdatedAt,
+			expectedSettlement.ChainID, expectedSettlement.BlockNumber, expectedSettlement.BlockHash,
+			expectedSettlement.LogIndex, expectedSettlement.Contract,
 		)
 
 	// Setup expectations
@@ -258,12 +558,15 @@ func TestGetSettlement(t *testing.T) {
 	assert.Equal(t, expectedSettlement.Amount, settlement.Amount)
 	assert.Equal(t, expectedSettlement.Fulfilled, settlement.Fulfilled)
 	assert.Equal(t, expectedSettlement.CreatedAt, settlement.CreatedAt)
+	assert.Equal(t, expectedSettlement.ChainID, settlement.ChainID)
+	assert.Equal(t, expectedSettlement.LogIndex, settlement.LogIndex)
+	assert.Equal(t, expectedSettlement.Contract, settlement.Contract)
 
 	// Verify expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetLastProcessedBlock(t *testing.T) {
+func TestGetEventCheckpoint(t *testing.T) {
 	postgresDB, mock := setupTestDB(t)
 	defer func() {
 		if err := postgresDB.Close(); err != nil {
@@ -272,6 +575,7 @@ func TestGetLastProcessedBlock(t *testing.T) {
 	}()
 
 	chainID := uint64(1)
+	contractAddress := "0x1234567890AbcdEF1234567890aBcdef12345678"
 	expectedBlockNumber := uint64(12345)
 
 	// Setup the expected rows
@@ -279,12 +583,15 @@ func TestGetLastProcessedBlock(t *testing.T) {
 		AddRow(expectedBlockNumber)
 
 	// Setup expectations
-	mock.ExpectQuery(`SELECT block_number FROM last_processed_blocks WHERE chain_id = \$1`).
-		WithArgs(chainID).
+	mock.ExpectExec(`INSERT INTO event_checkpoints .* FROM last_processed_blocks`).
+		WithArgs(chainID, "fulfillment", strings.ToLower(contractAddress)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT block_number FROM event_checkpoints WHERE chain_id = \$1 AND event_type = \$2`).
+		WithArgs(chainID, "fulfillment", strings.ToLower(contractAddress)).
 		WillReturnRows(rows)
 
 	// Run test
-	blockNumber, err := postgresDB.GetLastProcessedBlock(context.Background(), chainID)
+	blockNumber, err := postgresDB.GetEventCheckpoint(context.Background(), chainID, "fulfillment", contractAddress)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBlockNumber, blockNumber)
 
@@ -292,7 +599,7 @@ func TestGetLastProcessedBlock(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateLastProcessedBlock(t *testing.T) {
+func TestUpdateEventCheckpoint(t *testing.T) {
 	postgresDB, mock := setupTestDB(t)
 	defer func() {
 		if err := postgresDB.Close(); err != nil {
@@ -301,20 +608,390 @@ func TestUpdateLastProcessedBlock(t *testing.T) {
 	}()
 
 	chainID := uint64(1)
+	contractAddress := "0x1234567890abcdef1234567890abcdef12345678"
 	blockNumber := uint64(12345)
 
 	// Setup expectations
-	mock.ExpectExec(`INSERT INTO last_processed_blocks .* ON CONFLICT .* DO UPDATE`).
+	mock.ExpectExec(`INSERT INTO event_checkpoints .* ON CONFLICT .* DO UPDATE`).
 		WithArgs(
 			chainID,
+			"settlement",
+			contractAddress,
 			blockNumber,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Run test
-	err := postgresDB.UpdateLastProcessedBlock(context.Background(), chainID, blockNumber)
+	err := postgresDB.UpdateEventCheckpoint(context.Background(), chainID, "settlement", contractAddress, blockNumber)
 	assert.NoError(t, err)
 
 	// Verify expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestListEventCheckpoints(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	contractAddress := "0x1234567890abcdef1234567890abcdef12345678"
+	updatedAt := time.Now()
+
+	// Setup the expected rows
+	rows := sqlmock.NewRows([]string{"chain_id", "event_type", "contract_address", "block_number", "updated_at"}).
+		AddRow(1, "fulfillment", contractAddress, 12345, updatedAt).
+		AddRow(1, "intent", contractAddress, 12340, updatedAt)
+
+	// Setup expectations
+	mock.ExpectQuery(`SELECT chain_id, event_type, contract_address, block_number, updated_at FROM event_checkpoints`).
+		WillReturnRows(rows)
+
+	// Run test
+	checkpoints, err := postgresDB.ListEventCheckpoints(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, checkpoints, 2)
+	assert.Equal(t, &models.EventCheckpoint{
+		ChainID:         1,
+		EventType:       "fulfillment",
+		ContractAddress: contractAddress,
+		BlockNumber:     12345,
+		UpdatedAt:       updatedAt,
+	}, checkpoints[0])
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListLeaderboardPaginated(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	since := now.Add(-24 * time.Hour)
+
+	columns := []string{
+		"address", "chain_id", "total_transfers", "total_volume_usd", "avg_completion_time_seconds",
+		"fastest_completion_time_seconds", "last_transfer_time", "total_count",
+	}
+
+	t.Run("view", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("0x5432109876543210987654321098765432109876", 8453, 3, "3000", 12.5, 4.0, now, 2).
+			AddRow("0x9876543210987654321098765432109876543210", 8453, 1, "1000", 8.0, 8.0, now, 2)
+
+		mock.ExpectQuery(`FROM leaderboard_view WHERE chain_id = \$1.*ORDER BY total_volume_usd DESC`).
+			WithArgs(uint64(8453), 20, 0).
+			WillReturnRows(rows)
+
+		entries, total, err := postgresDB.ListLeaderboardPaginated(context.Background(), models.LeaderboardQuery{
+			ChainID:  8453,
+			SortBy:   models.LeaderboardSortVolume,
+			Page:     1,
+			PageSize: 20,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "3000", entries[0].TotalVolume)
+		assert.Equal(t, int64(3), entries[0].TotalTransfers)
+		assert.Equal(t, 4.0, entries[0].FastestCompletionTimeSeconds)
+	})
+
+	t.Run("time window", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("0x5432109876543210987654321098765432109876", 8453, 1, "1000", 4.0, 4.0, now, 1)
+
+		mock.ExpectQuery(`FROM intents.*created_at >= \$2.*ORDER BY fastest_completion_time_seconds ASC`).
+			WithArgs(uint64(8453), since, 10, 10).
+			WillReturnRows(rows)
+
+		entries, total, err := postgresDB.ListLeaderboardPaginated(context.Background(), models.LeaderboardQuery{
+			ChainID:  8453,
+			SortBy:   models.LeaderboardSortFastest,
+			Since:    since,
+			Page:     2,
+			PageSize: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, _, err := postgresDB.ListLeaderboardPaginated(context.Background(), models.LeaderboardQuery{
+			ChainID:  8453,
+			SortBy:   "bogus",
+			Page:     1,
+			PageSize: 10,
+		})
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchIntents(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	sender := "0x5432109876543210987654321098765432109876"
+
+	columns := []string{
+		"id", "source_chain", "destination_chain", "token", "amount", "recipient", "sender", "intent_fee", "status",
+		"created_at", "updated_at", "is_call", "call_data", "block_number", "tx_hash", "amount_usd", "intent_fee_usd",
+	}
+	intentRow := func(id, amount string) []driver.Value {
+		return []driver.Value{
+			id, 8453, 137, "0x1234567890123456789012345678901234567890", amount,
+			"0x9876543210987654321098765432109876543210", sender, "100", "settled",
+			now, now, false, "", 100, "0xabc", "", "",
+		}
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		isCall := false
+		rows := sqlmock.NewRows(columns).
+			AddRow(intentRow("0x01", "3000")...).
+			AddRow(intentRow("0x02", "2000")...).
+			AddRow(intentRow("0x03", "1000")...)
+
+		mock.ExpectQuery(`WHERE source_chain = \$1 AND sender = \$2 AND status = ANY\(\$3\) AND is_call = \$4 `+
+			`AND amount::NUMERIC >= \$5::NUMERIC AND created_at >= \$6\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$7`).
+			WithArgs(uint64(8453), sender, `{"settled","settled_without_fulfillment"}`, false, "1000", now, 3).
+			WillReturnRows(rows)
+
+		intents, hasMore, err := postgresDB.SearchIntents(context.Background(), models.IntentQuery{
+			SourceChain: 8453,
+			Sender:      sender,
+			Statuses:    []models.IntentStatus{models.IntentStatusSettled, models.IntentStatusSettledWithoutFulfillment},
+			IsCall:      &isCall,
+			MinAmount:   big.NewInt(1000),
+			Since:       now,
+			PageSize:    2,
+		})
+		require.NoError(t, err)
+		assert.True(t, hasMore)
+		require.Len(t, intents, 2)
+		assert.Equal(t, "0x02", intents[1].ID)
+		assert.Equal(t, uint64(137), intents[1].DestinationChain)
+	})
+
+	t.Run("sort by amount after cursor", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).AddRow(intentRow("0x04", "5000")...)
+
+		mock.ExpectQuery(`WHERE \(amount::NUMERIC, id\) > \(\$1::NUMERIC, \$2\)\s+ORDER BY amount::NUMERIC ASC, id ASC`).
+			WithArgs("4000", "0x03", 11).
+			WillReturnRows(rows)
+
+		intents, hasMore, err := postgresDB.SearchIntents(context.Background(), models.IntentQuery{
+			SortBy:   models.IntentSortAmount,
+			Order:    models.SortOrderAsc,
+			After:    &models.IntentCursor{SortBy: models.IntentSortAmount, Amount: "4000", ID: "0x03"},
+			PageSize: 10,
+		})
+		require.NoError(t, err)
+		assert.False(t, hasMore)
+		require.Len(t, intents, 1)
+	})
+
+	t.Run("keyset pagination", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE status = ANY\(\$1\) AND \(created_at, id\) < \(\$2, \$3\)`).
+			WithArgs(`{"pending"}`, now, "0x03", 21).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		intents, hasMore, err := postgresDB.ListIntentsKeysetPaginated(context.Background(), now, "0x03", 20, "pending")
+		require.NoError(t, err)
+		assert.False(t, hasMore)
+		assert.Empty(t, intents)
+	})
+
+	t.Run("cursor of another sort", func(t *testing.T) {
+		_, _, err := postgresDB.SearchIntents(context.Background(), models.IntentQuery{
+			SortBy:   models.IntentSortAmount,
+			After:    &models.IntentCursor{SortBy: models.IntentSortCreatedAt, CreatedAt: now, ID: "0x03"},
+			PageSize: 10,
+		})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		_, _, err := postgresDB.SearchIntents(context.Background(), models.IntentQuery{Order: "sideways", PageSize: 10})
+		assert.ErrorIs(t, err, errs.ErrValidation)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRollbackFromBlock(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	chainID := uint64(8453)
+	fromBlock := uint64(100)
+	removedHash := "0xaaaa000000000000000000000000000000000000000000000000000000000000"
+	recordedHash := "0xbbbb000000000000000000000000000000000000000000000000000000000000"
+	intentID := "0x1234567890123456789012345678901234567890123456789012345678901234"
+
+	// Setup expectations
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT block_hash FROM processed_blocks`).
+		WithArgs(chainID, fromBlock).
+		WillReturnRows(sqlmock.NewRows([]string{"block_hash"}).AddRow(recordedHash).AddRow(removedHash))
+	mock.ExpectQuery(`DELETE FROM settlements WHERE block_hash = ANY`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(intentID))
+	mock.ExpectQuery(`DELETE FROM fulfillments WHERE block_hash = ANY`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`DELETE FROM intents WHERE block_hash = ANY`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(`DELETE FROM intent_status_history WHERE block_hash = ANY`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE intents i SET status = COALESCE`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM processed_blocks WHERE chain_id = \$1 AND block_number >= \$2`).
+		WithArgs(chainID, fromBlock).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM unconfirmed_events`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM orphan_events`).
+		WithArgs(chainID, fromBlock, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE event_checkpoints`).
+		WithArgs(chainID, fromBlock-1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`UPDATE last_processed_blocks`).
+		WithArgs(chainID, fromBlock-1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Run test
+	result, err := postgresDB.RollbackFromBlock(context.Background(), chainID, fromBlock, []string{removedHash})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Settlements)
+	assert.Equal(t, int64(0), result.Fulfillments)
+	assert.Equal(t, int64(0), result.Intents)
+	assert.Equal(t, int64(1), result.RederivedIntents)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUnvaluedIntents(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	createdAt := time.Now().Add(-24 * time.Hour)
+
+	// Setup expectations, the intents are listed by ID after the last intent of the previous batch
+	rows := sqlmock.NewRows([]string{"id", "source_chain", "token", "amount", "intent_fee", "created_at"}).
+		AddRow("0x02", uint64(8453), "0x1234567890123456789012345678901234567890", "1000000", "500", createdAt)
+	mock.ExpectQuery(`FROM intents\s+WHERE source_chain = \$1 AND amount_usd IS NULL AND id > \$2\s+ORDER BY id ASC`).
+		WithArgs(uint64(8453), "0x01", 100).
+		WillReturnRows(rows)
+
+	// Run test
+	intents, err := postgresDB.ListUnvaluedIntents(context.Background(), 8453, "0x01", 100)
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	assert.Equal(t, "0x02", intents[0].ID)
+	assert.Equal(t, "1000000", intents[0].Amount)
+	assert.Equal(t, createdAt, intents[0].CreatedAt)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateIntentUSDValue(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	// Setup expectations, values stored in the meantime aren't overwritten
+	mock.ExpectExec(`UPDATE intents\s+SET amount_usd = .*\s+WHERE id = \$1 AND amount_usd IS NULL`).
+		WithArgs("0x01", "1.5", "0.0005").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Run test
+	err := postgresDB.UpdateIntentUSDValue(context.Background(), "0x01", "1.5", "0.0005")
+	require.NoError(t, err)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUnconfirmedEvents(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	createdAt := time.Now().Add(-time.Minute)
+
+	// Setup expectations, events that exhausted their attempts are left out
+	rows := sqlmock.NewRows([]string{
+		"chain_id", "event_type", "block_number", "block_hash", "tx_hash", "log_index", "log",
+		"attempts", "last_error", "created_at",
+	}).
+		AddRow(uint64(8453), "intent", uint64(90), "0xb1", "0xc1", 0, []byte(`{}`), 0, nil, createdAt).
+		AddRow(uint64(8453), "fulfillment", uint64(91), "0xb2", "0xc2", 1, []byte(`{}`), 3, "intent not found", createdAt)
+	mock.ExpectQuery(`FROM unconfirmed_events\s+WHERE chain_id = \$1 AND block_number <= \$2 AND attempts < \$3`).
+		WithArgs(uint64(8453), uint64(96), 10).
+		WillReturnRows(rows)
+
+	// Run test
+	events, err := postgresDB.ListUnconfirmedEvents(context.Background(), 8453, 96, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Empty(t, events[0].LastError)
+	assert.Equal(t, 3, events[1].Attempts)
+	assert.Equal(t, "intent not found", events[1].LastError)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountStuckUnconfirmedEvents(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	// Setup expectations
+	mock.ExpectQuery(`FROM unconfirmed_events\s+WHERE chain_id = \$1 AND attempts >= \$2\s+GROUP BY event_type`).
+		WithArgs(uint64(8453), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"event_type", "count"}).AddRow("fulfillment", 2))
+
+	// Run test
+	counts, err := postgresDB.CountStuckUnconfirmedEvents(context.Background(), 8453, 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"fulfillment": 2}, counts)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
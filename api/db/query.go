@@ -0,0 +1,36 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryBuilder composes the conditions of a query from optional filters,
+// numbering the placeholders of their args in the order they're added
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// where adds a condition, each "?" of the condition is the placeholder of the next value
+func (b *queryBuilder) where(condition string, values ...interface{}) *queryBuilder {
+	for _, value := range values {
+		condition = strings.Replace(condition, "?", b.arg(value), 1)
+	}
+	b.conditions = append(b.conditions, condition)
+	return b
+}
+
+// arg adds a value and returns its placeholder, e.g. for the LIMIT of the query
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// whereClause returns the WHERE clause of the conditions, empty if there is none
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
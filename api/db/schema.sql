@@ -8,24 +8,37 @@ CREATE TABLE IF NOT EXISTS intents (
     recipient VARCHAR(42) NOT NULL,
     sender VARCHAR(42) NOT NULL,
     intent_fee VARCHAR(78) NOT NULL,
-    status VARCHAR(20) NOT NULL,
+    status VARCHAR(32) NOT NULL,
     is_call BOOLEAN NOT NULL DEFAULT FALSE,
     call_data TEXT,
+    block_number BIGINT,
+    block_hash VARCHAR(66),
+    tx_hash VARCHAR(66),
+    log_index INTEGER NOT NULL DEFAULT 0,
+    contract_address VARCHAR(42),
+    amount_usd NUMERIC,
+    intent_fee_usd NUMERIC,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
--- Create fulfillments table
+-- Create fulfillments table, an intent (id) can be filled several times, each fill is identified by its log
 CREATE TABLE IF NOT EXISTS fulfillments (
-    id VARCHAR(66) PRIMARY KEY,
+    id VARCHAR(66) NOT NULL,
     asset VARCHAR(42) NOT NULL,
     amount VARCHAR(78) NOT NULL,
     receiver VARCHAR(42) NOT NULL,
+    chain_id BIGINT,
+    block_number BIGINT,
+    block_hash VARCHAR(66),
     tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL DEFAULT 0,
+    contract_address VARCHAR(42),
     is_call BOOLEAN NOT NULL DEFAULT FALSE,
     call_data TEXT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, tx_hash, log_index)
 );
 
 -- Create settlements table
@@ -38,20 +51,218 @@ CREATE TABLE IF NOT EXISTS settlements (
     fulfiller VARCHAR(42) NOT NULL,
     actual_amount VARCHAR(78) NOT NULL,
     paid_tip VARCHAR(78) NOT NULL,
+    chain_id BIGINT,
+    block_number BIGINT,
+    block_hash VARCHAR(66),
     tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL DEFAULT 0,
+    contract_address VARCHAR(42),
     is_call BOOLEAN NOT NULL DEFAULT FALSE,
     call_data TEXT,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
--- Create last_processed_blocks table
+-- Create last_processed_blocks table (legacy per-chain checkpoint, event_checkpoints are seeded from it)
 CREATE TABLE IF NOT EXISTS last_processed_blocks (
     chain_id BIGINT PRIMARY KEY,
     block_number BIGINT NOT NULL,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Create event_checkpoints table tracking the last processed block per chain, event type and contract
+CREATE TABLE IF NOT EXISTS event_checkpoints (
+    chain_id BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    contract_address VARCHAR(42) NOT NULL,
+    block_number BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chain_id, event_type, contract_address)
+);
+
+-- Create intent_status_history table recording every status transition and the event that caused it
+CREATE TABLE IF NOT EXISTS intent_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    intent_id VARCHAR(66) NOT NULL,
+    from_status VARCHAR(32),
+    to_status VARCHAR(32) NOT NULL,
+    chain_id BIGINT,
+    block_number BIGINT,
+    block_hash VARCHAR(66),
+    tx_hash VARCHAR(66),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_intent_status_history_intent ON intent_status_history(intent_id, id);
+CREATE INDEX IF NOT EXISTS idx_intent_status_history_block_hash ON intent_status_history(block_hash);
+
+-- Create processed_blocks table used for reorg detection
+CREATE TABLE IF NOT EXISTS processed_blocks (
+    chain_id BIGINT NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash VARCHAR(66) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chain_id, block_number)
+);
+
+-- Create unconfirmed_events table staging logs until they reach the required confirmations
+CREATE TABLE IF NOT EXISTS unconfirmed_events (
+    chain_id BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash VARCHAR(66) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    log JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chain_id, block_hash, log_index)
+);
+
+-- Migration for the failed promotions of staged events, retried up to a maximum number of attempts
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'unconfirmed_events' AND column_name = 'attempts') THEN
+        ALTER TABLE unconfirmed_events ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'unconfirmed_events' AND column_name = 'last_error') THEN
+        ALTER TABLE unconfirmed_events ADD COLUMN last_error TEXT;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_unconfirmed_events_chain_block ON unconfirmed_events(chain_id, block_number);
+
+-- Create orphan_events table holding the fulfillment and settlement logs indexed before their intent
+CREATE TABLE IF NOT EXISTS orphan_events (
+    id BIGSERIAL PRIMARY KEY,
+    chain_id BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    intent_id VARCHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash VARCHAR(66) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    log JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (chain_id, block_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orphan_events_intent_id ON orphan_events(intent_id);
+
+-- Create skipped_block_ranges table recording the ranges the catchup skipped as empty, for later audits
+CREATE TABLE IF NOT EXISTS skipped_block_ranges (
+    id BIGSERIAL PRIMARY KEY,
+    chain_id BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    contract_address VARCHAR(42) NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    skipped_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    missed_blocks BIGINT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_skipped_block_ranges_unverified
+    ON skipped_block_ranges(chain_id, from_block) WHERE verified_at IS NULL;
+
+-- Create outbox_messages table recording the notifications of persisted events in their transaction,
+-- until they're dispatched downstream
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP WITH TIME ZONE,
+    dispatch_cursor BIGINT
+);
+
+-- Migration for the dispatch cursors of the outbox messages, the cursors of the intent event stream and webhooks.
+-- Stream clients used to resume from the intent status history IDs, so the dispatch cursors start after them.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'outbox_messages' AND column_name = 'dispatch_cursor') THEN
+        ALTER TABLE outbox_messages ADD COLUMN dispatch_cursor BIGINT;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE relkind = 'S' AND relname = 'outbox_dispatch_cursor_seq') THEN
+        CREATE SEQUENCE outbox_dispatch_cursor_seq;
+        PERFORM setval('outbox_dispatch_cursor_seq', (SELECT COALESCE(MAX(id), 0) + 1 FROM intent_status_history), false);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_pending ON outbox_messages(id) WHERE dispatched_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_messages_dispatch_cursor
+    ON outbox_messages(dispatch_cursor) WHERE dispatch_cursor IS NOT NULL;
+
+-- Create webhook_subscriptions table storing the endpoints notified of intent events
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    sender VARCHAR(42),
+    recipient VARCHAR(42),
+    token VARCHAR(42),
+    source_chain BIGINT,
+    destination_chain BIGINT,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Deliveries are enqueued when the outbox messages are dispatched, the webhook cursor is no longer used
+DROP TABLE IF EXISTS webhook_cursor;
+
+-- Create webhook_deliveries table queuing the events to deliver to each subscription
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id),
+    event_cursor BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    intent_id VARCHAR(66) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    last_status_code INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (subscription_id, event_cursor)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id DESC);
+
+-- Create webhook_delivery_attempts table logging every delivery attempt
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL REFERENCES webhook_deliveries(id),
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id);
+
+-- Create webhook_dead_letters table keeping the deliveries that exhausted their attempts
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL UNIQUE REFERENCES webhook_deliveries(id),
+    subscription_id BIGINT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Create indexes
 CREATE INDEX IF NOT EXISTS idx_intents_status ON intents(status);
 CREATE INDEX IF NOT EXISTS idx_fulfillments_id ON fulfillments(id);
@@ -64,8 +275,49 @@ CREATE INDEX IF NOT EXISTS idx_intents_recipient ON intents(recipient);
 CREATE INDEX IF NOT EXISTS idx_intents_sender_status ON intents(sender, status);
 CREATE INDEX IF NOT EXISTS idx_intents_recipient_status ON intents(recipient, status);
 CREATE INDEX IF NOT EXISTS idx_intents_created_at ON intents(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_intents_source_chain_created_at ON intents(source_chain, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_intents_destination_chain_created_at ON intents(destination_chain, created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_fulfillments_created_at ON fulfillments(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_settlements_created_at ON settlements(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_settlements_fulfiller_created_at ON settlements(fulfiller, created_at DESC);
+
+-- Migration for the intent state machine: widen the status column and
+-- mark intents settled without being fulfilled.
+-- Runs before the views are (re)created as they depend on the status column.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'intents' AND column_name = 'status' AND character_maximum_length < 32
+    ) THEN
+        -- views depending on the column are recreated below
+        DROP VIEW IF EXISTS intent_lifecycle_view, user_activity_view, chain_activity_view, leaderboard_view;
+        ALTER TABLE intents ALTER COLUMN status TYPE VARCHAR(32);
+    END IF;
+
+    UPDATE intents i
+    SET status = 'settled_without_fulfillment'
+    FROM settlements s
+    WHERE s.id = i.id AND NOT s.fulfilled AND i.status = 'settled';
+END $$;
+
+-- Migration for the USD values of intents, stored when the intent is created (NULL if the price is unknown).
+-- Runs before the views are (re)created as they depend on these columns.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'amount_usd') THEN
+        ALTER TABLE intents ADD COLUMN amount_usd NUMERIC;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'intent_fee_usd') THEN
+        ALTER TABLE intents ADD COLUMN intent_fee_usd NUMERIC;
+    END IF;
+
+    -- the analytics views summed raw amounts of different tokens, their volume columns are now in USD
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'leaderboard_view' AND column_name = 'total_volume') THEN
+        DROP VIEW IF EXISTS user_activity_view, chain_activity_view, leaderboard_view;
+    END IF;
+END $$;
 
 -- Create views for analytics and reporting
 
@@ -100,8 +352,10 @@ SELECT
     END as time_to_fulfillment_seconds
 FROM 
     intents i
-LEFT JOIN 
-    fulfillments f ON i.id = f.id
+LEFT JOIN LATERAL (
+    -- an intent can be filled several times, its fulfillment time is the time of the first fill
+    SELECT MIN(created_at) AS created_at FROM fulfillments WHERE id = i.id
+) f ON TRUE
 LEFT JOIN 
     settlements s ON i.id = s.id
 ORDER BY 
@@ -113,7 +367,7 @@ SELECT
     sender as address,
     'sender' as role,
     COUNT(*) as transaction_count,
-    SUM(CAST(amount as NUMERIC)) as total_amount,
+    SUM(amount_usd) as total_amount_usd,
     MIN(created_at) as first_activity,
     MAX(created_at) as last_activity
 FROM
@@ -125,7 +379,7 @@ SELECT
     recipient as address,
     'receiver' as role,
     COUNT(*) as transaction_count,
-    SUM(CAST(amount as NUMERIC)) as total_amount,
+    SUM(amount_usd) as total_amount_usd,
     MIN(created_at) as first_activity,
     MAX(created_at) as last_activity
 FROM
@@ -139,8 +393,8 @@ SELECT
     source_chain,
     destination_chain,
     COUNT(*) as transaction_count,
-    SUM(CAST(amount as NUMERIC)) as total_volume,
-    AVG(CAST(intent_fee as NUMERIC)) as avg_fee,
+    SUM(amount_usd) as total_volume_usd,
+    AVG(intent_fee_usd) as avg_fee_usd,
     MIN(created_at) as first_transaction,
     MAX(created_at) as last_transaction
 FROM
@@ -173,18 +427,18 @@ SELECT
     sender as address,
     source_chain as chain_id,
     COUNT(*) as total_transfers,
-    SUM(CAST(amount as NUMERIC)) as total_volume,
+    SUM(amount_usd) as total_volume_usd,
     AVG(EXTRACT(EPOCH FROM (updated_at - created_at))) as avg_completion_time_seconds,
     MIN(EXTRACT(EPOCH FROM (updated_at - created_at))) as fastest_completion_time_seconds,
     MAX(updated_at) as last_transfer_time
 FROM
     intents
 WHERE
-    status = 'settled'
+    status IN ('settled', 'settled_without_fulfillment')
 GROUP BY
     sender, source_chain
 ORDER BY
-    total_volume DESC;
+    total_volume_usd DESC NULLS LAST;
 
 -- Migration for adding is_call and call_data columns
 DO $$
@@ -215,4 +469,124 @@ BEGIN
     IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'settlements' AND column_name = 'call_data') THEN
         ALTER TABLE settlements ADD COLUMN call_data TEXT;
     END IF;
-END $$;
\ No newline at end of file
+END $$;
+
+-- Migration for adding block_number and block_hash columns used for reorg rollback
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'block_number') THEN
+        ALTER TABLE intents ADD COLUMN block_number BIGINT;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'block_hash') THEN
+        ALTER TABLE intents ADD COLUMN block_hash VARCHAR(66);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'fulfillments' AND column_name = 'block_number') THEN
+        ALTER TABLE fulfillments ADD COLUMN block_number BIGINT;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'fulfillments' AND column_name = 'block_hash') THEN
+        ALTER TABLE fulfillments ADD COLUMN block_hash VARCHAR(66);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'settlements' AND column_name = 'block_number') THEN
+        ALTER TABLE settlements ADD COLUMN block_number BIGINT;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'settlements' AND column_name = 'block_hash') THEN
+        ALTER TABLE settlements ADD COLUMN block_hash VARCHAR(66);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_intents_block_hash ON intents(block_hash);
+CREATE INDEX IF NOT EXISTS idx_fulfillments_block_hash ON fulfillments(block_hash);
+CREATE INDEX IF NOT EXISTS idx_settlements_block_hash ON settlements(block_hash);
+
+-- Migration for partial fills: fulfillments were keyed by the ID of their intent, allowing a single fill per intent.
+-- They are now keyed by the intent ID, transaction hash and log index of the fill.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'fulfillments' AND column_name = 'log_index') THEN
+        ALTER TABLE fulfillments ADD COLUMN log_index INTEGER NOT NULL DEFAULT 0;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.key_column_usage
+        WHERE table_name = 'fulfillments' AND constraint_name = 'fulfillments_pkey' AND column_name = 'log_index'
+    ) THEN
+        ALTER TABLE fulfillments DROP CONSTRAINT IF EXISTS fulfillments_pkey;
+        ALTER TABLE fulfillments ADD PRIMARY KEY (id, tx_hash, log_index);
+    END IF;
+END $$;
+
+-- Migration for the transaction metadata of indexed events: every entity records the transaction, log index
+-- and contract it was indexed from. Fulfillments and settlements happen on the destination chain of their intent.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'tx_hash') THEN
+        ALTER TABLE intents ADD COLUMN tx_hash VARCHAR(66);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'log_index') THEN
+        ALTER TABLE intents ADD COLUMN log_index INTEGER NOT NULL DEFAULT 0;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'intents' AND column_name = 'contract_address') THEN
+        ALTER TABLE intents ADD COLUMN contract_address VARCHAR(42);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'fulfillments' AND column_name = 'chain_id') THEN
+        ALTER TABLE fulfillments ADD COLUMN chain_id BIGINT;
+
+        UPDATE fulfillments f
+        SET chain_id = i.destination_chain
+        FROM intents i
+        WHERE i.id = f.id;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'fulfillments' AND column_name = 'contract_address') THEN
+        ALTER TABLE fulfillments ADD COLUMN contract_address VARCHAR(42);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'settlements' AND column_name = 'chain_id') THEN
+        ALTER TABLE settlements ADD COLUMN chain_id BIGINT;
+
+        UPDATE settlements s
+        SET chain_id = i.destination_chain
+        FROM intents i
+        WHERE i.id = s.id;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'settlements' AND column_name = 'log_index') THEN
+        ALTER TABLE settlements ADD COLUMN log_index INTEGER NOT NULL DEFAULT 0;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'settlements' AND column_name = 'contract_address') THEN
+        ALTER TABLE settlements ADD COLUMN contract_address VARCHAR(42);
+    END IF;
+END $$;
+
+-- An event is identified by its chain, transaction and log index. Rows indexed before the log index was recorded
+-- share log index 0 and are left out, as are intents created through the API. A legacy fill indexed again is
+-- completed in place by CreateFulfillment rather than inserted a second time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_intents_chain_tx_log
+    ON intents(source_chain, tx_hash, log_index) WHERE tx_hash IS NOT NULL AND contract_address IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fulfillments_chain_tx_log
+    ON fulfillments(chain_id, tx_hash, log_index) WHERE contract_address IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlements_chain_tx_log
+    ON settlements(chain_id, tx_hash, log_index) WHERE contract_address IS NOT NULL;
+
+-- Migration for the status history of the intents indexed before it was recorded: their current status is recorded
+-- as a single transition, at their creation for pending intents and at their last update otherwise. The event of
+-- the intent is recorded for pending intents only, the event that set the status of the others isn't known.
+-- Runs after the migrations of the event metadata columns it reads.
+INSERT INTO intent_status_history (intent_id, from_status, to_status, chain_id, block_number, block_hash, tx_hash, created_at)
+SELECT i.id, NULL, i.status,
+    CASE WHEN i.status = 'pending' THEN i.source_chain END,
+    CASE WHEN i.status = 'pending' THEN i.block_number END,
+    CASE WHEN i.status = 'pending' THEN i.block_hash END,
+    CASE WHEN i.status = 'pending' THEN i.tx_hash END,
+    CASE WHEN i.status = 'pending' THEN i.created_at ELSE i.updated_at END
+FROM intents i
+WHERE NOT EXISTS (SELECT 1 FROM intent_status_history h WHERE h.intent_id = i.id);
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// RecordSkippedRange records a block range the catchup skipped as empty and sets its ID and skip time
+func (p *PostgresDB) RecordSkippedRange(ctx context.Context, skipped *models.SkippedRange) error {
+	query := `
+		INSERT INTO skipped_block_ranges (chain_id, event_type, contract_address, from_block, to_block, skipped_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, skipped_at
+	`
+
+	err := p.conn().QueryRowContext(ctx, query,
+		skipped.ChainID,
+		skipped.EventType,
+		strings.ToLower(skipped.ContractAddress),
+		skipped.FromBlock,
+		skipped.ToBlock,
+	).Scan(&skipped.ID, &skipped.SkippedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record skipped range: %v", err)
+	}
+	return nil
+}
+
+// ListUnverifiedSkippedRanges lists the skipped ranges of a chain not audited yet, oldest blocks first
+func (p *PostgresDB) ListUnverifiedSkippedRanges(
+	ctx context.Context,
+	chainID uint64,
+	limit int,
+) ([]*models.SkippedRange, error) {
+	query := `
+		SELECT id, chain_id, event_type, contract_address, from_block, to_block, skipped_at
+		FROM skipped_block_ranges
+		WHERE chain_id = $1 AND verified_at IS NULL
+		ORDER BY from_block ASC, id ASC
+		LIMIT $2
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, chainID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query skipped ranges: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListUnverifiedSkippedRanges: failed to close: %v", err)
+		}
+	}()
+
+	var ranges []*models.SkippedRange
+	for rows.Next() {
+		var r models.SkippedRange
+		if err := rows.Scan(
+			&r.ID,
+			&r.ChainID,
+			&r.EventType,
+			&r.ContractAddress,
+			&r.FromBlock,
+			&r.ToBlock,
+			&r.SkippedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan skipped range: %v", err)
+		}
+		ranges = append(ranges, &r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating skipped ranges: %v", err)
+	}
+
+	return ranges, nil
+}
+
+// MarkSkippedRangeVerified records the audit of a skipped range with the blocks found to have events
+func (p *PostgresDB) MarkSkippedRangeVerified(ctx context.Context, id int64, missedBlocks []uint64) error {
+	missed := make([]int64, len(missedBlocks))
+	for i, block := range missedBlocks {
+		missed[i] = int64(block)
+	}
+
+	result, err := p.conn().ExecContext(ctx, `
+		UPDATE skipped_block_ranges
+		SET verified_at = NOW(),
+			missed_blocks = $2
+		WHERE id = $1
+	`, id, pq.Array(missed))
+	if err != nil {
+		return fmt.Errorf("failed to mark skipped range verified: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
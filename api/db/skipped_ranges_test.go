@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkSkippedRangeVerified(t *testing.T) {
+	t.Run("records the missed blocks", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations
+		mock.ExpectExec(`UPDATE skipped_block_ranges`).
+			WithArgs(int64(7), pq.Array([]int64{15, 18})).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// Run test
+		err := postgresDB.MarkSkippedRangeVerified(context.Background(), 7, []uint64{15, 18})
+		assert.NoError(t, err)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown range", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations
+		mock.ExpectExec(`UPDATE skipped_block_ranges`).
+			WithArgs(int64(7), pq.Array([]int64{})).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// Run test
+		err := postgresDB.MarkSkippedRangeVerified(context.Background(), 7, nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// querier runs queries on the database connection pool or in a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction of a WithTx callback, or the connection pool outside of transactions
+func (p *PostgresDB) conn() querier {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.db
+}
+
+// WithTx runs fn in a transaction, the database passed to fn runs all its operations in the transaction.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+// WithTx calls made in fn join the transaction they're called in.
+func (p *PostgresDB) WithTx(ctx context.Context, fn func(tx Database) error) error {
+	if p.tx != nil {
+		return fn(p)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("WithTx: failed to rollback transaction: %v", err)
+		}
+	}()
+
+	txDB := *p
+	txDB.tx = tx
+
+	if err := fn(&txDB); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// txn is a transaction of a multi-statement operation. Operations called in a WithTx callback
+// join its transaction, their commit and rollback are left to WithTx.
+type txn struct {
+	*sql.Tx
+	joined bool
+}
+
+// beginTx begins the transaction of a multi-statement operation
+func (p *PostgresDB) beginTx(ctx context.Context) (*txn, error) {
+	if p.tx != nil {
+		return &txn{Tx: p.tx, joined: true}, nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx}, nil
+}
+
+// Commit commits the transaction unless it was joined
+func (t *txn) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls back the transaction unless it was joined
+func (t *txn) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTx(t *testing.T) {
+	transition := &models.IntentStatusTransition{
+		IntentID: "0x01",
+		ToStatus: models.IntentStatusFulfilled,
+		ChainID:  2,
+	}
+
+	t.Run("commits the operations of the callback", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations, the status update joins the transaction
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO fulfillments`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`SELECT status FROM intents WHERE id = \$1 FOR UPDATE`).
+			WithArgs("0x01").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("pending"))
+		mock.ExpectExec(`UPDATE intents`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO intent_status_history`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`INSERT INTO outbox_messages`).
+			WithArgs(models.OutboxTopicIntentFulfilled, []byte(`{}`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+		mock.ExpectCommit()
+
+		// Run test
+		err := postgresDB.WithTx(context.Background(), func(tx Database) error {
+			if err := tx.CreateFulfillment(context.Background(), &models.Fulfillment{ID: "0x01"}); err != nil {
+				return err
+			}
+			if err := tx.UpdateIntentStatus(context.Background(), transition); err != nil {
+				return err
+			}
+			return tx.EnqueueOutboxMessage(context.Background(), &models.OutboxMessage{
+				Topic:   models.OutboxTopicIntentFulfilled,
+				Payload: []byte(`{}`),
+			})
+		})
+		assert.NoError(t, err)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back when the callback fails", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO fulfillments`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`SELECT status FROM intents WHERE id = \$1 FOR UPDATE`).
+			WithArgs("0x01").
+			WillReturnError(errors.New("connection reset"))
+		mock.ExpectRollback()
+
+		// Run test
+		err := postgresDB.WithTx(context.Background(), func(tx Database) error {
+			if err := tx.CreateFulfillment(context.Background(), &models.Fulfillment{ID: "0x01"}); err != nil {
+				return err
+			}
+			return tx.UpdateIntentStatus(context.Background(), transition)
+		})
+		assert.ErrorContains(t, err, "connection reset")
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nested calls join the transaction", func(t *testing.T) {
+		postgresDB, mock := setupTestDB(t)
+		defer func() {
+			if err := postgresDB.Close(); err != nil {
+				log.Printf("failed to close: %v", err)
+			}
+		}()
+
+		// Setup expectations
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`WITH pending AS`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(dispatchedEventColumns))
+		mock.ExpectCommit()
+
+		// Run test
+		err := postgresDB.WithTx(context.Background(), func(tx Database) error {
+			return tx.WithTx(context.Background(), func(tx Database) error {
+				_, _, err := tx.DispatchOutboxMessages(context.Background(), 1)
+				return err
+			})
+		})
+		assert.NoError(t, err)
+
+		// Verify expectations were met
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAdvanceEventCheckpoint(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	// Setup expectations, checkpoints past the block are left untouched
+	mock.ExpectExec(`ON CONFLICT \(chain_id, event_type, contract_address\) DO UPDATE .* WHERE event_checkpoints.block_number < \$4`).
+		WithArgs(uint64(1), "intent", "0xabc", uint64(99)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Run test
+	err := postgresDB.AdvanceEventCheckpoint(context.Background(), 1, "intent", "0xABC", 99)
+	assert.NoError(t, err)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// intentEventColumnNames are the columns of intentEventColumns
+var intentEventColumnNames = []string{
+	"dispatch_cursor", "intent_id", "to_status", "from_status", "source_chain", "destination_chain", "token",
+	"amount", "sender", "recipient", "chain_id", "block_number", "tx_hash", "created_at",
+}
+
+// dispatchedEventColumns are the columns of the query of DispatchOutboxMessages
+var dispatchedEventColumns = append([]string{"found"}, intentEventColumnNames...)
+
+func TestDispatchOutboxMessages(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	// Setup expectations, dispatches are serialized and the message of a rolled back intent is dispatched without event
+	now := time.Now()
+	rows := sqlmock.NewRows(dispatchedEventColumns).
+		AddRow(true, int64(41), "0x01", "fulfilled", "pending", 1, 2, "0xtoken", "100", "0xa", "0xb", 2, 10, "0xtx", now).
+		AddRow(false, int64(42), "0x02", "settled", "fulfilled", 0, 0, "", "", "", "", 2, 11, "0xtx2", now)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).
+		WithArgs(outboxDispatchLock).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FOR UPDATE.*nextval\('outbox_dispatch_cursor_seq'\).*ORDER BY o.dispatch_cursor`).
+		WithArgs(10).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	// Run test
+	events, dispatched, err := postgresDB.DispatchOutboxMessages(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dispatched)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(41), events[0].Cursor)
+	assert.Equal(t, "0x01", events[0].IntentID)
+	assert.Equal(t, models.EventType(models.IntentStatusPending, models.IntentStatusFulfilled), events[0].Type)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListIntentEvents(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	// Setup expectations, events are read in dispatch order
+	rows := sqlmock.NewRows(intentEventColumnNames).
+		AddRow(int64(43), "0x01", "settled", "fulfilled", 1, 2, "0xtoken", "100", "0xa", "0xb", 2, 12, "0xtx", time.Now())
+	mock.ExpectQuery(`WHERE o.dispatch_cursor > \$1\s+ORDER BY o.dispatch_cursor ASC\s+LIMIT \$2`).
+		WithArgs(int64(42), 5).
+		WillReturnRows(rows)
+
+	// Run test
+	events, err := postgresDB.ListIntentEvents(context.Background(), 42, 5)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(43), events[0].Cursor)
+	assert.Equal(t, models.IntentStatusSettled, events[0].Status)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
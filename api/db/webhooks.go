@@ -0,0 +1,376 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// webhookSubscriptionColumns are the columns scanned by scanWebhookSubscription
+const webhookSubscriptionColumns = `
+	id, url, COALESCE(sender, ''), COALESCE(recipient, ''), COALESCE(token, ''),
+	COALESCE(source_chain, 0), COALESCE(destination_chain, 0), created_at
+`
+
+// webhookDeliveryColumns are the columns scanned by scanWebhookDelivery
+const webhookDeliveryColumns = `
+	id, subscription_id, event_cursor, event_type, intent_id, payload, status, attempts,
+	next_attempt_at, COALESCE(last_error, ''), COALESCE(last_status_code, 0), created_at, delivered_at
+`
+
+// CreateWebhookSubscription registers a webhook subscription and sets its ID and creation time
+func (p *PostgresDB) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (
+			url, secret, sender, recipient, token, source_chain, destination_chain, created_at, updated_at
+		) VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, 0), NULLIF($7, 0), NOW(), NOW())
+		RETURNING id, created_at
+	`
+
+	err := p.conn().QueryRowContext(ctx, query,
+		sub.URL,
+		sub.Secret,
+		sub.Sender,
+		sub.Recipient,
+		sub.Token,
+		sub.SourceChain,
+		sub.DestinationChain,
+	).Scan(&sub.ID, &sub.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %v", err)
+	}
+	return nil
+}
+
+// GetWebhookSubscription retrieves an active webhook subscription, without its secret
+func (p *PostgresDB) GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 AND active`
+
+	sub, err := scanWebhookSubscription(p.conn().QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %v", err)
+	}
+	return sub, nil
+}
+
+// ListWebhookSubscriptions lists the active webhook subscriptions, without their secret
+func (p *PostgresDB) ListWebhookSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE active ORDER BY id ASC`
+
+	rows, err := p.conn().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListWebhookSubscriptions: failed to close: %v", err)
+		}
+	}()
+
+	var subs []*models.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %v", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook subscriptions: %v", err)
+	}
+
+	return subs, nil
+}
+
+// DeleteWebhookSubscription deactivates a webhook subscription, its pending deliveries are dropped
+// while the delivery logs are kept
+func (p *PostgresDB) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	result, err := p.conn().ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET active = FALSE,
+			updated_at = NOW()
+		WHERE id = $1 AND active
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateWebhookDeliveries queues the deliveries of intent events, a delivery of an event already queued
+// for the subscription is skipped. It's meant to be called in the transaction dispatching the events.
+func (p *PostgresDB) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		_, err := p.conn().ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (
+				subscription_id, event_cursor, event_type, intent_id, payload, status, next_attempt_at, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, 'pending', NOW(), NOW(), NOW())
+			ON CONFLICT (subscription_id, event_cursor) DO NOTHING
+		`,
+			delivery.SubscriptionID,
+			delivery.EventCursor,
+			delivery.EventType,
+			delivery.IntentID,
+			[]byte(delivery.Payload),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue webhook delivery: %v", err)
+		}
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries claims the pending deliveries due for an attempt, oldest first.
+// Claimed deliveries are not due again before the lease expires, so that deliveries of
+// a crashed instance are retried.
+func (p *PostgresDB) ClaimWebhookDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]*models.WebhookDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond',
+				updated_at = NOW()
+			WHERE id IN (
+				SELECT d.id
+				FROM webhook_deliveries d
+				JOIN webhook_subscriptions s ON s.id = d.subscription_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND s.active
+				ORDER BY d.next_attempt_at ASC
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING ` + webhookDeliveryColumns + `
+		)
+		SELECT c.*, s.url, s.secret
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.id = c.subscription_id
+		ORDER BY c.id ASC
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ClaimWebhookDeliveries: failed to close: %v", err)
+		}
+	}()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %v", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %v", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt logs a delivery attempt and stores the resulting delivery state.
+// Dead deliveries are copied to the dead letters.
+func (p *PostgresDB) RecordWebhookAttempt(
+	ctx context.Context,
+	delivery *models.WebhookDelivery,
+	attempt *models.WebhookDeliveryAttempt,
+) error {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("RecordWebhookAttempt: failed to rollback transaction: %v", err)
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (
+			delivery_id, attempt, status_code, error, duration_ms, created_at
+		) VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5, $6)
+	`,
+		attempt.DeliveryID,
+		attempt.Attempt,
+		attempt.StatusCode,
+		attempt.Error,
+		attempt.Duration.Milliseconds(),
+		attempt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log webhook delivery attempt: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $1,
+			attempts = $2,
+			next_attempt_at = $3,
+			last_error = NULLIF($4, ''),
+			last_status_code = NULLIF($5, 0),
+			delivered_at = $6,
+			updated_at = NOW()
+		WHERE id = $7
+	`,
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.LastStatusCode,
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %v", err)
+	}
+
+	if delivery.Status == models.WebhookDeliveryDead {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO webhook_dead_letters (
+				delivery_id, subscription_id, event_type, payload, attempts, last_error, created_at
+			) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NOW())
+			ON CONFLICT (delivery_id) DO NOTHING
+		`,
+			delivery.ID,
+			delivery.SubscriptionID,
+			delivery.EventType,
+			[]byte(delivery.Payload),
+			delivery.Attempts,
+			delivery.LastError,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store webhook dead letter: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit webhook delivery attempt: %v", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries lists the latest deliveries of a subscription, newest first.
+// An empty status lists deliveries of every status.
+func (p *PostgresDB) ListWebhookDeliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	status models.WebhookDeliveryStatus,
+	limit int,
+) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, err := p.conn().QueryContext(ctx, query, subscriptionID, string(status), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("ListWebhookDeliveries: failed to close: %v", err)
+		}
+	}()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %v", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %v", err)
+	}
+
+	return deliveries, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := row.Scan(
+		&sub.ID,
+		&sub.URL,
+		&sub.Sender,
+		&sub.Recipient,
+		&sub.Token,
+		&sub.SourceChain,
+		&sub.DestinationChain,
+		&sub.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// scanWebhookDelivery scans a delivery, followed by the subscription endpoint if withEndpoint is set
+func scanWebhookDelivery(row rowScanner, withEndpoint bool) (*models.WebhookDelivery, error) {
+	var (
+		delivery    models.WebhookDelivery
+		payload     []byte
+		deliveredAt sql.NullTime
+	)
+
+	dest := []any{
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventCursor,
+		&delivery.EventType,
+		&delivery.IntentID,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&delivery.LastStatusCode,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	}
+	if withEndpoint {
+		dest = append(dest, &delivery.URL, &delivery.Secret)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWebhookDeliveries(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	deliveries := []*models.WebhookDelivery{{
+		SubscriptionID: 1,
+		EventCursor:    11,
+		EventType:      models.IntentEventCreated,
+		IntentID:       "0x01",
+		Payload:        []byte(`{"cursor":11}`),
+	}}
+
+	// Setup expectations, a delivery already queued for the subscription is skipped
+	mock.ExpectExec(`INSERT INTO webhook_deliveries .* ON CONFLICT \(subscription_id, event_cursor\) DO NOTHING`).
+		WithArgs(int64(1), int64(11), models.IntentEventCreated, "0x01", []byte(`{"cursor":11}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Run test
+	err := postgresDB.CreateWebhookDeliveries(context.Background(), deliveries)
+	assert.NoError(t, err)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordWebhookAttempt(t *testing.T) {
+	postgresDB, mock := setupTestDB(t)
+	defer func() {
+		if err := postgresDB.Close(); err != nil {
+			log.Printf("failed to close: %v", err)
+		}
+	}()
+
+	delivery := &models.WebhookDelivery{
+		ID:             42,
+		SubscriptionID: 1,
+		EventType:      models.IntentEventCreated,
+		Payload:        []byte(`{}`),
+		Status:         models.WebhookDeliveryDead,
+		Attempts:       8,
+		LastError:      "unexpected status code 500",
+		LastStatusCode: 500,
+	}
+
+	// Setup expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO webhook_delivery_attempts`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE webhook_deliveries`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO webhook_dead_letters`).
+		WithArgs(int64(42), int64(1), models.IntentEventCreated, []byte(`{}`), 8, "unexpected status code 500").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Run test
+	err := postgresDB.RecordWebhookAttempt(context.Background(), delivery, &models.WebhookDeliveryAttempt{
+		DeliveryID: 42,
+		Attempt:    8,
+		StatusCode: 500,
+		Error:      "unexpected status code 500",
+	})
+	assert.NoError(t, err)
+
+	// Verify expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
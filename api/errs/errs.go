@@ -0,0 +1,135 @@
+// Package errs defines the domain error taxonomy shared by the db, services and http layers.
+//
+// Errors carry a Kind that callers branch on with errors.Is against the sentinels
+// (errors.Is(err, errs.ErrNotFound)) or errors.As to *Error, instead of matching messages.
+// The kind is also the stable, machine-readable code returned by the API.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error, its value is the error code exposed to API clients
+type Kind string
+
+const (
+	KindNotFound     Kind = "not_found"
+	KindDuplicate    Kind = "duplicate"
+	KindValidation   Kind = "invalid_argument"
+	KindUnauthorized Kind = "unauthorized"
+	KindUpstream     Kind = "upstream_error"
+	KindTimeout      Kind = "timeout"
+	KindUnavailable  Kind = "unavailable"
+	KindInternal     Kind = "internal"
+)
+
+// Sentinels matching any error of their kind with errors.Is
+var (
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrDuplicate    = &Error{Kind: KindDuplicate}
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrUpstream     = &Error{Kind: KindUpstream}
+	ErrTimeout      = &Error{Kind: KindTimeout}
+	ErrUnavailable  = &Error{Kind: KindUnavailable}
+)
+
+// Error is an error of a given kind with an optional message and cause
+type Error struct {
+	Kind Kind
+	Msg  string
+	Err  error
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = e.Kind.message()
+	}
+
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel of the error kind
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Msg == "" && t.Err == nil && t.Kind == e.Kind
+}
+
+// New returns an error of the given kind
+func New(kind Kind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an error of the given kind caused by err. Returns nil if err is nil.
+func Wrap(kind Kind, err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...), Err: err}
+}
+
+// NotFound returns a not found error
+func NotFound(format string, args ...any) *Error {
+	return New(KindNotFound, format, args...)
+}
+
+// Validation returns a validation error
+func Validation(format string, args ...any) *Error {
+	return New(KindValidation, format, args...)
+}
+
+// Upstream wraps an error of an upstream dependency such as an RPC node.
+// Deadline errors are classified as timeouts.
+func Upstream(err error, format string, args ...any) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Wrap(KindTimeout, err, format, args...)
+	}
+	return Wrap(KindUpstream, err, format, args...)
+}
+
+// KindOf returns the kind of the first typed error in the chain of err.
+// Untyped deadline errors are timeouts, other untyped errors are internal.
+func KindOf(err error) Kind {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e.Kind
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	default:
+		return KindInternal
+	}
+}
+
+func (k Kind) message() string {
+	switch k {
+	case KindNotFound:
+		return "not found"
+	case KindDuplicate:
+		return "duplicate"
+	case KindValidation:
+		return "invalid argument"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindUpstream:
+		return "upstream error"
+	case KindTimeout:
+		return "timeout"
+	case KindUnavailable:
+		return "unavailable"
+	default:
+		return "internal error"
+	}
+}
@@ -0,0 +1,59 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	t.Run("sentinels match errors of their kind", func(t *testing.T) {
+		err := fmt.Errorf("failed to get intent: %w", NotFound("intent %s", "0x01"))
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrDuplicate)
+		assert.Equal(t, "failed to get intent: intent 0x01", err.Error())
+	})
+
+	t.Run("sentinels keep their message", func(t *testing.T) {
+		assert.Equal(t, "not found", ErrNotFound.Error())
+		assert.Equal(t, "wrapped: duplicate", fmt.Errorf("wrapped: %w", ErrDuplicate).Error())
+	})
+
+	t.Run("wrap keeps the cause", func(t *testing.T) {
+		cause := errors.New("connection refused")
+		err := Wrap(KindUpstream, cause, "failed to get block %d", 10)
+
+		assert.ErrorIs(t, err, cause)
+		assert.ErrorIs(t, err, ErrUpstream)
+		assert.Equal(t, "failed to get block 10: connection refused", err.Error())
+		assert.Nil(t, Wrap(KindUpstream, nil, "no error"))
+	})
+
+	t.Run("as exposes the kind", func(t *testing.T) {
+		var e *Error
+		assert.True(t, errors.As(fmt.Errorf("outer: %w", Validation("invalid amount")), &e))
+		assert.Equal(t, KindValidation, e.Kind)
+	})
+}
+
+func TestKindOf(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		kind Kind
+	}{
+		{"typed", fmt.Errorf("outer: %w", ErrDuplicate), KindDuplicate},
+		{"upstream", Upstream(errors.New("connection refused"), "rpc"), KindUpstream},
+		{"upstream deadline", Upstream(context.DeadlineExceeded, "rpc"), KindTimeout},
+		{"untyped deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), KindTimeout},
+		{"untyped", errors.New("boom"), KindInternal},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, KindOf(tt.err))
+		})
+	}
+}
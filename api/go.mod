@@ -17,6 +17,7 @@ require (
 	github.com/tidwall/gjson v1.18.0
 	golang.org/x/sync v0.16.0
 	gopkg.in/h2non/gentleman.v2 v2.0.5
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -77,6 +78,5 @@ require (
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	rsc.io/tmplfunc v0.0.3 // indirect
 )
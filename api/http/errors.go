@@ -4,8 +4,21 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/speedrun-hq/speedrun/api/errs"
 )
 
+// statusByKind maps the domain error kinds to their HTTP status
+var statusByKind = map[errs.Kind]int{
+	errs.KindNotFound:     http.StatusNotFound,
+	errs.KindDuplicate:    http.StatusConflict,
+	errs.KindValidation:   http.StatusBadRequest,
+	errs.KindUnauthorized: http.StatusUnauthorized,
+	errs.KindUpstream:     http.StatusBadGateway,
+	errs.KindTimeout:      http.StatusGatewayTimeout,
+	errs.KindUnavailable:  http.StatusServiceUnavailable,
+	errs.KindInternal:     http.StatusInternalServerError,
+}
+
 func ErrNotFound(c *gin.Context, err error) {
 	Err(c, http.StatusNotFound, err)
 }
@@ -18,6 +31,28 @@ func ErrInternalServerError(c *gin.Context, err error) {
 	Err(c, http.StatusInternalServerError, err)
 }
 
+// ErrFrom responds with the HTTP status of the domain error kind, untyped errors are internal errors
+func ErrFrom(c *gin.Context, err error) {
+	Err(c, statusByKind[errs.KindOf(err)], err)
+}
+
+// Err responds with the error message and a stable machine-readable code clients can branch on
 func Err(c *gin.Context, code int, err error) {
-	c.JSON(code, gin.H{"error": err.Error()})
+	c.JSON(code, gin.H{"error": err.Error(), "code": ErrorCode(code, err)})
+}
+
+// ErrorCode returns the code of an error response: the kind of the error if it matches
+// the status, otherwise the kind the status stands for
+func ErrorCode(status int, err error) errs.Kind {
+	if kind := errs.KindOf(err); statusByKind[kind] == status {
+		return kind
+	}
+
+	for kind, kindStatus := range statusByKind {
+		if kindStatus == status {
+			return kind
+		}
+	}
+
+	return errs.KindInternal
 }
@@ -1,12 +1,14 @@
 package http
 
 import (
+	"crypto/subtle"
 	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/errs"
 )
 
 const slowRequestThreshold = 500 * time.Millisecond
@@ -57,3 +59,18 @@ func CORS(allowedOrigins string) gin.HandlerFunc {
 
 	return cors.New(config)
 }
+
+// AdminAuth restricts routes to the requests bearing the admin token as "Authorization: Bearer <token>".
+// Every request is rejected if the token is empty.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			ErrFrom(c, errs.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // IntentInitiatedEvent represents the event emitted when a new intent is created
@@ -21,7 +21,10 @@ type IntentInitiatedEvent struct {
 	Salt        *big.Int `json:"salt"`     // Salt used for intent ID generation
 	ChainID     uint64   `json:"chainId"`  // Source chain ID
 	BlockNumber uint64   `json:"blockNumber"`
+	BlockHash   string   `json:"blockHash"`
 	TxHash      string   `json:"txHash"`
+	LogIndex    uint     `json:"logIndex"`
+	Contract    string   `json:"contract"`
 	Sender      string   `json:"sender"` // Sender address that initiated the intent
 	IsCall      bool     `json:"isCall"` // Whether this is a call intent
 	Data        []byte   `json:"data"`   // Call data if this is a call intent
@@ -33,8 +36,12 @@ type IntentFulfilledEvent struct {
 	Asset       string
 	Amount      *big.Int
 	Receiver    string
+	ChainID     uint64 // Chain the intent was fulfilled on
 	BlockNumber uint64
+	BlockHash   string
 	TxHash      string
+	LogIndex    uint
+	Contract    string // Address of the contract emitting the event
 	IsCall      bool   // Whether this is a call intent
 	Data        []byte // Call data if this is a call intent
 }
@@ -48,14 +55,24 @@ type IntentSettledEvent struct {
 	Fulfiller    string
 	ActualAmount *big.Int
 	PaidTip      *big.Int
+	ChainID      uint64 // Chain the intent was settled on
 	BlockNumber  uint64
+	BlockHash    string
 	TxHash       string
+	LogIndex     uint
+	Contract     string // Address of the contract emitting the event
 	IsCall       bool   // Whether this is a call intent
 	Data         []byte // Call data if this is a call intent
 }
 
+// BlockFetcher fetches the blocks used to timestamp events; satisfied by evm.Client
+type BlockFetcher interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
 // ToIntent converts an IntentInitiatedEvent to an Intent
-func (e *IntentInitiatedEvent) ToIntent(client *ethclient.Client, ctx ...context.Context) (*Intent, error) {
+func (e *IntentInitiatedEvent) ToIntent(client BlockFetcher, ctx ...context.Context) (*Intent, error) {
 	// Convert big.Int to string for amount and tip
 	amount := e.Amount.String()
 	tip := e.Tip.String()
@@ -114,6 +131,11 @@ func (e *IntentInitiatedEvent) ToIntent(client *ethclient.Client, ctx ...context
 		CreatedAt:        timestamp,
 		UpdatedAt:        timestamp,
 		IsCall:           e.IsCall,
+		BlockNumber:      e.BlockNumber,
+		BlockHash:        e.BlockHash,
+		TxHash:           e.TxHash,
+		LogIndex:         e.LogIndex,
+		Contract:         e.Contract,
 	}
 
 	// Set call data if present
@@ -125,7 +147,7 @@ func (e *IntentInitiatedEvent) ToIntent(client *ethclient.Client, ctx ...context
 }
 
 // ToFulfillment converts an IntentFulfilledEvent to a Fulfillment
-func (e *IntentFulfilledEvent) ToFulfillment(client *ethclient.Client, ctx ...context.Context) (*Fulfillment, error) {
+func (e *IntentFulfilledEvent) ToFulfillment(client BlockFetcher, ctx ...context.Context) (*Fulfillment, error) {
 	amount := e.Amount.String()
 
 	// Get block timestamp
@@ -164,8 +186,12 @@ func (e *IntentFulfilledEvent) ToFulfillment(client *ethclient.Client, ctx ...co
 		Asset:       e.Asset,
 		Amount:      amount,
 		Receiver:    e.Receiver,
+		ChainID:     e.ChainID,
 		BlockNumber: e.BlockNumber,
+		BlockHash:   e.BlockHash,
 		TxHash:      e.TxHash,
+		LogIndex:    e.LogIndex,
+		Contract:    e.Contract,
 		CreatedAt:   timestamp,
 		UpdatedAt:   timestamp,
 		IsCall:      e.IsCall,
@@ -180,7 +206,7 @@ func (e *IntentFulfilledEvent) ToFulfillment(client *ethclient.Client, ctx ...co
 }
 
 // ToSettlement converts an IntentSettledEvent to a Settlement
-func (e *IntentSettledEvent) ToSettlement(client *ethclient.Client, ctx ...context.Context) (*Settlement, error) {
+func (e *IntentSettledEvent) ToSettlement(client BlockFetcher, ctx ...context.Context) (*Settlement, error) {
 	// Get block timestamp
 	var timestamp time.Time
 	if client != nil {
@@ -221,8 +247,12 @@ func (e *IntentSettledEvent) ToSettlement(client *ethclient.Client, ctx ...conte
 		Fulfiller:    e.Fulfiller,
 		ActualAmount: e.ActualAmount.String(),
 		PaidTip:      e.PaidTip.String(),
+		ChainID:      e.ChainID,
 		BlockNumber:  e.BlockNumber,
+		BlockHash:    e.BlockHash,
 		TxHash:       e.TxHash,
+		LogIndex:     e.LogIndex,
+		Contract:     e.Contract,
 		CreatedAt:    timestamp,
 		UpdatedAt:    timestamp,
 		IsCall:       e.IsCall,
@@ -237,7 +267,7 @@ func (e *IntentSettledEvent) ToSettlement(client *ethclient.Client, ctx ...conte
 }
 
 // fetchBlockTimestamp is a helper function to get a block timestamp with fallback methods
-func fetchBlockTimestamp(ctx context.Context, client *ethclient.Client, blockNumber uint64) (time.Time, error) {
+func fetchBlockTimestamp(ctx context.Context, client BlockFetcher, blockNumber uint64) (time.Time, error) {
 	if client == nil {
 		return time.Time{}, fmt.Errorf("no client provided")
 	}
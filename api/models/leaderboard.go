@@ -0,0 +1,100 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// LeaderboardSort represents the metric runners are ranked by
+type LeaderboardSort string
+
+const (
+	// LeaderboardSortVolume ranks runners by total transferred volume
+	LeaderboardSortVolume LeaderboardSort = "volume"
+
+	// LeaderboardSortTransfers ranks runners by number of settled transfers
+	LeaderboardSortTransfers LeaderboardSort = "transfers"
+
+	// LeaderboardSortFastest ranks runners by their fastest completion time
+	LeaderboardSortFastest LeaderboardSort = "fastest"
+)
+
+// IsValid checks whether the sort is one of the supported values
+func (s LeaderboardSort) IsValid() bool {
+	switch s {
+	case LeaderboardSortVolume, LeaderboardSortTransfers, LeaderboardSortFastest:
+		return true
+	default:
+		return false
+	}
+}
+
+// LeaderboardQuery represents the filters applied when building a leaderboard
+type LeaderboardQuery struct {
+	ChainID  uint64
+	SortBy   LeaderboardSort
+	Since    time.Time // zero value means no lower bound
+	Until    time.Time // zero value means no upper bound
+	Page     int
+	PageSize int
+}
+
+// HasTimeWindow returns true if the query is restricted to a time window
+func (q LeaderboardQuery) HasTimeWindow() bool {
+	return !q.Since.IsZero() || !q.Until.IsZero()
+}
+
+// LeaderboardEntry represents a single row of the leaderboard_view, the volume is in USD
+type LeaderboardEntry struct {
+	Address                      string
+	ChainID                      uint64
+	TotalTransfers               int64
+	TotalVolume                  string
+	AvgCompletionTimeSeconds     float64
+	FastestCompletionTimeSeconds float64
+	LastTransferTime             time.Time
+}
+
+// LeaderboardResponse represents a runner as returned by the API
+type LeaderboardResponse struct {
+	Rank           int    `json:"rank"`
+	Address        string `json:"address"`
+	ChainID        uint64 `json:"chain_id"`
+	Score          string `json:"score"`
+	TotalTransfers int64  `json:"total_transfers"`
+	TotalVolume    string `json:"total_volume"`
+	AverageTime    string `json:"average_time"`
+	FastestTime    string `json:"fastest_time"`
+	LastTransfer   string `json:"last_transfer"`
+}
+
+// ToResponse converts a LeaderboardEntry to a LeaderboardResponse.
+// Score holds the value of the metric the leaderboard is ranked by.
+func (e *LeaderboardEntry) ToResponse(rank int, sortBy LeaderboardSort) *LeaderboardResponse {
+	var (
+		avg     = strconv.FormatFloat(e.AvgCompletionTimeSeconds, 'f', 2, 64)
+		fastest = strconv.FormatFloat(e.FastestCompletionTimeSeconds, 'f', 2, 64)
+		score   string
+	)
+
+	switch sortBy {
+	case LeaderboardSortTransfers:
+		score = strconv.FormatInt(e.TotalTransfers, 10)
+	case LeaderboardSortFastest:
+		score = fastest
+	default:
+		score = e.TotalVolume
+	}
+
+	return &LeaderboardResponse{
+		Rank:           rank,
+		Address:        e.Address,
+		ChainID:        e.ChainID,
+		Score:          score,
+		TotalTransfers: e.TotalTransfers,
+		TotalVolume:    e.TotalVolume,
+		AverageTime:    avg,
+		FastestTime:    fastest,
+		LastTransfer:   e.LastTransferTime.Format(time.RFC3339),
+	}
+}
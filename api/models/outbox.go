@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Outbox topics of the intent events
+const (
+	OutboxTopicIntentInitiated = "intent.initiated"
+	OutboxTopicIntentFulfilled = "intent.fulfilled"
+	OutboxTopicIntentSettled   = "intent.settled"
+	OutboxTopicIntentExpired   = "intent.expired"
+)
+
+// OutboxMessage is the notification of a persisted event. It's written in the transaction persisting
+// the event, so that a notification is dispatched downstream if and only if the event was committed.
+// The payload of the intent topics is the status transition of the intent.
+type OutboxMessage struct {
+	ID             int64
+	Topic          string
+	Payload        json.RawMessage
+	CreatedAt      time.Time
+	DispatchedAt   *time.Time
+	DispatchCursor int64 // position of the message in the dispatch order, the cursor of its intent event
+}
@@ -48,3 +48,22 @@ func NewPaginatedResponse(data any, page, pageSize, totalCount int) *PaginatedRe
 		TotalPages: totalPages,
 	}
 }
+
+// CursorPaginatedResponse represents a page of a keyset paginated response,
+// the next page is requested with the cursor of the response
+type CursorPaginatedResponse struct {
+	Data       any    `json:"data"`
+	PageSize   int    `json:"page_size"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// CreateWebhookRequest represents the request body for registering a webhook subscription
+type CreateWebhookRequest struct {
+	URL              string `json:"url"               binding:"required"`
+	Sender           string `json:"sender"`
+	Recipient        string `json:"recipient"`
+	Token            string `json:"token"`
+	SourceChain      uint64 `json:"source_chain"`
+	DestinationChain uint64 `json:"destination_chain"`
+}
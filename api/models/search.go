@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// IntentSort represents the field intents are sorted by in a search
+type IntentSort string
+
+const (
+	// IntentSortCreatedAt sorts intents by creation time
+	IntentSortCreatedAt IntentSort = "created_at"
+
+	// IntentSortAmount sorts intents by amount in base units of their token
+	IntentSortAmount IntentSort = "amount"
+)
+
+// IsValid checks whether the sort is one of the supported values
+func (s IntentSort) IsValid() bool {
+	switch s {
+	case IntentSortCreatedAt, IntentSortAmount:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortOrder represents the direction of a sort
+type SortOrder string
+
+const (
+	// SortOrderDesc sorts from the largest value, the default
+	SortOrderDesc SortOrder = "desc"
+
+	// SortOrderAsc sorts from the smallest value
+	SortOrderAsc SortOrder = "asc"
+)
+
+// IsValid checks whether the order is one of the supported values
+func (o SortOrder) IsValid() bool {
+	return o == SortOrderDesc || o == SortOrderAsc
+}
+
+// IntentQuery represents the filters, sort and page of an intent search, zero values don't filter
+type IntentQuery struct {
+	SourceChain      uint64
+	DestinationChain uint64
+	Token            string
+	Sender           string
+	Recipient        string
+	Statuses         []IntentStatus // any of the statuses
+	IsCall           *bool
+	MinAmount        *big.Int  // inclusive, in base units of the token
+	MaxAmount        *big.Int  // inclusive, in base units of the token
+	Since            time.Time // zero value means no lower bound
+	Until            time.Time // zero value means no upper bound
+
+	SortBy   IntentSort
+	Order    SortOrder
+	After    *IntentCursor // last intent of the previous page, nil for the first page
+	PageSize int
+}
+
+// IntentCursor is the position of an intent in the sort order of a search, used for keyset pagination
+type IntentCursor struct {
+	SortBy    IntentSort `json:"s"`
+	CreatedAt time.Time  `json:"t,omitempty"`
+	Amount    string     `json:"a,omitempty"`
+	ID        string     `json:"id"`
+}
+
+// NewIntentCursor returns the cursor of an intent in a search sorted by a field
+func NewIntentCursor(intent *Intent, sortBy IntentSort) *IntentCursor {
+	cursor := &IntentCursor{SortBy: sortBy, ID: intent.ID}
+	if sortBy == IntentSortAmount {
+		cursor.Amount = intent.Amount
+	} else {
+		cursor.CreatedAt = intent.CreatedAt
+	}
+	return cursor
+}
+
+// Encode returns the opaque string representation of the cursor
+func (c *IntentCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseIntentCursor decodes a cursor returned by Encode, it must come from a search with the same sort
+func ParseIntentCursor(raw string, sortBy IntentSort) (*IntentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor IntentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, errors.New("invalid cursor")
+	}
+
+	if cursor.SortBy != sortBy {
+		return nil, errors.New("cursor doesn't match the sort")
+	}
+
+	if sortBy == IntentSortAmount {
+		if _, ok := new(big.Int).SetString(cursor.Amount, 10); !ok {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+
+	return &cursor, nil
+}
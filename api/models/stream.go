@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// IntentEventCreated is the type of the event published when an intent is indexed.
+// Other event types are the status the intent moved to (fulfilled, settled, ...).
+const IntentEventCreated = "created"
+
+// IntentEvent is an intent status change published on the stream.
+// Cursor is the position of the event in the intent status history and is used to resume the stream.
+type IntentEvent struct {
+	Cursor           int64        `json:"cursor"`
+	Type             string       `json:"type"`
+	IntentID         string       `json:"intent_id"`
+	Status           IntentStatus `json:"status"`
+	FromStatus       IntentStatus `json:"from_status,omitempty"`
+	SourceChain      uint64       `json:"source_chain"`
+	DestinationChain uint64       `json:"destination_chain"`
+	Token            string       `json:"token"`
+	Amount           string       `json:"amount"`
+	Sender           string       `json:"sender"`
+	Recipient        string       `json:"recipient"`
+	ChainID          uint64       `json:"chain_id,omitempty"`
+	BlockNumber      uint64       `json:"block_number,omitempty"`
+	TxHash           string       `json:"tx_hash,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+// EventType returns the stream event type of a status transition
+func EventType(from, to IntentStatus) string {
+	if from == "" {
+		return IntentEventCreated
+	}
+	return string(to)
+}
+
+// IntentEventFilter selects the intent events a stream client receives.
+// Zero values match every event, addresses and intent IDs are compared case-insensitively.
+type IntentEventFilter struct {
+	IntentID         string
+	Sender           string
+	Recipient        string
+	Token            string
+	SourceChain      uint64
+	DestinationChain uint64
+}
+
+// Matches returns true if the event passes the filter
+func (f IntentEventFilter) Matches(e *IntentEvent) bool {
+	switch {
+	case f.IntentID != "" && !strings.EqualFold(f.IntentID, e.IntentID):
+		return false
+	case f.Sender != "" && !strings.EqualFold(f.Sender, e.Sender):
+		return false
+	case f.Recipient != "" && !strings.EqualFold(f.Recipient, e.Recipient):
+		return false
+	case f.Token != "" && !strings.EqualFold(f.Token, e.Token):
+		return false
+	case f.SourceChain != 0 && f.SourceChain != e.SourceChain:
+		return false
+	case f.DestinationChain != 0 && f.DestinationChain != e.DestinationChain:
+		return false
+	default:
+		return true
+	}
+}
@@ -1,16 +1,35 @@
 package models
 
 import (
+	"math/big"
 	"time"
 )
 
-// Chain represents a supported blockchain network
+// Chain represents a supported blockchain network and its indexing progress
 type Chain struct {
 	ID          uint64 `json:"id"`
 	Name        string `json:"name"`
-	NetworkID   string `json:"network_id"`
-	RPCURL      string `json:"rpc_url"`
 	ExplorerURL string `json:"explorer_url"`
+	Contract    string `json:"contract"`
+
+	// State of the live ingestion, the polling flag tells which of the hybrid mode is in use
+	IngestionMode string `json:"ingestion_mode"`
+	Polling       bool   `json:"polling"`
+	Healthy       bool   `json:"healthy"`
+
+	// Latest block of the chain and last block whose events are all indexed, 0 until known
+	HeadBlock        uint64 `json:"head_block"`
+	LastIndexedBlock uint64 `json:"last_indexed_block"`
+}
+
+// ExplorerLinks are the block explorer URLs of the transaction and addresses of an indexed entity,
+// links of chains without explorer are left empty
+type ExplorerLinks struct {
+	Tx        string `json:"tx,omitempty"`
+	Sender    string `json:"sender,omitempty"`
+	Recipient string `json:"recipient,omitempty"`
+	Token     string `json:"token,omitempty"`
+	Fulfiller string `json:"fulfiller,omitempty"`
 }
 
 // Token represents a supported token on a chain
@@ -20,6 +39,10 @@ type Token struct {
 	Decimals int    `json:"decimals"`
 	ChainID  uint64 `json:"chain_id"`
 	LogoURL  string `json:"logo_url,omitempty"`
+	AssetID  string `json:"asset_id,omitempty"` // identifies the token across chains, e.g. "usdc"
+
+	// MaxAmount is the largest amount of an intent in base units
+	MaxAmount *big.Int `json:"-"`
 }
 
 // Intent represents a cross-chain transfer intent
@@ -37,6 +60,31 @@ type Intent struct {
 	UpdatedAt        time.Time    `json:"updated_at"`
 	IsCall           bool         `json:"is_call"`
 	CallData         string       `json:"call_data,omitempty"`
+
+	// Log of the source chain the intent was initiated by, unset for intents created through the API
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	BlockHash   string `json:"block_hash,omitempty"`
+	TxHash      string `json:"tx_hash,omitempty"`
+	LogIndex    uint   `json:"log_index"`
+	Contract    string `json:"contract,omitempty"`
+
+	// USD values of the amount and fee at the creation of the intent, empty if the token price was unknown
+	AmountUSD    string `json:"amount_usd,omitempty"`
+	IntentFeeUSD string `json:"intent_fee_usd,omitempty"`
+
+	// Fills of the intent and their cumulative amount, set when serving a single intent
+	FilledAmount string         `json:"filled_amount,omitempty"`
+	Fulfillments []*Fulfillment `json:"fulfillments,omitempty"`
+
+	// Confirmation state of the source chain block and explorer links, set when serving the intent
+	Confirmations *uint64        `json:"confirmations,omitempty"`
+	Finalized     *bool          `json:"finalized,omitempty"`
+	Explorer      *ExplorerLinks `json:"explorer,omitempty"`
+
+	// Token of the source chain and amounts in whole tokens, set when serving the intent if the token is known
+	TokenInfo          *Token `json:"token_info,omitempty"`
+	AmountFormatted    string `json:"amount_formatted,omitempty"`
+	IntentFeeFormatted string `json:"intent_fee_formatted,omitempty"`
 }
 
 // IntentStatus represents the possible states of an intent
@@ -49,13 +97,121 @@ const (
 	// IntentStatusPending indicates the intent has been initiated but not yet fulfilled
 	IntentStatusPending IntentStatus = "pending"
 
+	// IntentStatusPartiallyFulfilled indicates the fills of the intent on the target chain
+	// don't cover the requested amount yet
+	IntentStatusPartiallyFulfilled IntentStatus = "partially_fulfilled"
+
 	// IntentStatusFulfilled indicates the intent has been fulfilled on the target chain
 	IntentStatusFulfilled IntentStatus = "fulfilled"
 
 	// IntentStatusSettled indicates the intent has been settled on the target chain
 	IntentStatusSettled IntentStatus = "settled"
+
+	// IntentStatusSettledWithoutFulfillment indicates the intent has been settled without being fulfilled,
+	// the receiver got the transferred amount directly
+	IntentStatusSettledWithoutFulfillment IntentStatus = "settled_without_fulfillment"
+
+	// IntentStatusExpired indicates the intent has been pending for longer than the expiry timeout
+	IntentStatusExpired IntentStatus = "expired"
+
+	// IntentStatusRefunded indicates the intent amount has been refunded to the sender
+	IntentStatusRefunded IntentStatus = "refunded"
 )
 
+// intentStatusTransitions lists the statuses each status can move to
+var intentStatusTransitions = map[IntentStatus][]IntentStatus{
+	IntentStatusPending: {
+		IntentStatusPartiallyFulfilled,
+		IntentStatusFulfilled,
+		IntentStatusSettled,
+		IntentStatusSettledWithoutFulfillment,
+		IntentStatusExpired,
+		IntentStatusRefunded,
+	},
+	IntentStatusPartiallyFulfilled: {
+		IntentStatusFulfilled,
+		IntentStatusSettled,
+		IntentStatusSettledWithoutFulfillment,
+	},
+	IntentStatusFulfilled: {
+		IntentStatusSettled,
+	},
+	// an expired intent can still be fulfilled or settled late
+	IntentStatusExpired: {
+		IntentStatusPartiallyFulfilled,
+		IntentStatusFulfilled,
+		IntentStatusSettled,
+		IntentStatusSettledWithoutFulfillment,
+		IntentStatusRefunded,
+	},
+}
+
+// IsValid returns true if the status is a known intent status
+func (s IntentStatus) IsValid() bool {
+	switch s {
+	case IntentStatusPending,
+		IntentStatusPartiallyFulfilled,
+		IntentStatusFulfilled,
+		IntentStatusSettled,
+		IntentStatusSettledWithoutFulfillment,
+		IntentStatusExpired,
+		IntentStatusRefunded:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinal returns true if the intent can't move out of the status
+func (s IntentStatus) IsFinal() bool {
+	return s.IsValid() && len(intentStatusTransitions[s]) == 0
+}
+
+// CanTransitionTo returns true if an intent can move from the status to the next one
+func (s IntentStatus) CanTransitionTo(next IntentStatus) bool {
+	for _, allowed := range intentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// SettlementStatus returns the intent status a settlement moves its intent to
+func SettlementStatus(fulfilled bool) IntentStatus {
+	if fulfilled {
+		return IntentStatusSettled
+	}
+	return IntentStatusSettledWithoutFulfillment
+}
+
+// FulfillmentStatus returns the intent status the fills of an intent move it to,
+// from their cumulative amount and the requested amount.
+// Amounts that can't be compared count as fulfilled, as before intents could be partially filled.
+func FulfillmentStatus(filledAmount, amount string) IntentStatus {
+	filled, ok := new(big.Rat).SetString(filledAmount)
+	if !ok {
+		return IntentStatusFulfilled
+	}
+	requested, ok := new(big.Rat).SetString(amount)
+	if !ok || filled.Cmp(requested) >= 0 {
+		return IntentStatusFulfilled
+	}
+	return IntentStatusPartiallyFulfilled
+}
+
+// IntentStatusTransition records a status change of an intent and the event that caused it
+type IntentStatusTransition struct {
+	IntentID    string       `json:"intent_id"`
+	FromStatus  IntentStatus `json:"from_status,omitempty"`
+	ToStatus    IntentStatus `json:"to_status"`
+	ChainID     uint64       `json:"chain_id,omitempty"`
+	BlockNumber uint64       `json:"block_number,omitempty"`
+	BlockHash   string       `json:"block_hash,omitempty"`
+	TxHash      string       `json:"tx_hash,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
 // ToResponse converts an Intent to an IntentResponse
 func (e *Intent) ToResponse() *IntentResponse {
 	return &IntentResponse{
@@ -69,6 +225,15 @@ func (e *Intent) ToResponse() *IntentResponse {
 		Status:           string(e.Status),
 		CreatedAt:        e.CreatedAt,
 		UpdatedAt:        e.UpdatedAt,
+		Confirmations:    e.Confirmations,
+		Finalized:        e.Finalized,
+		Explorer:         e.Explorer,
+
+		TokenInfo:          e.TokenInfo,
+		AmountFormatted:    e.AmountFormatted,
+		IntentFeeFormatted: e.IntentFeeFormatted,
+		AmountUSD:          e.AmountUSD,
+		IntentFeeUSD:       e.IntentFeeUSD,
 	}
 }
 
@@ -84,20 +249,42 @@ type IntentResponse struct {
 	Status           string    `json:"status"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
+	Confirmations    *uint64   `json:"confirmations,omitempty"`
+	Finalized        *bool     `json:"finalized,omitempty"`
+
+	Explorer *ExplorerLinks `json:"explorer,omitempty"`
+
+	TokenInfo          *Token `json:"token_info,omitempty"`
+	AmountFormatted    string `json:"amount_formatted,omitempty"`
+	IntentFeeFormatted string `json:"intent_fee_formatted,omitempty"`
+	AmountUSD          string `json:"amount_usd,omitempty"`
+	IntentFeeUSD       string `json:"intent_fee_usd,omitempty"`
 }
 
-// Fulfillment represents a fulfillment of an intent
+// Fulfillment represents a fill of an intent, an intent can be filled by several fulfillments
+// identified by their transaction hash and log index. ID is the ID of the filled intent.
 type Fulfillment struct {
 	ID          string    `json:"id"`
 	Asset       string    `json:"asset"`
 	Amount      string    `json:"amount"`
 	Receiver    string    `json:"receiver"`
+	ChainID     uint64    `json:"chain_id"`
 	BlockNumber uint64    `json:"block_number"`
+	BlockHash   string    `json:"block_hash,omitempty"`
 	TxHash      string    `json:"tx_hash"`
+	LogIndex    uint      `json:"log_index"`
+	Contract    string    `json:"contract,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	IsCall      bool      `json:"is_call"`
 	CallData    string    `json:"call_data,omitempty"`
+
+	// Explorer links, set when serving the fulfillment
+	Explorer *ExplorerLinks `json:"explorer,omitempty"`
+
+	// Asset and amount in whole tokens, set when serving the fulfillment if the asset is known
+	AssetInfo       *Token `json:"asset_info,omitempty"`
+	AmountFormatted string `json:"amount_formatted,omitempty"`
 }
 
 // Settlement represents a settlement of an intent
@@ -110,10 +297,157 @@ type Settlement struct {
 	Fulfiller    string    `json:"fulfiller"`
 	ActualAmount string    `json:"actual_amount"`
 	PaidTip      string    `json:"paid_tip"`
+	ChainID      uint64    `json:"chain_id"`
 	BlockNumber  uint64    `json:"block_number"`
+	BlockHash    string    `json:"block_hash,omitempty"`
 	TxHash       string    `json:"tx_hash"`
+	LogIndex     uint      `json:"log_index"`
+	Contract     string    `json:"contract,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	IsCall       bool      `json:"is_call"`
 	CallData     string    `json:"call_data,omitempty"`
+
+	// Explorer links, set when serving the settlement
+	Explorer *ExplorerLinks `json:"explorer,omitempty"`
+
+	// Asset and amounts in whole tokens, set when serving the settlement if the asset is known
+	AssetInfo             *Token `json:"asset_info,omitempty"`
+	AmountFormatted       string `json:"amount_formatted,omitempty"`
+	ActualAmountFormatted string `json:"actual_amount_formatted,omitempty"`
+	PaidTipFormatted      string `json:"paid_tip_formatted,omitempty"`
+}
+
+// StatusTransition returns the initial status of the intent as a status transition
+func (e *Intent) StatusTransition() *IntentStatusTransition {
+	return &IntentStatusTransition{
+		IntentID:    e.ID,
+		ToStatus:    e.Status,
+		ChainID:     e.SourceChain,
+		BlockNumber: e.BlockNumber,
+		BlockHash:   e.BlockHash,
+		TxHash:      e.TxHash,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+// StatusTransition returns the status transition of the intent caused by the fulfillment on the given chain,
+// status is the status derived from the fills of the intent
+func (f *Fulfillment) StatusTransition(chainID uint64, status IntentStatus) *IntentStatusTransition {
+	return &IntentStatusTransition{
+		IntentID:    f.ID,
+		ToStatus:    status,
+		ChainID:     chainID,
+		BlockNumber: f.BlockNumber,
+		BlockHash:   f.BlockHash,
+		TxHash:      f.TxHash,
+		CreatedAt:   f.CreatedAt,
+	}
+}
+
+// StatusTransition returns the status transition of the intent caused by the settlement on the given chain
+func (s *Settlement) StatusTransition(chainID uint64) *IntentStatusTransition {
+	return &IntentStatusTransition{
+		IntentID:    s.ID,
+		ToStatus:    SettlementStatus(s.Fulfilled),
+		ChainID:     chainID,
+		BlockNumber: s.BlockNumber,
+		BlockHash:   s.BlockHash,
+		TxHash:      s.TxHash,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// SetFulfillments sets the fills of the intent and their cumulative amount
+func (e *Intent) SetFulfillments(fulfillments []*Fulfillment) {
+	filled := new(big.Rat)
+	for _, f := range fulfillments {
+		if amount, ok := new(big.Rat).SetString(f.Amount); ok {
+			filled.Add(filled, amount)
+		}
+	}
+
+	e.Fulfillments = fulfillments
+	e.FilledAmount = new(big.Float).SetPrec(256).SetRat(filled).Text('f', -1)
+}
+
+// ProcessedBlock represents the hash of a block the indexer has processed.
+// It's used to detect chain reorganizations.
+type ProcessedBlock struct {
+	ChainID     uint64
+	BlockNumber uint64
+	BlockHash   string
+}
+
+// EventCheckpoint is the last block whose events of a type emitted by a contract are indexed
+type EventCheckpoint struct {
+	ChainID         uint64
+	EventType       string
+	ContractAddress string
+	BlockNumber     uint64
+	UpdatedAt       time.Time
+}
+
+// RollbackResult summarizes the entities removed by a reorg rollback
+type RollbackResult struct {
+	ChainID          uint64
+	FromBlock        uint64
+	Intents          int64
+	Fulfillments     int64
+	Settlements      int64
+	RederivedIntents int64
+}
+
+// UnconfirmedEvent represents a raw event log staged until its block reaches
+// the confirmation depth required by its chain
+type UnconfirmedEvent struct {
+	ChainID     uint64
+	EventType   string
+	BlockNumber uint64
+	BlockHash   string
+	TxHash      string
+	LogIndex    uint
+	Log         []byte // JSON encoded log
+	Attempts    int    // failed promotions
+	LastError   string
+	CreatedAt   time.Time
+}
+
+// OrphanEvent is a fulfillment or settlement log indexed before the intent it refers to.
+// It's replayed once the intent is indexed, e.g. when the chains are caught up independently.
+type OrphanEvent struct {
+	ID          int64
+	ChainID     uint64
+	EventType   string
+	IntentID    string
+	BlockNumber uint64
+	BlockHash   string
+	TxHash      string
+	LogIndex    uint
+	Log         []byte // JSON encoded log
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+}
+
+// OrphanEventStats summarizes the orphan events of an event type on a chain
+type OrphanEventStats struct {
+	ChainID         uint64
+	EventType       string
+	Count           int
+	OldestCreatedAt time.Time
+}
+
+// SkippedRange is a block range the catchup fast-forwarded through because the contract
+// emitted no events in it. Skipped ranges are audited against the block headers later.
+type SkippedRange struct {
+	ID              int64
+	ChainID         uint64
+	EventType       string
+	ContractAddress string
+	FromBlock       uint64
+	ToBlock         uint64
+	SkippedAt       time.Time
+	VerifiedAt      *time.Time
+	MissedBlocks    []uint64 // blocks with events found by the audit
 }
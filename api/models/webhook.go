@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookDeliveryStatus is the delivery state of a webhook event
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending deliveries are waiting for their next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered deliveries were acknowledged with a 2xx response
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead deliveries exhausted their attempts and were moved to the dead letters
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookSubscription is an endpoint notified of the intent events matching its filters.
+// Empty filters match every event.
+type WebhookSubscription struct {
+	ID               int64     `json:"id"`
+	URL              string    `json:"url"`
+	Secret           string    `json:"secret,omitempty"` // only returned when the subscription is created
+	Sender           string    `json:"sender,omitempty"`
+	Recipient        string    `json:"recipient,omitempty"`
+	Token            string    `json:"token,omitempty"`
+	SourceChain      uint64    `json:"source_chain,omitempty"`
+	DestinationChain uint64    `json:"destination_chain,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Filter returns the intent event filter of the subscription
+func (s *WebhookSubscription) Filter() IntentEventFilter {
+	return IntentEventFilter{
+		Sender:           s.Sender,
+		Recipient:        s.Recipient,
+		Token:            s.Token,
+		SourceChain:      s.SourceChain,
+		DestinationChain: s.DestinationChain,
+	}
+}
+
+// WebhookDelivery is an intent event to deliver to a webhook subscription
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscription_id"`
+	EventCursor    int64                 `json:"event_cursor"`
+	EventType      string                `json:"event_type"`
+	IntentID       string                `json:"intent_id"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	LastError      string                `json:"last_error,omitempty"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+
+	// endpoint of the subscription, set when the delivery is claimed
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookDeliveryAttempt is the log of a single delivery attempt
+type WebhookDeliveryAttempt struct {
+	DeliveryID int64
+	Attempt    int
+	StatusCode int // 0 if no response was received
+	Error      string
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
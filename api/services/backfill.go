@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+)
+
+// BackfillWindowSize is the number of blocks re-indexed between two progress reports
+const BackfillWindowSize = uint64(10000)
+
+// BackfillEventTypes are the event types a backfill can re-index, in processing order:
+// fulfillments and settlements reference the intents indexed before them
+var BackfillEventTypes = []string{eventTypeIntent, eventTypeFulfillment, eventTypeSettlement}
+
+// BackfillRequest describes an inclusive block range of a chain to re-index
+type BackfillRequest struct {
+	ChainID         uint64
+	ContractAddress common.Address
+	FromBlock       uint64
+	ToBlock         uint64
+	EventTypes      []string
+}
+
+// BackfillProgress reports how far the backfill of an event type went
+type BackfillProgress struct {
+	ChainID   uint64
+	EventType string
+	FromBlock uint64
+	ToBlock   uint64
+	Block     uint64 // last re-indexed block
+}
+
+// Percent returns the share of the range already re-indexed
+func (p BackfillProgress) Percent() float64 {
+	total := p.ToBlock - p.FromBlock + 1
+	done := p.Block - p.FromBlock + 1
+	return float64(done) * 100 / float64(total)
+}
+
+// BackfillService re-indexes a block range of a chain with the catchup logic.
+// Events already indexed are skipped and the live checkpoints are never moved,
+// so a backfill is idempotent and can run alongside the server.
+type BackfillService struct {
+	catchup             *EventCatchupService
+	intentServices      map[uint64]*IntentService
+	fulfillmentServices map[uint64]*FulfillmentService
+	settlementServices  map[uint64]*SettlementService
+	logger              zerolog.Logger
+}
+
+// NewBackfillService creates a new BackfillService instance.
+// The services are dedicated to the backfill, they're set to re-index logs without moving the checkpoints.
+func NewBackfillService(
+	intentServices map[uint64]*IntentService,
+	fulfillmentServices map[uint64]*FulfillmentService,
+	settlementServices map[uint64]*SettlementService,
+	database db.Database,
+	logger zerolog.Logger,
+) *BackfillService {
+	for _, service := range intentServices {
+		service.SetBackfill()
+	}
+	for _, service := range fulfillmentServices {
+		service.SetBackfill()
+	}
+	for _, service := range settlementServices {
+		service.SetBackfill()
+	}
+
+	catchup := NewEventCatchupService(intentServices, fulfillmentServices, settlementServices, database, logger)
+	catchup.backfill = true
+
+	return &BackfillService{
+		catchup:             catchup,
+		intentServices:      intentServices,
+		fulfillmentServices: fulfillmentServices,
+		settlementServices:  settlementServices,
+		logger:              logger.With().Str(logging.FieldModule, "backfill").Logger(),
+	}
+}
+
+// ParseBackfillEventTypes parses a comma-separated list of event types.
+// The returned event types are deduplicated and in processing order.
+func ParseBackfillEventTypes(value string) ([]string, error) {
+	requested := make(map[string]bool)
+	for _, eventType := range strings.Split(value, ",") {
+		eventType = strings.TrimSpace(strings.ToLower(eventType))
+		if eventType == "" {
+			continue
+		}
+		if !isBackfillEventType(eventType) {
+			return nil, fmt.Errorf("unknown event type %q, expected one of %s",
+				eventType, strings.Join(BackfillEventTypes, ", "))
+		}
+		requested[eventType] = true
+	}
+
+	var eventTypes []string
+	for _, eventType := range BackfillEventTypes {
+		if requested[eventType] {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("no event types specified")
+	}
+
+	return eventTypes, nil
+}
+
+func isBackfillEventType(eventType string) bool {
+	for _, t := range BackfillEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Run re-indexes the requested range for each event type, reporting progress after every window
+func (b *BackfillService) Run(ctx context.Context, req BackfillRequest, onProgress func(BackfillProgress)) error {
+	if req.FromBlock > req.ToBlock {
+		return fmt.Errorf("invalid block range: from block %d is after to block %d", req.FromBlock, req.ToBlock)
+	}
+
+	if len(req.EventTypes) == 0 {
+		return fmt.Errorf("no event types specified")
+	}
+
+	for _, eventType := range req.EventTypes {
+		catchUp, err := b.catchUpFunc(req.ChainID, eventType)
+		if err != nil {
+			return err
+		}
+
+		logger := b.logger.With().
+			Uint64(logging.FieldChain, req.ChainID).
+			Str("event_type", eventType).
+			Logger()
+
+		logger.Info().
+			Uint64("from_block", req.FromBlock).
+			Uint64("to_block", req.ToBlock).
+			Msg("Starting backfill")
+
+		// the catchup functions process the blocks after their from block
+		for windowStart := req.FromBlock; windowStart <= req.ToBlock; windowStart += BackfillWindowSize {
+			windowEnd := windowStart + BackfillWindowSize - 1
+			if windowEnd > req.ToBlock {
+				windowEnd = req.ToBlock
+			}
+
+			after := windowStart
+			if after > 0 {
+				after--
+			}
+
+			opName := fmt.Sprintf("%s_backfill_chain_%d_%d_%d", eventType, req.ChainID, windowStart, windowEnd)
+			if err := catchUp(ctx, req.ContractAddress, after, windowEnd, opName); err != nil {
+				return fmt.Errorf("failed to backfill %s events for blocks %d-%d: %v",
+					eventType, windowStart, windowEnd, err)
+			}
+
+			if onProgress != nil {
+				onProgress(BackfillProgress{
+					ChainID:   req.ChainID,
+					EventType: eventType,
+					FromBlock: req.FromBlock,
+					ToBlock:   req.ToBlock,
+					Block:     windowEnd,
+				})
+			}
+		}
+
+		logger.Info().Msg("Completed backfill")
+	}
+
+	return nil
+}
+
+type catchUpFunc func(ctx context.Context, contractAddress common.Address, fromBlock, toBlock uint64, opName string) error
+
+// catchUpFunc returns the catchup function of an event type bound to the chain's handler
+func (b *BackfillService) catchUpFunc(chainID uint64, eventType string) (catchUpFunc, error) {
+	switch eventType {
+	case eventTypeIntent, eventTypeFulfillment, eventTypeSettlement:
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	handler, ok := b.catchup.eventHandlers(eventType)[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no %s service for chain %d", eventType, chainID)
+	}
+	return func(ctx context.Context, contractAddress common.Address, fromBlock, toBlock uint64, opName string) error {
+		return b.catchup.catchUpOnEvents(ctx, handler, contractAddress, fromBlock, toBlock, opName)
+	}, nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackfillEventTypes(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		value         string
+		expected      []string
+		errorContains string
+	}{
+		{
+			name:     "all event types in processing order",
+			value:    "settlement,intent,fulfillment",
+			expected: []string{eventTypeIntent, eventTypeFulfillment, eventTypeSettlement},
+		},
+		{
+			name:     "deduplicates and trims",
+			value:    " Fulfillment, fulfillment ,",
+			expected: []string{eventTypeFulfillment},
+		},
+		{
+			name:          "unknown event type",
+			value:         "intent,refund",
+			errorContains: `unknown event type "refund"`,
+		},
+		{
+			name:          "empty",
+			value:         " , ",
+			errorContains: "no event types specified",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			eventTypes, err := ParseBackfillEventTypes(tt.value)
+			if tt.errorContains != "" {
+				require.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, eventTypes)
+		})
+	}
+}
+
+func TestBackfillProgress_Percent(t *testing.T) {
+	p := BackfillProgress{FromBlock: 101, ToBlock: 200, Block: 125}
+	assert.InDelta(t, 25.0, p.Percent(), 0.001)
+
+	p.Block = 200
+	assert.InDelta(t, 100.0, p.Percent(), 0.001)
+}
+
+func TestBackfillService_Run(t *testing.T) {
+	newService := func(t *testing.T) *BackfillService {
+		return NewBackfillService(
+			map[uint64]*IntentService{},
+			map[uint64]*FulfillmentService{},
+			map[uint64]*SettlementService{},
+			mocks.NewDatabaseMock(t),
+			logging.NewTesting(t),
+		)
+	}
+
+	t.Run("invalid block range", func(t *testing.T) {
+		err := newService(t).Run(context.Background(), BackfillRequest{
+			ChainID:    8453,
+			FromBlock:  456,
+			ToBlock:    123,
+			EventTypes: []string{eventTypeIntent},
+		}, nil)
+		require.ErrorContains(t, err, "invalid block range")
+	})
+
+	t.Run("unsupported chain", func(t *testing.T) {
+		err := newService(t).Run(context.Background(), BackfillRequest{
+			ChainID:    8453,
+			FromBlock:  123,
+			ToBlock:    456,
+			EventTypes: []string{eventTypeFulfillment},
+		}, nil)
+		require.ErrorContains(t, err, "no fulfillment service for chain 8453")
+	})
+
+	t.Run("puts the services in backfill mode", func(t *testing.T) {
+		fulfillments := newTestFulfillmentService(t, nil)
+		NewBackfillService(
+			map[uint64]*IntentService{},
+			map[uint64]*FulfillmentService{1: fulfillments},
+			map[uint64]*SettlementService{},
+			mocks.NewDatabaseMock(t),
+			logging.NewTesting(t),
+		)
+		assert.True(t, fulfillments.backfill)
+	})
+
+	t.Run("leaves checkpoints untouched", func(t *testing.T) {
+		// the database mock fails the test on any unexpected UpdateEventCheckpoint call
+		service := newService(t)
+		service.catchup.persistChunkCheckpoint(
+			context.Background(),
+			"intent_backfill",
+			8453,
+			eventTypeIntent,
+			common.HexToAddress("0x1"),
+			456,
+		)
+	})
+}
@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"math/big"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -14,11 +12,13 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
 	"github.com/speedrun-hq/speedrun/api/config"
 	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
 )
 
 // Constants for timeouts and monitoring
@@ -38,17 +38,16 @@ const (
 	// MonitoringInterval is how often to log the status of ongoing operations
 	MonitoringInterval = 30 * time.Second
 
-	// DefaultMaxBlockRange is the default maximum block range for catchup operations
-	// add these constants for block range optimization
-	// default max range for most chains
-	DefaultMaxBlockRange = uint64(5000)
-
-	// EthereumMaxBlockRange is the maximum block range for Ethereum chains
-	// NOTE: Smaller range for Ethereum mainnet (chain ID 1) since it has higher transaction density
-	EthereumMaxBlockRange = uint64(1000)
+	// pollMaxRetries and pollRetryDelay bound the retries of the RPC calls of a polling cycle
+	pollMaxRetries = 3
+	pollRetryDelay = 5 * time.Second
 )
 
-const eventTypeIntent = "intent"
+const (
+	eventTypeIntent      = "intent"
+	eventTypeFulfillment = "fulfillment"
+	eventTypeSettlement  = "settlement"
+)
 
 // EventCatchupService coordinates the catch-up process between intent and fulfillment services
 type EventCatchupService struct {
@@ -56,12 +55,16 @@ type EventCatchupService struct {
 	fulfillmentServices map[uint64]*FulfillmentService
 	settlementServices  map[uint64]*SettlementService
 	db                  db.Database
+	reorg               *ReorgDetector
 	mu                  sync.Mutex
-	intentProgress      map[uint64]uint64 // chainID -> last processed block
-	fulfillmentProgress map[uint64]uint64 // chainID -> last processed block
-	settlementProgress  map[uint64]uint64 // chainID -> last processed block
-	activeCatchups      map[string]bool   // Track active catchup operations
-	catchupMu           sync.Mutex        // Mutex for the activeCatchups map
+	intentProgress      map[uint64]uint64           // chainID -> last processed block
+	fulfillmentProgress map[uint64]uint64           // chainID -> last processed block
+	settlementProgress  map[uint64]uint64           // chainID -> last processed block
+	pollingChains       map[uint64]bool             // chains whose events are polled instead of received from subscriptions
+	engines             map[uint64]*IngestionEngine // chainID -> live ingestion of subscribed chains
+	activeCatchups      map[string]bool             // Track active catchup operations
+	backfill            bool                        // Re-indexing a range, checkpoints are left untouched
+	catchupMu           sync.Mutex                  // Mutex for the activeCatchups map
 	logger              zerolog.Logger
 
 	// Goroutine tracking
@@ -91,9 +94,12 @@ func NewEventCatchupService(
 		fulfillmentServices: fulfillmentServices,
 		settlementServices:  settlementServices,
 		db:                  db,
+		reorg:               NewReorgDetector(db, logger),
 		intentProgress:      make(map[uint64]uint64),
 		fulfillmentProgress: make(map[uint64]uint64),
 		settlementProgress:  make(map[uint64]uint64),
+		pollingChains:       make(map[uint64]bool),
+		engines:             make(map[uint64]*IngestionEngine),
 		activeCatchups:      make(map[string]bool),
 		logger:              logger,
 		cleanupCtx:          cleanupCtx,
@@ -129,32 +135,17 @@ func (s *EventCatchupService) StartListening(ctx context.Context) error {
 			Msg("Configured chain")
 	}
 
-	// Initialize progress tracking for all chains
-	s.mu.Lock()
-	for chainID := range s.intentServices {
-		s.logger.Info().
-			Uint64(logging.FieldChain, chainID).
-			Msg("Initializing intent progress tracking")
-		lastBlock, err := s.db.GetLastProcessedBlock(ctx, chainID)
-		if err != nil {
-			s.mu.Unlock()
-			return fmt.Errorf("failed to get last processed block for chain %d: %v", chainID, err)
-		}
-		if lastBlock < cfg.ChainConfigs[chainID].DefaultBlock {
-			s.logger.Info().
+	// Roll back anything indexed from blocks that were reorged out while we were down
+	for chainID, intentService := range s.intentServices {
+		reorgCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		if _, err := s.reorg.CheckCanonical(reorgCtx, chainID, intentService.client); err != nil {
+			s.logger.Warn().
 				Uint64(logging.FieldChain, chainID).
-				Uint64(logging.FieldBlock, lastBlock).
-				Uint64("default_block", cfg.ChainConfigs[chainID].DefaultBlock).
-				Msg("Last processed block is less than default block, using default")
-			lastBlock = cfg.ChainConfigs[chainID].DefaultBlock
+				Err(err).
+				Msg("Failed to check for chain reorganization")
 		}
-		s.intentProgress[chainID] = lastBlock
-		s.logger.Info().
-			Uint64(logging.FieldChain, chainID).
-			Uint64(logging.FieldBlock, lastBlock).
-			Msg("Setting intent progress block")
+		cancel()
 	}
-	s.mu.Unlock()
 
 	// Get current block numbers for all chains
 	currentBlocks := make(map[uint64]uint64)
@@ -165,7 +156,7 @@ func (s *EventCatchupService) StartListening(ctx context.Context) error {
 		cancel()
 
 		if err != nil {
-			return fmt.Errorf("failed to get current block number for chain %d: %v", chainID, err)
+			return errs.Upstream(err, "failed to get current block number for chain %d", chainID)
 		}
 		currentBlocks[chainID] = currentBlock
 		s.logger.Info().
@@ -179,7 +170,7 @@ func (s *EventCatchupService) StartListening(ctx context.Context) error {
 
 	// INTENT CATCHUP
 	s.logger.Info().Msg("Starting intent event catchup")
-	if err := s.runIntentCatchup(ctx, cfg, currentBlocks); err != nil {
+	if err := s.runCatchup(ctx, cfg, currentBlocks, eventTypeIntent); err != nil {
 		// Store the error but continue with fulfillment and settlement catchup
 		catchupErrors = append(catchupErrors, fmt.Errorf("intent catchup failed: %v", err))
 		// TODO: consider throwing an error here
@@ -190,7 +181,7 @@ func (s *EventCatchupService) StartListening(ctx context.Context) error {
 
 	// FULFILLMENT CATCHUP
 	log.Printf("Starting fulfillment catchup")
-	if err := s.runFulfillmentCatchup(ctx, cfg, currentBlocks); err != nil {
+	if err := s.runCatchup(ctx, cfg, currentBlocks, eventTypeFulfillment); err != nil {
 		// Store the error but continue with settlement catchup
 		catchupErrors = append(catchupErrors, fmt.Errorf("fulfillment catchup failed: %v", err))
 		// TODO: consider throwing an error here
@@ -201,7 +192,7 @@ func (s *EventCatchupService) StartListening(ctx context.Context) error {
 
 	// SETTLEMENT CATCHUP
 	s.logger.Info().Msg("Starting settlement catchup")
-	if err := s.runSettlementCatchup(ctx, cfg, currentBlocks); err != nil {
+	if err := s.runCatchup(ctx, cfg, currentBlocks, eventTypeSettlement); err != nil {
 		// Store the error
 		catchupErrors = append(catchupErrors, fmt.Errorf("settlement catchup failed: %v", err))
 		// TODO: consider throwing an error here
@@ -210,24 +201,6 @@ func (s *EventCatchupService) StartListening(ctx context.Context) error {
 		s.logger.Info().Msg("All settlement services have completed catchup successfully")
 	}
 
-	// Only attempt to update processed blocks for chains that completed successfully
-	for chainID, currentBlock := range currentBlocks {
-		updateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		if err := s.db.UpdateLastProcessedBlock(updateCtx, chainID, currentBlock); err != nil {
-			s.logger.Warn().
-				Uint64(logging.FieldChain, chainID).
-				Err(err).
-				Msg("Failed to update last processed block")
-			// Don't return an error here, just log the warning
-		} else {
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Uint64(logging.FieldBlock, currentBlock).
-				Msg("Updated last processed block")
-		}
-		cancel()
-	}
-
 	// Start live subscriptions for all services
 	if err := s.StartLiveEventListeners(ctx, cfg); err != nil {
 		catchupErrors = append(catchupErrors, fmt.Errorf("failed to start live subscriptions: %v", err))
@@ -308,84 +281,105 @@ func (s *EventCatchupService) untrackCatchupOperation(operation string) {
 	s.logger.Debug().Str("operation", operation).Msg("Completed catchup operation")
 }
 
-// runIntentCatchup handles the intent catchup process with proper error handling and timeouts
-func (s *EventCatchupService) runIntentCatchup(
+// runCatchup catches up on the events of an event type of every chain from their checkpoint
+// to the current block, with proper error handling and timeouts
+func (s *EventCatchupService) runCatchup(
 	ctx context.Context,
 	cfg *config.Config,
 	currentBlocks map[uint64]uint64,
+	eventType string,
 ) error {
 	// Create a context with a global timeout
 	catchupCtx, catchupCancel := context.WithTimeout(ctx, CatchupOperationTimeout)
 	defer catchupCancel()
 
-	var intentWg sync.WaitGroup
-	intentErrors := make(chan error, len(s.intentServices))
+	handlers := s.eventHandlers(eventType)
+
+	// Initialize progress tracking for all chains
+	s.mu.Lock()
+	for chainID := range handlers {
+		lastBlock, err := s.loadCheckpoint(ctx, cfg.ChainConfigs[chainID], eventType)
+		if err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		s.progressOf(eventType)[chainID] = lastBlock
+	}
+	s.mu.Unlock()
+
+	var catchupWg sync.WaitGroup
+	catchupErrors := make(chan error, len(handlers))
 
 	// Track number of chains that need catchup
 	chainsToProcess := 0
 
-	// Start intent catch-up for all chains in parallel
-	for chainID, intentService := range s.intentServices {
-		lastBlock := s.intentProgress[chainID]
+	// Start the catch-up for all chains in parallel
+	for chainID, handler := range handlers {
+		lastBlock := s.lastProcessedBlock(chainID, eventType)
 		currentBlock := currentBlocks[chainID]
 		contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
 
 		if lastBlock >= currentBlock {
-			s.logger.Info().
+			s.logger.Debug().
 				Uint64(logging.FieldChain, chainID).
+				Str("event_type", eventType).
 				Msg("No missed events to process")
 			continue
 		}
 
 		chainsToProcess++
-		intentWg.Add(1)
+		catchupWg.Add(1)
 
 		// Use a descriptive operation name
-		opName := fmt.Sprintf("intent_catchup_chain_%d", chainID)
+		opName := fmt.Sprintf("%s_catchup_chain_%d", eventType, chainID)
 		s.trackCatchupOperation(opName)
 
-		go func(chainID uint64, intentService *IntentService, lastBlock, currentBlock uint64, opName string) {
-			defer intentWg.Done()
+		go func(chainID uint64, handler chainHandler, lastBlock, currentBlock uint64, opName string) {
+			defer catchupWg.Done()
 			defer s.untrackCatchupOperation(opName)
 
 			s.logger.Info().
 				Uint64(logging.FieldChain, chainID).
+				Str("event_type", eventType).
 				Uint64("from_block", lastBlock+1).
 				Uint64("to_block", currentBlock).
-				Msg("Starting intent event catch-up")
+				Msg("Starting event catch-up")
 
 			// Create a timeout context for this specific chain's catchup
 			chainCtx, chainCancel := context.WithTimeout(catchupCtx, CatchupOperationTimeout)
 			defer chainCancel()
 
-			if err := s.catchUpOnIntentEvents(chainCtx, intentService, contractAddress, lastBlock, currentBlock, opName); err != nil {
-				intentErrors <- fmt.Errorf("failed to catch up on intent events for chain %d: %v", chainID, err)
+			if err := s.catchUpOnEvents(chainCtx, handler, contractAddress, lastBlock, currentBlock, opName); err != nil {
+				catchupErrors <- fmt.Errorf("failed to catch up on %s events for chain %d: %v", eventType, chainID, err)
 				s.logger.Error().
 					Uint64(logging.FieldChain, chainID).
+					Str("event_type", eventType).
 					Err(err).
-					Msg("Intent catchup failed")
+					Msg("Catchup failed")
 				return
 			}
 
 			// Update progress
-			s.UpdateIntentProgress(chainID, currentBlock)
+			s.updateProgress(chainID, eventType, currentBlock)
+			s.persistCheckpoint(ctx, chainID, eventType, contractAddress, currentBlock)
 			s.logger.Info().
 				Uint64(logging.FieldChain, chainID).
-				Msg("Completed intent event catch-up")
-		}(chainID, intentService, lastBlock, currentBlock, opName)
+				Str("event_type", eventType).
+				Msg("Completed event catch-up")
+		}(chainID, handler, lastBlock, currentBlock, opName)
 	}
 
 	// If there are no chains to process, we can return early
 	if chainsToProcess == 0 {
-		s.logger.Debug().Msg("No intent catchup needed for any chain")
+		s.logger.Debug().Str("event_type", eventType).Msg("No catchup needed for any chain")
 		return nil
 	}
 
 	// Create a separate goroutine to wait for all work to complete and close the error channel
 	done := make(chan struct{})
 	go func() {
-		intentWg.Wait()
-		close(intentErrors)
+		catchupWg.Wait()
+		close(catchupErrors)
 		close(done)
 	}()
 
@@ -394,605 +388,303 @@ func (s *EventCatchupService) runIntentCatchup(
 	select {
 	case <-done:
 		// Process any errors that were collected
-		for err := range intentErrors {
+		for err := range catchupErrors {
 			if err != nil {
 				errs = append(errs, err)
-				s.logger.Error().Err(err).Msg("Intent catchup error")
+				s.logger.Error().Str("event_type", eventType).Err(err).Msg("Catchup error")
 			}
 		}
 	case <-catchupCtx.Done():
-		return fmt.Errorf("intent catchup timed out after %v", CatchupOperationTimeout)
+		return fmt.Errorf("%s catchup timed out after %v", eventType, CatchupOperationTimeout)
 	}
 
 	// Return combined errors if any
 	if len(errs) > 0 {
-		return fmt.Errorf("intent catchup completed with %d errors", len(errs))
+		return fmt.Errorf("%s catchup completed with %d errors", eventType, len(errs))
 	}
 
 	return nil
 }
 
-// runFulfillmentCatchup handles the fulfillment catchup process with proper error handling and timeouts
-func (s *EventCatchupService) runFulfillmentCatchup(
-	ctx context.Context,
-	cfg *config.Config,
-	currentBlocks map[uint64]uint64,
-) error {
-	// Create a context with a global timeout
-	catchupCtx, catchupCancel := context.WithTimeout(ctx, CatchupOperationTimeout)
-	defer catchupCancel()
-
-	// Initialize progress tracking for all chains
+// StartLiveEventListeners starts the live ingestion of every chain: an ingestion engine subscribed
+// to all the events of the contract of the chain, or polling for chains without subscriptions
+func (s *EventCatchupService) StartLiveEventListeners(ctx context.Context, cfg *config.Config) error {
 	s.mu.Lock()
-	for chainID := range s.fulfillmentServices {
-		blockCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		lastBlock, err := s.db.GetLastProcessedBlock(blockCtx, chainID)
-		cancel()
-
-		if err != nil {
-			s.mu.Unlock()
-			return fmt.Errorf("failed to get last processed block for chain %d: %v", chainID, err)
-		}
-		if lastBlock < cfg.ChainConfigs[chainID].DefaultBlock {
-			lastBlock = cfg.ChainConfigs[chainID].DefaultBlock
+	for chainID, chain := range cfg.ChainConfigs {
+		if chain.IngestionMode == config.IngestionPolling {
+			s.pollingChains[chainID] = true
 		}
-		s.fulfillmentProgress[chainID] = lastBlock
 	}
 	s.mu.Unlock()
 
-	var fulfillmentWg sync.WaitGroup
-	fulfillmentErrors := make(chan error, len(s.fulfillmentServices))
-
-	// Track number of chains that need catchup
-	chainsToProcess := 0
-
-	for chainID, fulfillmentService := range s.fulfillmentServices {
-		lastBlock := s.fulfillmentProgress[chainID]
-		currentBlock := currentBlocks[chainID]
-		contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
+	s.logger.Info().Msg("Starting live event listeners")
+	for chainID, intentService := range s.intentServices {
+		chainConfig := cfg.ChainConfigs[chainID]
+		contractAddress := common.HexToAddress(chainConfig.ContractAddr)
+		handlers := s.chainHandlers(chainID)
 
-		if lastBlock >= currentBlock {
-			s.logger.Debug().
-				Uint64(logging.FieldChain, chainID).
-				Msg("No missed fulfillment events to process")
-			continue
+		// For live events, use the last processed block + 1 of each event type as the starting point
+		// This ensures we don't miss events and don't process duplicates
+		from := make(map[string]uint64, len(handlers))
+		for _, handler := range handlers {
+			lastBlock := s.lastProcessedBlock(chainID, handler.EventType)
+			from[handler.EventType] = s.liveStartBlock(ctx, chainID, handler.EventType, lastBlock, intentService.client)
 		}
 
-		chainsToProcess++
-		fulfillmentWg.Add(1)
-
-		// Use a descriptive operation name
-		opName := fmt.Sprintf("fulfillment_catchup_chain_%d", chainID)
-		s.trackCatchupOperation(opName)
-
-		go func(chainID uint64, fulfillmentService *FulfillmentService, lastBlock, currentBlock uint64, opName string) {
-			defer fulfillmentWg.Done()
-			defer s.untrackCatchupOperation(opName)
-
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", lastBlock+1).
-				Uint64("to_block", currentBlock).
-				Msg("Starting fulfillment event catch-up")
+		if !s.IsPollingChain(chainID) {
+			fromBlock := from[eventTypeIntent]
+			for _, handler := range handlers {
+				fromBlock = min(fromBlock, from[handler.EventType])
+			}
 
-			// Create a timeout context for this specific chain's catchup
-			chainCtx, chainCancel := context.WithTimeout(catchupCtx, CatchupOperationTimeout)
-			defer chainCancel()
+			err := s.startIngestion(ctx, chainID, handlers, contractAddress, fromBlock)
+			if err == nil {
+				continue
+			}
 
-			if err := s.catchUpOnFulfillmentEvents(chainCtx, fulfillmentService, contractAddress, lastBlock, currentBlock, opName); err != nil {
-				fulfillmentErrors <- fmt.Errorf("failed to catch up on fulfillment events for chain %d: %v", chainID, err)
+			if !s.fallBackToPolling(chainID, chainConfig.IngestionMode, err) {
 				s.logger.Error().
 					Uint64(logging.FieldChain, chainID).
 					Err(err).
-					Msg("Fulfillment catchup failed")
-				return
-			}
-
-			// Update progress
-			s.UpdateFulfillmentProgress(chainID, currentBlock)
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Msg("Completed fulfillment event catch-up")
-		}(chainID, fulfillmentService, lastBlock, currentBlock, opName)
-	}
-
-	// If there are no chains to process, we can return early
-	if chainsToProcess == 0 {
-		s.logger.Debug().Msg("No fulfillment catchup needed for any chain")
-		return nil
-	}
-
-	// Create a separate goroutine to wait for all work to complete and close the error channel
-	done := make(chan struct{})
-	go func() {
-		fulfillmentWg.Wait()
-		close(fulfillmentErrors)
-		close(done)
-	}()
-
-	// Wait for either completion or timeout
-	var errs []error
-	select {
-	case <-done:
-		// Process any errors that were collected
-		for err := range fulfillmentErrors {
-			if err != nil {
-				errs = append(errs, err)
-				s.logger.Error().Err(err).Msg("Fulfillment catchup error")
+					Msg("Failed to start ingestion")
+				return fmt.Errorf("failed to start ingestion for chain %d: %v", chainID, err)
 			}
 		}
-	case <-catchupCtx.Done():
-		return fmt.Errorf("fulfillment catchup timed out after %v", CatchupOperationTimeout)
-	}
 
-	// Return combined errors if any
-	if len(errs) > 0 {
-		return fmt.Errorf("fulfillment catchup completed with %d errors", len(errs))
+		intentService.SetPolling()
+		for _, handler := range handlers {
+			s.startPolling(ctx, handler, contractAddress, from[handler.EventType], chainConfig.BlockInterval, intentService)
+		}
 	}
 
+	s.logger.Info().Msg("All live event listeners started successfully")
 	return nil
 }
 
-// runSettlementCatchup handles the settlement catchup process with proper error handling and timeouts
-// TODO: lot of duplicated logic among these catchup functions, check for factorization
-func (s *EventCatchupService) runSettlementCatchup(
+// liveStartBlock returns the block the live events of an event type resume from:
+// the block after the last processed one, or the current block without progress
+func (s *EventCatchupService) liveStartBlock(
 	ctx context.Context,
-	cfg *config.Config,
-	currentBlocks map[uint64]uint64,
-) error {
-	// Create a context with a global timeout
-	catchupCtx, catchupCancel := context.WithTimeout(ctx, CatchupOperationTimeout)
-	defer catchupCancel()
-
-	// Initialize progress tracking for all chains
-	s.mu.Lock()
-	for chainID := range s.settlementServices {
-		blockCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		lastBlock, err := s.db.GetLastProcessedBlock(blockCtx, chainID)
-		cancel()
-
-		if err != nil {
-			s.mu.Unlock()
-			return fmt.Errorf("failed to get last processed block for chain %d: %v", chainID, err)
-		}
-		if lastBlock < cfg.ChainConfigs[chainID].DefaultBlock {
-			lastBlock = cfg.ChainConfigs[chainID].DefaultBlock
-		}
-		s.settlementProgress[chainID] = lastBlock
+	chainID uint64,
+	eventType string,
+	lastBlock uint64,
+	client evm.Client,
+) uint64 {
+	if lastBlock > 0 {
+		return lastBlock + 1
 	}
-	s.mu.Unlock()
 
-	var settlementWg sync.WaitGroup
-	settlementErrors := make(chan error, len(s.settlementServices))
+	// If we don't have a stored last block, get the current one
+	blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	currentBlock, err := client.BlockNumber(blockCtx)
+	cancel()
+	if err != nil {
+		s.logger.Warn().
+			Uint64(logging.FieldChain, chainID).
+			Str("event_type", eventType).
+			Err(err).
+			Msg("Unable to get current block")
+		return 0
+	}
 
-	// Track number of chains that need catchup
-	chainsToProcess := 0
+	s.logger.Debug().
+		Uint64(logging.FieldChain, chainID).
+		Str("event_type", eventType).
+		Uint64("from_block", currentBlock).
+		Msg("No stored progress found, starting live events from current block")
 
-	for chainID, settlementService := range s.settlementServices {
-		lastBlock := s.settlementProgress[chainID]
-		currentBlock := currentBlocks[chainID]
-		contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
+	return currentBlock
+}
 
-		if lastBlock >= currentBlock {
-			s.logger.Debug().
-				Uint64(logging.FieldChain, chainID).
-				Msg("No missed settlement events to process")
-			continue
-		}
+// startIngestion starts the ingestion engine of a chain, routing the events of its contract
+// to the handlers of the chain
+func (s *EventCatchupService) startIngestion(
+	ctx context.Context,
+	chainID uint64,
+	handlers []chainHandler,
+	contractAddress common.Address,
+	fromBlock uint64,
+) error {
+	intentService := s.intentServices[chainID]
 
-		chainsToProcess++
-		settlementWg.Add(1)
+	engine := NewIngestionEngine(intentService.client, chainID, contractAddress, s.logger)
+	for _, handler := range handlers {
+		engine.Register(handler.EventHandler)
+	}
+	engine.SetProgressFunc(func(eventType string, blockNumber uint64) {
+		s.updateProgress(chainID, eventType, blockNumber)
+	})
 
-		// Use a descriptive operation name
-		opName := fmt.Sprintf("settlement_catchup_chain_%d", chainID)
-		s.trackCatchupOperation(opName)
+	s.logger.Info().
+		Uint64(logging.FieldChain, chainID).
+		Uint64("from_block", fromBlock).
+		Msg("Starting ingestion engine")
 
-		go func(chainID uint64, settlementService *SettlementService, lastBlock, currentBlock uint64, opName string) {
-			defer settlementWg.Done()
-			defer s.untrackCatchupOperation(opName)
+	if err := engine.Start(ctx, fromBlock); err != nil {
+		return err
+	}
 
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", lastBlock+1).
-				Uint64("to_block", currentBlock).
-				Msg("Starting settlement event catch-up")
+	s.mu.Lock()
+	s.engines[chainID] = engine
+	s.mu.Unlock()
 
-			// Create a timeout context for this specific chain's catchup
-			chainCtx, chainCancel := context.WithTimeout(catchupCtx, CatchupOperationTimeout)
-			defer chainCancel()
+	intentService.SetIngestionEngine(engine)
 
-			if err := s.catchUpOnSettlementEvents(chainCtx, settlementService, contractAddress, lastBlock, currentBlock, opName); err != nil {
-				settlementErrors <- fmt.Errorf("failed to catch up on settlement events for chain %d: %v", chainID, err)
-				s.logger.Error().
-					Uint64(logging.FieldChain, chainID).
-					Err(err).
-					Msg("Settlement catchup failed")
-				return
-			}
+	return nil
+}
 
-			// Update progress
-			s.UpdateSettlementProgress(chainID, currentBlock)
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Msg("Completed settlement event catch-up")
-		}(chainID, settlementService, lastBlock, currentBlock, opName)
-	}
+// chainHandler is the handler of an event type of a chain,
+// with the client and the log range the logs of the chain are read with
+type chainHandler struct {
+	EventHandler
+	chainID         uint64
+	client          evm.Client
+	logRange        *LogRange
+	skipEmptyRanges bool
+}
 
-	// If there are no chains to process, we can return early
-	if chainsToProcess == 0 {
-		s.logger.Debug().Msg("No settlement catchup needed for any chain")
-		return nil
+// chainHandlers returns the handlers of the event types of a chain, in processing order
+func (s *EventCatchupService) chainHandlers(chainID uint64) []chainHandler {
+	var handlers []chainHandler
+	for _, eventType := range []string{eventTypeIntent, eventTypeFulfillment, eventTypeSettlement} {
+		if handler, ok := s.eventHandlers(eventType)[chainID]; ok {
+			handlers = append(handlers, handler)
+		}
 	}
+	return handlers
+}
 
-	// Create a separate goroutine to wait for all work to complete and close the error channel
-	done := make(chan struct{})
-	go func() {
-		settlementWg.Wait()
-		close(settlementErrors)
-		close(done)
-	}()
-
-	// Wait for either completion or timeout
-	var errs []error
-	select {
-	case <-done:
-		// Process any errors that were collected
-		for err := range settlementErrors {
-			if err != nil {
-				errs = append(errs, err)
-				s.logger.Error().Err(err).Msg("Settlement catchup error")
-			}
+// eventHandlers returns the handlers of an event type by chain
+func (s *EventCatchupService) eventHandlers(eventType string) map[uint64]chainHandler {
+	handlers := make(map[uint64]chainHandler)
+	switch eventType {
+	case eventTypeIntent:
+		for chainID, service := range s.intentServices {
+			handlers[chainID] = service.chainHandler()
+		}
+	case eventTypeFulfillment:
+		for chainID, service := range s.fulfillmentServices {
+			handlers[chainID] = service.chainHandler()
+		}
+	case eventTypeSettlement:
+		for chainID, service := range s.settlementServices {
+			handlers[chainID] = service.chainHandler()
 		}
-	case <-catchupCtx.Done():
-		return fmt.Errorf("settlement catchup timed out after %v", CatchupOperationTimeout)
 	}
+	return handlers
+}
 
-	// Return combined errors if any
-	if len(errs) > 0 {
-		return fmt.Errorf("settlement catchup completed with %d errors", len(errs))
+// updateProgress updates the progress of an event type of a chain
+func (s *EventCatchupService) updateProgress(chainID uint64, eventType string, blockNumber uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if progress := s.progressOf(eventType); progress != nil {
+		progress[chainID] = blockNumber
 	}
-
-	return nil
 }
 
-// StartLiveEventListeners starts the live event listeners for all services
-func (s *EventCatchupService) StartLiveEventListeners(ctx context.Context, cfg *config.Config) error {
-	// Start intent listeners with block tracking
-	s.logger.Info().Msg("Starting live intent event listeners")
-	for chainID, intentService := range s.intentServices {
-		chainID := chainID // Create a copy of the loop variable for the closure
-		intentService := intentService
-
-		contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-		s.logger.Info().
-			Uint64(logging.FieldChain, chainID).
-			Str("contract", contractAddress.Hex()).
-			Msg("Starting intent event listener")
+// lastProcessedBlock returns the progress of an event type of a chain
+func (s *EventCatchupService) lastProcessedBlock(chainID uint64, eventType string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.progressOf(eventType)[chainID]
+}
 
-		// For live subscriptions, use the last processed block + 1 as the starting point
-		// This ensures we don't miss events and don't process duplicates
-		var fromBlock uint64
-		s.mu.Lock()
-		if lastBlock, exists := s.intentProgress[chainID]; exists && lastBlock > 0 {
-			fromBlock = lastBlock + 1
-			log.Printf("Setting intent listener for chain %d to start from block %d", chainID, fromBlock)
-		} else {
-			// If we don't have a stored last block, get the current one
-			blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-			currentBlock, err := intentService.client.BlockNumber(blockCtx)
-			cancel()
-			if err != nil {
-				s.logger.Warn().
-					Uint64(logging.FieldChain, chainID).
-					Err(err).
-					Msg("Unable to get current block")
-			} else {
-				fromBlock = currentBlock
-				s.logger.Debug().
-					Uint64(logging.FieldChain, chainID).
-					Uint64("from_block", fromBlock).
-					Msg("No stored progress found, setting intent listener to start from current block")
-			}
-		}
-		s.mu.Unlock()
+// progressOf returns the progress of an event type by chain, the caller must hold mu
+func (s *EventCatchupService) progressOf(eventType string) map[uint64]uint64 {
+	switch eventType {
+	case eventTypeIntent:
+		return s.intentProgress
+	case eventTypeFulfillment:
+		return s.fulfillmentProgress
+	case eventTypeSettlement:
+		return s.settlementProgress
+	default:
+		return nil
+	}
+}
 
-		// Special handling for ZetaChain - use polling instead of subscription
-		if chainID == 7000 {
-			// Store the initial block to start polling from
-			s.mu.Lock()
-			s.intentProgress[chainID] = fromBlock
-			s.mu.Unlock()
+// IngestionEngines returns the running ingestion engines by chain
+func (s *EventCatchupService) IngestionEngines() map[uint64]*IngestionEngine {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", fromBlock).
-				Msg("Setting up polling-based event monitoring for ZetaChain")
+	engines := make(map[uint64]*IngestionEngine, len(s.engines))
+	for chainID, engine := range s.engines {
+		engines[chainID] = engine
+	}
+	return engines
+}
 
-			// Start polling goroutine
-			go s.pollZetachainEvents(ctx, intentService, contractAddress, cfg.ChainConfigs[chainID].BlockInterval)
+// StartConfirmationTrackers refreshes the confirmed head of every chain with an intent service, so that
+// the catchup persists the confirmed logs right away, then starts refreshing it and promoting its staged events
+func (s *EventCatchupService) StartConfirmationTrackers(ctx context.Context, trackers ConfirmationTrackers) {
+	for chainID, tracker := range trackers {
+		intentService, ok := s.intentServices[chainID]
+		if !ok {
 			continue
 		}
 
-		// Start the intent service's own subscription management
-		s.logger.Info().
-			Uint64(logging.FieldChain, chainID).
-			Msg("Starting intent service subscription through StartListening")
-		if err := intentService.StartListening(ctx, contractAddress); err != nil {
+		client := intentService.client
+
+		rpcCtx, cancel := context.WithTimeout(ctx, DefaultRPCTimeout)
+		err := tracker.Refresh(rpcCtx, client)
+		cancel()
+		if err != nil {
 			s.logger.Error().
 				Uint64(logging.FieldChain, chainID).
 				Err(err).
-				Msg("Failed to start intent service")
-			return fmt.Errorf("failed to start intent service for chain %d: %v", chainID, err)
+				Msg("CRITICAL: Failed to get the confirmed block, the events of the chain fail until it's known")
 		}
-		s.logger.Info().
-			Uint64(logging.FieldChain, chainID).
-			Msg("Successfully started intent service subscription")
-	}
 
-	// Start fulfillment listeners with similar block tracking
-	s.logger.Debug().Msg("Starting live fulfillment event listeners")
-	for chainID, fulfillmentService := range s.fulfillmentServices {
-		chainID := chainID // Create a copy of the loop variable for the closure
-		fulfillmentService := fulfillmentService
+		s.StartGoroutine(fmt.Sprintf("confirmations-%d", chainID), func() {
+			tracker.Run(s.cleanupCtx, client)
+		})
+	}
+}
 
-		contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-		// For live subscriptions, use the last processed block + 1 as the starting point
-		var fromBlock uint64
-		s.mu.Lock()
-		if lastBlock, exists := s.fulfillmentProgress[chainID]; exists && lastBlock > 0 {
-			fromBlock = lastBlock + 1
-			s.logger.Debug().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", fromBlock).
-				Msg("Setting fulfillment listener to start from block")
-		} else {
-			// If we don't have a stored last block, get the current one
-			blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-			currentBlock, err := fulfillmentService.client.BlockNumber(blockCtx)
-			cancel()
-			if err != nil {
-				s.logger.Warn().
-					Uint64(logging.FieldChain, chainID).
-					Err(err).
-					Msg("Unable to get current block")
-			} else {
-				fromBlock = currentBlock
-				s.logger.Debug().
-					Uint64(logging.FieldChain, chainID).
-					Uint64("from_block", fromBlock).
-					Msg("No stored progress found, setting fulfillment listener to start from current block")
-			}
-		}
-		s.mu.Unlock()
-
-		// Special handling for ZetaChain - use polling instead of subscription
-		if chainID == 7000 {
-			// Store the initial block to start polling from
-			s.mu.Lock()
-			s.fulfillmentProgress[chainID] = fromBlock
-			s.mu.Unlock()
-
-			s.logger.Debug().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", fromBlock).
-				Msg("Setting up polling-based fulfillment monitoring for ZetaChain")
-
-			// Start polling goroutine
-			go s.pollZetachainFulfillmentEvents(
-				ctx,
-				fulfillmentService,
-				contractAddress,
-				cfg.ChainConfigs[chainID].BlockInterval,
-			)
-			continue
-		}
-
-		fulfillmentQuery := ethereum.FilterQuery{
-			Addresses: []common.Address{contractAddress},
-			Topics: [][]common.Hash{
-				{fulfillmentService.abi.Events[IntentFulfilledEventName].ID},
-			},
-		}
-
-		// Set FromBlock explicitly
-		if fromBlock > 0 {
-			fulfillmentQuery.FromBlock = big.NewInt(int64(fromBlock))
-		}
-
-		s.logger.Debug().
-			Uint64(logging.FieldChain, chainID).
-			Interface("from_block", fulfillmentQuery.FromBlock).
-			Str("address", contractAddress.Hex()).
-			Str("topic", fulfillmentQuery.Topics[0][0].Hex()).
-			Msg("Fulfillment subscription filter")
-
-		fulfillmentLogs := make(chan types.Log)
-		// Use the parent context for the subscription
-		fulfillmentSub, err := fulfillmentService.client.SubscribeFilterLogs(ctx, fulfillmentQuery, fulfillmentLogs)
-		if err != nil {
-			return fmt.Errorf("failed to subscribe to fulfillment logs for chain %d: %v", chainID, err)
-		}
-
-		// Add monitoring goroutine for subscription errors
-		go func() {
-			for {
-				select {
-				case err := <-fulfillmentSub.Err():
-					if err != nil {
-						s.logger.Error().
-							Uint64(logging.FieldChain, chainID).
-							Err(err).
-							Msg("Fulfillment subscription encountered an error")
-						// Try to resubscribe
-						resubCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-						newSub, resubErr := fulfillmentService.client.SubscribeFilterLogs(
-							resubCtx,
-							fulfillmentQuery,
-							fulfillmentLogs,
-						)
-						cancel()
-
-						if resubErr != nil {
-							s.logger.Error().
-								Uint64(logging.FieldChain, chainID).
-								Err(resubErr).
-								Msg("CRITICAL: Failed to resubscribe fulfillment listener")
-						} else {
-							fulfillmentSub = newSub
-							s.logger.Info().
-								Uint64(logging.FieldChain, chainID).
-								Msg("Successfully resubscribed fulfillment listener")
-						}
-					}
-				case <-ctx.Done():
-					s.logger.Debug().
-						Uint64(logging.FieldChain, chainID).
-						Msg("Fulfillment subscription monitor shutting down")
-					return
-				}
-			}
-		}()
+// StartIntentExpiry starts expiring stale pending intents
+func (s *EventCatchupService) StartIntentExpiry(expiryService *IntentExpiryService) {
+	s.StartGoroutine("intent-expiry", func() {
+		expiryService.Run(s.cleanupCtx)
+	})
+}
 
-		go fulfillmentService.processEventLogs(ctx, fulfillmentSub, fulfillmentLogs, contractAddress.Hex())
+// StartRPCHealthChecks starts probing the RPC providers of every chain
+func (s *EventCatchupService) StartRPCHealthChecks(pools map[uint64]*evm.Pool) {
+	for chainID, pool := range pools {
+		s.StartGoroutine(fmt.Sprintf("rpc-health-%d", chainID), func() {
+			pool.Run(s.cleanupCtx)
+		})
 	}
+}
 
-	// Start settlement listeners with similar block tracking
-	s.logger.Info().Msg("Starting live settlement event listeners")
-	for chainID, settlementService := range s.settlementServices {
-		chainID := chainID // Create a copy of the loop variable for the closure
-		settlementService := settlementService
-
-		contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-		// For live subscriptions, use the last processed block + 1 as the starting point
-		var fromBlock uint64
-		s.mu.Lock()
-		if lastBlock, exists := s.settlementProgress[chainID]; exists && lastBlock > 0 {
-			fromBlock = lastBlock + 1
-			s.logger.Debug().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", fromBlock).
-				Msg("Setting settlement listener to start from block")
-		} else {
-			// If we don't have a stored last block, get the current one
-			blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-			currentBlock, err := settlementService.client.BlockNumber(blockCtx)
-			cancel()
-			if err != nil {
-				s.logger.Warn().
-					Uint64(logging.FieldChain, chainID).
-					Err(err).
-					Msg("Unable to get current block")
-			} else {
-				fromBlock = currentBlock
-				s.logger.Debug().
-					Uint64(logging.FieldChain, chainID).
-					Uint64("from_block", fromBlock).
-					Msg("No stored progress found, setting settlement listener to start from current block")
-			}
-		}
-		s.mu.Unlock()
-
-		// Special handling for ZetaChain - use polling instead of subscription
-		if chainID == 7000 {
-			// Store the initial block to start polling from
-			s.mu.Lock()
-			s.settlementProgress[chainID] = fromBlock
-			s.mu.Unlock()
-
-			s.logger.Info().
-				Uint64(logging.FieldChain, chainID).
-				Uint64("from_block", fromBlock).
-				Msg("Setting up polling-based settlement monitoring for ZetaChain starting from block")
-
-			// Start polling goroutine
-			go s.pollZetachainSettlementEvents(
-				ctx,
-				settlementService,
-				contractAddress,
-				cfg.ChainConfigs[chainID].BlockInterval,
-			)
-			continue
-		}
-
-		settlementQuery := ethereum.FilterQuery{
-			Addresses: []common.Address{contractAddress},
-			Topics: [][]common.Hash{
-				{settlementService.abi.Events[IntentSettledEventName].ID},
-			},
-		}
-
-		// Set FromBlock explicitly
-		if fromBlock > 0 {
-			settlementQuery.FromBlock = big.NewInt(int64(fromBlock))
-		}
-
-		s.logger.Info().
-			Uint64(logging.FieldChain, chainID).
-			Interface("from_block", settlementQuery.FromBlock).
-			Str("contract", contractAddress.Hex()).
-			Str("topic", settlementQuery.Topics[0][0].Hex()).
-			Msg("Settlement subscription filter")
-
-		settlementLogs := make(chan types.Log)
-		// Use the parent context for the subscription
-		settlementSub, err := settlementService.client.SubscribeFilterLogs(ctx, settlementQuery, settlementLogs)
-		if err != nil {
-			return fmt.Errorf("failed to subscribe to settlement logs for chain %d: %v", chainID, err)
-		}
+// StartIntentEventBroker starts publishing intent events to stream subscribers
+func (s *EventCatchupService) StartIntentEventBroker(broker *IntentEventBroker) {
+	s.StartGoroutine("intent-event-broker", func() {
+		broker.Run(s.cleanupCtx)
+	})
+}
 
-		// Add monitoring goroutine for subscription errors
-		go func() {
-			for {
-				select {
-				case err := <-settlementSub.Err():
-					if err != nil {
-						s.logger.Error().
-							Uint64(logging.FieldChain, chainID).
-							Err(err).
-							Msg("Settlement subscription encountered an error")
-						// Try to resubscribe
-						resubCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-						newSub, resubErr := settlementService.client.SubscribeFilterLogs(
-							resubCtx,
-							settlementQuery,
-							settlementLogs,
-						)
-						cancel()
-
-						if resubErr != nil {
-							s.logger.Error().
-								Uint64(logging.FieldChain, chainID).
-								Err(resubErr).
-								Msg("CRITICAL: Failed to resubscribe settlement listener")
-						} else {
-							settlementSub = newSub
-							s.logger.Debug().
-								Uint64(logging.FieldChain, chainID).
-								Msg("Successfully resubscribed settlement listener")
-						}
-					}
-				case <-ctx.Done():
-					s.logger.Debug().
-						Uint64(logging.FieldChain, chainID).
-						Msg("Settlement subscription monitor shutting down")
-					return
-				}
-			}
-		}()
+// StartOutboxDispatcher starts dispatching the outbox messages of persisted events
+func (s *EventCatchupService) StartOutboxDispatcher(dispatcher *OutboxDispatcher) {
+	s.StartGoroutine("outbox-dispatcher", func() {
+		dispatcher.Run(s.cleanupCtx)
+	})
+}
 
-		go settlementService.processEventLogs(
-			ctx,
-			settlementSub,
-			settlementLogs,
-			contractAddress.Hex(),
-			contractAddress,
-		)
-	}
+// StartOrphanReconciler starts replaying the events queued until their intent is indexed
+func (s *EventCatchupService) StartOrphanReconciler(reconciler *OrphanReconciler) {
+	s.StartGoroutine("orphan-reconciler", func() {
+		reconciler.Run(s.cleanupCtx)
+	})
+}
 
-	s.logger.Info().Msg("All live event listeners started successfully")
-	return nil
+// StartWebhooks starts delivering intent events to the webhook subscriptions
+func (s *EventCatchupService) StartWebhooks(webhookService *WebhookService) {
+	s.StartGoroutine("webhooks", func() {
+		webhookService.Run(s.cleanupCtx)
+	})
 }
 
 // UpdateIntentProgress updates the progress of an intent service
@@ -1016,542 +708,196 @@ func (s *EventCatchupService) UpdateSettlementProgress(chainID, blockNumber uint
 	s.settlementProgress[chainID] = blockNumber
 }
 
-// hasEventsInBlockRange needs to check for both standard and call event signatures
-func hasEventsInBlockRange(
+// loadCheckpoint returns the block an event type of a chain resumes from,
+// never below the default block of the chain
+func (s *EventCatchupService) loadCheckpoint(
 	ctx context.Context,
-	client *ethclient.Client,
-	contractAddress common.Address,
-	eventSigs []common.Hash,
-	startBlock, endBlock uint64,
-) (bool, error) {
-	// Skip if range is too small
-	if endBlock <= startBlock {
-		return false, nil
-	}
+	chainConfig *config.ChainConfig,
+	eventType string,
+) (uint64, error) {
+	blockCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-	// Sample a few blocks to check if they have events
-	// The strategy is to check blocks at regular intervals in the range
-	sampleCount := 3
-	if endBlock-startBlock <= uint64(sampleCount) {
-		sampleCount = 1 // If range is small, just check one block
+	lastBlock, err := s.db.GetEventCheckpoint(blockCtx, chainConfig.ChainID, eventType, chainConfig.ContractAddr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s checkpoint for chain %d: %v", eventType, chainConfig.ChainID, err)
 	}
 
-	step := (endBlock - startBlock) / uint64(sampleCount)
-	if step == 0 {
-		step = 1
+	if lastBlock < chainConfig.DefaultBlock {
+		s.logger.Info().
+			Str("event_type", eventType).
+			Uint64(logging.FieldChain, chainConfig.ChainID).
+			Uint64(logging.FieldBlock, lastBlock).
+			Uint64("default_block", chainConfig.DefaultBlock).
+			Msg("Checkpoint is less than default block, using default")
+		lastBlock = chainConfig.DefaultBlock
 	}
 
-	// Use a timeout for each sample block check
-	scanCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	return lastBlock, nil
+}
+
+// persistCheckpoint stores the last processed block of an event type of a chain
+func (s *EventCatchupService) persistCheckpoint(
+	ctx context.Context,
+	chainID uint64,
+	eventType string,
+	contractAddress common.Address,
+	blockNumber uint64,
+) {
+	updateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	for i := uint64(0); i < uint64(sampleCount); i++ {
-		blockNum := startBlock + (i * step)
-		if blockNum > endBlock {
-			blockNum = endBlock
-		}
+	if err := s.db.UpdateEventCheckpoint(updateCtx, chainID, eventType, contractAddress.Hex(), blockNumber); err != nil {
+		// Don't fail the catchup, the cursor is persisted again on the next update
+		s.logger.Warn().
+			Str("event_type", eventType).
+			Uint64(logging.FieldChain, chainID).
+			Err(err).
+			Msg("Failed to update event checkpoint")
+		return
+	}
 
-		// For each event signature, check if there are any logs
-		for _, eventSig := range eventSigs {
-			// Create a query for just this block
-			query := ethereum.FilterQuery{
-				FromBlock: big.NewInt(int64(blockNum)),
-				ToBlock:   big.NewInt(int64(blockNum)),
-				Addresses: []common.Address{contractAddress},
-				Topics: [][]common.Hash{
-					{eventSig},
-				},
-			}
+	s.logger.Info().
+		Str("event_type", eventType).
+		Uint64(logging.FieldChain, chainID).
+		Uint64(logging.FieldBlock, blockNumber).
+		Msg("Updated event checkpoint")
+}
 
-			// Check for logs in this block
-			logs, err := client.FilterLogs(scanCtx, query)
-			if err != nil {
-				return true, nil // Error getting logs, so process the range to be safe
-			}
+// persistChunkCheckpoint stores the end of a processed catchup chunk as the checkpoint of an event type.
+// Backfills re-index ranges behind the checkpoints, so they never move them.
+func (s *EventCatchupService) persistChunkCheckpoint(
+	ctx context.Context,
+	opName string,
+	chainID uint64,
+	eventType string,
+	contractAddress common.Address,
+	chunkEnd uint64,
+) {
+	if s.backfill {
+		return
+	}
 
-			// If we found any logs in the sample block, there might be more in the range
-			if len(logs) > 0 {
-				return true, nil
-			}
-		}
+	dbUpdateCtx, dbUpdateCancel := context.WithTimeout(ctx, 10*time.Second)
+	dbErr := s.db.UpdateEventCheckpoint(dbUpdateCtx, chainID, eventType, contractAddress.Hex(), chunkEnd)
+	dbUpdateCancel()
+	if dbErr != nil {
+		s.logger.Debug().
+			Str("operation", opName).
+			Err(dbErr).
+			Msg("Warning: Failed to persist progress to DB")
+		// Continue processing even if DB update fails
+		return
 	}
 
-	return false, nil
+	s.logger.Debug().
+		Str("operation", opName).
+		Uint64(logging.FieldChain, chainID).
+		Uint64("block_number", chunkEnd).
+		Msg("Persisted progress to DB")
 }
 
-// Modify the intent events function to use bloom filtering on Ethereum
-func (s *EventCatchupService) catchUpOnIntentEvents(
+// skipRange records a block range skipped as empty so that it can be audited later,
+// and persists its end as the checkpoint of the event type
+func (s *EventCatchupService) skipRange(
 	ctx context.Context,
-	intentService *IntentService,
+	opName string,
+	chainID uint64,
+	eventType string,
 	contractAddress common.Address,
 	fromBlock, toBlock uint64,
-	opName string,
-) error {
-	// Use a chain-specific block range - smaller for Ethereum mainnet
-	var maxBlockRange uint64
-	if intentService.chainID == 1 { // Ethereum mainnet
-		maxBlockRange = EthereumMaxBlockRange
-		s.logger.Debug().
+) {
+	s.logger.Debug().
+		Str("operation", opName).
+		Uint64("from_block", fromBlock+1).
+		Uint64("to_block", toBlock).
+		Msg("Fast-forwarding through block range (no events)")
+
+	recordCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	err := s.db.RecordSkippedRange(recordCtx, &models.SkippedRange{
+		ChainID:         chainID,
+		EventType:       eventType,
+		ContractAddress: contractAddress.Hex(),
+		FromBlock:       fromBlock + 1,
+		ToBlock:         toBlock,
+	})
+	cancel()
+	if err != nil {
+		// The range is still skipped, it's only missing from the audits
+		s.logger.Warn().
 			Str("operation", opName).
-			Uint64("max_block_range", maxBlockRange).
-			Msg("Using smaller block range for Ethereum mainnet")
-	} else {
-		maxBlockRange = DefaultMaxBlockRange
-	}
-
-	// Prepare event signatures for bloom filtering
-	eventSigs := []common.Hash{
-		intentService.abi.Events[IntentInitiatedEventName].ID,
-		intentService.abi.Events[IntentInitiatedWithCallEventName].ID,
+			Uint64(logging.FieldChain, chainID).
+			Err(err).
+			Msg("Failed to record skipped block range")
 	}
 
-	// Process in chunks to avoid RPC provider limitations
-	for chunkStart := fromBlock; chunkStart < toBlock; chunkStart += maxBlockRange {
-		// Check for context cancellation
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
-
-		chunkEnd := chunkStart + maxBlockRange
-		if chunkEnd > toBlock {
-			chunkEnd = toBlock
-		}
-
-		// For Ethereum, do a quick check if this range might have events
-		// This can dramatically speed up scanning large empty ranges
-		if intentService.chainID == 1 { // Ethereum mainnet
-			hasEvents, err := hasEventsInBlockRange(ctx, intentService.client, contractAddress,
-				eventSigs, chunkStart+1, chunkEnd)
-			if err != nil {
-				s.logger.Debug().
-					Str("operation", opName).
-					Err(err).
-					Msg("Error in bloom check, will process range")
-			} else if !hasEvents {
-				// Skip this chunk as it likely has no events
-				s.logger.Debug().
-					Str("operation", opName).
-					Uint64("from_block", chunkStart+1).
-					Uint64("to_block", chunkEnd).
-					Msg("Fast-forwarding through block range (no events detected)")
-
-				// Update progress even though we're skipping
-				s.UpdateIntentProgress(intentService.chainID, chunkEnd)
-				continue
-			}
-		}
-
-		// Track this chunk processing
-		chunkOpName := fmt.Sprintf("%s_chunk_%d_%d", opName, chunkStart, chunkStart+maxBlockRange)
-		s.trackCatchupOperation(chunkOpName)
-
-		// Create a context with timeout for this chunk
-		chunkCtx, chunkCancel := context.WithTimeout(ctx, BlockRangeProcessTimeout)
-
-		err := func() error {
-			defer chunkCancel()
-			defer s.untrackCatchupOperation(chunkOpName)
-
-			s.logger.Debug().
-				Str("operation", opName).
-				Uint64("from_block", chunkStart+1).
-				Uint64("to_block", chunkEnd).
-				Msg("Fetching intent logs for blocks")
-
-			query := ethereum.FilterQuery{
-				FromBlock: big.NewInt(int64(chunkStart + 1)),
-				ToBlock:   big.NewInt(int64(chunkEnd)),
-				Addresses: []common.Address{contractAddress},
-				Topics: [][]common.Hash{
-					{
-						intentService.abi.Events[IntentInitiatedEventName].ID,
-						intentService.abi.Events[IntentInitiatedWithCallEventName].ID,
-					},
-				},
-			}
-
-			// Add timeout for FilterLogs
-			filterCtx, filterCancel := context.WithTimeout(chunkCtx, FilterLogsTimeout)
-			logs, err := intentService.client.FilterLogs(filterCtx, query)
-			filterCancel()
-
-			if err != nil {
-				return fmt.Errorf("failed to fetch intent logs for range %d-%d: %v", chunkStart+1, chunkEnd, err)
-			}
-
-			s.logger.Debug().
-				Str("operation", opName).
-				Int("log_count", len(logs)).
-				Uint64("from_block", chunkStart+1).
-				Uint64("to_block", chunkEnd).
-				Msg("Processing logs from blocks")
-
-			// Process logs in batches to report progress
-			batchSize := 100
-			for i := 0; i < len(logs); i += batchSize {
-				// Check for context cancellation
-				if chunkCtx.Err() != nil {
-					return chunkCtx.Err()
-				}
-
-				end := i + batchSize
-				if end > len(logs) {
-					end = len(logs)
-				}
-
-				batch := logs[i:end]
-
-				// Create a context with timeout for this batch
-				batchCtx, batchCancel := context.WithTimeout(chunkCtx, LogBatchProcessTimeout)
-
-				batchErr := func() error {
-					defer batchCancel()
-
-					for j, txlog := range batch {
-						// Check for context cancellation
-						if batchCtx.Err() != nil {
-							return batchCtx.Err()
-						}
-
-						s.logger.Debug().
-							Str("operation", opName).
-							Int("log_index", i+j+1).
-							Int("total_logs", len(logs)).
-							Uint64("block_number", txlog.BlockNumber).
-							Str("tx_hash", txlog.TxHash.Hex()).
-							Msg("Processing intent log")
-
-						// Extract intent ID from the log
-						intentID := txlog.Topics[1].Hex()
-
-						// Check if intent already exists
-						getIntentCtx, cancel := context.WithTimeout(batchCtx, 10*time.Second)
-						existingIntent, err := s.db.GetIntent(getIntentCtx, intentID)
-						cancel()
-
-						if err != nil && !errors.Is(err, db.ErrNotFound) {
-							return fmt.Errorf("failed to check for existing intent: %v", err)
-						}
-
-						// Skip if intent already exists
-						if existingIntent != nil {
-							s.logger.Debug().
-								Str("operation", opName).
-								Str(logging.FieldIntent, intentID).
-								Msg("Skipping existing intent")
-							continue
-						}
-
-						// Process log with timeout
-						processCtx, processCancel := context.WithTimeout(batchCtx, 20*time.Second)
-						err = intentService.processLog(processCtx, txlog)
-						processCancel()
-
-						if err != nil {
-							// Skip if intent already exists
-							if strings.Contains(err.Error(), "duplicate key") {
-								s.logger.Debug().
-									Str("operation", opName).
-									Str(logging.FieldIntent, intentID).
-									Msg("Skipping duplicate intent")
-								continue
-							}
-							return fmt.Errorf("failed to process intent log: %v", err)
-						}
-					}
-					return nil
-				}()
-
-				if batchErr != nil {
-					return batchErr
-				}
-
-				// Update progress after each batch
-				if len(batch) > 0 {
-					lastBlock := batch[len(batch)-1].BlockNumber
-					s.UpdateIntentProgress(intentService.chainID, lastBlock)
-					s.logger.Debug().
-						Str("operation", opName).
-						Uint64(logging.FieldChain, intentService.chainID).
-						Uint64("block_number", lastBlock).
-						Msg("Updated progress")
-				}
-			}
-
-			// Update progress after processing each chunk
-			s.UpdateIntentProgress(intentService.chainID, chunkEnd)
+	s.persistChunkCheckpoint(ctx, opName, chainID, eventType, contractAddress, toBlock)
+}
 
-			// Persist progress to the database after each chunk
-			dbUpdateCtx, dbUpdateCancel := context.WithTimeout(ctx, 10*time.Second)
-			dbErr := s.db.UpdateLastProcessedBlock(dbUpdateCtx, intentService.chainID, chunkEnd)
-			dbUpdateCancel()
-			if dbErr != nil {
-				s.logger.Debug().
-					Str("operation", opName).
-					Err(dbErr).
-					Msg("Warning: Failed to persist progress to DB")
-				// Continue processing even if DB update fails
-			} else {
-				s.logger.Debug().
-					Str("operation", opName).
-					Uint64(logging.FieldChain, intentService.chainID).
-					Uint64("block_number", chunkEnd).
-					Msg("Persisted progress to DB")
-			}
+// rewindProgress moves the progress of every event type on a chain back before fromBlock
+// so that the blocks replacing a reorged range get re-indexed
+func (s *EventCatchupService) rewindProgress(chainID, fromBlock uint64) {
+	if fromBlock == 0 {
+		return
+	}
 
-			s.logger.Debug().
-				Str("operation", opName).
-				Uint64("from_block", chunkStart+1).
-				Uint64("to_block", chunkEnd).
-				Msg("Completed processing intent logs for blocks")
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-			return nil
-		}()
-		if err != nil {
-			return err
+	for _, progress := range []map[uint64]uint64{s.intentProgress, s.fulfillmentProgress, s.settlementProgress} {
+		if last, ok := progress[chainID]; ok && last >= fromBlock {
+			progress[chainID] = fromBlock - 1
 		}
 	}
-
-	return nil
 }
 
-// catchUpOnFulfillmentEvents processes missed fulfillment events for a specific chain
-func (s *EventCatchupService) catchUpOnFulfillmentEvents(
+// catchUpOnEvents processes the missed events of a handler on its chain. Logs are handed to the handler
+// like live ones, so logs of intents that aren't indexed yet are queued by the handler until they are.
+func (s *EventCatchupService) catchUpOnEvents(
 	ctx context.Context,
-	fulfillmentService *FulfillmentService,
+	handler chainHandler,
 	contractAddress common.Address,
 	fromBlock, toBlock uint64,
 	opName string,
 ) error {
-	// Use a chain-specific block range - smaller for Ethereum mainnet
-	var maxBlockRange uint64
-	if fulfillmentService.chainID == 1 { // Ethereum mainnet
-		maxBlockRange = EthereumMaxBlockRange
-		s.logger.Debug().
-			Str("operation", opName).
-			Uint64("max_block_range", maxBlockRange).
-			Msg("Using smaller block range for Ethereum mainnet")
-	} else {
-		maxBlockRange = DefaultMaxBlockRange
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{contractAddress},
+		Topics:    [][]common.Hash{handler.Topics},
 	}
 
-	// Process in chunks to avoid RPC provider limitations
-	for chunkStart := fromBlock; chunkStart < toBlock; chunkStart += maxBlockRange {
+	// Probe for events ahead at the start and after empty chunks, not right after a skip or a chunk with events
+	probe := handler.skipEmptyRanges
+
+	// Process in chunks of the adaptive range of the chain to avoid RPC provider limitations
+	for chunkStart := fromBlock; chunkStart < toBlock; {
 		// Check for context cancellation
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
-		// Track this chunk processing
-		chunkOpName := fmt.Sprintf("%s_chunk_%d_%d", opName, chunkStart, chunkStart+maxBlockRange)
-		s.trackCatchupOperation(chunkOpName)
-
-		// Create a context with timeout for this chunk
-		chunkCtx, chunkCancel := context.WithTimeout(ctx, BlockRangeProcessTimeout)
-
-		err := func() error {
-			defer chunkCancel()
-			defer s.untrackCatchupOperation(chunkOpName)
-
-			chunkEnd := chunkStart + maxBlockRange
-			if chunkEnd > toBlock {
-				chunkEnd = toBlock
-			}
-
-			s.logger.Debug().
-				Str("operation", opName).
-				Uint64("from_block", chunkStart+1).
-				Uint64("to_block", chunkEnd).
-				Msg("Fetching fulfillment logs for blocks")
-
-			query := ethereum.FilterQuery{
-				FromBlock: big.NewInt(int64(chunkStart + 1)),
-				ToBlock:   big.NewInt(int64(chunkEnd)),
-				Addresses: []common.Address{contractAddress},
-				Topics: [][]common.Hash{
-					{
-						fulfillmentService.abi.Events[IntentFulfilledEventName].ID,
-						fulfillmentService.abi.Events[IntentFulfilledWithCallEventName].ID,
-					},
-				},
-			}
-
-			// Add timeout for FilterLogs
-			filterCtx, filterCancel := context.WithTimeout(chunkCtx, FilterLogsTimeout)
-			logs, err := fulfillmentService.client.FilterLogs(filterCtx, query)
-			filterCancel()
+		chunkEnd := min(chunkStart+handler.logRange.Size(), toBlock)
 
+		// Fast-forward through the blocks before the next event, this dramatically speeds up
+		// scanning sparse contracts on chains with dense blocks
+		if probe {
+			probeEnd := min(chunkStart+skipProbeChunks*handler.logRange.Size(), toBlock)
+			emptyEnd, err := emptyRangeEnd(ctx, handler.client, query, chunkStart, probeEnd)
 			if err != nil {
-				return fmt.Errorf("failed to fetch fulfillment logs for range %d-%d: %v", chunkStart+1, chunkEnd, err)
-			}
-
-			s.logger.Debug().
-				Str("operation", opName).
-				Int("log_count", len(logs)).
-				Uint64("from_block", chunkStart+1).
-				Uint64("to_block", chunkEnd).
-				Msg("Processing logs from blocks")
-
-			// Process logs in batches to report progress
-			batchSize := 100
-			for i := 0; i < len(logs); i += batchSize {
-				// Check for context cancellation
-				if chunkCtx.Err() != nil {
-					return chunkCtx.Err()
-				}
-
-				end := i + batchSize
-				if end > len(logs) {
-					end = len(logs)
-				}
-
-				batch := logs[i:end]
-
-				// Create a context with timeout for this batch
-				batchCtx, batchCancel := context.WithTimeout(chunkCtx, LogBatchProcessTimeout)
-
-				batchErr := func() error {
-					defer batchCancel()
-
-					for j, txlog := range batch {
-						// Check for context cancellation
-						if batchCtx.Err() != nil {
-							return batchCtx.Err()
-						}
-
-						s.logger.Debug().
-							Str("operation", opName).
-							Int("log_index", i+j+1).
-							Int("total_logs", len(logs)).
-							Uint64("block_number", txlog.BlockNumber).
-							Str("tx_hash", txlog.TxHash.Hex()).
-							Msg("Processing fulfillment log")
-
-						// Extract intent ID from the log
-						intentID := txlog.Topics[1].Hex()
-
-						// Check if intent exists (fulfillments need an intent)
-						getIntentCtx, cancel := context.WithTimeout(batchCtx, 10*time.Second)
-						_, err := s.db.GetIntent(getIntentCtx, intentID)
-						cancel()
-
-						if err != nil {
-							if errors.Is(err, db.ErrNotFound) {
-								s.logger.Debug().
-									Str("operation", opName).
-									Str(logging.FieldIntent, intentID).
-									Msg("Skipping fulfillment for non-existent intent")
-								continue
-							}
-							s.logger.Debug().
-								Str("operation", opName).
-								Err(err).
-								Msg("Failed to check for existing intent")
-							continue
-						}
-
-						// Process log with timeout
-						processCtx, processCancel := context.WithTimeout(batchCtx, 20*time.Second)
-						err = fulfillmentService.processLog(processCtx, txlog)
-						processCancel()
-
-						if err != nil {
-							// Skip if fulfillment already exists
-							if strings.Contains(err.Error(), "duplicate key") {
-								s.logger.Debug().
-									Str("operation", opName).
-									Str(logging.FieldIntent, intentID).
-									Msg("Skipping duplicate fulfillment")
-								continue
-							}
-							return fmt.Errorf("failed to process fulfillment log: %v", err)
-						}
-					}
-					return nil
-				}()
-
-				if batchErr != nil {
-					return batchErr
-				}
-
-				// Update progress after each batch
-				if len(batch) > 0 {
-					lastBlock := batch[len(batch)-1].BlockNumber
-					s.UpdateFulfillmentProgress(fulfillmentService.chainID, lastBlock)
-					s.logger.Debug().
-						Str("operation", opName).
-						Uint64(logging.FieldChain, fulfillmentService.chainID).
-						Uint64("block_number", lastBlock).
-						Msg("Updated progress")
-				}
-			}
-
-			// Update progress after processing each chunk
-			s.UpdateFulfillmentProgress(fulfillmentService.chainID, chunkEnd)
-
-			// Persist progress to the database after each chunk
-			dbUpdateCtx, dbUpdateCancel := context.WithTimeout(ctx, 10*time.Second)
-			dbErr := s.db.UpdateLastProcessedBlock(dbUpdateCtx, fulfillmentService.chainID, chunkEnd)
-			dbUpdateCancel()
-			if dbErr != nil {
 				s.logger.Debug().
 					Str("operation", opName).
-					Err(dbErr).
-					Msg("Warning: Failed to persist progress to DB")
-				// Continue processing even if DB update fails
-			} else {
-				s.logger.Debug().
-					Str("operation", opName).
-					Uint64(logging.FieldChain, fulfillmentService.chainID).
-					Uint64("block_number", chunkEnd).
-					Msg("Persisted progress to DB")
-			}
-
-			s.logger.Debug().
-				Str("operation", opName).
-				Uint64("from_block", chunkStart+1).
-				Uint64("to_block", chunkEnd).
-				Msg("Completed processing fulfillment logs for blocks")
-
-			return nil
-		}()
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// catchUpOnSettlementEvents processes missed settlement events for a specific chain
-func (s *EventCatchupService) catchUpOnSettlementEvents(
-	ctx context.Context,
-	settlementService *SettlementService,
-	contractAddress common.Address,
-	fromBlock, toBlock uint64,
-	opName string,
-) error {
-	// Use a chain-specific block range - smaller for Ethereum mainnet
-	var maxBlockRange uint64
-	if settlementService.chainID == 1 { // Ethereum mainnet
-		maxBlockRange = EthereumMaxBlockRange
-		s.logger.Debug().
-			Str("operation", opName).
-			Uint64("max_block_range", maxBlockRange).
-			Msg("Using smaller block range for Ethereum mainnet")
-	} else {
-		maxBlockRange = DefaultMaxBlockRange
-	}
-
-	// Process in chunks to avoid RPC provider limitations
-	for chunkStart := fromBlock; chunkStart < toBlock; chunkStart += maxBlockRange {
-		// Check for context cancellation
-		if ctx.Err() != nil {
-			return ctx.Err()
+					Err(err).
+					Msg("Failed to probe block range for events, will process range")
+			} else if emptyEnd > chunkStart {
+				s.skipRange(ctx, opName, handler.chainID, handler.EventType, contractAddress, chunkStart, emptyEnd)
+				s.updateProgress(handler.chainID, handler.EventType, emptyEnd)
+				chunkStart = emptyEnd
+				probe = false
+				continue
+			}
 		}
 
 		// Track this chunk processing
-		chunkOpName := fmt.Sprintf("%s_chunk_%d_%d", opName, chunkStart, chunkStart+maxBlockRange)
+		chunkOpName := fmt.Sprintf("%s_chunk_%d_%d", opName, chunkStart, chunkEnd)
 		s.trackCatchupOperation(chunkOpName)
 
 		// Create a context with timeout for this chunk
@@ -1561,37 +907,19 @@ func (s *EventCatchupService) catchUpOnSettlementEvents(
 			defer chunkCancel()
 			defer s.untrackCatchupOperation(chunkOpName)
 
-			chunkEnd := chunkStart + maxBlockRange
-			if chunkEnd > toBlock {
-				chunkEnd = toBlock
-			}
-
 			s.logger.Debug().
 				Str("operation", opName).
 				Uint64("from_block", chunkStart+1).
 				Uint64("to_block", chunkEnd).
-				Msg("Fetching settlement logs for blocks")
-
-			query := ethereum.FilterQuery{
-				FromBlock: big.NewInt(int64(chunkStart + 1)),
-				ToBlock:   big.NewInt(int64(chunkEnd)),
-				Addresses: []common.Address{contractAddress},
-				Topics: [][]common.Hash{
-					{
-						settlementService.abi.Events[IntentSettledEventName].ID,
-						settlementService.abi.Events[IntentSettledWithCallEventName].ID,
-					},
-				},
-			}
-
-			// Add timeout for FilterLogs
-			filterCtx, filterCancel := context.WithTimeout(chunkCtx, FilterLogsTimeout)
-			logs, err := settlementService.client.FilterLogs(filterCtx, query)
-			filterCancel()
+				Msg("Fetching logs for blocks")
 
+			// The chunk ends earlier if the range had to shrink
+			logs, end, err := filterLogsInRange(chunkCtx, handler.client, handler.logRange, query, chunkStart, chunkEnd)
 			if err != nil {
-				return fmt.Errorf("failed to fetch settlement logs for range %d-%d: %v", chunkStart+1, chunkEnd, err)
+				return errs.Upstream(err, "failed to fetch %s logs for range %d-%d", handler.EventType, chunkStart+1, end)
 			}
+			chunkEnd = end
+			probe = handler.skipEmptyRanges && len(logs) == 0
 
 			s.logger.Debug().
 				Str("operation", opName).
@@ -1633,42 +961,23 @@ func (s *EventCatchupService) catchUpOnSettlementEvents(
 							Int("total_logs", len(logs)).
 							Uint64("block_number", txlog.BlockNumber).
 							Str("tx_hash", txlog.TxHash.Hex()).
-							Msg("Processing settlement log")
-
-						// Extract intent ID from the log
-						intentID := txlog.Topics[1].Hex()
-
-						// Check if intent exists (settlements need an intent)
-						getIntentCtx, cancel := context.WithTimeout(batchCtx, 10*time.Second)
-						_, err := s.db.GetIntent(getIntentCtx, intentID)
-						cancel()
-
-						if err != nil {
-							if errors.Is(err, db.ErrNotFound) {
-								s.logger.Debug().
-									Str("operation", opName).
-									Str(logging.FieldIntent, intentID).
-									Msg("Skipping settlement for non-existent intent")
-								continue
-							}
-							return fmt.Errorf("failed to check for existing intent: %v", err)
-						}
+							Msg("Processing log")
 
 						// Process log with timeout
 						processCtx, processCancel := context.WithTimeout(batchCtx, 20*time.Second)
-						err = settlementService.processLog(processCtx, txlog)
+						err := handler.Handle(processCtx, txlog)
 						processCancel()
 
 						if err != nil {
-							// Skip if settlement already exists
-							if strings.Contains(err.Error(), "duplicate key") {
+							// Skip if the event already exists
+							if errors.Is(err, db.ErrDuplicate) {
 								s.logger.Debug().
 									Str("operation", opName).
-									Str(logging.FieldIntent, intentID).
-									Msg("Skipping duplicate settlement")
+									Str("tx_hash", txlog.TxHash.Hex()).
+									Msg("Skipping duplicate event")
 								continue
 							}
-							return fmt.Errorf("failed to process settlement log: %v", err)
+							return fmt.Errorf("failed to process %s log: %v", handler.EventType, err)
 						}
 					}
 					return nil
@@ -1681,154 +990,111 @@ func (s *EventCatchupService) catchUpOnSettlementEvents(
 				// Update progress after each batch
 				if len(batch) > 0 {
 					lastBlock := batch[len(batch)-1].BlockNumber
-					s.UpdateSettlementProgress(settlementService.chainID, lastBlock)
+					s.updateProgress(handler.chainID, handler.EventType, lastBlock)
 					s.logger.Debug().
 						Str("operation", opName).
-						Uint64(logging.FieldChain, settlementService.chainID).
+						Uint64(logging.FieldChain, handler.chainID).
 						Uint64("block_number", lastBlock).
 						Msg("Updated progress")
 				}
 			}
 
 			// Update progress after processing each chunk
-			s.UpdateSettlementProgress(settlementService.chainID, chunkEnd)
+			s.updateProgress(handler.chainID, handler.EventType, chunkEnd)
 
 			// Persist progress to the database after each chunk
-			dbUpdateCtx, dbUpdateCancel := context.WithTimeout(ctx, 10*time.Second)
-			dbErr := s.db.UpdateLastProcessedBlock(dbUpdateCtx, settlementService.chainID, chunkEnd)
-			dbUpdateCancel()
-			if dbErr != nil {
-				s.logger.Debug().
-					Str("operation", opName).
-					Err(dbErr).
-					Msg("Warning: Failed to persist progress to DB")
-				// Continue processing even if DB update fails
-			} else {
-				s.logger.Debug().
-					Str("operation", opName).
-					Uint64(logging.FieldChain, settlementService.chainID).
-					Uint64("block_number", chunkEnd).
-					Msg("Persisted progress to DB")
-			}
+			s.persistChunkCheckpoint(ctx, opName, handler.chainID, handler.EventType, contractAddress, chunkEnd)
 
 			s.logger.Debug().
 				Str("operation", opName).
 				Uint64("from_block", chunkStart+1).
 				Uint64("to_block", chunkEnd).
-				Msg("Completed processing settlement logs for blocks")
+				Msg("Completed processing logs for blocks")
 
 			return nil
 		}()
 		if err != nil {
 			return err
 		}
+
+		chunkStart = chunkEnd
 	}
 
 	return nil
 }
 
-// pollZetachainEvents polls for events on ZetaChain at regular intervals instead of using WebSocket subscription
-func (s *EventCatchupService) pollZetachainEvents(
-	ctx context.Context,
-	intentService *IntentService,
-	contractAddress common.Address,
-	blockInterval int64,
-) {
-	// Use the generic polling function with health reporting
-	s.pollChainEvents(
-		ctx,
-		eventTypeIntent,
-		7000,
-		intentService.client,
-		contractAddress,
-		[]common.Hash{
-			intentService.abi.Events[IntentInitiatedEventName].ID,
-			intentService.abi.Events[IntentInitiatedWithCallEventName].ID,
-		},
-		intentService.processLog,
-		func(blockNum uint64) { s.UpdateIntentProgress(7000, blockNum) },
-		blockInterval,
-		intentService,
-	) // Pass intent service for health reporting
+// IsPollingChain returns whether the events of a chain are polled instead of received from subscriptions
+func (s *EventCatchupService) IsPollingChain(chainID uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pollingChains[chainID]
 }
 
-// pollZetachainFulfillmentEvents polls for events on ZetaChain at regular intervals instead of using WebSocket subscription
-func (s *EventCatchupService) pollZetachainFulfillmentEvents(
-	ctx context.Context,
-	fulfillmentService *FulfillmentService,
-	contractAddress common.Address,
-	blockInterval int64,
-) {
-	// Use the generic polling function
-	s.pollChainEvents(
-		ctx,
-		"fulfillment",
-		7000,
-		fulfillmentService.client,
-		contractAddress,
-		[]common.Hash{
-			fulfillmentService.abi.Events[IntentFulfilledEventName].ID,
-			fulfillmentService.abi.Events[IntentFulfilledWithCallEventName].ID,
-		},
-		fulfillmentService.processLog,
-		func(blockNum uint64) { s.UpdateFulfillmentProgress(7000, blockNum) },
-		blockInterval,
-		nil,
-	) // No health reporting for fulfillment services yet
+// fallBackToPolling switches a hybrid chain to polling after its subscription failed.
+// Returns false if the ingestion mode of the chain doesn't allow polling.
+func (s *EventCatchupService) fallBackToPolling(chainID uint64, mode config.IngestionMode, err error) bool {
+	if mode != config.IngestionHybrid {
+		return false
+	}
+
+	s.mu.Lock()
+	s.pollingChains[chainID] = true
+	s.mu.Unlock()
+
+	s.logger.Warn().
+		Uint64(logging.FieldChain, chainID).
+		Err(err).
+		Msg("Subscription failed, falling back to polling")
+
+	return true
 }
 
-// pollZetachainSettlementEvents polls for events on ZetaChain at regular intervals instead of using WebSocket subscription
-func (s *EventCatchupService) pollZetachainSettlementEvents(
+// startPolling polls the events of a handler on its chain from a block,
+// the polling health of the chain is reported to its intent service
+func (s *EventCatchupService) startPolling(
 	ctx context.Context,
-	settlementService *SettlementService,
+	handler chainHandler,
 	contractAddress common.Address,
+	fromBlock uint64,
 	blockInterval int64,
+	intentService *IntentService,
 ) {
-	// Use the generic polling function
-	s.pollChainEvents(
-		ctx,
-		"settlement",
-		7000,
-		settlementService.client,
-		contractAddress,
-		[]common.Hash{
-			settlementService.abi.Events[IntentSettledEventName].ID,
-			settlementService.abi.Events[IntentSettledWithCallEventName].ID,
-		},
-		settlementService.processLog,
-		func(blockNum uint64) { s.UpdateSettlementProgress(7000, blockNum) },
-		blockInterval,
-		nil,
-	) // No health reporting for settlement services yet
+	// Store the initial block to start polling from
+	s.updateProgress(handler.chainID, handler.EventType, fromBlock)
+
+	s.logger.Info().
+		Uint64(logging.FieldChain, handler.chainID).
+		Str("event_type", handler.EventType).
+		Uint64("from_block", fromBlock).
+		Msg("Setting up polling-based event monitoring")
+
+	go s.pollChainEvents(ctx, handler, contractAddress, blockInterval, intentService)
 }
 
-// pollChainEvents is a generic function to poll for blockchain events
+// pollChainEvents polls for the events of a handler on its chain
 // It handles all the common logic for different event types
 func (s *EventCatchupService) pollChainEvents(
 	ctx context.Context,
-	eventType string,
-	chainID uint64,
-	client *ethclient.Client,
+	handler chainHandler,
 	contractAddress common.Address,
-	eventSignatures []common.Hash,
-	processLogFunc func(context.Context, types.Log) error,
-	updateProgressFunc func(uint64),
 	blockInterval int64,
-	intentService *IntentService, // Optional: for health reporting (ZetaChain only)
+	intentService *IntentService, // Optional: for health reporting of intent polling
 ) {
+	eventType := handler.EventType
+	chainID := handler.chainID
+	client := handler.client
+
 	// Default to checking every 15 seconds if not specified in config
 	interval := time.Duration(blockInterval) * time.Second
 	if interval < 5*time.Second {
 		interval = 15 * time.Second
 	}
 
-	maxRetries := 3
-	baseRetryDelay := 5 * time.Second
-
 	s.logger.Info().
+		Uint64(logging.FieldChain, chainID).
 		Str("event_type", eventType).
 		Dur("interval", interval).
-		Msg("Starting ZetaChain polling for events")
+		Msg("Starting polling for events")
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -1842,26 +1108,16 @@ func (s *EventCatchupService) pollChainEvents(
 		case <-ctx.Done():
 			s.logger.Info().
 				Str("event_type", eventType).
-				Msg("Context cancelled, stopping ZetaChain event polling")
+				Msg("Context cancelled, stopping event polling")
 			return
 		case <-ticker.C:
 			// Get the last processed block
-			s.mu.Lock()
-			var lastProcessedBlock uint64
-			switch eventType {
-			case eventTypeIntent:
-				lastProcessedBlock = s.intentProgress[chainID]
-			case "fulfillment":
-				lastProcessedBlock = s.fulfillmentProgress[chainID]
-			case "settlement":
-				lastProcessedBlock = s.settlementProgress[chainID]
-			}
-			s.mu.Unlock()
+			lastProcessedBlock := s.lastProcessedBlock(chainID, eventType)
 
 			// Get current block with retry logic
 			var currentBlock uint64
 			var err error
-			for retry := 0; retry < maxRetries; retry++ {
+			for retry := 0; retry < pollMaxRetries; retry++ {
 				blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 				currentBlock, err = client.BlockNumber(blockCtx)
 				cancel()
@@ -1870,13 +1126,13 @@ func (s *EventCatchupService) pollChainEvents(
 					break
 				}
 
-				retryDelay := baseRetryDelay * time.Duration(1<<retry)
+				retryDelay := pollRetryDelay * time.Duration(1<<retry)
 				s.logger.Error().
 					Int("attempt", retry+1).
-					Int("max_attempts", maxRetries).
+					Int("max_attempts", pollMaxRetries).
 					Err(err).
 					Dur("retry_delay", retryDelay).
-					Msg("Failed to get current block for ZetaChain")
+					Msg("Failed to get current block")
 
 				select {
 				case <-time.After(retryDelay):
@@ -1888,8 +1144,8 @@ func (s *EventCatchupService) pollChainEvents(
 
 			if err != nil {
 				s.logger.Error().
-					Int("max_attempts", maxRetries).
-					Msg("CRITICAL: Failed to get current block for ZetaChain after retries. Skipping this polling cycle.")
+					Int("max_attempts", pollMaxRetries).
+					Msg("CRITICAL: Failed to get current block after retries. Skipping this polling cycle.")
 				// Report unhealthy polling if we have an intent service to report to
 				if intentService != nil && eventType == eventTypeIntent {
 					intentService.UpdatePollingHealth(false)
@@ -1902,12 +1158,36 @@ func (s *EventCatchupService) pollChainEvents(
 				intentService.UpdatePollingHealth(true)
 			}
 
+			// Verify previously polled blocks are still canonical before moving forward
+			reorgCtx, reorgCancel := context.WithTimeout(ctx, 30*time.Second)
+			rollback, err := s.reorg.CheckCanonical(reorgCtx, chainID, client)
+			reorgCancel()
+
+			if err != nil {
+				s.logger.Warn().
+					Str("event_type", eventType).
+					Err(err).
+					Msg("Failed to check for chain reorganization")
+			} else if rollback != nil {
+				s.rewindProgress(chainID, rollback.FromBlock)
+				if lastProcessedBlock >= rollback.FromBlock {
+					lastProcessedBlock = rollback.FromBlock - 1
+				}
+			}
+
 			// Skip if no new blocks
 			if currentBlock <= lastProcessedBlock {
 				if time.Since(lastDbUpdateTime) >= dbUpdateInterval {
 					// Even if no new blocks, periodically update the DB to ensure we don't lose progress
 					dbUpdateCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-					if err := s.db.UpdateLastProcessedBlock(dbUpdateCtx, chainID, lastProcessedBlock); err != nil {
+					err := s.db.AdvanceEventCheckpoint(
+						dbUpdateCtx,
+						chainID,
+						eventType,
+						contractAddress.Hex(),
+						lastProcessedBlock,
+					)
+					if err != nil {
 						s.logger.Warn().
 							Str("event_type", eventType).
 							Err(err).
@@ -1925,134 +1205,152 @@ func (s *EventCatchupService) pollChainEvents(
 				continue
 			}
 
-			// Limit the number of blocks we process at once to avoid timeouts
-			endBlock := lastProcessedBlock + 5000
-			if endBlock > currentBlock {
-				endBlock = currentBlock
-			}
-
-			s.logger.Debug().
-				Str("event_type", eventType).
-				Uint64("from_block", lastProcessedBlock+1).
-				Uint64("to_block", endBlock).
-				Msg("Polling ZetaChain for events")
-
-			// Create query for the block range
-			query := ethereum.FilterQuery{
-				FromBlock: big.NewInt(int64(lastProcessedBlock + 1)),
-				ToBlock:   big.NewInt(int64(endBlock)),
-				Addresses: []common.Address{contractAddress},
-				Topics: [][]common.Hash{
-					eventSignatures,
-				},
-			}
-
-			// Filter logs with retry logic
-			var logs []types.Log
-			for retry := 0; retry < maxRetries; retry++ {
-				filterCtx, filterCancel := context.WithTimeout(ctx, FilterLogsTimeout)
-				logs, err = client.FilterLogs(filterCtx, query)
-				filterCancel()
-
-				if err == nil {
-					break
+			// The blocks are polled at most the adaptive log range of the chain at once
+			completedBlock, err := s.pollRange(ctx, handler, contractAddress, lastProcessedBlock, currentBlock)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
 				}
-
-				retryDelay := baseRetryDelay * time.Duration(1<<retry)
 				s.logger.Error().
 					Str("event_type", eventType).
-					Int("attempt", retry+1).
-					Int("max_attempts", maxRetries).
+					Uint64("from_block", lastProcessedBlock+1).
 					Err(err).
-					Dur("retry_delay", retryDelay).
-					Msg("Failed to filter logs for ZetaChain events")
-
-				select {
-				case <-time.After(retryDelay):
-					continue
-				case <-ctx.Done():
-					return
-				}
+					Msg("Polling stopped before the end of the block range")
 			}
-
-			if err != nil {
-				s.logger.Error().
-					Str("event_type", eventType).
-					Int("max_attempts", maxRetries).
-					Msg("CRITICAL: Failed to filter logs for ZetaChain events after retries. Skipping this block range.")
+			if completedBlock <= lastProcessedBlock {
 				continue
 			}
 
-			// Process logs if any found
-			processedCount := 0
-			errorCount := 0
-			if len(logs) > 0 {
-				s.logger.Debug().
-					Int("log_count", len(logs)).
+			// Remember the hash of the range end so a later reorg of it can be detected
+			headCtx, headCancel := context.WithTimeout(ctx, 10*time.Second)
+			if err := s.reorg.RecordHead(headCtx, chainID, client, completedBlock); err != nil {
+				s.logger.Warn().
 					Str("event_type", eventType).
-					Uint64("from_block", lastProcessedBlock+1).
-					Uint64("to_block", endBlock).
-					Msg("Found new events in ZetaChain blocks")
+					Err(err).
+					Msg("Failed to record block hash")
+			}
+			headCancel()
 
-				// Process the logs with individual timeouts
-				for _, logEntry := range logs {
-					processCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-					err := processLogFunc(processCtx, logEntry)
-					cancel()
+			lastDbUpdateTime = time.Now()
+		}
+	}
+}
 
-					if err != nil {
-						errorCount++
-						if strings.Contains(err.Error(), "duplicate key") {
-							// This is expected for duplicates, just log at debug level
-							s.logger.Debug().
-								Str("event_type", eventType).
-								Str("tx_hash", logEntry.TxHash.Hex()).
-								Msg("Skipping duplicate event")
-						} else {
-							s.logger.Error().
-								Str("event_type", eventType).
-								Err(err).
-								Msg("Failed to process ZetaChain log")
-						}
-					} else {
-						processedCount++
-					}
-				}
-				s.logger.Info().
-					Int("processed_count", processedCount).
-					Int("total_logs", len(logs)).
-					Str("event_type", eventType).
-					Int("error_count", errorCount).
-					Msg("Successfully processed events")
-			} else {
-				s.logger.Info().
-					Str("event_type", eventType).
-					Uint64("from_block", lastProcessedBlock+1).
-					Uint64("to_block", endBlock).
-					Msg("No new events found in ZetaChain blocks")
-			}
+// pollRange hands the logs of the blocks after fromBlock up to toBlock to a handler, at most the adaptive log range
+// of its chain at once, stopping at the first log that fails. It moves the progress and checkpoint of the handler
+// to the last completed block.
+// The checkpoint only moves forward, so it never overwrites the checkpoint of a log persisted since.
+// Returns the last completed block, fromBlock if the first log failed.
+func (s *EventCatchupService) pollRange(
+	ctx context.Context,
+	handler chainHandler,
+	contractAddress common.Address,
+	fromBlock, toBlock uint64,
+) (uint64, error) {
+	eventType := handler.EventType
+	chainID := handler.chainID
+
+	s.logger.Debug().
+		Str("event_type", eventType).
+		Uint64("from_block", fromBlock+1).
+		Uint64("to_block", toBlock).
+		Msg("Polling for events")
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{contractAddress},
+		Topics: [][]common.Hash{
+			handler.Topics,
+		},
+	}
 
-			// Update the last processed block
-			updateProgressFunc(endBlock)
+	// Filter logs with retry logic, the range shrinks while the provider rejects it as too large
+	var (
+		logs     []types.Log
+		endBlock uint64
+		err      error
+	)
+	for retry := 0; retry < pollMaxRetries; retry++ {
+		logs, endBlock, err = filterLogsInRange(ctx, handler.client, handler.logRange, query, fromBlock, toBlock)
+		if err == nil {
+			break
+		}
 
-			// Persist progress to the database
-			dbUpdateCtx, dbUpdateCancel := context.WithTimeout(ctx, 10*time.Second)
-			if err := s.db.UpdateLastProcessedBlock(dbUpdateCtx, chainID, endBlock); err != nil {
-				s.logger.Warn().
-					Str("event_type", eventType).
-					Err(err).
-					Msg("Failed to persist progress to DB")
-			} else {
-				s.logger.Debug().
-					Str("event_type", eventType).
-					Uint64(logging.FieldChain, chainID).
-					Uint64("block_number", endBlock).
-					Msg("Persisted progress to DB")
-				lastDbUpdateTime = time.Now()
-			}
-			dbUpdateCancel()
+		retryDelay := pollRetryDelay * time.Duration(1<<retry)
+		s.logger.Error().
+			Str("event_type", eventType).
+			Int("attempt", retry+1).
+			Int("max_attempts", pollMaxRetries).
+			Err(err).
+			Dur("retry_delay", retryDelay).
+			Msg("Failed to filter logs for events")
+
+		select {
+		case <-time.After(retryDelay):
+			continue
+		case <-ctx.Done():
+			return fromBlock, ctx.Err()
+		}
+	}
+
+	if err != nil {
+		return fromBlock, errs.Upstream(err, "failed to filter %s logs after %d attempts", eventType, pollMaxRetries)
+	}
+
+	// Process the logs with individual timeouts, the blocks before a failed log are complete
+	var handleErr error
+	processedCount := 0
+	for _, logEntry := range logs {
+		processCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		err := handler.Handle(processCtx, logEntry)
+		cancel()
+
+		if err != nil {
+			handleErr = fmt.Errorf("failed to process %s log of block %d: %v", eventType, logEntry.BlockNumber, err)
+			endBlock = logEntry.BlockNumber - 1
+			break
 		}
+		processedCount++
+	}
+
+	if len(logs) > 0 {
+		s.logger.Info().
+			Int("processed_count", processedCount).
+			Int("total_logs", len(logs)).
+			Str("event_type", eventType).
+			Msg("Processed polled events")
+	} else {
+		s.logger.Info().
+			Str("event_type", eventType).
+			Uint64("from_block", fromBlock+1).
+			Uint64("to_block", endBlock).
+			Msg("No new events found in polled blocks")
+	}
+
+	if endBlock <= fromBlock {
+		return fromBlock, handleErr
+	}
+
+	// Update the last processed block
+	s.updateProgress(chainID, eventType, endBlock)
+
+	// Persist progress to the database
+	dbUpdateCtx, dbUpdateCancel := context.WithTimeout(ctx, 10*time.Second)
+	err = s.db.AdvanceEventCheckpoint(dbUpdateCtx, chainID, eventType, contractAddress.Hex(), endBlock)
+	dbUpdateCancel()
+	if err != nil {
+		s.logger.Warn().
+			Str("event_type", eventType).
+			Err(err).
+			Msg("Failed to persist progress to DB")
+	} else {
+		s.logger.Debug().
+			Str("event_type", eventType).
+			Uint64(logging.FieldChain, chainID).
+			Uint64("block_number", endBlock).
+			Msg("Persisted progress to DB")
 	}
+
+	return endBlock, handleErr
 }
 
 // StartSubscriptionSupervisor starts a background goroutine that periodically checks
@@ -2071,141 +1369,58 @@ func (s *EventCatchupService) StartSubscriptionSupervisor(ctx context.Context, c
 	// Track last full reconnect time
 	lastFullReconnect := time.Now()
 
-	// ZetaChain ID constant
-	const zetaChainID = uint64(7000)
-
 	for {
 		select {
 		case <-healthCheckTicker.C:
 			s.logger.Info().Msg("Subscription supervisor checking service health...")
 
-			// Check intent services
-			for chainID, intentService := range s.intentServices {
-				// Skip health check for ZetaChain as it's using polling
-				if chainID == zetaChainID {
-					s.logger.Info().
-						Msg("ZetaChain intent service using polling mechanism - skipping subscription check")
-					continue
-				}
-
-				activeGoroutines := intentService.ActiveGoroutines()
-				s.logger.Debug().
-					Uint64(logging.FieldChain, chainID).
-					Int32("active_goroutines", activeGoroutines).
-					Msg("Intent service")
-
-				if activeGoroutines == 0 {
-					s.logger.Warn().
-						Uint64(logging.FieldChain, chainID).
-						Msg("Intent service has 0 active goroutines, restarting")
-					contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-					// Create a context with timeout for restart
-					restartCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-					err := intentService.Restart(restartCtx, contractAddress)
-					if err != nil {
-						s.logger.Error().
-							Uint64(logging.FieldChain, chainID).
-							Err(err).
-							Msg("Failed to restart intent service")
-					} else {
-						s.logger.Info().
-							Uint64(logging.FieldChain, chainID).
-							Msg("RECOVERY: Successfully restarted intent service")
-					}
-					cancel()
-				}
-			}
-
-			// Check fulfillment services
-			for chainID, fulfillmentService := range s.fulfillmentServices {
-				// Skip health check for ZetaChain as it's using polling
-				if chainID == zetaChainID {
-					s.logger.Info().
-						Msg("ZetaChain fulfillment service using polling mechanism - skipping subscription check")
-					continue
-				}
-
-				count := fulfillmentService.GetSubscriptionCount()
+			// Check ingestion engines, polled chains have none
+			for chainID, engine := range s.IngestionEngines() {
+				count := engine.SubscriptionCount()
 				s.logger.Info().
 					Uint64(logging.FieldChain, chainID).
+					Int32("active_goroutines", engine.ActiveGoroutines()).
 					Int("active_subscriptions", count).
-					Msg("Fulfillment service")
+					Msg("Ingestion engine")
 
 				if count == 0 {
 					s.logger.Warn().
 						Uint64(logging.FieldChain, chainID).
-						Msg("Fulfillment service has no active subscriptions, restarting")
-					contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
+						Msg("Ingestion engine has no active subscription, restarting")
 
 					// Create a context with timeout for restart
 					restartCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-					err := fulfillmentService.Restart(restartCtx, contractAddress)
-					if err != nil {
+					if err := engine.Restart(restartCtx); err != nil {
 						s.logger.Error().
 							Uint64(logging.FieldChain, chainID).
 							Err(err).
-							Msg("Failed to restart fulfillment service")
+							Msg("Failed to restart ingestion engine")
 					} else {
 						s.logger.Info().
 							Uint64(logging.FieldChain, chainID).
-							Msg("Successfully restarted fulfillment service")
+							Msg("RECOVERY: Successfully restarted ingestion engine")
 					}
 					cancel()
 				}
 			}
 
-			// Check settlement services
-			for chainID, settlementService := range s.settlementServices {
-				// Skip health check for ZetaChain as it's using polling
-				if chainID == zetaChainID {
-					s.logger.Info().
-						Msg("ZetaChain settlement service using polling mechanism - skipping subscription check")
+			// Check the health of polled chains by getting block number
+			for chainID, intentService := range s.intentServices {
+				if !s.IsPollingChain(chainID) {
 					continue
 				}
 
-				count := settlementService.GetSubscriptionCount()
-				s.logger.Info().
-					Uint64(logging.FieldChain, chainID).
-					Int("active_subscriptions", count).
-					Msg("Settlement service")
-
-				if count == 0 {
-					s.logger.Warn().
-						Uint64(logging.FieldChain, chainID).
-						Msg("Settlement service has no active subscriptions, restarting")
-					contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-					// Create a context with timeout for restart
-					restartCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-					err := settlementService.Restart(restartCtx, contractAddress)
-					if err != nil {
-						s.logger.Error().
-							Uint64(logging.FieldChain, chainID).
-							Err(err).
-							Msg("Failed to restart settlement service")
-					} else {
-						s.logger.Info().
-							Uint64(logging.FieldChain, chainID).
-							Msg("Successfully restarted settlement service")
-					}
-					cancel()
-				}
-			}
-
-			// Check ZetaChain health by getting block number
-			if client, ok := s.intentServices[zetaChainID]; ok && client != nil {
-				s.logger.Info().Msg("Checking ZetaChain polling health...")
+				s.logger.Info().Uint64(logging.FieldChain, chainID).Msg("Checking polling health...")
 				blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-				_, err := client.client.BlockNumber(blockCtx)
+				_, err := intentService.client.BlockNumber(blockCtx)
 				cancel()
 
 				if err != nil {
-					s.logger.Warn().Err(err).Msg("ZetaChain polling health check failed")
-					client.UpdatePollingHealth(false)
+					s.logger.Warn().Uint64(logging.FieldChain, chainID).Err(err).Msg("Polling health check failed")
+					intentService.UpdatePollingHealth(false)
 				} else {
-					s.logger.Info().Msg("ZetaChain polling health check passed")
-					client.UpdatePollingHealth(true)
+					s.logger.Info().Uint64(logging.FieldChain, chainID).Msg("Polling health check passed")
+					intentService.UpdatePollingHealth(true)
 				}
 			}
 
@@ -2217,98 +1432,23 @@ func (s *EventCatchupService) StartSubscriptionSupervisor(ctx context.Context, c
 				Msg("Performing scheduled full reconnection of all services")
 			lastFullReconnect = time.Now()
 
-			// Force reconnect all intent services (except ZetaChain)
-			for chainID, intentService := range s.intentServices {
-				// Skip ZetaChain as it's using polling
-				if chainID == zetaChainID {
-					s.logger.Info().Msg("Skipping ZetaChain intent service reconnection (using polling)")
-					continue
-				}
-
-				s.logger.Info().
-					Uint64(logging.FieldChain, chainID).
-					Msg("Scheduled reconnect: Restarting intent service")
-				contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-				// First, unsubscribe from all existing subscriptions
-				intentService.UnsubscribeAll()
-
-				// Create a context with timeout for restart
-				restartCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-				err := intentService.Restart(restartCtx, contractAddress)
-				if err != nil {
-					s.logger.Error().
-						Uint64(logging.FieldChain, chainID).
-						Err(err).
-						Msg("Failed to reconnect intent service")
-				} else {
-					s.logger.Info().
-						Uint64(logging.FieldChain, chainID).
-						Msg("Scheduled reconnect: Successfully reconnected intent service")
-				}
-				cancel()
-			}
-
-			// Force reconnect all fulfillment services (except ZetaChain)
-			for chainID, fulfillmentService := range s.fulfillmentServices {
-				// Skip ZetaChain as it's using polling
-				if chainID == zetaChainID {
-					s.logger.Info().Msg("Skipping ZetaChain fulfillment service reconnection (using polling)")
-					continue
-				}
-
-				s.logger.Info().
-					Uint64(logging.FieldChain, chainID).
-					Msg("Scheduled reconnect: Restarting fulfillment service")
-				contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-				// First, unsubscribe from all existing subscriptions
-				fulfillmentService.UnsubscribeAll()
-
-				// Create a context with timeout for restart
-				restartCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-				err := fulfillmentService.Restart(restartCtx, contractAddress)
-				if err != nil {
-					s.logger.Error().
-						Uint64(logging.FieldChain, chainID).
-						Err(err).
-						Msg("Failed to reconnect fulfillment service")
-				} else {
-					s.logger.Info().
-						Uint64(logging.FieldChain, chainID).
-						Msg("Scheduled reconnect: Successfully reconnected fulfillment service")
-				}
-				cancel()
-			}
-
-			// Force reconnect all settlement services (except ZetaChain)
-			for chainID, settlementService := range s.settlementServices {
-				// Skip ZetaChain as it's using polling
-				if chainID == zetaChainID {
-					s.logger.Info().Msg("Skipping ZetaChain settlement service reconnection (using polling)")
-					continue
-				}
-
+			// Force reconnect all ingestion engines, polled chains have none
+			for chainID, engine := range s.IngestionEngines() {
 				s.logger.Info().
 					Uint64(logging.FieldChain, chainID).
-					Msg("Scheduled reconnect: Restarting settlement service")
-				contractAddress := common.HexToAddress(cfg.ChainConfigs[chainID].ContractAddr)
-
-				// First, unsubscribe from all existing subscriptions
-				settlementService.UnsubscribeAll()
+					Msg("Scheduled reconnect: Restarting ingestion engine")
 
 				// Create a context with timeout for restart
 				restartCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-				err := settlementService.Restart(restartCtx, contractAddress)
-				if err != nil {
+				if err := engine.Restart(restartCtx); err != nil {
 					s.logger.Error().
 						Uint64(logging.FieldChain, chainID).
 						Err(err).
-						Msg("Failed to reconnect settlement service")
+						Msg("Failed to reconnect ingestion engine")
 				} else {
 					s.logger.Info().
 						Uint64(logging.FieldChain, chainID).
-						Msg("Scheduled reconnect: Successfully reconnected settlement service")
+						Msg("Scheduled reconnect: Successfully reconnected ingestion engine")
 				}
 				cancel()
 			}
@@ -2335,6 +1475,14 @@ func (s *EventCatchupService) Shutdown(timeout time.Duration) error {
 	// Cancel the cleanup context to signal all goroutines to stop
 	s.cleanupCancel()
 
+	// Stop the live ingestion of every chain
+	var engineErrs []error
+	for chainID, engine := range s.IngestionEngines() {
+		if err := engine.Shutdown(timeout); err != nil {
+			engineErrs = append(engineErrs, fmt.Errorf("failed to shutdown ingestion of chain %d: %w", chainID, err))
+		}
+	}
+
 	// Wait for all goroutines to complete with timeout
 	done := make(chan struct{})
 	go func() {
@@ -2344,6 +1492,9 @@ func (s *EventCatchupService) Shutdown(timeout time.Duration) error {
 
 	select {
 	case <-done:
+		if len(engineErrs) > 0 {
+			return errors.Join(engineErrs...)
+		}
 		s.logger.Info().Msg("EventCatchupService shutdown completed successfully")
 		return nil
 	case <-time.After(timeout):
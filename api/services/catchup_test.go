@@ -1,11 +1,21 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/db"
 	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEventCatchupService_GoroutineTracking(t *testing.T) {
@@ -91,3 +101,159 @@ func TestEventCatchupService_ShutdownPreventsNewGoroutines(t *testing.T) {
 	// Should still have 0 goroutines (new ones shouldn't start)
 	assert.Equal(t, int32(0), eventCatchupService.ActiveGoroutines())
 }
+
+func TestEventCatchupService_CatchUpOnEvents(t *testing.T) {
+	contract := common.HexToAddress("0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB")
+
+	newHandler := func(handle func(context.Context, types.Log) error) chainHandler {
+		return chainHandler{
+			EventHandler: EventHandler{
+				EventType: eventTypeFulfillment,
+				Topics:    []common.Hash{common.HexToHash("0x01")},
+				Handle:    handle,
+			},
+			chainID:  1,
+			client:   &eventBlocksClient{events: []uint64{15, 40}},
+			logRange: NewLogRange(100, 10, 100),
+		}
+	}
+
+	t.Run("hands the logs to the handler", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		database.On("UpdateEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(100)).
+			Return(nil).Once()
+		service := NewEventCatchupService(nil, nil, nil, database, logging.NewTesting(t))
+
+		// duplicates are skipped
+		var handled []uint64
+		handler := newHandler(func(_ context.Context, vLog types.Log) error {
+			handled = append(handled, vLog.BlockNumber)
+			if vLog.BlockNumber == 15 {
+				return db.ErrDuplicate
+			}
+			return nil
+		})
+
+		err := service.catchUpOnEvents(context.Background(), handler, contract, 0, 100, "test")
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{15, 40}, handled)
+		assert.Equal(t, uint64(100), service.lastProcessedBlock(1, eventTypeFulfillment))
+	})
+
+	t.Run("stops at a failed log", func(t *testing.T) {
+		service := NewEventCatchupService(nil, nil, nil, mocks.NewDatabaseMock(t), logging.NewTesting(t))
+
+		handler := newHandler(func(_ context.Context, vLog types.Log) error {
+			if vLog.BlockNumber == 40 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+
+		err := service.catchUpOnEvents(context.Background(), handler, contract, 0, 100, "test")
+		require.ErrorContains(t, err, "failed to process fulfillment log")
+		assert.Equal(t, uint64(0), service.lastProcessedBlock(1, eventTypeFulfillment))
+	})
+}
+
+func TestEventCatchupService_PollRange(t *testing.T) {
+	contract := common.HexToAddress("0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB")
+
+	newHandler := func(failedBlock uint64) chainHandler {
+		return chainHandler{
+			EventHandler: EventHandler{
+				EventType: eventTypeFulfillment,
+				Topics:    []common.Hash{common.HexToHash("0x01")},
+				Handle: func(_ context.Context, vLog types.Log) error {
+					if vLog.BlockNumber == failedBlock {
+						return errors.New("connection refused")
+					}
+					return nil
+				},
+			},
+			chainID:  1,
+			client:   &eventBlocksClient{events: []uint64{15, 40}},
+			logRange: NewLogRange(100, 10, 100),
+		}
+	}
+
+	t.Run("completes the range", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(100)).
+			Return(nil).Once()
+		service := NewEventCatchupService(nil, nil, nil, database, logging.NewTesting(t))
+
+		completed, err := service.pollRange(context.Background(), newHandler(0), contract, 0, 100)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), completed)
+		assert.Equal(t, uint64(100), service.lastProcessedBlock(1, eventTypeFulfillment))
+	})
+
+	t.Run("stops before a failed log", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(39)).
+			Return(nil).Once()
+		service := NewEventCatchupService(nil, nil, nil, database, logging.NewTesting(t))
+
+		completed, err := service.pollRange(context.Background(), newHandler(40), contract, 0, 100)
+		require.ErrorContains(t, err, "failed to process fulfillment log of block 40")
+		assert.Equal(t, uint64(39), completed)
+		assert.Equal(t, uint64(39), service.lastProcessedBlock(1, eventTypeFulfillment))
+	})
+
+	t.Run("shrinks the range until the provider accepts it", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(625)).
+			Return(nil).Once()
+		service := NewEventCatchupService(nil, nil, nil, database, logging.NewTesting(t))
+
+		client := &eventBlocksClient{events: []uint64{15, 1500}, maxRange: 1000}
+		handler := newHandler(0)
+		handler.client = client
+		handler.logRange = NewLogRange(DefaultLogRange, DefaultMinLogRange, DefaultMaxLogRange)
+
+		completed, err := service.pollRange(context.Background(), handler, contract, 0, 3000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(625), completed)
+		assert.Equal(t, []uint64{3000, 2500, 1250, 625}, client.ranges)
+	})
+
+	t.Run("first log failed", func(t *testing.T) {
+		// the database mock fails the test on any checkpoint update
+		service := NewEventCatchupService(nil, nil, nil, mocks.NewDatabaseMock(t), logging.NewTesting(t))
+
+		completed, err := service.pollRange(context.Background(), newHandler(15), contract, 14, 100)
+		require.Error(t, err)
+		assert.Equal(t, uint64(14), completed)
+		assert.Equal(t, uint64(0), service.lastProcessedBlock(1, eventTypeFulfillment))
+	})
+}
+
+func TestEventCatchupService_StartConfirmationTrackers(t *testing.T) {
+	tracker := NewConfirmationTracker(mocks.NewDatabaseMock(t), 1, 1, "", logging.NewTesting(t))
+	intentService := &IntentService{client: &headerClient{headers: newFakeHeaderFetcher(100)}}
+
+	service := NewEventCatchupService(
+		map[uint64]*IntentService{1: intentService},
+		nil,
+		nil,
+		mocks.NewDatabaseMock(t),
+		logging.NewTesting(t),
+	)
+	defer service.Shutdown(time.Second)
+
+	// the confirmed head is known before the catchup starts
+	service.StartConfirmationTrackers(context.Background(), ConfirmationTrackers{1: tracker})
+	assert.Equal(t, uint64(100), tracker.HeadBlock())
+}
+
+// headerClient is a client answering the headers of a fakeHeaderFetcher
+type headerClient struct {
+	evm.Client
+
+	headers *fakeHeaderFetcher
+}
+
+func (c *headerClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.headers.HeaderByNumber(ctx, number)
+}
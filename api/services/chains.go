@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// ChainIngestion reports the state of the live ingestion of a chain, satisfied by IntentService
+type ChainIngestion interface {
+	IsHealthy() bool
+	IsPolling() bool
+}
+
+// ChainService serves the configured chains with their indexing progress,
+// and the block explorer URLs of the transactions and addresses of the indexed entities
+type ChainService struct {
+	db            db.Database
+	chains        map[uint64]*config.ChainConfig
+	confirmations ConfirmationTrackers
+
+	mu        sync.RWMutex
+	ingestion map[uint64]ChainIngestion
+}
+
+// NewChainService creates a new ChainService instance
+func NewChainService(
+	database db.Database,
+	chains map[uint64]*config.ChainConfig,
+	confirmations ConfirmationTrackers,
+) *ChainService {
+	return &ChainService{
+		db:            database,
+		chains:        chains,
+		confirmations: confirmations,
+		ingestion:     make(map[uint64]ChainIngestion),
+	}
+}
+
+// RegisterIngestion sets the ingestion whose state is reported for a chain
+func (s *ChainService) RegisterIngestion(chainID uint64, ingestion ChainIngestion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ingestion[chainID] = ingestion
+}
+
+// ListChains returns the configured chains ordered by ID.
+// The last indexed block of a chain is the last block whose intent, fulfillment and settlement events are indexed.
+func (s *ChainService) ListChains(ctx context.Context) ([]*models.Chain, error) {
+	checkpoints, err := s.db.ListEventCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[uint64]map[string]uint64)
+	for _, c := range checkpoints {
+		chain, ok := s.chains[c.ChainID]
+		if !ok || !strings.EqualFold(c.ContractAddress, chain.ContractAddr) {
+			continue
+		}
+		if indexed[c.ChainID] == nil {
+			indexed[c.ChainID] = make(map[string]uint64)
+		}
+		indexed[c.ChainID][c.EventType] = c.BlockNumber
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chains := make([]*models.Chain, 0, len(s.chains))
+	for chainID, cfg := range s.chains {
+		chain := &models.Chain{
+			ID:               chainID,
+			Name:             cfg.Name,
+			ExplorerURL:      cfg.ExplorerURL,
+			Contract:         cfg.ContractAddr,
+			IngestionMode:    string(cfg.IngestionMode),
+			HeadBlock:        s.confirmations[chainID].HeadBlock(),
+			LastIndexedBlock: lastIndexedBlock(indexed[chainID]),
+		}
+
+		if ingestion, ok := s.ingestion[chainID]; ok {
+			chain.Polling = ingestion.IsPolling()
+			chain.Healthy = ingestion.IsHealthy()
+		}
+
+		chains = append(chains, chain)
+	}
+
+	sort.Slice(chains, func(i, j int) bool { return chains[i].ID < chains[j].ID })
+
+	return chains, nil
+}
+
+// lastIndexedBlock returns the lowest checkpoint of the event types, 0 until every type has one
+func lastIndexedBlock(checkpoints map[string]uint64) uint64 {
+	var last uint64
+	for i, eventType := range []string{eventTypeIntent, eventTypeFulfillment, eventTypeSettlement} {
+		block, ok := checkpoints[eventType]
+		if !ok {
+			return 0
+		}
+		if i == 0 || block < last {
+			last = block
+		}
+	}
+	return last
+}
+
+// TxURL returns the block explorer URL of a transaction, empty if the chain has no explorer
+func (s *ChainService) TxURL(chainID uint64, txHash string) string {
+	return s.explorerURL(chainID, "tx", txHash)
+}
+
+// AddressURL returns the block explorer URL of an address, empty if the chain has no explorer
+func (s *ChainService) AddressURL(chainID uint64, address string) string {
+	return s.explorerURL(chainID, "address", address)
+}
+
+// explorerURL builds the URL of an explorer page, explorers are expected to follow the etherscan paths
+func (s *ChainService) explorerURL(chainID uint64, page, value string) string {
+	chain, ok := s.chains[chainID]
+	if !ok || chain.ExplorerURL == "" || value == "" {
+		return ""
+	}
+	return strings.TrimSuffix(chain.ExplorerURL, "/") + "/" + page + "/" + value
+}
+
+// AnnotateIntents sets the explorer links of the intents and their fills.
+// The transaction, sender and token are on the source chain, the recipient on the destination chain.
+func (s *ChainService) AnnotateIntents(intents ...*models.Intent) {
+	for _, intent := range intents {
+		intent.Explorer = explorerLinks(models.ExplorerLinks{
+			Tx:        s.TxURL(intent.SourceChain, intent.TxHash),
+			Sender:    s.AddressURL(intent.SourceChain, intent.Sender),
+			Token:     s.AddressURL(intent.SourceChain, intent.Token),
+			Recipient: s.AddressURL(intent.DestinationChain, intent.Recipient),
+		})
+
+		s.AnnotateFulfillments(intent.Fulfillments...)
+	}
+}
+
+// AnnotateFulfillments sets the explorer links of the fulfillments on the chain they were made on
+func (s *ChainService) AnnotateFulfillments(fulfillments ...*models.Fulfillment) {
+	for _, f := range fulfillments {
+		f.Explorer = explorerLinks(models.ExplorerLinks{
+			Tx:        s.TxURL(f.ChainID, f.TxHash),
+			Token:     s.AddressURL(f.ChainID, f.Asset),
+			Recipient: s.AddressURL(f.ChainID, f.Receiver),
+		})
+	}
+}
+
+// AnnotateSettlements sets the explorer links of the settlements on the chain they were made on
+func (s *ChainService) AnnotateSettlements(settlements ...*models.Settlement) {
+	for _, settlement := range settlements {
+		settlement.Explorer = explorerLinks(models.ExplorerLinks{
+			Tx:        s.TxURL(settlement.ChainID, settlement.TxHash),
+			Token:     s.AddressURL(settlement.ChainID, settlement.Asset),
+			Recipient: s.AddressURL(settlement.ChainID, settlement.Receiver),
+			Fulfiller: s.AddressURL(settlement.ChainID, settlement.Fulfiller),
+		})
+	}
+}
+
+// explorerLinks returns nil if no link is known, e.g. for the entities of chains without explorer
+func explorerLinks(links models.ExplorerLinks) *models.ExplorerLinks {
+	if links == (models.ExplorerLinks{}) {
+		return nil
+	}
+	return &links
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chainsTestContract    = "0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB"
+	chainsTestOldContract = "0x0000000000000000000000000000000000000bad"
+)
+
+func newTestChainService(t *testing.T, database *mocks.DatabaseMock) *ChainService {
+	tracker := NewConfirmationTracker(database, 1, 1, "", logging.NewTesting(t))
+	require.NoError(t, tracker.Refresh(context.Background(), newFakeHeaderFetcher(150)))
+
+	return NewChainService(database, map[uint64]*config.ChainConfig{
+		1: {
+			Name:          "Ethereum",
+			ExplorerURL:   "https://etherscan.io/",
+			ContractAddr:  chainsTestContract,
+			IngestionMode: config.IngestionSubscription,
+		},
+		8453: {
+			Name:          "Base",
+			ContractAddr:  chainsTestContract,
+			IngestionMode: config.IngestionPolling,
+		},
+	}, ConfirmationTrackers{1: tracker})
+}
+
+func TestChainService_ListChains(t *testing.T) {
+	t.Run("reports indexing progress", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		service := newTestChainService(t, database)
+		service.RegisterIngestion(1, fakeIngestion{healthy: true})
+		service.RegisterIngestion(8453, fakeIngestion{polling: true})
+
+		database.On("ListEventCheckpoints", mock.Anything).Return([]*models.EventCheckpoint{
+			{ChainID: 1, EventType: eventTypeIntent, ContractAddress: chainsTestContract, BlockNumber: 120},
+			{ChainID: 1, EventType: eventTypeFulfillment, ContractAddress: chainsTestContract, BlockNumber: 110},
+			{ChainID: 1, EventType: eventTypeSettlement, ContractAddress: chainsTestContract, BlockNumber: 130},
+			// checkpoints of a previous contract are ignored
+			{ChainID: 1, EventType: eventTypeFulfillment, ContractAddress: chainsTestOldContract, BlockNumber: 10},
+			// settlements aren't indexed yet
+			{ChainID: 8453, EventType: eventTypeIntent, ContractAddress: chainsTestContract, BlockNumber: 200},
+			{ChainID: 8453, EventType: eventTypeFulfillment, ContractAddress: chainsTestContract, BlockNumber: 200},
+			// unknown chains are ignored
+			{ChainID: 10, EventType: eventTypeIntent, ContractAddress: chainsTestContract, BlockNumber: 300},
+		}, nil).Once()
+
+		chains, err := service.ListChains(context.Background())
+		require.NoError(t, err)
+		require.Len(t, chains, 2)
+
+		assert.Equal(t, &models.Chain{
+			ID:               1,
+			Name:             "Ethereum",
+			ExplorerURL:      "https://etherscan.io/",
+			Contract:         chainsTestContract,
+			IngestionMode:    string(config.IngestionSubscription),
+			Healthy:          true,
+			HeadBlock:        150,
+			LastIndexedBlock: 110,
+		}, chains[0])
+		assert.Equal(t, &models.Chain{
+			ID:            8453,
+			Name:          "Base",
+			Contract:      chainsTestContract,
+			IngestionMode: string(config.IngestionPolling),
+			Polling:       true,
+		}, chains[1])
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		service := newTestChainService(t, database)
+
+		database.On("ListEventCheckpoints", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := service.ListChains(context.Background())
+		require.ErrorContains(t, err, "connection refused")
+	})
+}
+
+func TestChainService_Annotate(t *testing.T) {
+	service := newTestChainService(t, mocks.NewDatabaseMock(t))
+
+	t.Run("intents", func(t *testing.T) {
+		intent := &models.Intent{
+			SourceChain:      1,
+			DestinationChain: 8453,
+			TxHash:           "0xaa",
+			Sender:           "0x01",
+			Token:            "0x02",
+			Recipient:        "0x03",
+			Fulfillments: []*models.Fulfillment{
+				{ChainID: 8453, TxHash: "0xbb"},
+			},
+		}
+
+		service.AnnotateIntents(intent)
+
+		// the recipient is on the destination chain, which has no explorer
+		assert.Equal(t, &models.ExplorerLinks{
+			Tx:     "https://etherscan.io/tx/0xaa",
+			Sender: "https://etherscan.io/address/0x01",
+			Token:  "https://etherscan.io/address/0x02",
+		}, intent.Explorer)
+		assert.Nil(t, intent.Fulfillments[0].Explorer)
+	})
+
+	t.Run("fulfillments", func(t *testing.T) {
+		fulfillment := &models.Fulfillment{ChainID: 1, TxHash: "0xbb", Asset: "0x02", Receiver: "0x03"}
+
+		service.AnnotateFulfillments(fulfillment)
+
+		assert.Equal(t, &models.ExplorerLinks{
+			Tx:        "https://etherscan.io/tx/0xbb",
+			Token:     "https://etherscan.io/address/0x02",
+			Recipient: "https://etherscan.io/address/0x03",
+		}, fulfillment.Explorer)
+	})
+
+	t.Run("settlements", func(t *testing.T) {
+		settlement := &models.Settlement{ChainID: 1, TxHash: "0xcc", Fulfiller: "0x04"}
+
+		service.AnnotateSettlements(settlement)
+
+		assert.Equal(t, &models.ExplorerLinks{
+			Tx:        "https://etherscan.io/tx/0xcc",
+			Fulfiller: "https://etherscan.io/address/0x04",
+		}, settlement.Explorer)
+	})
+}
+
+// fakeIngestion reports a fixed ingestion state
+type fakeIngestion struct {
+	healthy bool
+	polling bool
+}
+
+func (f fakeIngestion) IsHealthy() bool { return f.healthy }
+
+func (f fakeIngestion) IsPolling() bool { return f.polling }
@@ -3,29 +3,29 @@ package services
 import (
 	"fmt"
 
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
 )
 
 // ClientResolver provides access to chain-specific Ethereum clients
 type ClientResolver interface {
-	// GetClient returns the ethclient.Client for the specified chain ID
-	GetClient(chainID uint64) (*ethclient.Client, error)
+	// GetClient returns the client for the specified chain ID
+	GetClient(chainID uint64) (evm.Client, error)
 }
 
 // SimpleClientResolver is a basic implementation of ClientResolver that maintains a map of chain IDs to clients
 type SimpleClientResolver struct {
-	clients map[uint64]*ethclient.Client
+	clients map[uint64]evm.Client
 }
 
 // NewSimpleClientResolver creates a new resolver with the provided map of chain IDs to clients
-func NewSimpleClientResolver(clients map[uint64]*ethclient.Client) *SimpleClientResolver {
+func NewSimpleClientResolver(clients map[uint64]evm.Client) *SimpleClientResolver {
 	return &SimpleClientResolver{
 		clients: clients,
 	}
 }
 
 // GetClient returns the client for the specified chain ID
-func (r *SimpleClientResolver) GetClient(chainID uint64) (*ethclient.Client, error) {
+func (r *SimpleClientResolver) GetClient(chainID uint64) (evm.Client, error) {
 	client, ok := r.clients[chainID]
 	if !ok {
 		return nil, fmt.Errorf("no client found for chain ID %d", chainID)
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+const (
+	// ConfirmationRefreshInterval is how often the confirmed head of a chain is refreshed
+	// and staged events are promoted
+	ConfirmationRefreshInterval = 15 * time.Second
+
+	// ConfirmationMaxAttempts is the number of failed promotions after which a staged event is left
+	// for manual inspection
+	ConfirmationMaxAttempts = 10
+)
+
+// stuckUnconfirmedEventsDesc describes the gauge of the staged events that exhausted their promotion attempts
+var stuckUnconfirmedEventsDesc = prometheus.NewDesc(
+	"speedrun_stuck_unconfirmed_events",
+	"Number of staged events left unpromoted after failing to be promoted ConfirmationMaxAttempts times",
+	[]string{"chain_id", "event_type"},
+	nil,
+)
+
+// errConfirmedHeadUnknown is returned for the logs seen before the confirmed block of their chain is known
+var errConfirmedHeadUnknown = errors.New("confirmed block not known yet")
+
+// LogHandler persists a single confirmed event log
+type LogHandler func(ctx context.Context, vLog types.Log) error
+
+// ConfirmationTracker holds back the events of a chain until their block is confirmed.
+// A block is confirmed once it is covered by the chain's finality tag (finalized/safe)
+// or, when no tag is configured, once it has the configured number of confirmations.
+// Events seen before that are staged in the database and promoted by Run.
+type ConfirmationTracker struct {
+	db            db.Database
+	chainID       uint64
+	confirmations uint64
+	finalityTag   string
+	handlers      map[string]LogHandler
+	logger        zerolog.Logger
+
+	mu             sync.RWMutex
+	latestBlock    uint64
+	confirmedBlock uint64
+	stuck          map[string]int // event type -> staged events that exhausted their attempts
+}
+
+// NewConfirmationTracker creates a new confirmation tracker for a chain
+func NewConfirmationTracker(
+	database db.Database,
+	chainID uint64,
+	confirmations int,
+	finalityTag string,
+	logger zerolog.Logger,
+) *ConfirmationTracker {
+	if confirmations < 1 {
+		confirmations = 1
+	}
+
+	return &ConfirmationTracker{
+		db:            database,
+		chainID:       chainID,
+		confirmations: uint64(confirmations),
+		finalityTag:   finalityTag,
+		handlers:      make(map[string]LogHandler),
+		logger: logger.With().
+			Str(logging.FieldModule, "confirmations").
+			Uint64(logging.FieldChain, chainID).
+			Logger(),
+	}
+}
+
+// Register sets the handler used to persist promoted events of the given type
+func (t *ConfirmationTracker) Register(eventType string, handler LogHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers[eventType] = handler
+}
+
+// requiresStaging returns false when events can be persisted as soon as they are seen
+func (t *ConfirmationTracker) requiresStaging() bool {
+	return t != nil && (t.confirmations > 1 || t.finalityTag != "")
+}
+
+// Stage stores the log if its block is not confirmed yet.
+// Returns true if the log was staged and must not be persisted by the caller.
+// Logs seen before the confirmed block is known fail, instead of staging the confirmed logs of a catchup,
+// so that they're processed again once it's known.
+func (t *ConfirmationTracker) Stage(ctx context.Context, eventType string, vLog types.Log) (bool, error) {
+	if !t.requiresStaging() {
+		return false, nil
+	}
+
+	t.mu.RLock()
+	latest, confirmed := t.latestBlock, t.confirmedBlock
+	t.mu.RUnlock()
+
+	if latest == 0 {
+		return false, fmt.Errorf("%w on chain %d", errConfirmedHeadUnknown, t.chainID)
+	}
+
+	if vLog.BlockNumber <= confirmed {
+		return false, nil
+	}
+
+	data, err := json.Marshal(vLog)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode log: %v", err)
+	}
+
+	event := &models.UnconfirmedEvent{
+		ChainID:     t.chainID,
+		EventType:   eventType,
+		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
+		TxHash:      vLog.TxHash.Hex(),
+		LogIndex:    vLog.Index,
+		Log:         data,
+	}
+
+	if err := t.db.StageUnconfirmedEvent(ctx, event); err != nil {
+		return false, err
+	}
+
+	t.logger.Debug().
+		Str("event_type", eventType).
+		Uint64(logging.FieldBlock, vLog.BlockNumber).
+		Str("tx_hash", vLog.TxHash.Hex()).
+		Msg("Staged unconfirmed event")
+
+	return true, nil
+}
+
+// Refresh updates the latest and confirmed block of the chain
+func (t *ConfirmationTracker) Refresh(ctx context.Context, client HeaderFetcher) error {
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %v", err)
+	}
+
+	latestBlock := latest.Number.Uint64()
+
+	var confirmedBlock uint64
+	if latestBlock+1 > t.confirmations {
+		confirmedBlock = latestBlock + 1 - t.confirmations
+	}
+
+	if tag, ok := finalityTagNumber(t.finalityTag); ok {
+		header, err := client.HeaderByNumber(ctx, tag)
+		if err != nil {
+			// not every chain supports finality tags, fall back to the confirmation depth
+			t.logger.Warn().
+				Err(err).
+				Str("finality_tag", t.finalityTag).
+				Msg("Failed to get header by finality tag, using confirmation depth")
+		} else {
+			confirmedBlock = header.Number.Uint64()
+		}
+	}
+
+	t.mu.Lock()
+	t.latestBlock = latestBlock
+	t.confirmedBlock = confirmedBlock
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Promote persists the staged events whose block is now confirmed, in chain order.
+// Events whose block is no longer part of the canonical chain are dropped. Failures are recorded
+// and retried up to ConfirmationMaxAttempts times, the event is then left staged for inspection.
+func (t *ConfirmationTracker) Promote(ctx context.Context, client HeaderFetcher) (int, error) {
+	t.mu.RLock()
+	confirmed := t.confirmedBlock
+	t.mu.RUnlock()
+
+	if confirmed == 0 {
+		return 0, nil
+	}
+
+	events, err := t.db.ListUnconfirmedEvents(ctx, t.chainID, confirmed, ConfirmationMaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		promoted  int
+		canonical = make(map[uint64]string)
+	)
+
+	for _, event := range events {
+		logger := t.logger.With().
+			Str("event_type", event.EventType).
+			Uint64(logging.FieldBlock, event.BlockNumber).
+			Str("tx_hash", event.TxHash).
+			Logger()
+
+		hash, ok := canonical[event.BlockNumber]
+		if !ok {
+			header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(event.BlockNumber))
+			if err != nil {
+				return promoted, fmt.Errorf("failed to get header for block %d: %v", event.BlockNumber, err)
+			}
+			hash = header.Hash().Hex()
+			canonical[event.BlockNumber] = hash
+		}
+
+		if hash == event.BlockHash {
+			if err := t.promote(ctx, event); err != nil {
+				// keep the event staged so it's retried on the next refresh
+				logger.Error().Err(err).Int("attempts", event.Attempts+1).Msg("Failed to promote staged event")
+				err := t.db.RecordUnconfirmedEventFailure(ctx, t.chainID, event.BlockHash, event.LogIndex, err.Error())
+				if err != nil {
+					return promoted, err
+				}
+				continue
+			}
+			promoted++
+		} else {
+			logger.Warn().Str("block_hash", event.BlockHash).Msg("Dropping staged event from orphaned block")
+		}
+
+		if err := t.db.DeleteUnconfirmedEvent(ctx, t.chainID, event.BlockHash, event.LogIndex); err != nil {
+			return promoted, err
+		}
+	}
+
+	return promoted, nil
+}
+
+func (t *ConfirmationTracker) promote(ctx context.Context, event *models.UnconfirmedEvent) error {
+	t.mu.RLock()
+	handler, ok := t.handlers[event.EventType]
+	t.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no handler registered for event type %s", event.EventType)
+	}
+
+	var vLog types.Log
+	if err := json.Unmarshal(event.Log, &vLog); err != nil {
+		return fmt.Errorf("failed to decode staged log: %v", err)
+	}
+
+	return handler(ctx, vLog)
+}
+
+// Run refreshes the confirmed head and promotes staged events until the context is done
+func (t *ConfirmationTracker) Run(ctx context.Context, client HeaderFetcher) {
+	ticker := time.NewTicker(ConfirmationRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		rpcCtx, cancel := context.WithTimeout(ctx, DefaultRPCTimeout)
+		err := t.Refresh(rpcCtx, client)
+		cancel()
+
+		if err != nil {
+			t.logger.Warn().Err(err).Msg("Failed to refresh confirmed block")
+		} else if t.requiresStaging() {
+			promoted, err := t.Promote(ctx, client)
+			if err != nil {
+				t.logger.Error().Err(err).Msg("Failed to promote staged events")
+			}
+			if promoted > 0 {
+				t.logger.Info().Int("promoted", promoted).Msg("Promoted confirmed events")
+			}
+
+			if err := t.UpdateMetrics(ctx); err != nil {
+				t.logger.Warn().Err(err).Msg("Failed to update staged event metrics")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UpdateMetrics refreshes the number of staged events that exhausted their promotion attempts
+func (t *ConfirmationTracker) UpdateMetrics(ctx context.Context) error {
+	stuck, err := t.db.CountStuckUnconfirmedEvents(ctx, t.chainID, ConfirmationMaxAttempts)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.stuck = stuck
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Annotate sets the confirmation state of the intent's block.
+// Intents with an unknown block or a chain whose head isn't known yet are left untouched.
+func (t *ConfirmationTracker) Annotate(intent *models.Intent) {
+	if t == nil || intent.BlockNumber == 0 {
+		return
+	}
+
+	t.mu.RLock()
+	latest, confirmed := t.latestBlock, t.confirmedBlock
+	t.mu.RUnlock()
+
+	if latest == 0 {
+		return
+	}
+
+	var confirmations uint64
+	if latest >= intent.BlockNumber {
+		confirmations = latest - intent.BlockNumber + 1
+	}
+	finalized := intent.BlockNumber <= confirmed
+
+	intent.Confirmations = &confirmations
+	intent.Finalized = &finalized
+}
+
+// HeadBlock returns the latest block of the chain, 0 until it's known
+func (t *ConfirmationTracker) HeadBlock() uint64 {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.latestBlock
+}
+
+// ConfirmationTrackers maps chain IDs to their confirmation trackers.
+// It exports the staged events of the chains that exhausted their promotion attempts as a prometheus gauge.
+type ConfirmationTrackers map[uint64]*ConfirmationTracker
+
+var _ prometheus.Collector = ConfirmationTrackers(nil)
+
+// Describe implements prometheus.Collector
+func (c ConfirmationTrackers) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stuckUnconfirmedEventsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c ConfirmationTrackers) Collect(ch chan<- prometheus.Metric) {
+	for chainID, tracker := range c {
+		tracker.mu.RLock()
+		for eventType, count := range tracker.stuck {
+			ch <- prometheus.MustNewConstMetric(
+				stuckUnconfirmedEventsDesc,
+				prometheus.GaugeValue,
+				float64(count),
+				strconv.FormatUint(chainID, 10),
+				eventType,
+			)
+		}
+		tracker.mu.RUnlock()
+	}
+}
+
+// Annotate sets the confirmation state of the intents using the tracker of their source chain
+func (c ConfirmationTrackers) Annotate(intents ...*models.Intent) {
+	for _, intent := range intents {
+		c[intent.SourceChain].Annotate(intent)
+	}
+}
+
+func finalityTagNumber(tag string) (*big.Int, bool) {
+	switch tag {
+	case config.FinalityTagFinalized:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber)), true
+	case config.FinalityTagSafe:
+		return big.NewInt(int64(rpc.SafeBlockNumber)), true
+	default:
+		return nil, false
+	}
+}
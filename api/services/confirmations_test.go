@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmationTracker_Stage(t *testing.T) {
+	const chainID = uint64(8453)
+
+	vLog := types.Log{
+		BlockNumber: 95,
+		BlockHash:   common.HexToHash("0x95"),
+		TxHash:      common.HexToHash("0xaa"),
+		Index:       3,
+	}
+
+	t.Run("single confirmation persists immediately", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		tracker := NewConfirmationTracker(mockDB, chainID, 1, "", logging.NewTesting(t))
+
+		staged, err := tracker.Stage(context.Background(), eventTypeIntent, vLog)
+		require.NoError(t, err)
+		assert.False(t, staged)
+	})
+
+	t.Run("nil tracker persists immediately", func(t *testing.T) {
+		var tracker *ConfirmationTracker
+
+		staged, err := tracker.Stage(context.Background(), eventTypeIntent, vLog)
+		require.NoError(t, err)
+		assert.False(t, staged)
+	})
+
+	t.Run("fails log while the confirmed block is unknown", func(t *testing.T) {
+		// the database mock fails the test if the log is staged
+		tracker := NewConfirmationTracker(mocks.NewDatabaseMock(t), chainID, 10, "", logging.NewTesting(t))
+
+		staged, err := tracker.Stage(context.Background(), eventTypeIntent, vLog)
+		require.ErrorIs(t, err, errConfirmedHeadUnknown)
+		assert.False(t, staged)
+	})
+
+	t.Run("stages log of unconfirmed block", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		tracker := NewConfirmationTracker(mockDB, chainID, 10, "", logging.NewTesting(t))
+		require.NoError(t, tracker.Refresh(context.Background(), newFakeHeaderFetcher(100)))
+
+		mockDB.On("StageUnconfirmedEvent", mock.Anything, mock.MatchedBy(func(e *models.UnconfirmedEvent) bool {
+			return e.ChainID == chainID &&
+				e.EventType == eventTypeIntent &&
+				e.BlockNumber == 95 &&
+				e.BlockHash == vLog.BlockHash.Hex() &&
+				e.LogIndex == 3
+		})).Return(nil)
+
+		staged, err := tracker.Stage(context.Background(), eventTypeIntent, vLog)
+		require.NoError(t, err)
+		assert.True(t, staged)
+	})
+
+	t.Run("persists log of confirmed block", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		tracker := NewConfirmationTracker(mockDB, chainID, 5, "", logging.NewTesting(t))
+		require.NoError(t, tracker.Refresh(context.Background(), newFakeHeaderFetcher(100)))
+
+		staged, err := tracker.Stage(context.Background(), eventTypeIntent, vLog)
+		require.NoError(t, err)
+		assert.False(t, staged)
+	})
+}
+
+func TestConfirmationTracker_Refresh(t *testing.T) {
+	const chainID = uint64(1)
+
+	t.Run("uses confirmation depth", func(t *testing.T) {
+		tracker := NewConfirmationTracker(mocks.NewDatabaseMock(t), chainID, 12, "", logging.NewTesting(t))
+
+		require.NoError(t, tracker.Refresh(context.Background(), newFakeHeaderFetcher(100)))
+		assert.Equal(t, uint64(100), tracker.latestBlock)
+		assert.Equal(t, uint64(89), tracker.confirmedBlock)
+	})
+
+	t.Run("uses finality tag", func(t *testing.T) {
+		tracker := NewConfirmationTracker(
+			mocks.NewDatabaseMock(t),
+			chainID,
+			1,
+			config.FinalityTagFinalized,
+			logging.NewTesting(t),
+		)
+
+		client := newFakeHeaderFetcher(100)
+		client.tags = map[int64]uint64{int64(rpc.FinalizedBlockNumber): 64}
+
+		require.NoError(t, tracker.Refresh(context.Background(), client))
+		assert.Equal(t, uint64(64), tracker.confirmedBlock)
+	})
+
+	t.Run("falls back to depth when tag is unsupported", func(t *testing.T) {
+		tracker := NewConfirmationTracker(
+			mocks.NewDatabaseMock(t),
+			chainID,
+			3,
+			config.FinalityTagSafe,
+			logging.NewTesting(t),
+		)
+
+		require.NoError(t, tracker.Refresh(context.Background(), newFakeHeaderFetcher(100)))
+		assert.Equal(t, uint64(98), tracker.confirmedBlock)
+	})
+}
+
+func TestConfirmationTracker_Promote(t *testing.T) {
+	const chainID = uint64(42161)
+
+	mockDB := mocks.NewDatabaseMock(t)
+	tracker := NewConfirmationTracker(mockDB, chainID, 5, "", logging.NewTesting(t))
+	client := newFakeHeaderFetcher(100)
+	require.NoError(t, tracker.Refresh(context.Background(), client))
+
+	var persisted []uint64
+	tracker.Register(eventTypeIntent, func(_ context.Context, vLog types.Log) error {
+		persisted = append(persisted, vLog.BlockNumber)
+		return nil
+	})
+	tracker.Register(eventTypeFulfillment, func(_ context.Context, _ types.Log) error {
+		return errors.New("intent not found")
+	})
+
+	staged := func(eventType string, blockNumber uint64, blockHash string, index uint) *models.UnconfirmedEvent {
+		data, err := json.Marshal(types.Log{
+			Topics:      []common.Hash{common.HexToHash("0x01")},
+			BlockNumber: blockNumber,
+			BlockHash:   common.HexToHash(blockHash),
+			Index:       index,
+		})
+		require.NoError(t, err)
+
+		return &models.UnconfirmedEvent{
+			ChainID:     chainID,
+			EventType:   eventType,
+			BlockNumber: blockNumber,
+			BlockHash:   blockHash,
+			LogIndex:    index,
+			Log:         data,
+		}
+	}
+
+	events := []*models.UnconfirmedEvent{
+		staged(eventTypeIntent, 90, client.hash(90), 0),
+		staged(eventTypeFulfillment, 91, client.hash(91), 1),
+		staged(eventTypeIntent, 92, common.HexToHash("0xdead").Hex(), 0),
+	}
+
+	mockDB.On("ListUnconfirmedEvents", mock.Anything, chainID, uint64(96), ConfirmationMaxAttempts).Return(events, nil)
+	mockDB.On("RecordUnconfirmedEventFailure", mock.Anything, chainID, client.hash(91), uint(1), "intent not found").
+		Return(nil).
+		Once()
+	mockDB.On("DeleteUnconfirmedEvent", mock.Anything, chainID, client.hash(90), uint(0)).Return(nil).Once()
+	mockDB.On("DeleteUnconfirmedEvent", mock.Anything, chainID, common.HexToHash("0xdead").Hex(), uint(0)).
+		Return(nil).
+		Once()
+
+	promoted, err := tracker.Promote(context.Background(), client)
+	require.NoError(t, err)
+
+	// the fulfillment stays staged with its failure recorded, the orphaned intent is dropped
+	assert.Equal(t, 1, promoted)
+	assert.Equal(t, []uint64{90}, persisted)
+}
+
+func TestConfirmationTrackers_Collect(t *testing.T) {
+	const chainID = uint64(42161)
+
+	mockDB := mocks.NewDatabaseMock(t)
+	mockDB.On("CountStuckUnconfirmedEvents", mock.Anything, chainID, ConfirmationMaxAttempts).
+		Return(map[string]int{eventTypeFulfillment: 2}, nil).
+		Once()
+
+	tracker := NewConfirmationTracker(mockDB, chainID, 5, "", logging.NewTesting(t))
+	require.NoError(t, tracker.UpdateMetrics(context.Background()))
+
+	trackers := ConfirmationTrackers{chainID: tracker}
+	assert.Equal(t, float64(2), testutil.ToFloat64(trackers))
+}
+
+func TestConfirmationTracker_Annotate(t *testing.T) {
+	tracker := NewConfirmationTracker(mocks.NewDatabaseMock(t), 1, 10, "", logging.NewTesting(t))
+	require.NoError(t, tracker.Refresh(context.Background(), newFakeHeaderFetcher(100)))
+
+	trackers := ConfirmationTrackers{1: tracker}
+
+	confirmed := &models.Intent{SourceChain: 1, BlockNumber: 80}
+	recent := &models.Intent{SourceChain: 1, BlockNumber: 95}
+	unknownBlock := &models.Intent{SourceChain: 1}
+	untracked := &models.Intent{SourceChain: 2, BlockNumber: 80}
+
+	trackers.Annotate(confirmed, recent, unknownBlock, untracked)
+
+	require.NotNil(t, confirmed.Confirmations)
+	assert.Equal(t, uint64(21), *confirmed.Confirmations)
+	assert.True(t, *confirmed.Finalized)
+
+	require.NotNil(t, recent.Confirmations)
+	assert.Equal(t, uint64(6), *recent.Confirmations)
+	assert.False(t, *recent.Finalized)
+
+	assert.Nil(t, unknownBlock.Confirmations)
+	assert.Nil(t, untracked.Finalized)
+}
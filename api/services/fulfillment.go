@@ -6,17 +6,15 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync"
-	"sync/atomic"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
 	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/logging"
 	"github.com/speedrun-hq/speedrun/api/models"
 )
@@ -38,29 +36,22 @@ const (
 
 // FulfillmentService handles monitoring and processing of fulfillment events
 type FulfillmentService struct {
-	client         *ethclient.Client
+	client         evm.Client
 	clientResolver ClientResolver
 	db             db.Database
+	reorg          *ReorgDetector
+	confirmations  *ConfirmationTracker
+	logRange       *LogRange
+	events         EventNotifier
 	abi            abi.ABI
 	chainID        uint64
-	subs           map[string]ethereum.Subscription
-	mu             sync.Mutex
+	backfill       bool // re-indexing a range, checkpoints are left untouched
 	logger         zerolog.Logger
-
-	// Goroutine tracking
-	activeGoroutines int32 // Counter for active goroutines
-
-	// Goroutine cleanup management
-	cleanupCtx    context.Context    // Context for cleanup operations
-	cleanupCancel context.CancelFunc // Cancel function for cleanup context
-	goroutineWg   sync.WaitGroup     // WaitGroup to track all goroutines
-	isShutdown    bool               // Flag to prevent new goroutines after shutdown
-	shutdownMu    sync.RWMutex       // Mutex for shutdown operations
 }
 
 // NewFulfillmentService creates a new FulfillmentService instance
 func NewFulfillmentService(
-	client *ethclient.Client,
+	client evm.Client,
 	clientResolver ClientResolver,
 	db db.Database,
 	intentFulfilledEventABI string,
@@ -73,211 +64,93 @@ func NewFulfillmentService(
 		return nil, fmt.Errorf("failed to parse contract ABI: %v", err)
 	}
 
-	// Create cleanup context
-	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-
 	return &FulfillmentService{
 		client:         client,
 		clientResolver: clientResolver,
 		db:             db,
+		reorg:          NewReorgDetector(db, logger),
+		logRange:       NewLogRange(DefaultLogRange, DefaultMinLogRange, DefaultMaxLogRange),
 		abi:            parsedABI,
 		chainID:        chainID,
-		subs:           make(map[string]ethereum.Subscription),
 		logger:         logger.With().Uint64(logging.FieldChain, chainID).Logger(),
-		cleanupCtx:     cleanupCtx,
-		cleanupCancel:  cleanupCancel,
 	}, nil
 }
 
-// StartListening starts listening for fulfillment events on all chains
-func (s *FulfillmentService) StartListening(ctx context.Context, contractAddress common.Address) error {
-	// Check if service is shutdown
-	if s.IsShutdown() {
-		return fmt.Errorf("cannot start listening: service is shutdown")
-	}
-
-	// Check if service is already running - prevent multiple starts
-	activeGoroutines := atomic.LoadInt32(&s.activeGoroutines)
-	if activeGoroutines > 0 {
-		s.logger.Info().
-			Int32("active_goroutines", activeGoroutines).
-			Msg("Service already running, skipping start")
-		return nil
-	}
-
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{contractAddress},
-		Topics: [][]common.Hash{
-			{
-				s.abi.Events[IntentFulfilledEventName].ID,
-				s.abi.Events[IntentFulfilledWithCallEventName].ID,
-			},
-		},
-	}
-
-	logs := make(chan types.Log)
-	sub, err := s.client.SubscribeFilterLogs(ctx, query, logs)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to logs: %v", err)
-	}
-
-	// Store the subscription with a unique ID
-	subID := contractAddress.Hex()
-	s.mu.Lock()
-	s.subs[subID] = sub
-	s.mu.Unlock()
-
-	s.logger.Info().
-		Str("contract", contractAddress.Hex()).
-		Msg("Successfully subscribed to fulfillment events")
-
-	s.startGoroutine("fulfillment-processor", func() {
-		s.processEventLogs(s.cleanupCtx, sub, logs, subID)
-	})
-	return nil
+// SetEventNotifier sets the notifier called after an intent event is persisted
+func (s *FulfillmentService) SetEventNotifier(notifier EventNotifier) {
+	s.events = notifier
 }
 
-// processEventLogs handles the event processing loop for the subscription.
-// It manages subscription errors, log processing, and context cancellation.
-func (s *FulfillmentService) processEventLogs(
-	ctx context.Context,
-	sub ethereum.Subscription,
-	logs chan types.Log,
-	subID string,
-) {
-	// Get the contract address from subID (which we set to contract address hex)
-	contractAddress := common.HexToAddress(subID)
-
-	defer func() {
-		sub.Unsubscribe()
-		// Remove the subscription from the map when done
-		s.mu.Lock()
-		delete(s.subs, subID)
-		s.mu.Unlock()
-		s.logger.Debug().
-			Str("subscription_id", subID).
-			Msg("Ended fulfillment event log processing")
-	}()
+// SetLogRange sets the adaptive block range of the FilterLogs requests made while catching up and polling
+func (s *FulfillmentService) SetLogRange(logRange *LogRange) {
+	s.logRange = logRange
+}
 
-	s.logger.Info().
-		Str("subscription_id", subID).
-		Msg("Starting fulfillment event log processing")
+// SetBackfill makes the service re-index logs without moving the live checkpoints
+func (s *FulfillmentService) SetBackfill() {
+	s.backfill = true
+}
 
-	// Add a ticker for debugging to periodically log subscription status
-	debugTicker := time.NewTicker(30 * time.Second)
-	defer debugTicker.Stop()
+// SetConfirmationTracker makes the service stage logs until their block is confirmed
+func (s *FulfillmentService) SetConfirmationTracker(tracker *ConfirmationTracker) {
+	s.confirmations = tracker
+	tracker.Register(eventTypeFulfillment, s.persistLog)
+}
 
-	for {
-		select {
-		case err := <-sub.Err():
-			if err != nil {
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Err(err).
-					Msg("Fulfillment subscription error")
-				// Try to resubscribe
-				if err := s.handleSubscriptionError(ctx, sub, logs, subID, contractAddress); err != nil {
-					s.logger.Error().Err(err).Msg("CRITICAL: Failed to resubscribe fulfillment service")
-					return
-				}
-			}
-		case vLog, ok := <-logs:
-			if !ok {
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Msg("Fulfillment log channel closed unexpectedly")
-				return
-			}
+// SetOrphanReconciler makes the reconciler replay the fulfillments queued until their intent is indexed
+func (s *FulfillmentService) SetOrphanReconciler(reconciler *OrphanReconciler) {
+	reconciler.Register(s.chainID, eventTypeFulfillment, s.storeLog)
+}
 
-			s.logger.Info().
-				Uint64(logging.FieldBlock, vLog.BlockNumber).
-				Str("tx_hash", vLog.TxHash.Hex()).
-				Msg("FULFILLMENT EVENT RECEIVED")
+// EventHandler returns the handler of the fulfillment events for an IngestionEngine
+func (s *FulfillmentService) EventHandler() EventHandler {
+	return EventHandler{
+		EventType: eventTypeFulfillment,
+		Topics: []common.Hash{
+			s.abi.Events[IntentFulfilledEventName].ID,
+			s.abi.Events[IntentFulfilledWithCallEventName].ID,
+		},
+		Handle: s.processLog,
+	}
+}
 
-			if err := s.processLog(ctx, vLog); err != nil {
-				s.logger.Error().Err(err).Msg("Error processing fulfillment log")
-				continue
-			}
-		case <-debugTicker.C:
-			// Extra debugging info
-			s.logger.Debug().
-				Str("subscription_id", subID).
-				Msg("Fulfillment subscription still active")
-		case <-ctx.Done():
-			s.logger.Debug().Msg("Context cancelled, stopping fulfillment event processing")
-			return
-		}
+// chainHandler returns the event handler of the service with the client and log range of its chain
+func (s *FulfillmentService) chainHandler() chainHandler {
+	return chainHandler{
+		EventHandler: s.EventHandler(),
+		chainID:      s.chainID,
+		client:       s.client,
+		logRange:     s.logRange,
 	}
 }
 
-// handleSubscriptionError attempts to recover from a subscription error by resubscribing.
-func (s *FulfillmentService) handleSubscriptionError(
-	ctx context.Context,
-	oldSub ethereum.Subscription,
-	logs chan types.Log,
-	subID string,
-	contractAddress common.Address,
-) error {
-	oldSub.Unsubscribe()
-	s.mu.Lock()
-	delete(s.subs, subID)
-	s.mu.Unlock()
-
-	// Implement exponential backoff for retry
-	maxRetries := 5
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		// Check if context is cancelled
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
+// processLog processes a single fulfillment event log
+func (s *FulfillmentService) processLog(ctx context.Context, vLog types.Log) error {
+	// The log was part of a block that is no longer canonical
+	if vLog.Removed {
+		return s.reorg.HandleRemovedLog(ctx, s.chainID, vLog)
+	}
 
-		// Create a new query with both event types
-		query := ethereum.FilterQuery{
-			Addresses: []common.Address{contractAddress},
-			Topics: [][]common.Hash{
-				{
-					s.abi.Events[IntentFulfilledEventName].ID,
-					s.abi.Events[IntentFulfilledWithCallEventName].ID,
-				},
-			},
-		}
+	// Hold the log back until its block is confirmed
+	if staged, err := s.confirmations.Stage(ctx, eventTypeFulfillment, vLog); err != nil || staged {
+		return err
+	}
 
-		// Try to resubscribe
-		newSub, err := s.client.SubscribeFilterLogs(ctx, query, logs)
-		if err == nil {
-			// Update the subscription
-			s.mu.Lock()
-			s.subs[subID] = newSub
-			s.mu.Unlock()
-			s.logger.Debug().Msg("Successfully resubscribed to fulfillment events")
-			return nil
-		}
+	return s.persistLog(ctx, vLog)
+}
 
-		// If we reach here, resubscription failed
-		backoffTime := time.Duration(1<<attempt) * time.Second
-		if backoffTime > 30*time.Second {
-			backoffTime = 30 * time.Second
-		}
-		s.logger.Debug().
-			Int("attempt", attempt+1).
-			Int("max_retries", maxRetries).
-			Err(err).
-			Dur("backoff_time", backoffTime).
-			Msg("Resubscription attempt failed, retrying")
-
-		select {
-		case <-time.After(backoffTime):
-			// Continue with next retry
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+// persistLog stores the fulfillment of a confirmed log,
+// logs whose intent isn't indexed yet are queued until it is
+func (s *FulfillmentService) persistLog(ctx context.Context, vLog types.Log) error {
+	err := s.storeLog(ctx, vLog)
+	if errors.Is(err, errIntentNotIndexed) {
+		return enqueueOrphan(ctx, s.db, s.chainID, eventTypeFulfillment, vLog, !s.backfill, s.logger)
 	}
-
-	return fmt.Errorf("failed to resubscribe after %d attempts", maxRetries)
+	return err
 }
 
-// processLog processes a single fulfillment event log
-func (s *FulfillmentService) processLog(ctx context.Context, vLog types.Log) error {
+// storeLog stores the fulfillment of a confirmed log, it fails with errIntentNotIndexed if its intent isn't indexed
+func (s *FulfillmentService) storeLog(ctx context.Context, vLog types.Log) error {
 	if err := s.validateLog(vLog); err != nil {
 		return err
 	}
@@ -289,13 +162,16 @@ func (s *FulfillmentService) processLog(ctx context.Context, vLog types.Log) err
 
 	// Get related intent to associate with fulfillment
 	intent, err := s.db.GetIntent(ctx, event.IntentID)
+	if errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("%w: %s", errIntentNotIndexed, event.IntentID)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get intent: %v", err)
+		return fmt.Errorf("failed to get intent: %w", err)
 	}
 
 	// Important: Use the destination chain client for fulfillment events
 	// Fulfillment events happen on the destination chain
-	var client *ethclient.Client
+	var client evm.Client
 	if s.clientResolver != nil && intent.DestinationChain != 0 {
 		// Try to get the destination chain client
 		destClient, err := s.clientResolver.GetClient(intent.DestinationChain)
@@ -345,22 +221,70 @@ func (s *FulfillmentService) processLog(ctx context.Context, vLog types.Log) err
 		fulfillment.Receiver = "0x" + fulfillment.Receiver[len(fulfillment.Receiver)-40:]
 	}
 
-	// Save fulfillment directly to database, preserving the block timestamp
-	if err := s.db.CreateFulfillment(ctx, fulfillment); err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			s.logger.Debug().
-				Str(logging.FieldIntent, event.IntentID).
-				Msg("Skipping duplicate fulfillment")
+	// Save the fulfillment with the intent status change, its outbox message and the checkpoint atomically,
+	// preserving the block timestamp
+	err = s.db.WithTx(ctx, func(tx db.Database) error {
+		if err := s.createFulfillment(ctx, tx, intent, fulfillment); err != nil {
+			return err
+		}
+		if s.backfill {
 			return nil
 		}
-		return fmt.Errorf("failed to create fulfillment: %v", err)
+		return advanceCheckpoint(ctx, tx, s.chainID, eventTypeFulfillment, vLog)
+	})
+	if errors.Is(err, db.ErrDuplicate) {
+		s.logger.Debug().
+			Str(logging.FieldIntent, event.IntentID).
+			Msg("Skipping duplicate fulfillment")
+		return nil
 	}
+	if err != nil {
+		return err
+	}
+
+	notifyEvents(s.events)
+
+	s.reorg.RecordLog(ctx, s.chainID, vLog)
+
+	return nil
+}
 
-	// Update intent status
-	if err := s.db.UpdateIntentStatus(ctx, event.IntentID, models.IntentStatusFulfilled); err != nil {
-		return fmt.Errorf("failed to update intent status: %v", err)
+// createFulfillment stores a fill of an intent with the status change of the intent and its outbox message
+// in a transaction. The intent is fulfilled once its fills cover the requested amount, partially fulfilled until then.
+func (s *FulfillmentService) createFulfillment(
+	ctx context.Context,
+	tx db.Database,
+	intent *models.Intent,
+	fulfillment *models.Fulfillment,
+) error {
+	if err := tx.CreateFulfillment(ctx, fulfillment); err != nil {
+		return fmt.Errorf("failed to create fulfillment: %w", err)
 	}
 
+	filled, err := tx.GetTotalFulfilledAmount(ctx, fulfillment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get filled amount: %w", err)
+	}
+
+	transition := fulfillment.StatusTransition(s.chainID, models.FulfillmentStatus(filled, intent.Amount))
+	if err := updateIntentStatus(ctx, tx, transition, s.logger); err != nil {
+		return err
+	}
+	if transition.FromStatus == "" {
+		// the intent status didn't change, there is no event to publish
+		return nil
+	}
+
+	return enqueueIntentEvent(ctx, tx, models.OutboxTopicIntentFulfilled, transition)
+}
+
+// fulfillmentLog returns the fulfillment log of an intent in a transaction receipt, nil if there is none
+func (s *FulfillmentService) fulfillmentLog(receipt *types.Receipt, intentID string) *types.Log {
+	for _, vLog := range receipt.Logs {
+		if s.validateLog(*vLog) == nil && vLog.Topics[1] == common.HexToHash(intentID) {
+			return vLog
+		}
+	}
 	return nil
 }
 
@@ -391,8 +315,12 @@ func (s *FulfillmentService) extractEventData(vLog types.Log) (*models.IntentFul
 	// Extract common data
 	event := &models.IntentFulfilledEvent{
 		IntentID:    vLog.Topics[1].Hex(),
+		ChainID:     s.chainID,
 		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
 		TxHash:      vLog.TxHash.Hex(),
+		LogIndex:    vLog.Index,
+		Contract:    vLog.Address.Hex(),
 		IsCall:      isCallFulfillment,
 	}
 
@@ -452,7 +380,7 @@ func (s *FulfillmentService) GetFulfillment(ctx context.Context, id string) (*mo
 	// Get fulfillment from database
 	fulfillment, err := s.db.GetFulfillment(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get fulfillment: %v", err)
+		return nil, fmt.Errorf("failed to get fulfillment: %w", err)
 	}
 
 	return fulfillment, nil
@@ -475,17 +403,20 @@ func (s *FulfillmentService) CreateFulfillment(ctx context.Context, intentID, tx
 	intent, err := s.db.GetIntent(ctx, intentID)
 	if err != nil {
 		if errors.Is(err, db.ErrNotFound) {
-			return fmt.Errorf("intent not found: %s", intentID)
+			return errs.NotFound("intent not found: %s", intentID)
 		}
-		return fmt.Errorf("failed to get intent: %v", err)
+		return fmt.Errorf("failed to get intent: %w", err)
 	}
 
 	// For API-created fulfillments, try to get the block timestamp from the transaction
 	// This provides more accurate timestamps even for API-created fulfillments
 	var timestamp time.Time
 
+	// The log of the fill in the transaction, it identifies the fill so that it isn't recorded again when indexed
+	var fillLog *types.Log
+
 	// Use the destination chain client if possible, as fulfillments happen on the destination chain
-	var client *ethclient.Client
+	var client evm.Client
 	if s.clientResolver != nil && intent.DestinationChain != 0 {
 		destClient, err := s.clientResolver.GetClient(intent.DestinationChain)
 		if err == nil {
@@ -514,6 +445,8 @@ func (s *FulfillmentService) CreateFulfillment(ctx context.Context, intentID, tx
 			// If we can get the transaction, try to get its receipt to find the block number
 			receipt, err := client.TransactionReceipt(ctx, txHashObj)
 			if err == nil {
+				fillLog = s.fulfillmentLog(receipt, intentID)
+
 				// If we have the receipt, get the block to find the timestamp
 				block, err := client.BlockByNumber(ctx, big.NewInt(int64(receipt.BlockNumber.Uint64())))
 				if err == nil {
@@ -568,6 +501,7 @@ func (s *FulfillmentService) CreateFulfillment(ctx context.Context, intentID, tx
 		Asset:     intent.Token,
 		Amount:    intent.Amount,
 		Receiver:  intent.Recipient,
+		ChainID:   intent.DestinationChain,
 		TxHash:    txHash,
 		CreatedAt: timestamp,
 		UpdatedAt: timestamp,
@@ -575,6 +509,13 @@ func (s *FulfillmentService) CreateFulfillment(ctx context.Context, intentID, tx
 		CallData:  intent.CallData,
 	}
 
+	if fillLog != nil {
+		fulfillment.BlockNumber = fillLog.BlockNumber
+		fulfillment.BlockHash = fillLog.BlockHash.Hex()
+		fulfillment.LogIndex = fillLog.Index
+		fulfillment.Contract = fillLog.Address.Hex()
+	}
+
 	// Convert padded blockchain addresses to standard Ethereum addresses
 	if len(fulfillment.Asset) > 42 && strings.HasPrefix(fulfillment.Asset, "0x") {
 		// Extract last 40 chars and add 0x prefix
@@ -586,15 +527,15 @@ func (s *FulfillmentService) CreateFulfillment(ctx context.Context, intentID, tx
 		fulfillment.Receiver = "0x" + fulfillment.Receiver[len(fulfillment.Receiver)-40:]
 	}
 
-	// Save fulfillment
-	if err := s.db.CreateFulfillment(ctx, fulfillment); err != nil {
-		return fmt.Errorf("failed to create fulfillment: %v", err)
+	// Save fulfillment with the intent status change
+	err = s.db.WithTx(ctx, func(tx db.Database) error {
+		return s.createFulfillment(ctx, tx, intent, fulfillment)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Update intent status
-	if err := s.db.UpdateIntentStatus(ctx, intentID, models.IntentStatusFulfilled); err != nil {
-		return fmt.Errorf("failed to update intent status: %v", err)
-	}
+	notifyEvents(s.events)
 
 	return nil
 }
@@ -609,22 +550,25 @@ func (s *FulfillmentService) CreateCallFulfillment(
 	intent, err := s.db.GetIntent(ctx, intentID)
 	if err != nil {
 		if errors.Is(err, db.ErrNotFound) {
-			return fmt.Errorf("intent not found: %s", intentID)
+			return errs.NotFound("intent not found: %s", intentID)
 		}
-		return fmt.Errorf("failed to get intent: %v", err)
+		return fmt.Errorf("failed to get intent: %w", err)
 	}
 
 	// Verify this is a call intent
 	if !intent.IsCall {
-		return fmt.Errorf("intent is not a call intent: %s", intentID)
+		return errs.Validation("intent is not a call intent: %s", intentID)
 	}
 
 	// For API-created fulfillments, try to get the block timestamp from the transaction
 	// This provides more accurate timestamps even for API-created fulfillments
 	var timestamp time.Time
 
+	// The log of the fill in the transaction, it identifies the fill so that it isn't recorded again when indexed
+	var fillLog *types.Log
+
 	// Use the destination chain client if possible, as fulfillments happen on the destination chain
-	var client *ethclient.Client
+	var client evm.Client
 	if s.clientResolver != nil && intent.DestinationChain != 0 {
 		destClient, err := s.clientResolver.GetClient(intent.DestinationChain)
 		if err == nil {
@@ -653,6 +597,8 @@ func (s *FulfillmentService) CreateCallFulfillment(
 			// If we can get the transaction, try to get its receipt to find the block number
 			receipt, err := client.TransactionReceipt(ctx, txHashObj)
 			if err == nil {
+				fillLog = s.fulfillmentLog(receipt, intentID)
+
 				// If we have the receipt, get the block to find the timestamp
 				block, err := client.BlockByNumber(ctx, big.NewInt(int64(receipt.BlockNumber.Uint64())))
 				if err == nil {
@@ -707,6 +653,7 @@ func (s *FulfillmentService) CreateCallFulfillment(
 		Asset:     intent.Token,
 		Amount:    intent.Amount,
 		Receiver:  intent.Recipient,
+		ChainID:   intent.DestinationChain,
 		TxHash:    txHash,
 		CreatedAt: timestamp,
 		UpdatedAt: timestamp,
@@ -714,6 +661,13 @@ func (s *FulfillmentService) CreateCallFulfillment(
 		CallData:  callData,
 	}
 
+	if fillLog != nil {
+		fulfillment.BlockNumber = fillLog.BlockNumber
+		fulfillment.BlockHash = fillLog.BlockHash.Hex()
+		fulfillment.LogIndex = fillLog.Index
+		fulfillment.Contract = fillLog.Address.Hex()
+	}
+
 	// Convert padded blockchain addresses to standard Ethereum addresses
 	if len(fulfillment.Asset) > 42 && strings.HasPrefix(fulfillment.Asset, "0x") {
 		// Extract last 40 chars and add 0x prefix
@@ -725,161 +679,15 @@ func (s *FulfillmentService) CreateCallFulfillment(
 		fulfillment.Receiver = "0x" + fulfillment.Receiver[len(fulfillment.Receiver)-40:]
 	}
 
-	// Save fulfillment
-	if err := s.db.CreateFulfillment(ctx, fulfillment); err != nil {
-		return fmt.Errorf("failed to create fulfillment: %v", err)
+	// Save fulfillment with the intent status change
+	err = s.db.WithTx(ctx, func(tx db.Database) error {
+		return s.createFulfillment(ctx, tx, intent, fulfillment)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Update intent status
-	if err := s.db.UpdateIntentStatus(ctx, intentID, models.IntentStatusFulfilled); err != nil {
-		return fmt.Errorf("failed to update intent status: %v", err)
-	}
+	notifyEvents(s.events)
 
 	return nil
 }
-
-// GetSubscriptionCount returns the number of active subscriptions
-func (s *FulfillmentService) GetSubscriptionCount() int {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return len(s.subs)
-}
-
-// Restart properly restarts the service by shutting down existing goroutines and starting new ones
-func (s *FulfillmentService) Restart(ctx context.Context, contractAddress common.Address) error {
-	s.logger.Info().Msg("Restarting fulfillment service...")
-
-	// Check if service is shutdown
-	if s.IsShutdown() {
-		return fmt.Errorf("cannot restart: service is shutdown")
-	}
-
-	// Cancel the cleanup context to signal all existing goroutines to stop
-	s.cleanupCancel()
-
-	// Wait for existing goroutines to complete with a short timeout
-	done := make(chan struct{})
-	go func() {
-		s.goroutineWg.Wait()
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		s.logger.Debug().Msg("Existing goroutines stopped successfully")
-	case <-time.After(5 * time.Second):
-		s.logger.Warn().Msg("Timeout waiting for existing goroutines to stop")
-	}
-
-	// Unsubscribe from all subscriptions
-	s.UnsubscribeAll()
-
-	// Create a new cleanup context
-	s.cleanupCtx, s.cleanupCancel = context.WithCancel(context.Background())
-
-	// Reset goroutine counter
-	atomic.StoreInt32(&s.activeGoroutines, 0)
-
-	// Start the service again
-	return s.StartListening(ctx, contractAddress)
-}
-
-// UnsubscribeAll unsubscribes from all active subscriptions
-func (s *FulfillmentService) UnsubscribeAll() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.logger.Debug().
-		Int("active_subscriptions", len(s.subs)).
-		Msg("Unsubscribing from all fulfillment subscriptions")
-
-	for id, sub := range s.subs {
-		sub.Unsubscribe()
-		s.logger.Debug().
-			Str("subscription_id", id).
-			Msg("Unsubscribed from fulfillment subscription")
-		delete(s.subs, id)
-	}
-}
-
-// Shutdown gracefully shuts down the service and waits for all goroutines to complete
-func (s *FulfillmentService) Shutdown(timeout time.Duration) error {
-	s.shutdownMu.Lock()
-	if s.isShutdown {
-		s.shutdownMu.Unlock()
-		return nil // Already shutdown
-	}
-	s.isShutdown = true
-	s.shutdownMu.Unlock()
-
-	s.logger.Info().Msg("Shutting down FulfillmentService...")
-
-	// Cancel the cleanup context to signal all goroutines to stop
-	s.cleanupCancel()
-
-	// Unsubscribe from all subscriptions
-	s.UnsubscribeAll()
-
-	// Wait for all goroutines to complete with timeout
-	done := make(chan struct{})
-	go func() {
-		s.goroutineWg.Wait()
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		s.logger.Info().Msg("FulfillmentService shutdown completed successfully")
-		return nil
-	case <-time.After(timeout):
-		s.logger.Error().
-			Dur("timeout", timeout).
-			Msg("FulfillmentService shutdown timed out")
-		return fmt.Errorf("shutdown timed out after %v", timeout)
-	}
-}
-
-// IsShutdown returns whether the service is in shutdown state
-func (s *FulfillmentService) IsShutdown() bool {
-	s.shutdownMu.RLock()
-	defer s.shutdownMu.RUnlock()
-	return s.isShutdown
-}
-
-// startGoroutine safely starts a goroutine with proper cleanup tracking
-func (s *FulfillmentService) startGoroutine(name string, fn func()) {
-	s.shutdownMu.RLock()
-	if s.isShutdown {
-		s.shutdownMu.RUnlock()
-		s.logger.Debug().
-			Str("goroutine_name", name).
-			Msg("Cannot start goroutine: service is shutdown")
-		return
-	}
-	s.shutdownMu.RUnlock()
-
-	s.goroutineWg.Add(1)
-	atomic.AddInt32(&s.activeGoroutines, 1)
-
-	go func() {
-		defer func() {
-			s.goroutineWg.Done()
-			atomic.AddInt32(&s.activeGoroutines, -1)
-
-			// Recover from panics
-			if r := recover(); r != nil {
-				s.logger.Error().
-					Str("goroutine_name", name).
-					Any("panic", r).
-					Msg("CRITICAL: Panic in goroutine")
-			}
-		}()
-
-		fn()
-	}()
-}
-
-// ActiveGoroutines returns the current count of active goroutines
-func (s *FulfillmentService) ActiveGoroutines() int32 {
-	return atomic.LoadInt32(&s.activeGoroutines)
-}
@@ -3,13 +3,10 @@ package services
 import (
 	"context"
 	"database/sql"
-	"testing"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/db"
 	"github.com/speedrun-hq/speedrun/api/models"
-	"github.com/stretchr/testify/assert"
 )
 
 // Simple mock database for testing
@@ -31,7 +28,7 @@ func (m *mockDB) Query(ctx context.Context, query string, args ...interface{}) (
 func (m *mockDB) CreateIntent(ctx context.Context, intent *models.Intent) error    { return nil }
 func (m *mockDB) GetIntent(ctx context.Context, id string) (*models.Intent, error) { return nil, nil }
 func (m *mockDB) ListIntents(ctx context.Context) ([]*models.Intent, error)        { return nil, nil }
-func (m *mockDB) UpdateIntentStatus(ctx context.Context, id string, status models.IntentStatus) error {
+func (m *mockDB) UpdateIntentStatus(ctx context.Context, transition *models.IntentStatusTransition) error {
 	return nil
 }
 
@@ -55,11 +52,20 @@ func (m *mockDB) GetSettlement(ctx context.Context, id string) (*models.Settleme
 	return nil, nil
 }
 func (m *mockDB) ListSettlements(ctx context.Context) ([]*models.Settlement, error) { return nil, nil }
-func (m *mockDB) GetLastProcessedBlock(ctx context.Context, chainID uint64) (uint64, error) {
+func (m *mockDB) GetEventCheckpoint(
+	ctx context.Context,
+	chainID uint64,
+	eventType, contractAddress string,
+) (uint64, error) {
 	return 0, nil
 }
 
-func (m *mockDB) UpdateLastProcessedBlock(ctx context.Context, chainID uint64, blockNumber uint64) error {
+func (m *mockDB) UpdateEventCheckpoint(
+	ctx context.Context,
+	chainID uint64,
+	eventType, contractAddress string,
+	blockNumber uint64,
+) error {
 	return nil
 }
 func (m *mockDB) InitDB(ctx context.Context) error { return nil }
@@ -120,131 +126,177 @@ func (m *mockDB) ListIntentsKeysetPaginated(ctx context.Context, lastTimestamp t
 }
 func (m *mockDB) PrepareStatements(ctx context.Context) error { return nil }
 
-func TestFulfillmentService_Shutdown(t *testing.T) {
-	// Create mock database
-	mockDB := &mockDB{}
-
-	// Create logger
-	logger := logging.NewTesting(t)
-
-	// Create service
-	service, err := NewFulfillmentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"}],"name":"IntentFulfilled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-	assert.NotNil(t, service)
-
-	// Test that service is not shutdown initially
-	assert.False(t, service.IsShutdown())
-
-	// Test shutdown
-	err = service.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-
-	// Test that service is now shutdown
-	assert.True(t, service.IsShutdown())
-
-	// Test that calling shutdown again returns nil (idempotent)
-	err = service.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-}
-
-func TestFulfillmentService_StartListening_Shutdown(t *testing.T) {
-	// Create mock database
-	mockDB := &mockDB{}
-
-	// Create logger
-	logger := logging.NewTesting(t)
-
-	// Create service
-	service, err := NewFulfillmentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"}],"name":"IntentFulfilled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-
-	// Shutdown the service first
-	err = service.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-
-	// Try to start listening after shutdown - should fail
-	err = service.StartListening(context.Background(), common.HexToAddress("0x1234567890123456789012345678901234567890"))
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "service is shutdown")
-}
-
-func TestFulfillmentService_GoroutineCleanup(t *testing.T) {
-	// Create mock database
-	mockDB := &mockDB{}
-
-	// Create logger
-	logger := logging.NewTesting(t)
-
-	// Create service
-	service, err := NewFulfillmentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"}],"name":"IntentFulfilled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-
-	// Start a test goroutine
-	done := make(chan bool)
-	service.startGoroutine("test-goroutine", func() {
-		time.Sleep(100 * time.Millisecond)
-		done <- true
-	})
-
-	// Wait for goroutine to complete
-	select {
-	case <-done:
-		// Goroutine completed successfully
-	case <-time.After(1 * time.Second):
-		t.Fatal("Goroutine did not complete within timeout")
-	}
-
-	// Shutdown should complete quickly since no goroutines are running
-	err = service.Shutdown(1 * time.Second)
-	assert.NoError(t, err)
-}
-
-func TestFulfillmentService_ShutdownTimeout(t *testing.T) {
-	// Create mock database
-	mockDB := &mockDB{}
-
-	// Create logger
-	logger := logging.NewTesting(t)
-
-	// Create service
-	service, err := NewFulfillmentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"}],"name":"IntentFulfilled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-
-	// Start a long-running goroutine
-	service.startGoroutine("long-running", func() {
-		time.Sleep(2 * time.Second)
-	})
-
-	// Try to shutdown with a short timeout - should timeout
-	err = service.Shutdown(100 * time.Millisecond)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "shutdown timed out")
+func (m *mockDB) SearchIntents(ctx context.Context, query models.IntentQuery) ([]*models.Intent, bool, error) {
+	return nil, false, nil
+}
+
+func (m *mockDB) ListEventCheckpoints(ctx context.Context) ([]*models.EventCheckpoint, error) {
+	return nil, nil
+}
+
+func (m *mockDB) ListIntentFulfillments(ctx context.Context, intentID string) ([]*models.Fulfillment, error) {
+	return nil, nil
+}
+
+func (m *mockDB) GetOrphanEventStats(ctx context.Context) ([]*models.OrphanEventStats, error) {
+	return nil, nil
+}
+
+func (m *mockDB) DeleteOrphanEvent(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (m *mockDB) RecordOrphanEventFailure(ctx context.Context, id int64, reason string) error {
+	return nil
+}
+
+func (m *mockDB) ListReconcilableOrphanEvents(ctx context.Context, maxAttempts, limit int) ([]*models.OrphanEvent, error) {
+	return nil, nil
+}
+
+func (m *mockDB) EnqueueOrphanEvent(ctx context.Context, event *models.OrphanEvent) error {
+	return nil
+}
+
+func (m *mockDB) DispatchOutboxMessages(ctx context.Context, limit int) ([]*models.IntentEvent, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockDB) EnqueueOutboxMessage(ctx context.Context, message *models.OutboxMessage) error {
+	return nil
+}
+
+func (m *mockDB) AdvanceEventCheckpoint(ctx context.Context, chainID uint64, eventType, contractAddress string, blockNumber uint64) error {
+	return nil
+}
+
+func (m *mockDB) WithTx(ctx context.Context, fn func(tx db.Database) error) error {
+	return fn(m)
+}
+
+func (m *mockDB) MarkSkippedRangeVerified(ctx context.Context, id int64, missedBlocks []uint64) error {
+	return nil
+}
+
+func (m *mockDB) ListUnverifiedSkippedRanges(ctx context.Context, chainID uint64, limit int) ([]*models.SkippedRange, error) {
+	return nil, nil
+}
+
+func (m *mockDB) RecordSkippedRange(ctx context.Context, skipped *models.SkippedRange) error {
+	return nil
+}
+
+func (m *mockDB) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status models.WebhookDeliveryStatus, limit int) ([]*models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockDB) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	return nil
+}
+
+func (m *mockDB) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockDB) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	return nil
+}
+
+func (m *mockDB) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (m *mockDB) ListWebhookSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockDB) GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return nil, nil
+}
+
+func (m *mockDB) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return nil
+}
+
+func (m *mockDB) GetLatestIntentEventCursor(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockDB) ListIntentEvents(ctx context.Context, afterCursor int64, limit int) ([]*models.IntentEvent, error) {
+	return nil, nil
+}
+
+func (m *mockDB) ExpireIntents(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockDB) ListUnvaluedIntents(
+	ctx context.Context,
+	sourceChain uint64,
+	afterID string,
+	limit int,
+) ([]*models.Intent, error) {
+	return nil, nil
+}
+
+func (m *mockDB) UpdateIntentUSDValue(ctx context.Context, id, amountUSD, intentFeeUSD string) error {
+	return nil
+}
+
+func (m *mockDB) ListIntentStatusHistory(ctx context.Context, intentID string) ([]*models.IntentStatusTransition, error) {
+	return nil, nil
+}
+
+func (m *mockDB) DeleteUnconfirmedEvent(ctx context.Context, chainID uint64, blockHash string, logIndex uint) error {
+	return nil
+}
+
+func (m *mockDB) ListUnconfirmedEvents(
+	ctx context.Context,
+	chainID, upToBlock uint64,
+	maxAttempts int,
+) ([]*models.UnconfirmedEvent, error) {
+	return nil, nil
+}
+
+func (m *mockDB) RecordUnconfirmedEventFailure(
+	ctx context.Context,
+	chainID uint64,
+	blockHash string,
+	logIndex uint,
+	reason string,
+) error {
+	return nil
+}
+
+func (m *mockDB) CountStuckUnconfirmedEvents(ctx context.Context, chainID uint64, maxAttempts int) (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *mockDB) StageUnconfirmedEvent(ctx context.Context, event *models.UnconfirmedEvent) error {
+	return nil
+}
+
+func (m *mockDB) RollbackFromBlock(ctx context.Context, chainID, fromBlock uint64, orphanedHashes []string) (*models.RollbackResult, error) {
+	return nil, nil
+}
+
+func (m *mockDB) PruneBlockHashes(ctx context.Context, chainID, belowBlock uint64) error {
+	return nil
+}
+
+func (m *mockDB) ListBlockHashes(ctx context.Context, chainID, fromBlock uint64) ([]*models.ProcessedBlock, error) {
+	return nil, nil
+}
+
+func (m *mockDB) RecordBlockHash(ctx context.Context, chainID, blockNumber uint64, blockHash string) error {
+	return nil
+}
+
+func (m *mockDB) ListSettlementsByFulfillerPaginatedOptimized(ctx context.Context, fulfiller string, page, pageSize int) ([]*models.Settlement, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockDB) ListLeaderboardPaginated(ctx context.Context, query models.LeaderboardQuery) ([]*models.LeaderboardEntry, int, error) {
+	return nil, 0, nil
 }
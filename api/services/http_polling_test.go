@@ -6,6 +6,10 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/stretchr/testify/require"
 )
 
 // TestHTTPPollingErrorHandling tests the error handling and retry logic
@@ -130,3 +134,31 @@ func TestContextCancellation(t *testing.T) {
 		t.Fatal("Timed out waiting for context cancellation to be handled")
 	}
 }
+
+// TestFallBackToPolling tests that only hybrid chains fall back to polling when subscribing fails
+func TestFallBackToPolling(t *testing.T) {
+	service := NewEventCatchupService(nil, nil, nil, nil, logging.NewTesting(t))
+	errSubscribe := errors.New("notifications not supported")
+
+	require.False(t, service.fallBackToPolling(1, config.IngestionSubscription, errSubscribe))
+	require.False(t, service.IsPollingChain(1))
+
+	require.True(t, service.fallBackToPolling(7001, config.IngestionHybrid, errSubscribe))
+	require.True(t, service.IsPollingChain(7001))
+}
+
+// TestIntentServicePollingHealth tests that the polling health is only tracked once the chain is polled
+func TestIntentServicePollingHealth(t *testing.T) {
+	intentService := newSkipTestIntentService(t, &eventBlocksClient{})
+
+	intentService.UpdatePollingHealth(false)
+	require.False(t, intentService.IsPolling())
+	require.False(t, intentService.GetMetrics().PollingHealthy)
+
+	intentService.SetPolling()
+	require.True(t, intentService.IsPolling())
+	require.True(t, intentService.GetMetrics().PollingHealthy)
+
+	intentService.UpdatePollingHealth(false)
+	require.False(t, intentService.GetMetrics().PollingHealthy)
+}
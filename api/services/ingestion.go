@@ -0,0 +1,530 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/logging"
+)
+
+const (
+	// ingestionMaxRetries is the number of consecutive failed subscription attempts before the engine gives up
+	ingestionMaxRetries = 10
+
+	// ingestionBaseDelay and ingestionMaxDelay bound the exponential backoff between subscription attempts
+	ingestionBaseDelay = time.Second
+	ingestionMaxDelay  = 5 * time.Minute
+
+	// ingestionGracePeriod is the time a started engine is considered healthy without a subscription
+	ingestionGracePeriod = 30 * time.Second
+)
+
+// EventHandler processes the logs of an event type routed by an IngestionEngine
+type EventHandler struct {
+	EventType string
+	Topics    []common.Hash // signatures of the events of the event type
+	Handle    func(context.Context, types.Log) error
+}
+
+// IngestionEngine receives the events of the contract of a chain through a single subscription
+// matching the signatures of all its handlers, and routes every log to the handler of its signature.
+// It owns the reconnection of the subscription, resuming from the last block with processed events.
+// Checkpoints are advanced by the handlers in the transaction persisting each log, never by the engine.
+// A log that failed stops the engine from handling the logs after it: it resubscribes from the last block
+// with processed events, so the failed log is processed again before a later log moves the checkpoints past it.
+type IngestionEngine struct {
+	client   evm.Client
+	chainID  uint64
+	contract common.Address
+	handlers []EventHandler
+	routes   map[common.Hash]EventHandler
+	progress func(eventType string, blockNumber uint64)
+	logger   zerolog.Logger
+
+	mu            sync.Mutex
+	sub           ethereum.Subscription
+	lastBlock     uint64 // last block with a processed log
+	lastEventTime time.Time
+	startTime     time.Time
+	reconnections int64
+	restartSignal chan struct{}
+
+	// Goroutine tracking
+	activeGoroutines int32
+
+	// Goroutine cleanup management
+	cleanupCtx    context.Context    // Context for cleanup operations
+	cleanupCancel context.CancelFunc // Cancel function for cleanup context
+	goroutineWg   sync.WaitGroup     // WaitGroup to track all goroutines
+	isShutdown    bool               // Flag to prevent new goroutines after shutdown
+	shutdownMu    sync.RWMutex       // Mutex for shutdown operations
+}
+
+// NewIngestionEngine creates a new IngestionEngine for the contract of a chain
+func NewIngestionEngine(
+	client evm.Client,
+	chainID uint64,
+	contract common.Address,
+	logger zerolog.Logger,
+) *IngestionEngine {
+	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
+
+	return &IngestionEngine{
+		client:   client,
+		chainID:  chainID,
+		contract: contract,
+		routes:   make(map[common.Hash]EventHandler),
+		logger: logger.With().
+			Str(logging.FieldModule, "ingestion").
+			Uint64(logging.FieldChain, chainID).
+			Logger(),
+		restartSignal: make(chan struct{}, 1),
+		cleanupCtx:    cleanupCtx,
+		cleanupCancel: cleanupCancel,
+	}
+}
+
+// Register routes the logs of the handler's event signatures to it, handlers must be registered before Start
+func (e *IngestionEngine) Register(handler EventHandler) {
+	e.handlers = append(e.handlers, handler)
+	for _, topic := range handler.Topics {
+		e.routes[topic] = handler
+	}
+}
+
+// SetProgressFunc sets the function called with the last completed block of each event type
+func (e *IngestionEngine) SetProgressFunc(progress func(eventType string, blockNumber uint64)) {
+	e.progress = progress
+}
+
+// filterQuery returns the query matching the events of all the handlers
+func (e *IngestionEngine) filterQuery(fromBlock uint64) ethereum.FilterQuery {
+	topics := make([]common.Hash, 0, len(e.routes))
+	for _, handler := range e.handlers {
+		topics = append(topics, handler.Topics...)
+	}
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{e.contract},
+		Topics:    [][]common.Hash{topics},
+	}
+	if fromBlock > 0 {
+		query.FromBlock = new(big.Int).SetUint64(fromBlock)
+	}
+
+	return query
+}
+
+// Start subscribes to the events of the handlers from a block and processes them until the engine is shut down.
+// The first subscription is made synchronously, so a chain without subscriptions can fall back to polling.
+func (e *IngestionEngine) Start(ctx context.Context, fromBlock uint64) error {
+	if e.IsShutdown() {
+		return fmt.Errorf("cannot start ingestion: engine is shutdown")
+	}
+
+	// Prevent multiple starts
+	if activeGoroutines := e.ActiveGoroutines(); activeGoroutines > 0 {
+		e.logger.Info().
+			Int32("active_goroutines", activeGoroutines).
+			Msg("Ingestion already running, skipping start")
+		return nil
+	}
+
+	e.mu.Lock()
+	if fromBlock > 0 && e.lastBlock == 0 {
+		e.lastBlock = fromBlock - 1
+	}
+	e.startTime = time.Now()
+	e.mu.Unlock()
+
+	logs := make(chan types.Log, DefaultLogsChannelBuffer)
+	if err := e.subscribe(ctx, fromBlock, logs); err != nil {
+		return err
+	}
+
+	e.startGoroutine("ingestion", func() {
+		e.run(e.cleanupCtx, logs)
+	})
+
+	return nil
+}
+
+// subscribe replaces the subscription of the engine with a new one from a block
+func (e *IngestionEngine) subscribe(ctx context.Context, fromBlock uint64, logs chan types.Log) error {
+	query := e.filterQuery(fromBlock)
+
+	sub, err := e.client.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to logs: %v", err)
+	}
+
+	e.mu.Lock()
+	e.sub = sub
+	e.mu.Unlock()
+
+	e.logger.Info().
+		Str("contract", e.contract.Hex()).
+		Interface("from_block", query.FromBlock).
+		Int("topics", len(query.Topics[0])).
+		Msg("Subscribed to contract events")
+
+	return nil
+}
+
+// unsubscribe drops the subscription of the engine
+func (e *IngestionEngine) unsubscribe() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.sub != nil {
+		e.sub.Unsubscribe()
+		e.sub = nil
+	}
+}
+
+// run processes the logs of the subscription, and resubscribes when it fails or a restart is requested
+func (e *IngestionEngine) run(ctx context.Context, logs chan types.Log) {
+	defer e.unsubscribe()
+
+	// consecutive failures of the logs, delaying their replay
+	var failures int
+
+	for {
+		e.mu.Lock()
+		sub := e.sub
+		e.mu.Unlock()
+
+		var subErr <-chan error
+		if sub != nil {
+			subErr = sub.Err()
+		}
+
+		select {
+		case vLog := <-logs:
+			if err := e.handleLog(ctx, vLog); err == nil {
+				failures = 0
+				continue
+			}
+
+			failures++
+			select {
+			case <-time.After(replayDelay(failures)):
+			case <-ctx.Done():
+				return
+			}
+		case err := <-subErr:
+			e.logger.Error().Err(err).Msg("Subscription error")
+		case <-e.restartSignal:
+			e.logger.Info().Msg("Restart signal received, resubscribing")
+		case <-ctx.Done():
+			e.logger.Debug().Msg("Context cancelled, stopping ingestion")
+			return
+		}
+
+		// The subscription ends with an error when the engine is shut down
+		if ctx.Err() != nil {
+			return
+		}
+
+		e.unsubscribe()
+		drainLogs(logs)
+		atomic.AddInt64(&e.reconnections, 1)
+
+		if !e.resubscribe(ctx, logs) {
+			return
+		}
+	}
+}
+
+// resubscribe subscribes again from the last block with events with an exponential backoff.
+// Returns false if the engine stopped or gave up.
+func (e *IngestionEngine) resubscribe(ctx context.Context, logs chan types.Log) bool {
+	for attempt := 0; attempt < ingestionMaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := min(time.Duration(1<<attempt)*ingestionBaseDelay, ingestionMaxDelay)
+			e.logger.Info().
+				Int("attempt", attempt+1).
+				Int("max_retries", ingestionMaxRetries).
+				Dur("delay", delay).
+				Msg("Retrying subscription attempt")
+
+			select {
+			case <-time.After(delay):
+			case <-e.restartSignal:
+				e.logger.Info().Msg("Restart signal received during delay, resubscribing immediately")
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Resume from the last block with events, its logs are deduplicated by the handlers
+		e.mu.Lock()
+		fromBlock := e.lastBlock
+		e.mu.Unlock()
+
+		err := e.subscribe(ctx, fromBlock, logs)
+		if err == nil {
+			return true
+		}
+
+		e.logger.Error().
+			Int("attempt", attempt+1).
+			Int("max_retries", ingestionMaxRetries).
+			Err(err).
+			Msg("Subscription failed")
+
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+
+	e.logger.Error().
+		Int("max_attempts", ingestionMaxRetries).
+		Msg("CRITICAL: Unable to establish stable subscription")
+
+	return false
+}
+
+// replayDelay returns the delay before resubscribing after a log failed for the given consecutive time,
+// the first replay is immediate
+func replayDelay(failures int) time.Duration {
+	if failures <= 1 {
+		return 0
+	}
+	return min(time.Duration(1<<min(failures-1, 10))*ingestionBaseDelay, ingestionMaxDelay)
+}
+
+// drainLogs discards the logs received from a dropped subscription, the next subscription delivers them again
+func drainLogs(logs chan types.Log) {
+	for {
+		select {
+		case <-logs:
+		default:
+			return
+		}
+	}
+}
+
+// handleLog routes a log to the handler of its event signature,
+// the engine moves to the block of the log only once it's processed
+func (e *IngestionEngine) handleLog(ctx context.Context, vLog types.Log) error {
+	if len(vLog.Topics) == 0 {
+		return nil
+	}
+
+	handler, ok := e.routes[vLog.Topics[0]]
+	if !ok {
+		e.logger.Debug().
+			Str("topic", vLog.Topics[0].Hex()).
+			Msg("No handler for event signature, skipping log")
+		return nil
+	}
+
+	e.logger.Info().
+		Str("event_type", handler.EventType).
+		Uint64(logging.FieldBlock, vLog.BlockNumber).
+		Str("tx_hash", vLog.TxHash.Hex()).
+		Msg("EVENT RECEIVED")
+
+	logCtx, cancel := context.WithTimeout(ctx, DefaultLogTimeout)
+	startTime := time.Now()
+	err := handler.Handle(logCtx, vLog)
+	cancel()
+
+	if err != nil {
+		e.logger.Error().
+			Str("event_type", handler.EventType).
+			Uint64(logging.FieldBlock, vLog.BlockNumber).
+			Str("tx_hash", vLog.TxHash.Hex()).
+			Err(err).
+			Msg("Failed to process log, resubscribing before it")
+		return err
+	}
+
+	// Logs arrive in block order, so the blocks before a new one are complete
+	if !vLog.Removed {
+		e.advance(vLog.BlockNumber)
+	}
+
+	e.mu.Lock()
+	e.lastEventTime = time.Now()
+	e.mu.Unlock()
+
+	e.logger.Info().
+		Str("event_type", handler.EventType).
+		Uint64(logging.FieldBlock, vLog.BlockNumber).
+		Str("tx_hash", vLog.TxHash.Hex()).
+		Dur("processing_time", time.Since(startTime)).
+		Msg("Successfully processed event")
+
+	return nil
+}
+
+// advance moves the engine to a block and reports the progress of the blocks before it for every event type,
+// the logs of all the handlers arrive in block order and none failed before it
+func (e *IngestionEngine) advance(blockNumber uint64) {
+	e.mu.Lock()
+	if blockNumber <= e.lastBlock {
+		e.mu.Unlock()
+		return
+	}
+	e.lastBlock = blockNumber
+	e.mu.Unlock()
+
+	if e.progress == nil {
+		return
+	}
+	for _, handler := range e.handlers {
+		e.progress(handler.EventType, blockNumber-1)
+	}
+}
+
+// Restart resubscribes the engine from the last block with events, starting it again if it stopped
+func (e *IngestionEngine) Restart(ctx context.Context) error {
+	if e.IsShutdown() {
+		return fmt.Errorf("cannot restart: engine is shutdown")
+	}
+
+	if e.ActiveGoroutines() == 0 {
+		e.mu.Lock()
+		fromBlock := e.lastBlock
+		e.mu.Unlock()
+
+		e.logger.Info().Uint64("from_block", fromBlock).Msg("Ingestion stopped, starting it again")
+		return e.Start(ctx, fromBlock)
+	}
+
+	select {
+	case e.restartSignal <- struct{}{}:
+		e.logger.Info().Msg("Restart signal sent")
+	default:
+		e.logger.Debug().Msg("Restart signal already pending")
+	}
+
+	return nil
+}
+
+// SubscriptionCount returns the number of active subscriptions, at most one
+func (e *IngestionEngine) SubscriptionCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.sub == nil {
+		return 0
+	}
+	return 1
+}
+
+// IsHealthy returns whether the engine is subscribed, engines starting up are given a grace period
+func (e *IngestionEngine) IsHealthy() bool {
+	e.mu.Lock()
+	startTime := e.startTime
+	e.mu.Unlock()
+
+	if e.ActiveGoroutines() == 0 {
+		return false
+	}
+
+	return e.SubscriptionCount() > 0 || time.Since(startTime) < ingestionGracePeriod
+}
+
+// ReconnectionCount returns the number of times the engine resubscribed
+func (e *IngestionEngine) ReconnectionCount() int64 {
+	return atomic.LoadInt64(&e.reconnections)
+}
+
+// LastEventTime returns the time of the last processed event
+func (e *IngestionEngine) LastEventTime() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastEventTime
+}
+
+// Shutdown stops the ingestion and waits for its goroutines to complete
+func (e *IngestionEngine) Shutdown(timeout time.Duration) error {
+	e.shutdownMu.Lock()
+	if e.isShutdown {
+		e.shutdownMu.Unlock()
+		return nil // Already shutdown
+	}
+	e.isShutdown = true
+	e.shutdownMu.Unlock()
+
+	e.logger.Info().Msg("Shutting down ingestion engine...")
+
+	// Cancel the cleanup context to signal all goroutines to stop
+	e.cleanupCancel()
+	e.unsubscribe()
+
+	// Wait for all goroutines to complete with timeout
+	done := make(chan struct{})
+	go func() {
+		e.goroutineWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		e.logger.Info().Msg("Ingestion engine shutdown completed successfully")
+		return nil
+	case <-time.After(timeout):
+		e.logger.Error().
+			Dur("timeout", timeout).
+			Msg("Ingestion engine shutdown timed out")
+		return fmt.Errorf("shutdown timed out after %v", timeout)
+	}
+}
+
+// IsShutdown returns whether the engine is in shutdown state
+func (e *IngestionEngine) IsShutdown() bool {
+	e.shutdownMu.RLock()
+	defer e.shutdownMu.RUnlock()
+	return e.isShutdown
+}
+
+// ActiveGoroutines returns the current count of active goroutines
+func (e *IngestionEngine) ActiveGoroutines() int32 {
+	return atomic.LoadInt32(&e.activeGoroutines)
+}
+
+// startGoroutine safely starts a goroutine with proper cleanup tracking
+func (e *IngestionEngine) startGoroutine(name string, fn func()) {
+	e.shutdownMu.RLock()
+	if e.isShutdown {
+		e.shutdownMu.RUnlock()
+		e.logger.Debug().
+			Str("goroutine_name", name).
+			Msg("Cannot start goroutine: engine is shutdown")
+		return
+	}
+	e.goroutineWg.Add(1)
+	e.shutdownMu.RUnlock()
+
+	atomic.AddInt32(&e.activeGoroutines, 1)
+
+	go func() {
+		defer func() {
+			e.goroutineWg.Done()
+			atomic.AddInt32(&e.activeGoroutines, -1)
+
+			// Recover from panics
+			if r := recover(); r != nil {
+				e.logger.Error().
+					Str("goroutine_name", name).
+					Any("panic", r).
+					Msg("CRITICAL: Panic in goroutine")
+			}
+		}()
+
+		fn()
+	}()
+}
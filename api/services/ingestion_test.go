@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ingestionTestContract = common.HexToAddress("0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB")
+	ingestionTestTopicA   = common.HexToHash("0x0a")
+	ingestionTestTopicB   = common.HexToHash("0x0b")
+)
+
+func TestIngestionEngine_RoutesLogs(t *testing.T) {
+	client := &subscriptionClient{}
+	engine := NewIngestionEngine(client, 1, ingestionTestContract, logging.NewTesting(t))
+	intents := &recordingHandler{eventType: eventTypeIntent, topic: ingestionTestTopicA}
+	fulfillments := &recordingHandler{eventType: eventTypeFulfillment, topic: ingestionTestTopicB}
+	engine.Register(intents.handler())
+	engine.Register(fulfillments.handler())
+
+	var (
+		mu       sync.Mutex
+		progress = make(map[string]uint64)
+	)
+	engine.SetProgressFunc(func(eventType string, blockNumber uint64) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress[eventType] = blockNumber
+	})
+
+	require.NoError(t, engine.Start(context.Background(), 100))
+	defer engine.Shutdown(time.Second)
+
+	// a single subscription matches the events of both handlers
+	query, logs := client.subscription(t, 0)
+	assert.Equal(t, []common.Hash{ingestionTestTopicA, ingestionTestTopicB}, query.Topics[0])
+	assert.Equal(t, uint64(100), query.FromBlock.Uint64())
+
+	logs <- types.Log{BlockNumber: 100, Topics: []common.Hash{ingestionTestTopicA}}
+	logs <- types.Log{BlockNumber: 100, Topics: []common.Hash{ingestionTestTopicB}}
+	logs <- types.Log{BlockNumber: 105, Topics: []common.Hash{common.HexToHash("0x0c")}}
+	logs <- types.Log{BlockNumber: 105, Topics: []common.Hash{ingestionTestTopicB}}
+
+	require.Eventually(t, func() bool {
+		return intents.count() == 1 && fulfillments.count() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// the blocks before the last block with events are complete
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]uint64{eventTypeIntent: 104, eventTypeFulfillment: 104}, progress)
+	assert.Equal(t, 1, engine.SubscriptionCount())
+	assert.True(t, engine.IsHealthy())
+}
+
+func TestIngestionEngine_ResubscribesAfterError(t *testing.T) {
+	client := &subscriptionClient{}
+	engine := NewIngestionEngine(client, 1, ingestionTestContract, logging.NewTesting(t))
+	engine.SetProgressFunc(func(string, uint64) {})
+
+	handler := &recordingHandler{eventType: eventTypeIntent, topic: ingestionTestTopicA}
+	engine.Register(handler.handler())
+
+	require.NoError(t, engine.Start(context.Background(), 100))
+	defer engine.Shutdown(time.Second)
+
+	_, logs := client.subscription(t, 0)
+	logs <- types.Log{BlockNumber: 120, Topics: []common.Hash{ingestionTestTopicA}}
+	require.Eventually(t, func() bool { return handler.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	client.fail(0, errors.New("connection reset"))
+
+	// the new subscription resumes from the last block with events
+	query, _ := client.subscription(t, 1)
+	assert.Equal(t, uint64(120), query.FromBlock.Uint64())
+	assert.Equal(t, int64(1), engine.ReconnectionCount())
+	assert.Equal(t, int32(1), engine.ActiveGoroutines())
+}
+
+func TestIngestionEngine_FailedLogHoldsCheckpoint(t *testing.T) {
+	client := &subscriptionClient{}
+	engine := NewIngestionEngine(client, 1, ingestionTestContract, logging.NewTesting(t))
+
+	var (
+		mu       sync.Mutex
+		progress uint64
+	)
+	engine.SetProgressFunc(func(_ string, blockNumber uint64) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress = blockNumber
+	})
+
+	handler := &recordingHandler{eventType: eventTypeIntent, topic: ingestionTestTopicA, release: make(chan struct{})}
+	engine.Register(handler.handler())
+
+	require.NoError(t, engine.Start(context.Background(), 100))
+	defer engine.Shutdown(time.Second)
+
+	_, logs := client.subscription(t, 0)
+	logs <- types.Log{BlockNumber: 120, Topics: []common.Hash{ingestionTestTopicA}}
+	require.Eventually(t, func() bool { return handler.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	// a successful log at a later block is received while the failed one is processed
+	handler.fail(130, errors.New("connection reset"))
+	logs <- types.Log{BlockNumber: 130, Topics: []common.Hash{ingestionTestTopicA}}
+	logs <- types.Log{BlockNumber: 140, Topics: []common.Hash{ingestionTestTopicA}}
+	close(handler.release)
+
+	// the engine resubscribes before the failed log without processing the later one
+	query, logs := client.subscription(t, 1)
+	assert.Equal(t, uint64(120), query.FromBlock.Uint64())
+	assert.Equal(t, 2, handler.count())
+	assert.Equal(t, uint64(119), handler.checkpointBlock())
+	mu.Lock()
+	assert.Equal(t, uint64(119), progress)
+	mu.Unlock()
+
+	// the failed log is processed again before the later one
+	handler.fail(130, nil)
+	logs <- types.Log{BlockNumber: 130, Topics: []common.Hash{ingestionTestTopicA}}
+	logs <- types.Log{BlockNumber: 140, Topics: []common.Hash{ingestionTestTopicA}}
+	require.Eventually(t, func() bool { return handler.count() == 4 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(139), handler.checkpointBlock())
+}
+
+func TestIngestionEngine_StartError(t *testing.T) {
+	client := &subscriptionClient{err: errors.New("notifications not supported")}
+	engine := NewIngestionEngine(client, 1, ingestionTestContract, logging.NewTesting(t))
+
+	err := engine.Start(context.Background(), 100)
+	require.ErrorContains(t, err, "notifications not supported")
+	assert.Equal(t, int32(0), engine.ActiveGoroutines())
+	assert.False(t, engine.IsHealthy())
+}
+
+func TestIngestionEngine_RestartNoGoroutineLeak(t *testing.T) {
+	client := &subscriptionClient{}
+	engine := NewIngestionEngine(client, 1, ingestionTestContract, logging.NewTesting(t))
+
+	require.NoError(t, engine.Start(context.Background(), 100))
+	client.subscription(t, 0)
+
+	// starting again is skipped while the engine runs
+	require.NoError(t, engine.Start(context.Background(), 100))
+	assert.Equal(t, int32(1), engine.ActiveGoroutines())
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, engine.Restart(context.Background()))
+		client.subscription(t, i+1)
+		assert.Equal(t, int32(1), engine.ActiveGoroutines(), "restart %d", i+1)
+	}
+
+	require.NoError(t, engine.Shutdown(time.Second))
+	assert.True(t, engine.IsShutdown())
+	assert.Equal(t, int32(0), engine.ActiveGoroutines())
+	assert.Equal(t, 0, engine.SubscriptionCount())
+
+	// shutdown is idempotent and the engine can't be started again
+	require.NoError(t, engine.Shutdown(time.Second))
+	require.ErrorContains(t, engine.Restart(context.Background()), "engine is shutdown")
+	require.ErrorContains(t, engine.Start(context.Background(), 100), "engine is shutdown")
+}
+
+func TestIngestionEngine_ShutdownTimeout(t *testing.T) {
+	engine := NewIngestionEngine(nil, 1, ingestionTestContract, logging.NewTesting(t))
+
+	engine.startGoroutine("long-running", func() {
+		time.Sleep(time.Second)
+	})
+
+	err := engine.Shutdown(100 * time.Millisecond)
+	require.ErrorContains(t, err, "shutdown timed out")
+
+	// no goroutine is started after shutdown
+	engine.startGoroutine("post-shutdown", func() {
+		t.Error("goroutine should not start after shutdown")
+	})
+}
+
+// recordingHandler counts the logs routed to an event handler,
+// and moves a checkpoint before the block of each processed log like the handlers of the services
+type recordingHandler struct {
+	eventType string
+	topic     common.Hash
+	release   chan struct{} // failing logs wait for it to be closed, if set
+
+	mu         sync.Mutex
+	logs       []types.Log
+	errs       map[uint64]error // returned for the logs of the blocks
+	checkpoint uint64
+}
+
+func (h *recordingHandler) handler() EventHandler {
+	return EventHandler{
+		EventType: h.eventType,
+		Topics:    []common.Hash{h.topic},
+		Handle: func(_ context.Context, vLog types.Log) error {
+			h.mu.Lock()
+			h.logs = append(h.logs, vLog)
+			err := h.errs[vLog.BlockNumber]
+			h.mu.Unlock()
+
+			if err != nil {
+				if h.release != nil {
+					<-h.release
+				}
+				return err
+			}
+
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.checkpoint = max(h.checkpoint, vLog.BlockNumber-1)
+			return nil
+		},
+	}
+}
+
+// fail sets the error of the logs of a block, nil processes them again
+func (h *recordingHandler) fail(blockNumber uint64, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.errs == nil {
+		h.errs = make(map[uint64]error)
+	}
+	h.errs[blockNumber] = err
+}
+
+func (h *recordingHandler) checkpointBlock() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.checkpoint
+}
+
+func (h *recordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.logs)
+}
+
+// subscriptionClient records the log subscriptions made with it
+type subscriptionClient struct {
+	evm.Client
+
+	mu   sync.Mutex
+	err  error
+	subs []*fakeSubscription
+}
+
+type fakeSubscription struct {
+	query ethereum.FilterQuery
+	logs  chan<- types.Log
+	errCh chan error
+	once  sync.Once
+}
+
+func (s *fakeSubscription) Err() <-chan error { return s.errCh }
+
+func (s *fakeSubscription) Unsubscribe() {
+	s.once.Do(func() { close(s.errCh) })
+}
+
+func (c *subscriptionClient) SubscribeFilterLogs(
+	_ context.Context,
+	q ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	sub := &fakeSubscription{query: q, logs: ch, errCh: make(chan error, 1)}
+	c.subs = append(c.subs, sub)
+	return sub, nil
+}
+
+// subscription waits for the i-th subscription and returns its query and logs channel
+func (c *subscriptionClient) subscription(t *testing.T, i int) (ethereum.FilterQuery, chan<- types.Log) {
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.subs) > i
+	}, time.Second, 5*time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subs[i].query, c.subs[i].logs
+}
+
+// fail sends an error on the i-th subscription
+func (c *subscriptionClient) fail(i int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subs[i].errCh <- err
+}
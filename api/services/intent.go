@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"reflect"
-	"runtime/debug"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,9 +14,10 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
 	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/logging"
 	"github.com/speedrun-hq/speedrun/api/models"
 	"github.com/speedrun-hq/speedrun/api/utils"
@@ -42,8 +41,7 @@ const (
 	IntentInitiatedWithCallRequiredFields = 7
 
 	// Buffer sizes for channels
-	DefaultErrorChannelBuffer = 100 // Increased from 10 for better handling of multiple chains
-	DefaultLogsChannelBuffer  = 200 // Increased from 100 for high-throughput scenarios
+	DefaultLogsChannelBuffer = 200 // Increased from 100 for high-throughput scenarios
 
 	// Timeout configurations
 	DefaultDBTimeout  = 10 * time.Second // Increased from 5s for complex DB operations
@@ -58,45 +56,40 @@ const (
 
 // IntentService handles monitoring and processing of intent events
 type IntentService struct {
-	client           *ethclient.Client
-	clientResolver   ClientResolver
-	db               db.Database
-	abi              abi.ABI
-	chainID          uint64
-	subs             map[string]ethereum.Subscription
-	activeGoroutines int32      // Counter for active goroutines
-	errChannel       chan error // Channel for collecting errors from goroutines
-	mu               sync.Mutex // Mutex for thread-safe operations
-	logger           zerolog.Logger
+	client          evm.Client
+	clientResolver  ClientResolver
+	db              db.Database
+	reorg           *ReorgDetector
+	confirmations   *ConfirmationTracker
+	logRange        *LogRange
+	skipEmptyRanges bool
+	backfill        bool // re-indexing a range, checkpoints are left untouched
+	events          EventNotifier
+	tokens          *TokenRegistry
+	prices          PriceSource
+	abi             abi.ABI
+	chainID         uint64
+	ingestion       *IngestionEngine
+	mu              sync.Mutex // Mutex for thread-safe operations
+	logger          zerolog.Logger
 
 	// Metrics tracking
-	eventsProcessed   int64     // Total events processed
-	eventsSkipped     int64     // Total events skipped (duplicates)
-	processingErrors  int64     // Total processing errors
-	lastEventTime     time.Time // Time of last processed event
-	lastHealthCheck   time.Time // Time of last health check
-	reconnectionCount int64     // Number of times reconnected
-	startTime         time.Time // When the service was started
-
-	// ZetaChain polling health tracking
-	isZetaChain      bool      // Whether this is ZetaChain (uses polling instead of subscriptions)
+	eventsProcessed  int64     // Total events processed
+	eventsSkipped    int64     // Total events skipped (duplicates)
+	processingErrors int64     // Total processing errors
+	lastEventTime    time.Time // Time of last processed event
+	lastHealthCheck  time.Time // Time of last health check
+	startTime        time.Time // When the service was started
+
+	// Polling health tracking
+	polling          bool      // Whether the events are polled instead of received from subscriptions
 	lastPollingCheck time.Time // Last time polling health was verified
 	pollingHealthy   bool      // Whether HTTP polling is working
-
-	// Restart coordination
-	restartSignal chan struct{} // Channel to signal subscription restart
-
-	// Goroutine cleanup management
-	cleanupCtx    context.Context    // Context for cleanup operations
-	cleanupCancel context.CancelFunc // Cancel function for cleanup context
-	goroutineWg   sync.WaitGroup     // WaitGroup to track all goroutines
-	isShutdown    bool               // Flag to prevent new goroutines after shutdown
-	shutdownMu    sync.RWMutex       // Mutex for shutdown operations
 }
 
 // NewIntentService creates a new IntentService instance
 func NewIntentService(
-	client *ethclient.Client,
+	client evm.Client,
 	clientResolver ClientResolver,
 	db db.Database,
 	intentInitiatedEventABI string,
@@ -109,45 +102,93 @@ func NewIntentService(
 		return nil, fmt.Errorf("failed to parse contract ABI: %v", err)
 	}
 
-	errChan := make(chan error, DefaultErrorChannelBuffer) // Buffer for errors to avoid blocking
-
-	// Detect if this is ZetaChain (uses polling instead of subscriptions)
-	isZetaChain := chainID == 7000
-
-	// Create cleanup context
-	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-
 	return &IntentService{
 		client:         client,
 		clientResolver: clientResolver,
 		db:             db,
+		reorg:          NewReorgDetector(db, logger),
+		logRange:       NewLogRange(DefaultLogRange, DefaultMinLogRange, DefaultMaxLogRange),
 		abi:            parsedABI,
 		chainID:        chainID,
-		subs:           make(map[string]ethereum.Subscription),
-		errChannel:     errChan,
 		logger:         logger.With().Uint64(logging.FieldChain, chainID).Logger(),
 		startTime:      time.Now(),
-		isZetaChain:    isZetaChain,
-		pollingHealthy: isZetaChain, // ZetaChain starts as healthy (polling assumed working)
-		restartSignal:  make(chan struct{}),
-		cleanupCtx:     cleanupCtx,
-		cleanupCancel:  cleanupCancel,
 	}, nil
 }
 
-// ActiveGoroutines returns the current count of active goroutines
-func (s *IntentService) ActiveGoroutines() int32 {
-	return atomic.LoadInt32(&s.activeGoroutines)
+// SetEventNotifier sets the notifier called after an intent event is persisted
+func (s *IntentService) SetEventNotifier(notifier EventNotifier) {
+	s.events = notifier
+}
+
+// SetTokenRegistry makes the validation of the intents created through the API use the limits of their token
+func (s *IntentService) SetTokenRegistry(tokens *TokenRegistry) {
+	s.tokens = tokens
+}
+
+// SetPriceSource makes the service store the USD value of the intents when they're created,
+// it requires the token registry to know the decimals of the tokens
+func (s *IntentService) SetPriceSource(prices PriceSource) {
+	s.prices = prices
+}
+
+// SetLogRange sets the adaptive block range of the FilterLogs requests made while catching up and polling
+func (s *IntentService) SetLogRange(logRange *LogRange) {
+	s.logRange = logRange
+}
+
+// SetSkipEmptyRanges makes the catchup probe the blocks ahead for events and fast-forward through the empty ranges
+func (s *IntentService) SetSkipEmptyRanges(skip bool) {
+	s.skipEmptyRanges = skip
+}
+
+// SetBackfill makes the service re-index logs without moving the live checkpoints
+func (s *IntentService) SetBackfill() {
+	s.backfill = true
+}
+
+// SetConfirmationTracker makes the service stage logs until their block is confirmed
+func (s *IntentService) SetConfirmationTracker(tracker *ConfirmationTracker) {
+	s.confirmations = tracker
+	tracker.Register(eventTypeIntent, s.persistLog)
+}
+
+// SetIngestionEngine sets the engine receiving the live events of the chain, reported by the health checks
+func (s *IntentService) SetIngestionEngine(engine *IngestionEngine) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ingestion = engine
+}
+
+// EventHandler returns the handler of the intent events for an IngestionEngine
+func (s *IntentService) EventHandler() EventHandler {
+	return EventHandler{
+		EventType: eventTypeIntent,
+		Topics: []common.Hash{
+			s.abi.Events[IntentInitiatedEventName].ID,
+			s.abi.Events[IntentInitiatedWithCallEventName].ID,
+		},
+		Handle: s.processLog,
+	}
+}
+
+// chainHandler returns the event handler of the service with the client and log range of its chain
+func (s *IntentService) chainHandler() chainHandler {
+	return chainHandler{
+		EventHandler:    s.EventHandler(),
+		chainID:         s.chainID,
+		client:          s.client,
+		logRange:        s.logRange,
+		skipEmptyRanges: s.skipEmptyRanges,
+	}
 }
 
 // IsHealthy checks if the service is healthy and processing events
 func (s *IntentService) IsHealthy() bool {
-	activeGoroutines := atomic.LoadInt32(&s.activeGoroutines)
-	subscriptionCount := s.GetSubscriptionCount()
-
 	// Update last health check time
 	s.mu.Lock()
 	s.lastHealthCheck = time.Now()
+	polling := s.polling
+	ingestion := s.ingestion
 	s.mu.Unlock()
 
 	// Provide a 30-second grace period for newly started services
@@ -156,14 +197,14 @@ func (s *IntentService) IsHealthy() bool {
 
 	var isHealthy bool
 
-	// ZetaChain uses HTTP polling instead of WebSocket subscriptions
-	if s.isZetaChain {
-		// For ZetaChain, health depends on HTTP client connectivity and polling status
+	// Polled chains don't use WebSocket subscriptions
+	if polling {
+		// For polled chains, health depends on HTTP client connectivity and polling status
 		// We don't expect subscriptions or the normal goroutines since polling happens in catchup service
 
 		// During startup, be lenient
 		if isStartingUp {
-			s.logger.Debug().Msg("ZetaChain service starting up (grace period): HTTP polling assumed healthy")
+			s.logger.Debug().Msg("Polling service starting up (grace period): HTTP polling assumed healthy")
 			isHealthy = true
 		} else {
 			// Check if polling health has been verified recently (within 10 minutes)
@@ -174,35 +215,28 @@ func (s *IntentService) IsHealthy() bool {
 				if pollingStale {
 					s.logger.Debug().
 						Dur("time_since_last_check", time.Since(s.lastPollingCheck)).
-						Msg("ZetaChain polling health stale")
+						Msg("Polling health stale")
 				} else {
-					s.logger.Debug().Msg("ZetaChain polling unhealthy")
+					s.logger.Debug().Msg("Polling unhealthy")
 				}
 			}
 		}
 	} else {
-		// For other chains, use the normal subscription-based health check
-		// Service is healthy if it has:
-		// 1. At least 3 goroutines (error monitor + health monitor + subscription goroutine)
-		// 2. At least 1 active subscription
-		isHealthy = activeGoroutines >= 3 && subscriptionCount >= 1
+		// For other chains, the ingestion engine of the chain must be subscribed
+		isHealthy = ingestion != nil && ingestion.IsHealthy()
 
 		// During startup, be more lenient
 		if isStartingUp {
-			s.logger.Debug().
-				Int32("active_goroutines", activeGoroutines).
-				Int("subscriptions", subscriptionCount).
-				Msg("Service starting up (grace period)")
+			s.logger.Debug().Msg("Service starting up (grace period)")
 			isHealthy = true
 		}
 
 		// Add debug logging when service is unhealthy
 		if !isHealthy {
-			timeSinceStart := time.Since(s.startTime)
 			s.logger.Debug().
-				Dur("time_since_start", timeSinceStart).
-				Int32("active_goroutines", activeGoroutines).
-				Int("subscriptions", subscriptionCount).
+				Dur("time_since_start", time.Since(s.startTime)).
+				Int32("active_goroutines", s.ActiveGoroutines()).
+				Int("subscriptions", s.GetSubscriptionCount()).
 				Msg("Service unhealthy")
 		}
 	}
@@ -210,34 +244,66 @@ func (s *IntentService) IsHealthy() bool {
 	return isHealthy
 }
 
-// GetSubscriptionCount returns the number of active subscriptions
+// GetSubscriptionCount returns the number of active subscriptions of the ingestion engine of the chain
 func (s *IntentService) GetSubscriptionCount() int {
+	s.mu.Lock()
+	ingestion := s.ingestion
+	s.mu.Unlock()
+
+	if ingestion == nil {
+		return 0
+	}
+	return ingestion.SubscriptionCount()
+}
+
+// ActiveGoroutines returns the number of active goroutines of the ingestion engine of the chain
+func (s *IntentService) ActiveGoroutines() int32 {
+	s.mu.Lock()
+	ingestion := s.ingestion
+	s.mu.Unlock()
+
+	if ingestion == nil {
+		return 0
+	}
+	return ingestion.ActiveGoroutines()
+}
+
+// SetPolling marks the events of the chain as polled instead of received from subscriptions,
+// polling starts healthy
+func (s *IntentService) SetPolling() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return len(s.subs)
+
+	if !s.polling {
+		s.polling = true
+		s.pollingHealthy = true
+	}
 }
 
-// UpdatePollingHealth updates the polling health status for ZetaChain
+// UpdatePollingHealth updates the polling health status of a polled chain
 func (s *IntentService) UpdatePollingHealth(healthy bool) {
-	if !s.isZetaChain {
-		return // Only applicable to ZetaChain
+	s.mu.Lock()
+	if !s.polling {
+		s.mu.Unlock()
+		return // Only applicable to polled chains
 	}
 
-	s.mu.Lock()
 	s.pollingHealthy = healthy
 	s.lastPollingCheck = time.Now()
 	s.mu.Unlock()
 
 	if healthy {
-		s.logger.Debug().Msg("ZetaChain polling health updated: healthy")
+		s.logger.Debug().Msg("Polling health updated: healthy")
 	} else {
-		s.logger.Debug().Msg("ZetaChain polling health updated: unhealthy")
+		s.logger.Debug().Msg("Polling health updated: unhealthy")
 	}
 }
 
-// IsZetaChain returns whether this service is for ZetaChain
-func (s *IntentService) IsZetaChain() bool {
-	return s.isZetaChain
+// IsPolling returns whether the events of the chain are polled instead of received from subscriptions
+func (s *IntentService) IsPolling() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.polling
 }
 
 // ServiceMetrics represents detailed metrics for the service
@@ -254,8 +320,8 @@ type ServiceMetrics struct {
 	TimeSinceLastEvent string    `json:"time_since_last_event"`
 	IsHealthy          bool      `json:"is_healthy"`
 
-	// ZetaChain-specific metrics
-	IsZetaChain           bool      `json:"is_zetachain"`
+	// Polling-specific metrics
+	IsPolling             bool      `json:"is_polling"`
 	PollingHealthy        bool      `json:"polling_healthy,omitempty"`
 	LastPollingCheck      time.Time `json:"last_polling_check,omitempty"`
 	TimeSincePollingCheck string    `json:"time_since_polling_check,omitempty"`
@@ -263,22 +329,26 @@ type ServiceMetrics struct {
 
 // GetMetrics returns detailed metrics about the service
 func (s *IntentService) GetMetrics() ServiceMetrics {
+	subscriptionCount := s.GetSubscriptionCount()
+	activeGoroutines := s.ActiveGoroutines()
+
 	s.mu.Lock()
-	subscriptionCount := len(s.subs)
+	var reconnectionCount int64
+	if s.ingestion != nil {
+		reconnectionCount = s.ingestion.ReconnectionCount()
+	}
 	eventsProcessed := atomic.LoadInt64(&s.eventsProcessed)
 	eventsSkipped := atomic.LoadInt64(&s.eventsSkipped)
 	processingErrors := atomic.LoadInt64(&s.processingErrors)
 	lastEventTime := s.lastEventTime
 	lastHealthCheck := s.lastHealthCheck
-	reconnectionCount := atomic.LoadInt64(&s.reconnectionCount)
 
-	// ZetaChain-specific metrics
-	isZetaChain := s.isZetaChain
+	// Polling-specific metrics
+	isPolling := s.polling
 	pollingHealthy := s.pollingHealthy
 	lastPollingCheck := s.lastPollingCheck
 	s.mu.Unlock()
 
-	activeGoroutines := atomic.LoadInt32(&s.activeGoroutines)
 	isHealthy := s.IsHealthy()
 
 	var timeSinceLastEvent string
@@ -289,9 +359,9 @@ func (s *IntentService) GetMetrics() ServiceMetrics {
 	}
 
 	var timeSincePollingCheck string
-	if isZetaChain && !lastPollingCheck.IsZero() {
+	if isPolling && !lastPollingCheck.IsZero() {
 		timeSincePollingCheck = time.Since(lastPollingCheck).String()
-	} else if isZetaChain {
+	} else if isPolling {
 		timeSincePollingCheck = "never"
 	}
 
@@ -307,310 +377,16 @@ func (s *IntentService) GetMetrics() ServiceMetrics {
 		ReconnectionCount:     reconnectionCount,
 		TimeSinceLastEvent:    timeSinceLastEvent,
 		IsHealthy:             isHealthy,
-		IsZetaChain:           isZetaChain,
+		IsPolling:             isPolling,
 		PollingHealthy:        pollingHealthy,
 		LastPollingCheck:      lastPollingCheck,
 		TimeSincePollingCheck: timeSincePollingCheck,
 	}
 }
 
-// RestartSubscription restarts a subscription for a given contract address
-func (s *IntentService) RestartSubscription(_ context.Context, contractAddress common.Address) error {
-	subID := contractAddress.Hex()
-
-	// Unsubscribe existing subscription if it exists
-	s.mu.Lock()
-	if existingSub, exists := s.subs[subID]; exists {
-		existingSub.Unsubscribe()
-		delete(s.subs, subID)
-		s.logger.Info().
-			Str("subscription_id", subID).
-			Msg("Unsubscribed existing subscription for restart")
-	}
-	s.mu.Unlock()
-
-	// Track reconnection
-	atomic.AddInt64(&s.reconnectionCount, 1)
-
-	// Signal the subscription goroutine to restart
-	select {
-	case s.restartSignal <- struct{}{}:
-		s.logger.Info().
-			Str("contract", contractAddress.Hex()).
-			Int64("reconnection_count", atomic.LoadInt64(&s.reconnectionCount)).
-			Msg("Restart signal sent")
-	default:
-		// Channel is full, restart signal already pending
-		s.logger.Debug().
-			Str("contract", contractAddress.Hex()).
-			Msg("Restart signal already pending")
-	}
-
-	return nil
-}
-
-// Restart properly restarts the service by shutting down existing goroutines and starting new ones
-func (s *IntentService) Restart(ctx context.Context, contractAddress common.Address) error {
-	s.logger.Info().Uint64(logging.FieldChain, s.chainID).Msg("Restarting intent service...")
-
-	// Check if service is shutdown
-	if s.IsShutdown() {
-		return fmt.Errorf("cannot restart: service is shutdown")
-	}
-
-	// Cancel the cleanup context to signal all existing goroutines to stop
-	s.cleanupCancel()
-
-	// Wait for existing goroutines to complete with a short timeout
-	done := make(chan struct{})
-	go func() {
-		s.goroutineWg.Wait()
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		s.logger.Debug().Uint64(logging.FieldChain, s.chainID).Msg("Existing goroutines stopped successfully")
-	case <-time.After(5 * time.Second):
-		s.logger.Info().Uint64(logging.FieldChain, s.chainID).Msg("Timeout waiting for existing goroutines to stop")
-	}
-
-	// Unsubscribe from all subscriptions
-	s.UnsubscribeAll()
-
-	// Create a new cleanup context
-	s.cleanupCtx, s.cleanupCancel = context.WithCancel(context.Background())
-
-	// Reset goroutine counter
-	atomic.StoreInt32(&s.activeGoroutines, 0)
-
-	// Start the service again
-	return s.StartListening(ctx, contractAddress)
-}
-
-// StartHealthMonitor starts a goroutine that monitors the health of the service
-func (s *IntentService) StartHealthMonitor(ctx context.Context, contractAddress common.Address) {
-	ticker := time.NewTicker(HealthCheckInterval) // Use constant for reduced CPU usage
-	defer ticker.Stop()
-
-	consecutiveFailures := 0
-	maxConsecutiveFailures := 3
-
-	for {
-		select {
-		case <-ticker.C:
-			// Check if service is healthy
-			if !s.IsHealthy() {
-				consecutiveFailures++
-				s.logger.Info().
-					Int("consecutive_failures", consecutiveFailures).
-					Int("max_failures", maxConsecutiveFailures).
-					Int32("active_goroutines", s.ActiveGoroutines()).
-					Int("subscriptions", s.GetSubscriptionCount()).
-					Msg("Health check failed")
-
-				if consecutiveFailures >= maxConsecutiveFailures {
-					s.logger.Info().Msg("Service appears unhealthy, attempting restart")
-
-					// Attempt to restart the subscription
-					if err := s.RestartSubscription(ctx, contractAddress); err != nil {
-						s.logger.Error().Err(err).Msg("Failed to restart subscription")
-					} else {
-						consecutiveFailures = 0 // Reset counter on successful restart
-					}
-				}
-			} else {
-				// Service is healthy, reset failure counter
-				if consecutiveFailures > 0 {
-					s.logger.Info().Msg("Service health restored")
-					consecutiveFailures = 0
-				}
-			}
-		case <-ctx.Done():
-			s.logger.Debug().Msg("Health monitor shutting down")
-			return
-		}
-	}
-}
-
-// StartListening starts a goroutine to listen for intent events from the specified contract address.
-// It sets up a subscription to the blockchain and processes events as they arrive.
-//
-// Parameters:
-//   - ctx: Context for cancellation and timeout
-//   - contractAddress: The address of the contract to listen to
-//
-// Returns:
-//   - error: Any error that occurred during setup
-func (s *IntentService) StartListening(ctx context.Context, contractAddress common.Address) error {
-	// Check if service is shutdown
-	if s.IsShutdown() {
-		return fmt.Errorf("cannot start listening: service is shutdown")
-	}
-
-	// Check if service is already running - prevent multiple starts
-	activeGoroutines := atomic.LoadInt32(&s.activeGoroutines)
-	if activeGoroutines > 0 {
-		s.logger.Info().
-			Int32("active_goroutines", activeGoroutines).
-			Msg("Service already running, skipping start")
-		return nil
-	}
-
-	// Check if the client is using a websocket connection, which is needed for subscriptions
-	clientType := reflect.TypeOf(s.client).String()
-	isWebsocket := strings.Contains(strings.ToLower(clientType), "websocket")
-	s.logger.Info().
-		Str("client_type", clientType).
-		Bool("is_websocket", isWebsocket).
-		Msg("Intent service client type")
-
-	if !isWebsocket {
-		s.logger.Warn().
-			Str("client_type", clientType).
-			Msg("Intent service may not receive real-time events because client is not websocket")
-	}
-
-	// Get current block number as a starting point to avoid processing old events
-	startBlockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	latestBlock, err := s.client.BlockNumber(startBlockCtx)
-	cancel()
-	if err != nil {
-		s.logger.Warn().Err(err).Msg("Failed to get current block number, will listen to all new blocks")
-	} else {
-		s.logger.Info().
-			Uint64(logging.FieldBlock, latestBlock).
-			Msg("Starting intent event subscription from block")
-	}
-
-	// Start a goroutine to monitor the error channel
-	s.startGoroutine("error-monitor", func() {
-		s.monitorErrors(s.cleanupCtx)
-	})
-
-	// Start the subscription listener with automatic reconnection
-	s.startGoroutine("subscription-reconnection", func() {
-		s.startSubscriptionWithReconnection(s.cleanupCtx, contractAddress, latestBlock)
-	})
-
-	// Start the health monitor
-	s.startGoroutine("health-monitor", func() {
-		s.StartHealthMonitor(s.cleanupCtx, contractAddress)
-	})
-
-	return nil
-}
-
-// monitorErrors processes errors from goroutines
-func (s *IntentService) monitorErrors(ctx context.Context) {
-	for {
-		select {
-		case err := <-s.errChannel:
-			s.logger.Error().Err(err).Msg("Error in IntentService goroutine")
-		case <-ctx.Done():
-			s.logger.Debug().Msg("Error monitor shutting down")
-			return
-		}
-	}
-}
-
-// startSubscriptionWithReconnection handles the subscription lifecycle with automatic reconnection
-func (s *IntentService) startSubscriptionWithReconnection(
-	ctx context.Context,
-	contractAddress common.Address,
-	startBlock uint64,
-) {
-	subID := contractAddress.Hex()
-
-	// Retry configuration
-	maxRetries := 10
-	baseDelay := 1 * time.Second
-	maxDelay := 5 * time.Minute
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		select {
-		case <-ctx.Done():
-			s.logger.Debug().Msg("Context cancelled, stopping subscription attempts")
-			return
-		case <-s.restartSignal:
-			s.logger.Info().Msg("Restart signal received, restarting subscription")
-			// Reset attempt counter for restart
-			attempt = 0
-			// Get current block for restart
-			blockCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-			currentBlock, err := s.client.BlockNumber(blockCtx)
-			cancel()
-			if err != nil {
-				s.logger.Warn().Err(err).Msg("Failed to get current block for restart, using existing startBlock")
-			} else {
-				startBlock = currentBlock
-				s.logger.Info().
-					Uint64("start_block", startBlock).
-					Msg("Restarting subscription from current block")
-			}
-			// Continue to create new subscription
-		default:
-		}
-
-		// Calculate delay with exponential backoff
-		delay := time.Duration(1<<attempt) * baseDelay
-		if delay > maxDelay {
-			delay = maxDelay
-		}
-
-		if attempt > 0 {
-			s.logger.Info().
-				Int("attempt", attempt+1).
-				Int("max_retries", maxRetries).
-				Dur("delay", delay).
-				Msg("Retrying subscription attempt")
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return
-			case <-s.restartSignal:
-				s.logger.Info().Msg("Restart signal received during delay, restarting immediately")
-				attempt = 0
-				continue
-			}
-		}
-
-		// Create subscription
-		err := s.createAndRunSubscription(ctx, contractAddress, subID, startBlock)
-		if err == nil {
-			// Subscription ended normally (context cancelled)
-			return
-		}
-
-		// Log the error
-		s.logger.Error().
-			Int("attempt", attempt+1).
-			Int("max_retries", maxRetries).
-			Err(err).
-			Msg("Subscription failed")
-
-		// If this is a context cancellation, don't retry
-		if ctx.Err() != nil {
-			return
-		}
-	}
-
-	// If we get here, all retries failed
-	s.errChannel <- fmt.Errorf("failed to establish stable subscription after %d attempts", maxRetries)
-	s.logger.Error().
-		Int("max_attempts", maxRetries).
-		Msg("CRITICAL: Unable to establish stable subscription")
-}
-
-// createAndRunSubscription creates a new subscription and runs the event processing loop
-func (s *IntentService) createAndRunSubscription(
-	ctx context.Context,
-	contractAddress common.Address,
-	subID string,
-	startBlock uint64,
-) error {
-	// Configure the filter query for events
-	query := ethereum.FilterQuery{
+// filterQuery returns the query matching the intent events of the contract
+func (s *IntentService) filterQuery(contractAddress common.Address) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
 		Addresses: []common.Address{contractAddress},
 		Topics: [][]common.Hash{
 			{
@@ -619,144 +395,10 @@ func (s *IntentService) createAndRunSubscription(
 			},
 		},
 	}
-
-	// Set FromBlock if we have a start block
-	if startBlock > 0 {
-		query.FromBlock = big.NewInt(int64(startBlock))
-	}
-
-	// Log the full query details for debugging
-	s.logger.Debug().
-		Interface("addresses", query.Addresses).
-		Interface("topics", query.Topics).
-		Interface("from_block", query.FromBlock).
-		Msg("Intent subscription filter query")
-
-	// Create a new logs channel for this subscription
-	logs := make(chan types.Log, DefaultLogsChannelBuffer) // Buffer to prevent blocking
-
-	// Create the subscription
-	sub, err := s.client.SubscribeFilterLogs(ctx, query, logs)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to logs: %v", err)
-	}
-
-	// Store the subscription
-	s.mu.Lock()
-	s.subs[subID] = sub
-	s.mu.Unlock()
-
-	s.logger.Info().
-		Str("contract", contractAddress.Hex()).
-		Msg("Successfully subscribed to intent events")
-
-	// Run the event processing loop
-	err = s.runEventProcessingLoop(ctx, sub, logs, subID)
-
-	// Clean up subscription
-	s.mu.Lock()
-	if storedSub, exists := s.subs[subID]; exists && storedSub == sub {
-		delete(s.subs, subID)
-	}
-	s.mu.Unlock()
-
-	sub.Unsubscribe()
-	close(logs) // Close the logs channel to prevent goroutine leaks
-
-	return err
-}
-
-// runEventProcessingLoop runs the main event processing loop for a subscription
-func (s *IntentService) runEventProcessingLoop(
-	ctx context.Context,
-	sub ethereum.Subscription,
-	logs chan types.Log,
-	subID string,
-) error {
-	s.logger.Info().
-		Str("subscription_id", subID).
-		Msg("Starting event log processing")
-
-	// Use a ticker to periodically check system health
-	healthTicker := time.NewTicker(HealthTickerInterval)
-	defer healthTicker.Stop()
-
-	// Add a ticker for debugging to periodically log subscription status
-	debugTicker := time.NewTicker(DebugLogInterval)
-	defer debugTicker.Stop()
-
-	// Track the number of events processed for debugging
-	eventCount := 0
-
-	for {
-		select {
-		case err := <-sub.Err():
-			if err != nil {
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Err(err).
-					Msg("Subscription error")
-				return fmt.Errorf("subscription error: %v", err)
-			}
-		case vLog, ok := <-logs:
-			if !ok {
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Msg("Log channel closed")
-				return fmt.Errorf("log channel closed")
-			}
-
-			eventCount++
-			s.logger.Info().
-				Uint64(logging.FieldBlock, vLog.BlockNumber).
-				Str("tx_hash", vLog.TxHash.Hex()).
-				Int("topics", len(vLog.Topics)).
-				Msg("EVENT RECEIVED")
-
-			// Process the log with timeout to prevent processing for too long
-			logCtx, logCancel := context.WithTimeout(ctx, DefaultLogTimeout)
-			startTime := time.Now()
-			err := s.processLog(logCtx, vLog)
-			processingTime := time.Since(startTime)
-			logCancel()
-
-			if err != nil {
-				atomic.AddInt64(&s.processingErrors, 1)
-				s.errChannel <- fmt.Errorf("error processing log: %v", err)
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Err(err).
-					Msg("Failed to process log")
-			} else {
-				s.logger.Info().
-					Uint64(logging.FieldBlock, vLog.BlockNumber).
-					Str("tx_hash", vLog.TxHash.Hex()).
-					Dur("processing_time", processingTime).
-					Msg("Successfully processed event")
-			}
-		case <-healthTicker.C:
-			// Log system health information
-			s.logger.Debug().
-				Int32("active_goroutines", s.ActiveGoroutines()).
-				Int("events_processed", eventCount).
-				Msg("IntentService health")
-		case <-debugTicker.C:
-			// Extra debugging info
-			s.logger.Debug().
-				Str("subscription_id", subID).
-				Int("events_processed", eventCount).
-				Msg("Intent subscription still active")
-		case <-ctx.Done():
-			s.logger.Debug().
-				Str("subscription_id", subID).
-				Msg("Context cancelled, stopping event processing")
-			return nil // Normal termination
-		}
-	}
 }
 
 // processLog processes a single log entry from the blockchain.
-// It validates the log, extracts event data, and stores the intent in the database.
+// Logs of unconfirmed blocks are staged, others are stored by persistLog.
 func (s *IntentService) processLog(ctx context.Context, vLog types.Log) error {
 	// Check for context cancellation
 	if ctx.Err() != nil {
@@ -775,6 +417,21 @@ func (s *IntentService) processLog(ctx context.Context, vLog types.Log) error {
 		}
 	}()
 
+	// The log was part of a block that is no longer canonical
+	if vLog.Removed {
+		return s.reorg.HandleRemovedLog(ctx, s.chainID, vLog)
+	}
+
+	// Hold the log back until its block is confirmed
+	if staged, err := s.confirmations.Stage(ctx, eventTypeIntent, vLog); err != nil || staged {
+		return err
+	}
+
+	return s.persistLog(ctx, vLog)
+}
+
+// persistLog validates a confirmed log, extracts event data, and stores the intent in the database.
+func (s *IntentService) persistLog(ctx context.Context, vLog types.Log) error {
 	if err := s.validateLog(vLog); err != nil {
 		return err
 	}
@@ -840,14 +497,24 @@ func (s *IntentService) processLog(ctx context.Context, vLog types.Log) error {
 		return nil
 	}
 
-	// Create the intent with a timeout
+	s.valueIntent(ctx, intent)
+
+	// Create the intent with its outbox message and checkpoint atomically, with a timeout
 	createCtx, createCancel := context.WithTimeout(ctx, DefaultDBTimeout)
-	err = s.db.CreateIntent(createCtx, intent)
+	err = s.db.WithTx(createCtx, func(tx db.Database) error {
+		if err := s.createIntent(createCtx, tx, intent); err != nil {
+			return err
+		}
+		if s.backfill {
+			return nil
+		}
+		return advanceCheckpoint(createCtx, tx, s.chainID, eventTypeIntent, vLog)
+	})
 	createCancel()
 
 	if err != nil {
 		// Skip if intent already exists
-		if strings.Contains(err.Error(), "duplicate key") {
+		if errors.Is(err, db.ErrDuplicate) {
 			atomic.AddInt64(&s.eventsSkipped, 1)
 			s.logger.Debug().
 				Str(logging.FieldIntent, intent.ID).
@@ -855,7 +522,7 @@ func (s *IntentService) processLog(ctx context.Context, vLog types.Log) error {
 			return nil
 		}
 		atomic.AddInt64(&s.processingErrors, 1)
-		return fmt.Errorf("failed to store intent in database: %v", err)
+		return fmt.Errorf("failed to store intent in database: %w", err)
 	}
 
 	// Update metrics
@@ -864,6 +531,9 @@ func (s *IntentService) processLog(ctx context.Context, vLog types.Log) error {
 	s.lastEventTime = time.Now()
 	s.mu.Unlock()
 
+	s.reorg.RecordLog(ctx, s.chainID, vLog)
+	notifyEvents(s.events)
+
 	s.logger.Info().
 		Str(logging.FieldIntent, intent.ID).
 		Msg("Successfully processed and stored intent")
@@ -944,7 +614,10 @@ func (s *IntentService) extractEventData(ctx context.Context, vLog types.Log) (*
 
 	event := &models.IntentInitiatedEvent{
 		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
 		TxHash:      vLog.TxHash.Hex(),
+		LogIndex:    vLog.Index,
+		Contract:    vLog.Address.Hex(),
 	}
 
 	// Parse indexed parameters from topics
@@ -1033,7 +706,7 @@ func (s *IntentService) extractEventData(ctx context.Context, vLog types.Log) (*
 	tx, _, err := s.client.TransactionByHash(txCtx, vLog.TxHash)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to get transaction")
-		return nil, fmt.Errorf("failed to get transaction: %v", err)
+		return nil, errs.Upstream(err, "failed to get transaction")
 	}
 
 	// Get the sender address from the transaction
@@ -1138,7 +811,7 @@ func (s *IntentService) GetIntent(ctx context.Context, id string) (*models.Inten
 
 			// Here you would typically query the blockchain or other sources
 			// For now, we're just improving error logging
-			return nil, fmt.Errorf("intent not found: %s (not in database)", id)
+			return nil, errs.NotFound("intent not found: %s (not in database)", id)
 		}
 
 		// Log detailed error for debugging
@@ -1147,7 +820,7 @@ func (s *IntentService) GetIntent(ctx context.Context, id string) (*models.Inten
 			Err(err).
 			Msg("Failed to get intent from database")
 
-		return nil, fmt.Errorf("error retrieving intent: %v", err)
+		return nil, fmt.Errorf("error retrieving intent: %w", err)
 	}
 
 	// Log success
@@ -1197,35 +870,35 @@ func (s *IntentService) CreateIntent(
 ) (*models.Intent, error) {
 	// Validate chain IDs
 	if err := utils.ValidateChain(sourceChain); err != nil {
-		return nil, fmt.Errorf("invalid source chain: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid source chain")
 	}
 	if err := utils.ValidateChain(destinationChain); err != nil {
-		return nil, fmt.Errorf("invalid destination chain: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid destination chain")
 	}
 
 	// Validate token address
 	if err := utils.ValidateAddress(token); err != nil {
-		return nil, fmt.Errorf("invalid token address: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid token address")
 	}
 
 	// Validate amount
-	if err := utils.ValidateAmount(amount); err != nil {
-		return nil, fmt.Errorf("invalid amount: %v", err)
+	if err := s.validateAmount(ctx, sourceChain, token, amount); err != nil {
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid amount")
 	}
 
 	// Validate recipient address
 	if err := utils.ValidateAddress(recipient); err != nil {
-		return nil, fmt.Errorf("invalid recipient address: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid recipient address")
 	}
 
 	// Validate sender address
 	if err := utils.ValidateAddress(sender); err != nil {
-		return nil, fmt.Errorf("invalid sender address: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid sender address")
 	}
 
 	// Validate intent fee
-	if err := utils.ValidateAmount(intentFee); err != nil {
-		return nil, fmt.Errorf("invalid intent fee: %v", err)
+	if err := s.validateAmount(ctx, sourceChain, token, intentFee); err != nil {
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid intent fee")
 	}
 
 	// For API-created intents, we use the current time
@@ -1251,13 +924,30 @@ func (s *IntentService) CreateIntent(
 		UpdatedAt:        now,
 	}
 
-	if err := s.db.CreateIntent(ctx, intent); err != nil {
+	s.valueIntent(ctx, intent)
+
+	err := s.db.WithTx(ctx, func(tx db.Database) error {
+		return s.createIntent(ctx, tx, intent)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	notifyEvents(s.events)
+
 	return intent, nil
 }
 
+// validateAmount checks an amount of a token against the limit of the token,
+// the default limit applies to the tokens that can't be looked up
+func (s *IntentService) validateAmount(ctx context.Context, chainID uint64, token, amount string) error {
+	if maxAmount := s.tokens.MaxAmount(ctx, chainID, token); maxAmount != nil {
+		return utils.ValidateAmountLimit(amount, maxAmount)
+	}
+
+	return utils.ValidateAmount(amount)
+}
+
 // CreateCallIntent creates a new intent with call data
 func (s *IntentService) CreateCallIntent(
 	ctx context.Context,
@@ -1270,35 +960,35 @@ func (s *IntentService) CreateCallIntent(
 ) (*models.Intent, error) {
 	// Validate chain IDs
 	if err := utils.ValidateChain(sourceChain); err != nil {
-		return nil, fmt.Errorf("invalid source chain: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid source chain")
 	}
 	if err := utils.ValidateChain(destinationChain); err != nil {
-		return nil, fmt.Errorf("invalid destination chain: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid destination chain")
 	}
 
 	// Validate token address
 	if err := utils.ValidateAddress(token); err != nil {
-		return nil, fmt.Errorf("invalid token address: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid token address")
 	}
 
 	// Validate amount
-	if err := utils.ValidateAmount(amount); err != nil {
-		return nil, fmt.Errorf("invalid amount: %v", err)
+	if err := s.validateAmount(ctx, sourceChain, token, amount); err != nil {
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid amount")
 	}
 
 	// Validate recipient address
 	if err := utils.ValidateAddress(recipient); err != nil {
-		return nil, fmt.Errorf("invalid recipient address: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid recipient address")
 	}
 
 	// Validate sender address
 	if err := utils.ValidateAddress(sender); err != nil {
-		return nil, fmt.Errorf("invalid sender address: %v", err)
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid sender address")
 	}
 
 	// Validate intent fee
-	if err := utils.ValidateAmount(intentFee); err != nil {
-		return nil, fmt.Errorf("invalid intent fee: %v", err)
+	if err := s.validateAmount(ctx, sourceChain, token, intentFee); err != nil {
+		return nil, errs.Wrap(errs.KindValidation, err, "invalid intent fee")
 	}
 
 	// For API-created intents, we use the current time
@@ -1326,126 +1016,96 @@ func (s *IntentService) CreateCallIntent(
 		CallData:         callData,
 	}
 
-	if err := s.db.CreateIntent(ctx, intent); err != nil {
+	s.valueIntent(ctx, intent)
+
+	err := s.db.WithTx(ctx, func(tx db.Database) error {
+		return s.createIntent(ctx, tx, intent)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	notifyEvents(s.events)
+
 	return intent, nil
 }
 
-// UnsubscribeAll unsubscribes from all active subscriptions
-func (s *IntentService) UnsubscribeAll() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.logger.Debug().
-		Int("active_subscriptions", len(s.subs)).
-		Msg("Unsubscribing from all intent subscriptions")
-
-	for id, sub := range s.subs {
-		sub.Unsubscribe()
-		s.logger.Debug().
-			Str("subscription_id", id).
-			Msg("Unsubscribed from intent subscription")
-		delete(s.subs, id)
+// valueIntent sets the USD value of the amount and fee of an intent at its creation time.
+// The values are left empty if the token or its price is unknown, the intent is stored anyway.
+func (s *IntentService) valueIntent(ctx context.Context, intent *models.Intent) {
+	if s.prices == nil {
+		return
 	}
-}
 
-// drainErrorChannel drains the error channel to prevent goroutine leaks during shutdown
-func (s *IntentService) drainErrorChannel() {
-	// Drain the error channel with a shorter timeout to prevent blocking
-	drainCtx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, DefaultRPCTimeout)
 	defer cancel()
 
-	for {
-		select {
-		case <-s.errChannel:
-			// Consume and discard errors during shutdown
-		case <-drainCtx.Done():
-			return
+	token := s.tokens.find(ctx, intent.SourceChain, intent.Token)
+	if token == nil {
+		return
+	}
+
+	price, err := s.prices.PriceUSD(ctx, token, intent.CreatedAt)
+	if err != nil {
+		if !errors.Is(err, ErrPriceNotFound) {
+			s.logger.Warn().Err(err).Str(logging.FieldIntent, intent.ID).Str("token", token.Symbol).Msg("Failed to get token price")
 		}
+		return
 	}
+
+	// amounts are validated when the intent is created through the API, event amounts are integers
+	intent.AmountUSD, _ = valueUSD(intent.Amount, token.Decimals, price)
+	intent.IntentFeeUSD, _ = valueUSD(intent.IntentFee, token.Decimals, price)
 }
 
-// Shutdown gracefully shuts down the service and waits for all goroutines to complete
-func (s *IntentService) Shutdown(timeout time.Duration) error {
-	s.shutdownMu.Lock()
-	if s.isShutdown {
-		s.shutdownMu.Unlock()
-		return nil // Already shutdown
+// BackfillUSDValues sets the USD value of the intents of the chain indexed without one, e.g. before prices
+// were configured, at the price of their token at their creation time. Intents whose token or price is
+// still unknown are left without value. Returns the number of intents scanned and valued.
+func (s *IntentService) BackfillUSDValues(ctx context.Context, batchSize int) (int, int, error) {
+	if s.prices == nil {
+		return 0, 0, errors.New("no price source configured")
+	}
+	if batchSize < 1 {
+		batchSize = DefaultUSDBackfillBatchSize
 	}
-	s.isShutdown = true
-	s.shutdownMu.Unlock()
-
-	s.logger.Info().Msg("Shutting down IntentService...")
 
-	// Cancel the cleanup context to signal all goroutines to stop
-	s.cleanupCancel()
+	var (
+		afterID         string
+		scanned, valued int
+	)
+	for {
+		intents, err := s.db.ListUnvaluedIntents(ctx, s.chainID, afterID, batchSize)
+		if err != nil {
+			return scanned, valued, err
+		}
 
-	// Unsubscribe from all subscriptions
-	s.UnsubscribeAll()
+		for _, intent := range intents {
+			s.valueIntent(ctx, intent)
+			if intent.AmountUSD == "" {
+				continue
+			}
 
-	// Drain the error channel to prevent goroutine leaks (run in current goroutine)
-	s.drainErrorChannel()
+			if err := s.db.UpdateIntentUSDValue(ctx, intent.ID, intent.AmountUSD, intent.IntentFeeUSD); err != nil {
+				return scanned, valued, err
+			}
+			valued++
+		}
+		scanned += len(intents)
 
-	// Wait for all goroutines to complete with timeout
-	done := make(chan struct{})
-	go func() {
-		s.goroutineWg.Wait()
-		close(done)
-	}()
+		if len(intents) < batchSize {
+			return scanned, valued, nil
+		}
+		afterID = intents[len(intents)-1].ID
 
-	select {
-	case <-done:
-		s.logger.Info().Msg("IntentService shutdown completed successfully")
-		return nil
-	case <-time.After(timeout):
-		s.logger.Error().
-			Dur("timeout", timeout).
-			Msg("IntentService shutdown timed out")
-		return fmt.Errorf("shutdown timed out after %v", timeout)
+		s.logger.Info().Int("scanned", scanned).Int("valued", valued).Msg("Backfilling USD values")
 	}
 }
 
-// IsShutdown returns whether the service is in shutdown state
-func (s *IntentService) IsShutdown() bool {
-	s.shutdownMu.RLock()
-	defer s.shutdownMu.RUnlock()
-	return s.isShutdown
-}
-
-// startGoroutine safely starts a goroutine with proper cleanup tracking
-func (s *IntentService) startGoroutine(name string, fn func()) {
-	s.shutdownMu.RLock()
-	if s.isShutdown {
-		s.shutdownMu.RUnlock()
-		s.logger.Debug().
-			Str("goroutine_name", name).
-			Msg("Cannot start goroutine: service is shutdown")
-		return
+// createIntent stores an intent with the outbox message of its creation in a transaction
+func (s *IntentService) createIntent(ctx context.Context, tx db.Database, intent *models.Intent) error {
+	if err := tx.CreateIntent(ctx, intent); err != nil {
+		return err
 	}
-	s.shutdownMu.RUnlock()
-
-	s.goroutineWg.Add(1)
-	atomic.AddInt32(&s.activeGoroutines, 1)
-
-	go func() {
-		defer func() {
-			s.goroutineWg.Done()
-			atomic.AddInt32(&s.activeGoroutines, -1)
-
-			// Recover from panics
-			if r := recover(); r != nil {
-				err := fmt.Errorf("panic in goroutine %s: %v\nstack: %s", name, r, debug.Stack())
-				s.errChannel <- err
-				s.logger.Error().
-					Str("goroutine_name", name).
-					Any("panic", r).
-					Str("stack", string(debug.Stack())).
-					Msg("CRITICAL: Panic in goroutine")
-			}
-		}()
 
-		fn()
-	}()
+	return enqueueIntentEvent(ctx, tx, models.OutboxTopicIntentInitiated, intent.StatusTransition())
 }
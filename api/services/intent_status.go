@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// IntentExpiryInterval is how often pending intents are checked for expiry
+const IntentExpiryInterval = 5 * time.Minute
+
+// updateIntentStatus applies a status transition of an intent.
+// Transitions the state machine rejects (e.g. a late fulfillment of a settled intent) are logged
+// and ignored: the event itself is already persisted and must not be reprocessed.
+func updateIntentStatus(
+	ctx context.Context,
+	database db.Database,
+	transition *models.IntentStatusTransition,
+	logger zerolog.Logger,
+) error {
+	err := database.UpdateIntentStatus(ctx, transition)
+	switch {
+	case errors.Is(err, db.ErrInvalidStatusTransition):
+		logger.Warn().
+			Err(err).
+			Str(logging.FieldIntent, transition.IntentID).
+			Str("tx_hash", transition.TxHash).
+			Msg("Ignoring invalid intent status transition")
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to update intent status: %v", err)
+	default:
+		return nil
+	}
+}
+
+// IntentExpiryService moves intents that stay pending for too long to expired
+type IntentExpiryService struct {
+	db     db.Database
+	expiry time.Duration
+	logger zerolog.Logger
+}
+
+// NewIntentExpiryService creates a new IntentExpiryService instance
+func NewIntentExpiryService(database db.Database, expiry time.Duration, logger zerolog.Logger) *IntentExpiryService {
+	return &IntentExpiryService{
+		db:     database,
+		expiry: expiry,
+		logger: logger.With().Str(logging.FieldModule, "intent_expiry").Logger(),
+	}
+}
+
+// ExpireStale expires the intents pending since before the expiry timeout
+func (s *IntentExpiryService) ExpireStale(ctx context.Context) (int64, error) {
+	return s.db.ExpireIntents(ctx, time.Now().Add(-s.expiry))
+}
+
+// Run expires stale intents periodically until the context is done
+func (s *IntentExpiryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(IntentExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := s.ExpireStale(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to expire stale intents")
+		} else if expired > 0 {
+			s.logger.Info().Int64("expired", expired).Dur("expiry", s.expiry).Msg("Expired stale intents")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
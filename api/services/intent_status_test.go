@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateIntentStatus(t *testing.T) {
+	transition := &models.IntentStatusTransition{
+		IntentID: "0x1234567890123456789012345678901234567890123456789012345678901234",
+		ToStatus: models.IntentStatusFulfilled,
+		TxHash:   "0xabcd",
+	}
+
+	t.Run("ignores invalid transition", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		mockDB.On("UpdateIntentStatus", mock.Anything, transition).
+			Return(fmt.Errorf("%w: settled -> fulfilled", db.ErrInvalidStatusTransition))
+
+		require.NoError(t, updateIntentStatus(context.Background(), mockDB, transition, logging.NewTesting(t)))
+	})
+
+	t.Run("returns database error", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		mockDB.On("UpdateIntentStatus", mock.Anything, transition).Return(db.ErrNotFound)
+
+		err := updateIntentStatus(context.Background(), mockDB, transition, logging.NewTesting(t))
+		require.ErrorContains(t, err, "failed to update intent status")
+	})
+}
+
+func TestIntentStatusTransitions(t *testing.T) {
+	for _, tt := range []struct {
+		from, to models.IntentStatus
+		allowed  bool
+	}{
+		{models.IntentStatusPending, models.IntentStatusFulfilled, true},
+		{models.IntentStatusPending, models.IntentStatusSettledWithoutFulfillment, true},
+		{models.IntentStatusFulfilled, models.IntentStatusSettled, true},
+		{models.IntentStatusExpired, models.IntentStatusFulfilled, true},
+		{models.IntentStatusFulfilled, models.IntentStatusSettledWithoutFulfillment, false},
+		{models.IntentStatusSettled, models.IntentStatusPending, false},
+		{models.IntentStatusSettled, models.IntentStatusFulfilled, false},
+		{models.IntentStatusRefunded, models.IntentStatusSettled, false},
+	} {
+		t.Run(fmt.Sprintf("%s to %s", tt.from, tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+
+	assert.True(t, models.IntentStatusSettled.IsFinal())
+	assert.True(t, models.IntentStatusSettledWithoutFulfillment.IsFinal())
+	assert.False(t, models.IntentStatusExpired.IsFinal())
+	assert.False(t, models.IntentStatus("unknown").IsFinal())
+}
+
+func TestIntentExpiryService_ExpireStale(t *testing.T) {
+	mockDB := mocks.NewDatabaseMock(t)
+	service := NewIntentExpiryService(mockDB, time.Hour, logging.NewTesting(t))
+
+	mockDB.On("ExpireIntents", mock.Anything, mock.MatchedBy(func(createdBefore time.Time) bool {
+		return time.Since(createdBefore) >= time.Hour && time.Since(createdBefore) < time.Hour+time.Minute
+	})).Return(int64(3), nil)
+
+	expired, err := service.ExpireStale(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+}
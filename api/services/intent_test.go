@@ -10,7 +10,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/speedrun-hq/speedrun/api/logging"
 	"github.com/speedrun-hq/speedrun/api/models"
 	"github.com/speedrun-hq/speedrun/api/testing/mocks"
 	"github.com/stretchr/testify/assert"
@@ -344,7 +343,11 @@ func TestIntentService_CreateCallIntent(t *testing.T) {
 	callData := "0xabcdef123456"
 	timestamp := time.Now()
 
-	// Mock database CreateIntent
+	// Mock database CreateIntent with its outbox message in a transaction
+	expectTx(mockDB)
+	mockDB.On("EnqueueOutboxMessage", ctx, mock.MatchedBy(func(m *models.OutboxMessage) bool {
+		return m.Topic == models.OutboxTopicIntentInitiated
+	})).Return(nil).Once()
 	mockDB.On("CreateIntent", ctx, mock.MatchedBy(func(i *models.Intent) bool {
 		return i.ID == intentID &&
 			i.SourceChain == sourceChain &&
@@ -379,285 +382,3 @@ func TestIntentService_CreateCallIntent(t *testing.T) {
 	// Verify the mock was called
 	mockDB.AssertExpectations(t)
 }
-
-func TestIntentServiceGoroutineCleanup(t *testing.T) {
-	// Create a mock logger
-	logger := logging.NewTesting(t)
-
-	// Create a mock database
-	mockDB := mocks.NewDatabaseMock(t)
-
-	// Create a mock client resolver
-	mockResolver := mocks.NewClientResolverMock(t)
-
-	// Create intent service
-	intentService, err := NewIntentService(
-		nil, // client will be nil for this test
-		mockResolver,
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"targetChain","type":"uint256"},{"indexed":false,"internalType":"bytes","name":"receiver","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"tip","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"salt","type":"uint256"}],"name":"IntentInitiated","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	if err != nil {
-		t.Fatalf("Failed to create IntentService: %v", err)
-	}
-
-	// Test that service is not shutdown initially
-	if intentService.IsShutdown() {
-		t.Error("Service should not be shutdown initially")
-	}
-
-	// Test that we can start goroutines
-	initialGoroutines := intentService.ActiveGoroutines()
-
-	// Start a test goroutine
-	intentService.startGoroutine("test-goroutine", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-
-	// Wait a bit for goroutine to start
-	time.Sleep(50 * time.Millisecond)
-
-	if intentService.ActiveGoroutines() <= initialGoroutines {
-		t.Error("Goroutine count should increase after starting a goroutine")
-	}
-
-	// Test shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	err = intentService.Shutdown(2 * time.Second)
-	if err != nil {
-		t.Errorf("Shutdown failed: %v", err)
-	}
-
-	// Test that service is now shutdown
-	if !intentService.IsShutdown() {
-		t.Error("Service should be shutdown after Shutdown() call")
-	}
-
-	// Test that we cannot start new goroutines after shutdown
-	intentService.startGoroutine("post-shutdown-goroutine", func() {
-		// This should not execute
-		t.Error("Goroutine should not start after shutdown")
-	})
-
-	// Wait for shutdown to complete
-	select {
-	case <-shutdownCtx.Done():
-		t.Error("Shutdown timed out")
-	default:
-		// Shutdown completed successfully
-	}
-
-	// Verify goroutine count is back to initial state
-	if intentService.ActiveGoroutines() != 0 {
-		t.Errorf("Expected 0 active goroutines after shutdown, got %d", intentService.ActiveGoroutines())
-	}
-}
-
-func TestIntentServiceShutdownTimeout(t *testing.T) {
-	// Create a mock logger
-	logger := logging.NewTesting(t)
-
-	// Create a mock database
-	mockDB := mocks.NewDatabaseMock(t)
-
-	// Create a mock client resolver
-	mockResolver := mocks.NewClientResolverMock(t)
-
-	// Create intent service
-	intentService, err := NewIntentService(
-		nil, // client will be nil for this test
-		mockResolver,
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"targetChain","type":"uint256"},{"indexed":false,"internalType":"bytes","name":"receiver","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"tip","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"salt","type":"uint256"}],"name":"IntentInitiated","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	if err != nil {
-		t.Fatalf("Failed to create IntentService: %v", err)
-	}
-
-	// Start a long-running goroutine that will cause timeout
-	intentService.startGoroutine("long-running", func() {
-		time.Sleep(3 * time.Second) // Longer than shutdown timeout
-	})
-
-	// Wait a bit for goroutine to start
-	time.Sleep(50 * time.Millisecond)
-
-	// Test shutdown with short timeout
-	err = intentService.Shutdown(1 * time.Second)
-	if err == nil {
-		t.Error("Expected shutdown to timeout, but it succeeded")
-	}
-
-	// Verify error message contains timeout information
-	if err != nil && !contains(err.Error(), "timed out") {
-		t.Errorf("Expected timeout error, got: %v", err)
-	}
-}
-
-// Helper function to check if string contains substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
-		(len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr ||
-			func() bool {
-				for i := 1; i <= len(s)-len(substr); i++ {
-					if s[i:i+len(substr)] == substr {
-						return true
-					}
-				}
-				return false
-			}())))
-}
-
-func TestIntentService_RestartSubscriptionNoGoroutineLeak(t *testing.T) {
-	// Create logger
-	logger := logging.NewTesting(t)
-
-	// Create mock database
-	mockDB := mocks.NewDatabaseMock(t)
-
-	// Create intent service
-	intentService, err := NewIntentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"targetChain","type":"uint256"},{"indexed":false,"internalType":"bytes","name":"receiver","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"tip","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"salt","type":"uint256"}],"name":"IntentInitiated","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-
-	// Initially should have 0 goroutines
-	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-
-	// Start some test goroutines to simulate normal operation
-	intentService.startGoroutine("test-1", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-	intentService.startGoroutine("test-2", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-
-	// Wait a bit for goroutines to start
-	time.Sleep(10 * time.Millisecond)
-
-	// Should have 2 goroutines
-	assert.Equal(t, int32(2), intentService.ActiveGoroutines())
-
-	// Call RestartSubscription multiple times - this should NOT create new goroutines
-	contractAddress := common.HexToAddress("0x1234567890123456789012345678901234567890")
-
-	for i := 0; i < 5; i++ {
-		err := intentService.RestartSubscription(context.Background(), contractAddress)
-		assert.NoError(t, err)
-
-		// Wait a bit
-		time.Sleep(10 * time.Millisecond)
-
-		// Should still have only 2 goroutines (no new ones created)
-		assert.Equal(t, int32(2), intentService.ActiveGoroutines(),
-			"RestartSubscription should not create new goroutines, attempt %d", i+1)
-	}
-
-	// Wait for goroutines to complete
-	time.Sleep(200 * time.Millisecond)
-
-	// Should be back to 0
-	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-
-	// Test shutdown
-	err = intentService.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-}
-
-func TestIntentService_MultipleStartListeningNoLeak(t *testing.T) {
-	// Create a mock logger
-	logger := logging.NewTesting(t)
-
-	// Create a mock database
-	mockDB := mocks.NewDatabaseMock(t)
-
-	// Create a mock client resolver
-	mockResolver := mocks.NewClientResolverMock(t)
-
-	// Create intent service
-	intentService, err := NewIntentService(
-		nil, // client will be nil for this test
-		mockResolver,
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"targetChain","type":"uint256"},{"indexed":false,"internalType":"bytes","name":"receiver","type":"bytes"},{"indexed":false,"internalType":"uint256","name":"tip","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"salt","type":"uint256"}],"name":"IntentInitiated","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	if err != nil {
-		t.Fatalf("Failed to create IntentService: %v", err)
-	}
-
-	// Initially should have 0 goroutines
-	if intentService.ActiveGoroutines() != 0 {
-		t.Errorf("Expected 0 active goroutines initially, got %d", intentService.ActiveGoroutines())
-	}
-
-	contractAddress := common.HexToAddress("0x1234567890123456789012345678901234567890")
-
-	// Test that multiple calls to StartListening don't create additional goroutines
-	// We'll test this by manually starting some goroutines first, then calling StartListening
-
-	// Start some test goroutines to simulate the service already running
-	intentService.startGoroutine("test-1", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-	intentService.startGoroutine("test-2", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-	intentService.startGoroutine("test-3", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-
-	// Wait a bit for goroutines to start
-	time.Sleep(10 * time.Millisecond)
-
-	// Should have 3 goroutines now
-	if intentService.ActiveGoroutines() != 3 {
-		t.Errorf("Expected 3 active goroutines after starting test goroutines, got %d", intentService.ActiveGoroutines())
-	}
-
-	// Now call StartListening multiple times - should be skipped since goroutines are already running
-	for i := 0; i < 5; i++ {
-		err := intentService.StartListening(context.Background(), contractAddress)
-		if err != nil {
-			t.Errorf("StartListening call %d failed: %v", i+1, err)
-		}
-
-		// Should still have exactly 3 goroutines (no new ones created)
-		if intentService.ActiveGoroutines() != 3 {
-			t.Errorf("After StartListening call %d: expected 3 goroutines, got %d",
-				i+1, intentService.ActiveGoroutines())
-		}
-	}
-
-	// Wait for test goroutines to complete
-	time.Sleep(200 * time.Millisecond)
-
-	// Should be back to 0 goroutines
-	if intentService.ActiveGoroutines() != 0 {
-		t.Errorf("Expected 0 active goroutines after test goroutines complete, got %d", intentService.ActiveGoroutines())
-	}
-
-	// Test shutdown
-	err = intentService.Shutdown(5 * time.Second)
-	if err != nil {
-		t.Errorf("Shutdown failed: %v", err)
-	}
-
-	// Should still be 0 goroutines
-	if intentService.ActiveGoroutines() != 0 {
-		t.Errorf("Expected 0 active goroutines after shutdown, got %d", intentService.ActiveGoroutines())
-	}
-}
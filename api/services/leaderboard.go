@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// LeaderboardService ranks runners based on their settled intents
+type LeaderboardService struct {
+	db     db.Database
+	logger zerolog.Logger
+}
+
+// NewLeaderboardService creates a new leaderboard service
+func NewLeaderboardService(database db.Database, logger zerolog.Logger) *LeaderboardService {
+	return &LeaderboardService{
+		db:     database,
+		logger: logger.With().Str(logging.FieldModule, "leaderboard").Logger(),
+	}
+}
+
+// GetLeaderboard returns a page of ranked runners for the given query
+func (s *LeaderboardService) GetLeaderboard(
+	ctx context.Context,
+	query models.LeaderboardQuery,
+) ([]*models.LeaderboardResponse, int, error) {
+	if query.SortBy == "" {
+		query.SortBy = models.LeaderboardSortVolume
+	}
+
+	if !query.SortBy.IsValid() {
+		return nil, 0, errs.Validation("invalid sort_by: %s", query.SortBy)
+	}
+
+	if query.Page < 1 || query.PageSize < 1 {
+		return nil, 0, errs.Validation("invalid pagination: page %d, page_size %d", query.Page, query.PageSize)
+	}
+
+	if !query.Since.IsZero() && !query.Until.IsZero() && !query.Since.Before(query.Until) {
+		return nil, 0, errs.Validation("invalid time window: since must be before until")
+	}
+
+	entries, totalCount, err := s.db.ListLeaderboardPaginated(ctx, query)
+	if err != nil {
+		s.logger.Error().Err(err).Uint64(logging.FieldChain, query.ChainID).Msg("Failed to list leaderboard")
+		return nil, 0, fmt.Errorf("failed to list leaderboard: %w", err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+
+	runners := make([]*models.LeaderboardResponse, len(entries))
+	for i, entry := range entries {
+		runners[i] = entry.ToResponse(offset+i+1, query.SortBy)
+	}
+
+	return runners, totalCount, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboardService_GetLeaderboard(t *testing.T) {
+	now := time.Now().UTC()
+
+	entries := []*models.LeaderboardEntry{
+		{
+			Address:                      "0x5432109876543210987654321098765432109876",
+			ChainID:                      8453,
+			TotalTransfers:               3,
+			TotalVolume:                  "3000",
+			AvgCompletionTimeSeconds:     12.5,
+			FastestCompletionTimeSeconds: 4,
+			LastTransferTime:             now,
+		},
+		{
+			Address:                      "0x9876543210987654321098765432109876543210",
+			ChainID:                      8453,
+			TotalTransfers:               5,
+			TotalVolume:                  "1000",
+			AvgCompletionTimeSeconds:     8,
+			FastestCompletionTimeSeconds: 2,
+			LastTransferTime:             now,
+		},
+	}
+
+	t.Run("ranks with page offset", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewLeaderboardService(mockDB, logging.NewTesting(t))
+
+		query := models.LeaderboardQuery{ChainID: 8453, Page: 2, PageSize: 2}
+		expected := query
+		expected.SortBy = models.LeaderboardSortVolume
+
+		mockDB.On("ListLeaderboardPaginated", mock.Anything, expected).Return(entries, 4, nil)
+
+		runners, total, err := service.GetLeaderboard(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		require.Len(t, runners, 2)
+		assert.Equal(t, 3, runners[0].Rank)
+		assert.Equal(t, 4, runners[1].Rank)
+		assert.Equal(t, "3000", runners[0].Score)
+		assert.Equal(t, "12.50", runners[0].AverageTime)
+	})
+
+	t.Run("score follows sort", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewLeaderboardService(mockDB, logging.NewTesting(t))
+
+		query := models.LeaderboardQuery{
+			ChainID:  8453,
+			SortBy:   models.LeaderboardSortTransfers,
+			Page:     1,
+			PageSize: 20,
+		}
+
+		mockDB.On("ListLeaderboardPaginated", mock.Anything, query).Return(entries, 2, nil)
+
+		runners, _, err := service.GetLeaderboard(context.Background(), query)
+		require.NoError(t, err)
+		assert.Equal(t, "3", runners[0].Score)
+		assert.Equal(t, "5", runners[1].Score)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		service := NewLeaderboardService(mocks.NewDatabaseMock(t), logging.NewTesting(t))
+
+		_, _, err := service.GetLeaderboard(context.Background(), models.LeaderboardQuery{
+			ChainID:  8453,
+			SortBy:   "bogus",
+			Page:     1,
+			PageSize: 20,
+		})
+		assert.ErrorContains(t, err, "invalid sort_by")
+
+		_, _, err = service.GetLeaderboard(context.Background(), models.LeaderboardQuery{
+			ChainID:  8453,
+			Since:    now,
+			Until:    now.Add(-time.Hour),
+			Page:     1,
+			PageSize: 20,
+		})
+		assert.ErrorContains(t, err, "invalid time window")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewLeaderboardService(mockDB, logging.NewTesting(t))
+
+		mockDB.On("ListLeaderboardPaginated", mock.Anything, mock.Anything).Return(nil, 0, errors.New("boom"))
+
+		_, _, err := service.GetLeaderboard(context.Background(), models.LeaderboardQuery{
+			ChainID:  8453,
+			Page:     1,
+			PageSize: 20,
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+)
+
+const (
+	// DefaultLogRange is the initial block range of the FilterLogs requests of a service without configured range
+	DefaultLogRange = uint64(5000)
+
+	// DefaultMinLogRange and DefaultMaxLogRange bound the adaptive block range
+	DefaultMinLogRange = uint64(10)
+	DefaultMaxLogRange = uint64(10000)
+
+	// sparseLogCount is the number of logs under which a full range is considered sparse and the range grows
+	sparseLogCount = 100
+)
+
+// LogRange sizes the block ranges of the FilterLogs requests of a chain at runtime.
+// The range halves when the provider rejects a query as too large and grows by half
+// while full ranges come back sparse.
+type LogRange struct {
+	mu       sync.Mutex
+	size     uint64
+	min, max uint64
+}
+
+// NewLogRange creates an adaptive range starting at start blocks, bounded by lower and upper
+func NewLogRange(start, lower, upper uint64) *LogRange {
+	lower = max(lower, 1)
+	upper = max(upper, lower)
+
+	return &LogRange{
+		size: clamp(start, lower, upper),
+		min:  lower,
+		max:  upper,
+	}
+}
+
+// Size returns the current block range
+func (r *LogRange) Size() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.size
+}
+
+// Shrink halves the range after the provider rejected a query, returns false if it's already at the minimum
+func (r *LogRange) Shrink() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size <= r.min {
+		return false
+	}
+
+	r.size = clamp(r.size/2, r.min, r.max)
+
+	return true
+}
+
+// Observe grows the range when a query of the full range returned few logs
+func (r *LogRange) Observe(blocks uint64, logs int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if blocks < r.size || logs >= sparseLogCount {
+		return
+	}
+
+	r.size = clamp(r.size+r.size/2+1, r.min, r.max)
+}
+
+// filterLogsInRange fetches the logs of the blocks after from up to to, at most the adaptive range at once.
+// The range shrinks while the provider rejects the query as too large.
+// Returns the logs and the last block of the range actually fetched.
+func filterLogsInRange(
+	ctx context.Context,
+	client evm.Client,
+	logRange *LogRange,
+	query ethereum.FilterQuery,
+	from, to uint64,
+) ([]types.Log, uint64, error) {
+	for {
+		end := min(from+logRange.Size(), to)
+		query.FromBlock = big.NewInt(int64(from + 1))
+		query.ToBlock = big.NewInt(int64(end))
+
+		filterCtx, cancel := context.WithTimeout(ctx, FilterLogsTimeout)
+		logs, err := client.FilterLogs(filterCtx, query)
+		cancel()
+
+		switch {
+		case err == nil:
+			logRange.Observe(end-from, len(logs))
+			return logs, end, nil
+		case evm.IsRangeTooLarge(err) && logRange.Shrink():
+			continue
+		default:
+			return nil, end, err
+		}
+	}
+}
+
+func clamp(value, lower, upper uint64) uint64 {
+	return min(max(value, lower), upper)
+}
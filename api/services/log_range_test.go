@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRange(t *testing.T) {
+	t.Run("start is clamped to the bounds", func(t *testing.T) {
+		assert.Equal(t, uint64(100), NewLogRange(5000, 10, 100).Size())
+		assert.Equal(t, uint64(10), NewLogRange(1, 10, 100).Size())
+		assert.Equal(t, uint64(1), NewLogRange(0, 0, 0).Size())
+	})
+
+	t.Run("shrinks down to the minimum", func(t *testing.T) {
+		r := NewLogRange(100, 30, 1000)
+
+		require.True(t, r.Shrink())
+		assert.Equal(t, uint64(50), r.Size())
+		require.True(t, r.Shrink())
+		assert.Equal(t, uint64(30), r.Size())
+		require.False(t, r.Shrink())
+	})
+
+	t.Run("grows on sparse full ranges only", func(t *testing.T) {
+		r := NewLogRange(100, 10, 200)
+
+		r.Observe(50, 0) // partial range at the head of the chain
+		assert.Equal(t, uint64(100), r.Size())
+
+		r.Observe(100, sparseLogCount) // dense range
+		assert.Equal(t, uint64(100), r.Size())
+
+		r.Observe(100, 3)
+		assert.Equal(t, uint64(151), r.Size())
+
+		r.Observe(151, 0)
+		assert.Equal(t, uint64(200), r.Size())
+	})
+}
+
+func TestFilterLogsInRange(t *testing.T) {
+	t.Run("shrinks the range until the provider accepts the query", func(t *testing.T) {
+		client := &rangeLimitedClient{maxRange: 300}
+		logRange := NewLogRange(1000, 10, 1000)
+
+		logs, end, err := filterLogsInRange(context.Background(), client, logRange, ethereum.FilterQuery{}, 100, 2000)
+		require.NoError(t, err)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, uint64(350), end)
+		assert.Equal(t, []uint64{1000, 500, 250}, client.ranges)
+
+		// the accepted range was sparse, the next one is larger again
+		assert.Equal(t, uint64(376), logRange.Size())
+	})
+
+	t.Run("returns the error once the minimum is reached", func(t *testing.T) {
+		client := &rangeLimitedClient{maxRange: 5}
+		logRange := NewLogRange(40, 10, 1000)
+
+		_, _, err := filterLogsInRange(context.Background(), client, logRange, ethereum.FilterQuery{}, 0, 100)
+		require.Error(t, err)
+		assert.True(t, evm.IsRangeTooLarge(err))
+		assert.Equal(t, []uint64{40, 20, 10}, client.ranges)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		client := &rangeLimitedClient{err: errors.New("connection refused")}
+		logRange := NewLogRange(40, 10, 1000)
+
+		_, _, err := filterLogsInRange(context.Background(), client, logRange, ethereum.FilterQuery{}, 0, 100)
+		require.Error(t, err)
+		assert.Len(t, client.ranges, 1)
+		assert.Equal(t, uint64(40), logRange.Size())
+	})
+}
+
+// rangeLimitedClient rejects FilterLogs queries over maxRange blocks like hosted providers do
+type rangeLimitedClient struct {
+	evm.Client
+
+	maxRange uint64
+	err      error
+	ranges   []uint64
+}
+
+func (c *rangeLimitedClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	blocks := q.ToBlock.Uint64() - q.FromBlock.Uint64() + 1
+	c.ranges = append(c.ranges, blocks)
+
+	switch {
+	case c.err != nil:
+		return nil, c.err
+	case blocks > c.maxRange:
+		return nil, errors.New("query returned more than 10000 results")
+	default:
+		return []types.Log{{BlockNumber: q.FromBlock.Uint64()}}, nil
+	}
+}
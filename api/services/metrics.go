@@ -30,9 +30,8 @@ type MetricsService struct {
 
 	// Service references
 	intentServices      map[uint64]*IntentService
-	fulfillmentServices map[uint64]*FulfillmentService
-	settlementServices  map[uint64]*SettlementService
 	eventCatchupService *EventCatchupService
+	chainNames          map[uint64]string
 	mu                  sync.RWMutex
 	logger              zerolog.Logger
 	registry            *prometheus.Registry
@@ -157,8 +156,6 @@ func NewMetricsService(logger zerolog.Logger) *MetricsService {
 		timeSinceLastEvent:       timeSinceLastEvent,
 		lastHealthCheckTimestamp: lastHealthCheckTimestamp,
 		intentServices:           make(map[uint64]*IntentService),
-		fulfillmentServices:      make(map[uint64]*FulfillmentService),
-		settlementServices:       make(map[uint64]*SettlementService),
 		logger:                   logger,
 		registry:                 registry,
 	}
@@ -173,24 +170,6 @@ func (m *MetricsService) RegisterIntentService(chainID uint64, service *IntentSe
 	m.logger.Info().Uint64(logging.FieldChain, chainID).Msg("Registered intent service for metrics collector")
 }
 
-// RegisterFulfillmentService registers a fulfillment service for metrics collection
-func (m *MetricsService) RegisterFulfillmentService(chainID uint64, service *FulfillmentService) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.fulfillmentServices[chainID] = service
-	m.logger.Info().Uint64(logging.FieldChain, chainID).Msg("Registered fulfillment service for metrics collector")
-}
-
-// RegisterSettlementService registers a settlement service for metrics collection
-func (m *MetricsService) RegisterSettlementService(chainID uint64, service *SettlementService) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.settlementServices[chainID] = service
-	m.logger.Info().Uint64(logging.FieldChain, chainID).Msg("Registered settlement service for metrics collector")
-}
-
 // RegisterEventCatchupService registers the event catchup service for metrics collection
 func (m *MetricsService) RegisterEventCatchupService(service *EventCatchupService) {
 	m.mu.Lock()
@@ -200,6 +179,11 @@ func (m *MetricsService) RegisterEventCatchupService(service *EventCatchupServic
 	m.logger.Info().Msg("Registered event catchup service in metrics collector")
 }
 
+// RegisterCollector exports the metrics of a collector, e.g. the RPC provider metrics
+func (m *MetricsService) RegisterCollector(collector prometheus.Collector) error {
+	return m.registry.Register(collector)
+}
+
 // UnregisterIntentService removes an intent service from metrics collection
 func (m *MetricsService) UnregisterIntentService(chainID uint64) {
 	m.mu.Lock()
@@ -209,26 +193,20 @@ func (m *MetricsService) UnregisterIntentService(chainID uint64) {
 	m.logger.Info().Uint64(logging.FieldChain, chainID).Msg("Unregistered intent service from metrics collector")
 }
 
+// SetChainNames sets the chain names of the metrics labels from the chain registry, before metrics are collected
+func (m *MetricsService) SetChainNames(names map[uint64]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.chainNames = names
+}
+
 // GetChainName returns a human-readable chain name for metrics labels
 func (m *MetricsService) GetChainName(chainID uint64) string {
-	switch chainID {
-	case 1:
-		return "ethereum"
-	case 42161:
-		return "arbitrum"
-	case 8453:
-		return "base"
-	case 137:
-		return "polygon"
-	case 56:
-		return "bsc"
-	case 43114:
-		return "avalanche"
-	case 7000:
-		return "zetachain"
-	default:
-		return fmt.Sprintf("chain_%d", chainID)
+	if name, ok := m.chainNames[chainID]; ok {
+		return name
 	}
+	return fmt.Sprintf("chain_%d", chainID)
 }
 
 // UpdateMetrics collects and updates all metrics from registered intent services
@@ -241,42 +219,16 @@ func (m *MetricsService) UpdateMetrics() {
 	// Create a map to track total goroutines per chain
 	chainGoroutines := make(map[uint64]int32)
 
-	// Collect goroutines from intent services
+	// Collect goroutines from the ingestion engines, reported by the intent services of their chain
 	for chainID, service := range m.intentServices {
 		if service != nil {
 			goroutines := service.ActiveGoroutines()
 			chainGoroutines[chainID] += goroutines
 
-			// Emit intent service goroutines metric
-			chainName := m.GetChainName(chainID)
-			chainIDStr := fmt.Sprintf("%d", chainID)
-			m.serviceGoroutines.WithLabelValues(chainIDStr, chainName, "intent").Set(float64(goroutines))
-		}
-	}
-
-	// Collect goroutines from fulfillment services
-	for chainID, service := range m.fulfillmentServices {
-		if service != nil {
-			goroutines := service.ActiveGoroutines()
-			chainGoroutines[chainID] += goroutines
-
-			// Emit fulfillment service goroutines metric
+			// Emit ingestion goroutines metric
 			chainName := m.GetChainName(chainID)
 			chainIDStr := fmt.Sprintf("%d", chainID)
-			m.serviceGoroutines.WithLabelValues(chainIDStr, chainName, "fulfillment").Set(float64(goroutines))
-		}
-	}
-
-	// Collect goroutines from settlement services
-	for chainID, service := range m.settlementServices {
-		if service != nil {
-			goroutines := service.ActiveGoroutines()
-			chainGoroutines[chainID] += goroutines
-
-			// Emit settlement service goroutines metric
-			chainName := m.GetChainName(chainID)
-			chainIDStr := fmt.Sprintf("%d", chainID)
-			m.serviceGoroutines.WithLabelValues(chainIDStr, chainName, "settlement").Set(float64(goroutines))
+			m.serviceGoroutines.WithLabelValues(chainIDStr, chainName, "ingestion").Set(float64(goroutines))
 		}
 	}
 
@@ -311,10 +263,9 @@ func (m *MetricsService) UpdateMetrics() {
 				m.intentServicesUp.WithLabelValues(chainIDStr, chainName).Set(0)
 			}
 
-			// For ZetaChain, subscription count is always 0 since it uses HTTP polling
+			// Polled chains have no subscriptions, report 1 if polling is healthy, 0 if not
 			// For other chains, report actual subscription count
-			if metrics.IsZetaChain {
-				// ZetaChain uses polling, so report 1 if polling is healthy, 0 if not
+			if metrics.IsPolling {
 				if metrics.PollingHealthy {
 					m.subscriptionCount.WithLabelValues(chainIDStr, chainName).Set(1)
 				} else {
@@ -384,27 +335,13 @@ func (m *MetricsService) GetMetricsSummary() map[string]interface{} {
 	// Create a map to track total goroutines per chain
 	chainGoroutines := make(map[uint64]int32)
 
-	// Collect goroutines from intent services
+	// Collect goroutines from the ingestion engines, reported by the intent services of their chain
 	for chainID, service := range m.intentServices {
 		if service != nil {
 			chainGoroutines[chainID] += service.ActiveGoroutines()
 		}
 	}
 
-	// Collect goroutines from fulfillment services
-	for chainID, service := range m.fulfillmentServices {
-		if service != nil {
-			chainGoroutines[chainID] += service.ActiveGoroutines()
-		}
-	}
-
-	// Collect goroutines from settlement services
-	for chainID, service := range m.settlementServices {
-		if service != nil {
-			chainGoroutines[chainID] += service.ActiveGoroutines()
-		}
-	}
-
 	// Add EventCatchupService goroutines to all chains (it's a global service)
 	if m.eventCatchupService != nil {
 		catchupGoroutines := m.eventCatchupService.ActiveGoroutines()
@@ -427,24 +364,12 @@ func (m *MetricsService) GetMetricsSummary() map[string]interface{} {
 		chainMetrics[chainName] = map[string]interface{}{
 			"chain_id":          chainID,
 			"active_goroutines": totalGoroutines, // Total from all services
-			"intent_goroutines": func() int32 {
+			"ingestion_goroutines": func() int32 {
 				if intentMetrics != nil {
 					return intentMetrics.ActiveGoroutines
 				}
 				return 0
 			}(),
-			"fulfillment_goroutines": func() int32 {
-				if service, exists := m.fulfillmentServices[chainID]; exists && service != nil {
-					return service.ActiveGoroutines()
-				}
-				return 0
-			}(),
-			"settlement_goroutines": func() int32 {
-				if service, exists := m.settlementServices[chainID]; exists && service != nil {
-					return service.ActiveGoroutines()
-				}
-				return 0
-			}(),
 		}
 
 		// Add intent service specific metrics if available
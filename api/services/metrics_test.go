@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/speedrun-hq/speedrun/api/logging"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,6 +15,7 @@ func TestMetricsService_GoroutineTracking(t *testing.T) {
 
 	// Create metrics service
 	metricsService := NewMetricsService(logger)
+	metricsService.SetChainNames(map[uint64]string{1: "ethereum"})
 
 	// Create mock services
 	mockDB := &mockDB{}
@@ -29,58 +31,28 @@ func TestMetricsService_GoroutineTracking(t *testing.T) {
 	)
 	assert.NoError(t, err)
 
-	// Create fulfillment service
-	fulfillmentService, err := NewFulfillmentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"}],"name":"IntentFulfilled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-
-	// Create settlement service
-	settlementService, err := NewSettlementService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"},{"indexed":false,"internalType":"bool","name":"fulfilled","type":"bool"}],"name":"IntentSettled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
+	// Create the ingestion engine of the chain
+	engine := NewIngestionEngine(nil, 1, common.Address{}, logger)
+	intentService.SetIngestionEngine(engine)
 
 	// Register all services
 	metricsService.RegisterIntentService(1, intentService)
-	metricsService.RegisterFulfillmentService(1, fulfillmentService)
-	metricsService.RegisterSettlementService(1, settlementService)
 
-	// Initially, all services should have 0 goroutines
+	// Initially, the engine should have 0 goroutines
 	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), settlementService.ActiveGoroutines())
-
-	// Start some test goroutines in each service
-	intentService.startGoroutine("test-intent", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-
-	fulfillmentService.startGoroutine("test-fulfillment", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
 
-	settlementService.startGoroutine("test-settlement", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
+	// Start some test goroutines in the engine
+	for i := 0; i < 3; i++ {
+		engine.startGoroutine("test-ingestion", func() {
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
 
 	// Wait a bit for goroutines to start
 	time.Sleep(50 * time.Millisecond)
 
-	// Check that each service has 1 goroutine
-	assert.Equal(t, int32(1), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(1), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(1), settlementService.ActiveGoroutines())
+	// The intent service reports the goroutines of the engine
+	assert.Equal(t, int32(3), intentService.ActiveGoroutines())
 
 	// Update metrics
 	metricsService.UpdateMetrics()
@@ -91,26 +63,14 @@ func TestMetricsService_GoroutineTracking(t *testing.T) {
 	ethereum := chains["ethereum"].(map[string]interface{})
 	activeGoroutines := ethereum["active_goroutines"].(int32)
 
-	// Total should be 3 (1 from each service)
 	assert.Equal(t, int32(3), activeGoroutines)
 
-	// Wait for goroutines to complete
-	time.Sleep(100 * time.Millisecond)
-
-	// Shutdown all services
-	err = intentService.Shutdown(5 * time.Second)
+	// Shutdown the engine
+	err = engine.Shutdown(5 * time.Second)
 	assert.NoError(t, err)
 
-	err = fulfillmentService.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-
-	err = settlementService.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-
-	// All services should have 0 goroutines after shutdown
+	// The engine should have 0 goroutines after shutdown
 	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), settlementService.ActiveGoroutines())
 }
 
 func TestMetricsService_ServiceSpecificGoroutineMetrics(t *testing.T) {
@@ -119,6 +79,7 @@ func TestMetricsService_ServiceSpecificGoroutineMetrics(t *testing.T) {
 
 	// Create metrics service
 	metricsService := NewMetricsService(logger)
+	metricsService.SetChainNames(map[uint64]string{1: "ethereum"})
 
 	// Create mock database
 	mockDB := &mockDB{}
@@ -134,59 +95,29 @@ func TestMetricsService_ServiceSpecificGoroutineMetrics(t *testing.T) {
 	)
 	assert.NoError(t, err)
 
-	// Create fulfillment service
-	fulfillmentService, err := NewFulfillmentService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"}],"name":"IntentFulfilled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
-
-	// Create settlement service
-	settlementService, err := NewSettlementService(
-		nil, // client will be nil for this test
-		nil, // clientResolver will be nil for this test
-		mockDB,
-		`[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"intentId","type":"bytes32"},{"indexed":true,"internalType":"address","name":"asset","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount","type":"uint256"},{"indexed":false,"internalType":"address","name":"receiver","type":"address"},{"indexed":false,"internalType":"bool","name":"fulfilled","type":"bool"}],"name":"IntentSettled","type":"event"}]`,
-		1, // chainID
-		logger,
-	)
-	assert.NoError(t, err)
+	// Create the ingestion engine of the chain
+	engine := NewIngestionEngine(nil, 1, common.Address{}, logger)
+	intentService.SetIngestionEngine(engine)
 
 	// Create event catchup service
 	eventCatchupService := NewEventCatchupService(
 		map[uint64]*IntentService{1: intentService},
-		map[uint64]*FulfillmentService{1: fulfillmentService},
-		map[uint64]*SettlementService{1: settlementService},
+		map[uint64]*FulfillmentService{},
+		map[uint64]*SettlementService{},
 		mockDB,
 		logger,
 	)
 
 	// Register all services
 	metricsService.RegisterIntentService(1, intentService)
-	metricsService.RegisterFulfillmentService(1, fulfillmentService)
-	metricsService.RegisterSettlementService(1, settlementService)
 	metricsService.RegisterEventCatchupService(eventCatchupService)
 
 	// Initially, all services should have 0 goroutines
 	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), settlementService.ActiveGoroutines())
 	assert.Equal(t, int32(0), eventCatchupService.ActiveGoroutines())
 
 	// Start some test goroutines in each service
-	intentService.startGoroutine("test-intent", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-
-	fulfillmentService.startGoroutine("test-fulfillment", func() {
-		time.Sleep(100 * time.Millisecond)
-	})
-
-	settlementService.startGoroutine("test-settlement", func() {
+	engine.startGoroutine("test-ingestion", func() {
 		time.Sleep(100 * time.Millisecond)
 	})
 
@@ -199,8 +130,6 @@ func TestMetricsService_ServiceSpecificGoroutineMetrics(t *testing.T) {
 
 	// Check that each service has 1 goroutine
 	assert.Equal(t, int32(1), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(1), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(1), settlementService.ActiveGoroutines())
 	assert.Equal(t, int32(1), eventCatchupService.ActiveGoroutines())
 
 	// Update metrics
@@ -212,33 +141,23 @@ func TestMetricsService_ServiceSpecificGoroutineMetrics(t *testing.T) {
 	ethereum := chains["ethereum"].(map[string]interface{})
 
 	// Check that service-specific goroutine counts are correct
-	assert.Equal(t, int32(1), ethereum["intent_goroutines"])
-	assert.Equal(t, int32(1), ethereum["fulfillment_goroutines"])
-	assert.Equal(t, int32(1), ethereum["settlement_goroutines"])
-	assert.Equal(t, int32(4), ethereum["active_goroutines"]) // Total should be 4 (1+1+1+1)
+	assert.Equal(t, int32(1), ethereum["ingestion_goroutines"])
+	assert.Equal(t, int32(2), ethereum["active_goroutines"]) // Total should be 2 (1+1)
 
 	// Wait for all goroutines to complete
 	time.Sleep(200 * time.Millisecond)
 
 	// All services should be back to 0 goroutines
 	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), settlementService.ActiveGoroutines())
 	assert.Equal(t, int32(0), eventCatchupService.ActiveGoroutines())
 
 	// Test shutdown of all services
-	err = intentService.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-	err = fulfillmentService.Shutdown(5 * time.Second)
-	assert.NoError(t, err)
-	err = settlementService.Shutdown(5 * time.Second)
+	err = engine.Shutdown(5 * time.Second)
 	assert.NoError(t, err)
 	err = eventCatchupService.Shutdown(5 * time.Second)
 	assert.NoError(t, err)
 
 	// All services should still have 0 goroutines after shutdown
 	assert.Equal(t, int32(0), intentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), fulfillmentService.ActiveGoroutines())
-	assert.Equal(t, int32(0), settlementService.ActiveGoroutines())
 	assert.Equal(t, int32(0), eventCatchupService.ActiveGoroutines())
 }
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+const (
+	// OrphanReconcileInterval is how often orphan events are checked when no intent event woke the reconciler up,
+	// e.g. for intents indexed while their orphan events were being queued
+	OrphanReconcileInterval = 30 * time.Second
+
+	// OrphanBatchSize is the maximum number of orphan events replayed at once
+	OrphanBatchSize = 100
+
+	// OrphanMaxAttempts is the number of failed replays after which an orphan event is left for manual inspection
+	OrphanMaxAttempts = 10
+)
+
+// errIntentNotIndexed is returned when a fulfillment or settlement refers to an intent that isn't indexed yet
+var errIntentNotIndexed = errors.New("intent not indexed yet")
+
+// enqueueOrphan queues a fulfillment or settlement log whose intent isn't indexed yet, to be replayed
+// by the OrphanReconciler once the intent is. The checkpoint advances past the queued log unless
+// it's re-indexed by a backfill.
+func enqueueOrphan(
+	ctx context.Context,
+	database db.Database,
+	chainID uint64,
+	eventType string,
+	vLog types.Log,
+	checkpoint bool,
+	logger zerolog.Logger,
+) error {
+	data, err := json.Marshal(vLog)
+	if err != nil {
+		return fmt.Errorf("failed to encode log: %v", err)
+	}
+
+	// fulfillment and settlement events index the intent ID first
+	orphan := &models.OrphanEvent{
+		ChainID:     chainID,
+		EventType:   eventType,
+		IntentID:    vLog.Topics[1].Hex(),
+		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
+		TxHash:      vLog.TxHash.Hex(),
+		LogIndex:    vLog.Index,
+		Log:         data,
+	}
+
+	err = database.WithTx(ctx, func(tx db.Database) error {
+		if err := tx.EnqueueOrphanEvent(ctx, orphan); err != nil {
+			return err
+		}
+		if !checkpoint {
+			return nil
+		}
+		return advanceCheckpoint(ctx, tx, chainID, eventType, vLog)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str(logging.FieldIntent, orphan.IntentID).
+		Str("event_type", eventType).
+		Uint64(logging.FieldBlock, vLog.BlockNumber).
+		Msg("Intent not indexed yet, queued event until it is")
+
+	return nil
+}
+
+// orphanHandlerKey identifies the handler replaying the orphan events of an event type on a chain
+type orphanHandlerKey struct {
+	chainID   uint64
+	eventType string
+}
+
+// OrphanReconciler replays the fulfillment and settlement events queued because their intent wasn't indexed,
+// once the intent is. It's woken up by the outbox dispatcher when intent events are persisted and polls
+// the queue otherwise. It exports the number and age of the queued events as prometheus metrics.
+type OrphanReconciler struct {
+	db     db.Database
+	wake   chan struct{}
+	logger zerolog.Logger
+
+	mu       sync.RWMutex
+	handlers map[orphanHandlerKey]LogHandler
+
+	count     *prometheus.GaugeVec
+	oldestAge *prometheus.GaugeVec
+}
+
+var _ prometheus.Collector = (*OrphanReconciler)(nil)
+
+// NewOrphanReconciler creates a new OrphanReconciler instance
+func NewOrphanReconciler(database db.Database, logger zerolog.Logger) *OrphanReconciler {
+	return &OrphanReconciler{
+		db:       database,
+		wake:     make(chan struct{}, 1),
+		logger:   logger.With().Str(logging.FieldModule, "orphans").Logger(),
+		handlers: make(map[orphanHandlerKey]LogHandler),
+		count: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "speedrun_orphan_events",
+				Help: "Number of fulfillment and settlement events waiting for their intent to be indexed",
+			},
+			[]string{"chain_id", "event_type"},
+		),
+		oldestAge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "speedrun_orphan_event_oldest_age_seconds",
+				Help: "Age in seconds of the oldest event waiting for its intent to be indexed",
+			},
+			[]string{"chain_id", "event_type"},
+		),
+	}
+}
+
+// Register sets the handler replaying the orphan events of the given type on a chain
+func (r *OrphanReconciler) Register(chainID uint64, eventType string, handler LogHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[orphanHandlerKey{chainID: chainID, eventType: eventType}] = handler
+}
+
+// Notify implements EventNotifier, it wakes the reconciler up
+func (r *OrphanReconciler) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Reconcile replays a batch of the orphan events whose intent is now indexed, in chain order.
+// Replayed events are removed from the queue, failures are recorded and retried up to OrphanMaxAttempts times.
+// Returns the number of replayed events.
+func (r *OrphanReconciler) Reconcile(ctx context.Context) (int, error) {
+	orphans, err := r.db.ListReconcilableOrphanEvents(ctx, OrphanMaxAttempts, OrphanBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var reconciled int
+	for _, orphan := range orphans {
+		logger := r.logger.With().
+			Uint64(logging.FieldChain, orphan.ChainID).
+			Str(logging.FieldIntent, orphan.IntentID).
+			Str("event_type", orphan.EventType).
+			Uint64(logging.FieldBlock, orphan.BlockNumber).
+			Logger()
+
+		if err := r.replay(ctx, orphan); err != nil {
+			logger.Warn().Err(err).Int("attempts", orphan.Attempts+1).Msg("Failed to replay orphan event")
+			if err := r.db.RecordOrphanEventFailure(ctx, orphan.ID, err.Error()); err != nil {
+				return reconciled, err
+			}
+			continue
+		}
+
+		// a crash before the deletion replays the event again, which is skipped as a duplicate
+		if err := r.db.DeleteOrphanEvent(ctx, orphan.ID); err != nil {
+			return reconciled, err
+		}
+
+		logger.Info().Dur("waited", time.Since(orphan.CreatedAt)).Msg("Replayed orphan event")
+		reconciled++
+	}
+
+	return reconciled, nil
+}
+
+func (r *OrphanReconciler) replay(ctx context.Context, orphan *models.OrphanEvent) error {
+	r.mu.RLock()
+	handler, ok := r.handlers[orphanHandlerKey{chainID: orphan.ChainID, eventType: orphan.EventType}]
+	r.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no handler registered for %s events of chain %d", orphan.EventType, orphan.ChainID)
+	}
+
+	var vLog types.Log
+	if err := json.Unmarshal(orphan.Log, &vLog); err != nil {
+		return fmt.Errorf("failed to decode orphan log: %v", err)
+	}
+
+	return handler(ctx, vLog)
+}
+
+// UpdateMetrics refreshes the number and age of the queued orphan events
+func (r *OrphanReconciler) UpdateMetrics(ctx context.Context) error {
+	stats, err := r.db.GetOrphanEventStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.count.Reset()
+	r.oldestAge.Reset()
+
+	now := time.Now()
+	for _, s := range stats {
+		chainID := strconv.FormatUint(s.ChainID, 10)
+		r.count.WithLabelValues(chainID, s.EventType).Set(float64(s.Count))
+		r.oldestAge.WithLabelValues(chainID, s.EventType).Set(now.Sub(s.OldestCreatedAt).Seconds())
+	}
+
+	return nil
+}
+
+// Describe implements prometheus.Collector
+func (r *OrphanReconciler) Describe(ch chan<- *prometheus.Desc) {
+	r.count.Describe(ch)
+	r.oldestAge.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (r *OrphanReconciler) Collect(ch chan<- prometheus.Metric) {
+	r.count.Collect(ch)
+	r.oldestAge.Collect(ch)
+}
+
+// Run replays the orphan events until the context is done
+func (r *OrphanReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(OrphanReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			reconciled, err := r.Reconcile(ctx)
+			if err != nil {
+				r.logger.Error().Err(err).Msg("Failed to reconcile orphan events")
+			}
+			if err != nil || reconciled < OrphanBatchSize {
+				break
+			}
+		}
+
+		if err := r.UpdateMetrics(ctx); err != nil {
+			r.logger.Warn().Err(err).Msg("Failed to update orphan event metrics")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
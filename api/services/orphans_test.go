@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFulfillmentService_QueuesOrphanEvents(t *testing.T) {
+	contract := common.HexToAddress("0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB")
+	intentID := common.HexToHash("0x01")
+
+	database := mocks.NewDatabaseMock(t)
+	expectTx(database)
+	notifier := &countingNotifier{}
+	service := newTestFulfillmentService(t, database)
+	service.SetEventNotifier(notifier)
+
+	vLog := types.Log{
+		Address:     contract,
+		BlockNumber: 100,
+		TxHash:      common.HexToHash("0xc1"),
+		Index:       4,
+		Topics: []common.Hash{
+			service.abi.Events[IntentFulfilledEventName].ID,
+			intentID,
+			common.HexToHash("0x02"),
+			common.HexToHash("0x03"),
+		},
+		Data: common.LeftPadBytes([]byte{1}, 32),
+	}
+
+	// the intent of another chain isn't indexed yet, the log is queued and the checkpoint moves on
+	database.On("GetIntent", mock.Anything, intentID.Hex()).Return(nil, db.ErrNotFound).Once()
+	database.On("EnqueueOrphanEvent", mock.Anything, mock.MatchedBy(func(orphan *models.OrphanEvent) bool {
+		var queued types.Log
+		return orphan.ChainID == 1 &&
+			orphan.EventType == eventTypeFulfillment &&
+			orphan.IntentID == intentID.Hex() &&
+			orphan.LogIndex == 4 &&
+			json.Unmarshal(orphan.Log, &queued) == nil &&
+			queued.TxHash == vLog.TxHash
+	})).Return(nil).Once()
+	database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(99)).
+		Return(nil).Once()
+
+	require.NoError(t, service.persistLog(context.Background(), vLog))
+	assert.Zero(t, notifier.count)
+
+	// the reconciler replays the log once the intent is indexed
+	reconciler := NewOrphanReconciler(database, logging.NewTesting(t))
+	service.SetOrphanReconciler(reconciler)
+
+	data, err := json.Marshal(vLog)
+	require.NoError(t, err)
+
+	database.On("ListReconcilableOrphanEvents", mock.Anything, OrphanMaxAttempts, OrphanBatchSize).
+		Return([]*models.OrphanEvent{
+			{ID: 7, ChainID: 1, EventType: eventTypeFulfillment, IntentID: intentID.Hex(), Log: data},
+		}, nil).Once()
+	database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex()}, nil).Once()
+	database.On("CreateFulfillment", mock.Anything, mock.MatchedBy(func(f *models.Fulfillment) bool {
+		return f.ChainID == 1 && f.TxHash == vLog.TxHash.Hex() && f.LogIndex == 4 && f.Contract == contract.Hex()
+	})).Return(nil).Once()
+	database.On("GetTotalFulfilledAmount", mock.Anything, intentID.Hex()).Return("1", nil).Once()
+	database.On("UpdateIntentStatus", mock.Anything, mock.Anything).
+		Run(fromStatus(models.IntentStatusPending)).
+		Return(nil).
+		Once()
+	database.On("EnqueueOutboxMessage", mock.Anything, mock.Anything).Return(nil).Once()
+	database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(99)).
+		Return(nil).Once()
+	database.On("DeleteOrphanEvent", mock.Anything, int64(7)).Return(nil).Once()
+
+	reconciled, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, reconciled)
+	assert.Equal(t, 1, notifier.count)
+}
+
+func TestOrphanReconciler_Reconcile(t *testing.T) {
+	database := mocks.NewDatabaseMock(t)
+	reconciler := NewOrphanReconciler(database, logging.NewTesting(t))
+
+	var replayed []uint64
+	reconciler.Register(1, eventTypeSettlement, func(_ context.Context, vLog types.Log) error {
+		replayed = append(replayed, vLog.BlockNumber)
+		return nil
+	})
+
+	database.On("ListReconcilableOrphanEvents", mock.Anything, OrphanMaxAttempts, OrphanBatchSize).
+		Return([]*models.OrphanEvent{
+			{ID: 1, ChainID: 1, EventType: eventTypeSettlement, Log: []byte(`{`)},
+			{ID: 2, ChainID: 1, EventType: eventTypeSettlement, Log: orphanLog(t, 120)},
+			{ID: 3, ChainID: 2, EventType: eventTypeSettlement, Log: orphanLog(t, 130)},
+		}, nil).Once()
+
+	// failures are recorded and the other events are still replayed
+	database.On("RecordOrphanEventFailure", mock.Anything, int64(1), mock.MatchedBy(func(reason string) bool {
+		return strings.HasPrefix(reason, "failed to decode orphan log")
+	})).Return(nil).Once()
+	database.On("DeleteOrphanEvent", mock.Anything, int64(2)).Return(nil).Once()
+	database.On("RecordOrphanEventFailure", mock.Anything, int64(3), "no handler registered for settlement events of chain 2").
+		Return(nil).Once()
+
+	reconciled, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, reconciled)
+	assert.Equal(t, []uint64{120}, replayed)
+}
+
+func TestOrphanReconciler_UpdateMetrics(t *testing.T) {
+	database := mocks.NewDatabaseMock(t)
+	reconciler := NewOrphanReconciler(database, logging.NewTesting(t))
+
+	database.On("GetOrphanEventStats", mock.Anything).Return([]*models.OrphanEventStats{
+		{ChainID: 1, EventType: eventTypeFulfillment, Count: 3, OldestCreatedAt: time.Now().Add(-time.Minute)},
+	}, nil).Once()
+
+	require.NoError(t, reconciler.UpdateMetrics(context.Background()))
+	assert.Equal(t, float64(3), testutil.ToFloat64(reconciler.count.WithLabelValues("1", eventTypeFulfillment)))
+	assert.InDelta(t, 60, testutil.ToFloat64(reconciler.oldestAge.WithLabelValues("1", eventTypeFulfillment)), 5)
+
+	// event types without orphans are no longer reported
+	database.On("GetOrphanEventStats", mock.Anything).Return(nil, nil).Once()
+
+	require.NoError(t, reconciler.UpdateMetrics(context.Background()))
+	assert.Zero(t, testutil.CollectAndCount(reconciler))
+}
+
+func orphanLog(t *testing.T, blockNumber uint64) []byte {
+	data, err := json.Marshal(types.Log{BlockNumber: blockNumber, Topics: []common.Hash{}, Data: []byte{}})
+	require.NoError(t, err)
+	return data
+}
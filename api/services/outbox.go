@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+const (
+	// OutboxPollInterval is how often the outbox is checked when no service notified the dispatcher,
+	// e.g. for messages committed right before a crash
+	OutboxPollInterval = 5 * time.Second
+
+	// OutboxBatchSize is the maximum number of outbox messages dispatched at once
+	OutboxBatchSize = 500
+)
+
+// enqueueIntentEvent writes the outbox message of an intent event in the transaction persisting the event
+func enqueueIntentEvent(
+	ctx context.Context,
+	tx db.Database,
+	topic string,
+	transition *models.IntentStatusTransition,
+) error {
+	payload, err := json.Marshal(transition)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox message: %v", err)
+	}
+
+	return tx.EnqueueOutboxMessage(ctx, &models.OutboxMessage{Topic: topic, Payload: payload})
+}
+
+// advanceCheckpoint moves the checkpoint of an event type to the block before a log
+// in the transaction persisting the log. Logs arrive in block order and the ingestion stops at a log that failed,
+// so the blocks before it are complete.
+func advanceCheckpoint(ctx context.Context, tx db.Database, chainID uint64, eventType string, vLog types.Log) error {
+	if vLog.BlockNumber == 0 {
+		return nil
+	}
+
+	if err := tx.AdvanceEventCheckpoint(ctx, chainID, eventType, vLog.Address.Hex(), vLog.BlockNumber-1); err != nil {
+		return fmt.Errorf("failed to advance %s checkpoint: %w", eventType, err)
+	}
+	return nil
+}
+
+// OutboxConsumer consumes the intent events of dispatched outbox messages in the dispatch transaction,
+// the messages are dispatched only if every consumer succeeds
+type OutboxConsumer interface {
+	ConsumeIntentEvents(ctx context.Context, tx db.Database, events []*models.IntentEvent) error
+}
+
+// OutboxDispatcher dispatches the outbox messages written along with the persisted events: their intent events
+// are handed to the consumers and get the cursor the stream clients read them after, then the downstream
+// notifiers are notified. Services notify the dispatcher once their transaction is committed, the dispatcher
+// also polls the outbox so that the messages of a crashed instance are dispatched.
+type OutboxDispatcher struct {
+	db        db.Database
+	consumers []OutboxConsumer
+	notifier  EventNotifier
+	wake      chan struct{}
+	logger    zerolog.Logger
+}
+
+// NewOutboxDispatcher creates a new OutboxDispatcher instance
+func NewOutboxDispatcher(database db.Database, notifier EventNotifier, logger zerolog.Logger) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		db:       database,
+		notifier: notifier,
+		wake:     make(chan struct{}, 1),
+		logger:   logger.With().Str(logging.FieldModule, "outbox").Logger(),
+	}
+}
+
+// SetConsumers sets the consumers of the dispatched intent events
+func (d *OutboxDispatcher) SetConsumers(consumers ...OutboxConsumer) {
+	d.consumers = consumers
+}
+
+// Notify implements EventNotifier, it wakes the dispatcher up
+func (d *OutboxDispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Dispatch hands the intent events of a batch of pending outbox messages to the consumers and marks
+// the messages dispatched in one transaction, then notifies the downstream notifiers.
+// Returns the number of dispatched messages.
+func (d *OutboxDispatcher) Dispatch(ctx context.Context) (int, error) {
+	var (
+		events     []*models.IntentEvent
+		dispatched int
+	)
+	err := d.db.WithTx(ctx, func(tx db.Database) error {
+		var err error
+		events, dispatched, err = tx.DispatchOutboxMessages(ctx, OutboxBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, consumer := range d.consumers {
+			if err := consumer.ConsumeIntentEvents(ctx, tx, events); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if dispatched == 0 {
+		return 0, nil
+	}
+
+	notifyEvents(d.notifier)
+
+	logEvent := d.logger.Debug().Int("messages", dispatched)
+	if len(events) > 0 {
+		logEvent = logEvent.Int64("last_cursor", events[len(events)-1].Cursor)
+	}
+	logEvent.Msg("Dispatched outbox messages")
+
+	return dispatched, nil
+}
+
+// Run dispatches the outbox messages until the context is done
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			dispatched, err := d.Dispatch(ctx)
+			if err != nil {
+				d.logger.Error().Err(err).Msg("Failed to dispatch outbox messages")
+			}
+			if err != nil || dispatched < OutboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxDispatcher_Dispatch(t *testing.T) {
+	events := []*models.IntentEvent{
+		{Cursor: 41, IntentID: "0x01", Status: models.IntentStatusFulfilled},
+		{Cursor: 42, IntentID: "0x01", Status: models.IntentStatusSettled},
+	}
+
+	t.Run("hands the events to the consumers", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		notifier := &countingNotifier{}
+		consumer := &recordingConsumer{}
+		dispatcher := NewOutboxDispatcher(database, notifier, logging.NewTesting(t))
+		dispatcher.SetConsumers(consumer)
+
+		expectTx(database)
+		database.On("DispatchOutboxMessages", mock.Anything, OutboxBatchSize).Return(events, 3, nil).Once()
+
+		dispatched, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, dispatched)
+		assert.Equal(t, events, consumer.events)
+		assert.Equal(t, 1, notifier.count)
+
+		// nothing is notified without pending messages
+		database.On("DispatchOutboxMessages", mock.Anything, OutboxBatchSize).Return(nil, 0, nil).Once()
+
+		dispatched, err = dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, dispatched)
+		assert.Equal(t, 1, notifier.count)
+	})
+
+	t.Run("messages stay pending when a consumer fails", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		notifier := &countingNotifier{}
+		dispatcher := NewOutboxDispatcher(database, notifier, logging.NewTesting(t))
+		dispatcher.SetConsumers(&recordingConsumer{err: errors.New("connection reset")})
+
+		// the transaction is rolled back with the error of the callback
+		database.On("WithTx", mock.Anything, mock.Anything).
+			Return(func(_ context.Context, fn func(tx db.Database) error) error {
+				return fn(database)
+			}).
+			Once()
+		database.On("DispatchOutboxMessages", mock.Anything, OutboxBatchSize).Return(events, 2, nil).Once()
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.ErrorContains(t, err, "connection reset")
+		assert.Zero(t, notifier.count)
+	})
+}
+
+func TestFulfillmentService_PersistLogIsAtomic(t *testing.T) {
+	contract := common.HexToAddress("0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB")
+	intentID := common.HexToHash("0x01")
+
+	newLog := func() types.Log {
+		service := newTestFulfillmentService(t, nil)
+		return types.Log{
+			Address:     contract,
+			BlockNumber: 100,
+			Topics: []common.Hash{
+				service.abi.Events[IntentFulfilledEventName].ID,
+				intentID,
+				common.HexToHash("0x02"),
+				common.HexToHash("0x03"),
+			},
+			Data: common.LeftPadBytes([]byte{1}, 32),
+		}
+	}
+
+	t.Run("commits the fulfillment, status change, outbox message and checkpoint", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		expectTx(database)
+		notifier := &countingNotifier{}
+		service := newTestFulfillmentService(t, database)
+		service.SetEventNotifier(notifier)
+
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex(), Amount: "1"}, nil)
+		database.On("CreateFulfillment", mock.Anything, mock.Anything).Return(nil).Once()
+		database.On("GetTotalFulfilledAmount", mock.Anything, intentID.Hex()).Return("1", nil).Once()
+		database.On("UpdateIntentStatus", mock.Anything, mock.Anything).
+			Run(fromStatus(models.IntentStatusPending)).
+			Return(nil).
+			Once()
+		database.On("EnqueueOutboxMessage", mock.Anything, mock.MatchedBy(func(message *models.OutboxMessage) bool {
+			var transition models.IntentStatusTransition
+			return message.Topic == models.OutboxTopicIntentFulfilled &&
+				json.Unmarshal(message.Payload, &transition) == nil &&
+				transition.IntentID == intentID.Hex() &&
+				transition.ToStatus == models.IntentStatusFulfilled
+		})).Return(nil).Once()
+		database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(99)).
+			Return(nil).Once()
+
+		require.NoError(t, service.persistLog(context.Background(), newLog()))
+		assert.Equal(t, 1, notifier.count)
+	})
+
+	t.Run("rolls back when the status change fails", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		expectTx(database)
+		notifier := &countingNotifier{}
+		service := newTestFulfillmentService(t, database)
+		service.SetEventNotifier(notifier)
+
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex(), Amount: "1"}, nil)
+		database.On("CreateFulfillment", mock.Anything, mock.Anything).Return(nil).Once()
+		database.On("GetTotalFulfilledAmount", mock.Anything, intentID.Hex()).Return("1", nil).Once()
+		database.On("UpdateIntentStatus", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+
+		err := service.persistLog(context.Background(), newLog())
+		require.ErrorContains(t, err, "connection reset")
+		assert.Zero(t, notifier.count)
+	})
+
+	t.Run("partially fulfills the intent until its amount is filled", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		expectTx(database)
+		service := newTestFulfillmentService(t, database)
+
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex(), Amount: "3"}, nil)
+		database.On("CreateFulfillment", mock.Anything, mock.MatchedBy(func(f *models.Fulfillment) bool {
+			return f.ID == intentID.Hex() && f.Amount == "1"
+		})).Return(nil).Once()
+		database.On("GetTotalFulfilledAmount", mock.Anything, intentID.Hex()).Return("2", nil).Once()
+		database.On("UpdateIntentStatus", mock.Anything, mock.MatchedBy(func(transition *models.IntentStatusTransition) bool {
+			return transition.ToStatus == models.IntentStatusPartiallyFulfilled
+		})).Run(fromStatus(models.IntentStatusPending)).Return(nil).Once()
+		database.On("EnqueueOutboxMessage", mock.Anything, mock.Anything).Return(nil).Once()
+		database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(99)).
+			Return(nil).Once()
+
+		require.NoError(t, service.persistLog(context.Background(), newLog()))
+	})
+
+	t.Run("backfills leave the checkpoint untouched", func(t *testing.T) {
+		// the database mock fails the test on any unexpected AdvanceEventCheckpoint call
+		database := mocks.NewDatabaseMock(t)
+		expectTx(database)
+		service := newTestFulfillmentService(t, database)
+		service.SetBackfill()
+
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex(), Amount: "1"}, nil)
+		database.On("CreateFulfillment", mock.Anything, mock.Anything).Return(nil).Once()
+		database.On("GetTotalFulfilledAmount", mock.Anything, intentID.Hex()).Return("1", nil).Once()
+		database.On("UpdateIntentStatus", mock.Anything, mock.Anything).
+			Run(fromStatus(models.IntentStatusPending)).
+			Return(nil).
+			Once()
+		database.On("EnqueueOutboxMessage", mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, service.persistLog(context.Background(), newLog()))
+
+		// nor do the logs queued until their intent is indexed
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Unset()
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(nil, db.ErrNotFound).Once()
+		database.On("EnqueueOrphanEvent", mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, service.persistLog(context.Background(), newLog()))
+	})
+
+	t.Run("publishes nothing when the status doesn't change", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		expectTx(database)
+		service := newTestFulfillmentService(t, database)
+
+		// another partial fill of a partially fulfilled intent
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex(), Amount: "3"}, nil)
+		database.On("CreateFulfillment", mock.Anything, mock.Anything).Return(nil).Once()
+		database.On("GetTotalFulfilledAmount", mock.Anything, intentID.Hex()).Return("2", nil).Once()
+		database.On("UpdateIntentStatus", mock.Anything, mock.Anything).Return(nil).Once()
+		database.On("AdvanceEventCheckpoint", mock.Anything, uint64(1), eventTypeFulfillment, contract.Hex(), uint64(99)).
+			Return(nil).Once()
+
+		require.NoError(t, service.persistLog(context.Background(), newLog()))
+	})
+
+	t.Run("skips duplicates", func(t *testing.T) {
+		database := mocks.NewDatabaseMock(t)
+		expectTx(database)
+		service := newTestFulfillmentService(t, database)
+
+		database.On("GetIntent", mock.Anything, intentID.Hex()).Return(&models.Intent{ID: intentID.Hex()}, nil)
+		database.On("CreateFulfillment", mock.Anything, mock.Anything).Return(db.ErrDuplicate).Once()
+
+		require.NoError(t, service.persistLog(context.Background(), newLog()))
+	})
+}
+
+func newTestFulfillmentService(t *testing.T, database db.Database) *FulfillmentService {
+	service, err := NewFulfillmentService(
+		blockTimeClient{},
+		nil,
+		database,
+		config.IntentFulfilledEventABI,
+		1,
+		logging.NewTesting(t),
+	)
+	require.NoError(t, err)
+	return service
+}
+
+// expectTx runs the WithTx callbacks with the mock itself, as the transaction of a PostgresDB would
+func expectTx(database *mocks.DatabaseMock) {
+	database.On("WithTx", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, fn func(tx db.Database) error) error {
+			return fn(database)
+		}).
+		Maybe()
+}
+
+// fromStatus sets the from status of the transitions passed to UpdateIntentStatus, as for an applied transition
+func fromStatus(status models.IntentStatus) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		args.Get(1).(*models.IntentStatusTransition).FromStatus = status
+	}
+}
+
+// recordingConsumer is an OutboxConsumer recording the consumed events
+type recordingConsumer struct {
+	events []*models.IntentEvent
+	err    error
+}
+
+func (c *recordingConsumer) ConsumeIntentEvents(_ context.Context, _ db.Database, events []*models.IntentEvent) error {
+	c.events = append(c.events, events...)
+	return c.err
+}
+
+// blockTimeClient returns blocks with a fixed timestamp
+type blockTimeClient struct {
+	evm.Client
+}
+
+func (blockTimeClient) BlockByNumber(_ context.Context, number *big.Int) (*types.Block, error) {
+	return types.NewBlockWithHeader(&types.Header{Number: number, Time: 1700000000}), nil
+}
+
+// countingNotifier counts its notifications
+type countingNotifier struct {
+	count int
+}
+
+func (n *countingNotifier) Notify() {
+	n.count++
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// usdDecimals is the precision of the stored USD values
+	usdDecimals = 6
+
+	// DefaultUSDBackfillBatchSize is the number of intents valued per batch by the USD value backfill
+	DefaultUSDBackfillBatchSize = 500
+)
+
+// ErrPriceNotFound is returned by the price sources that don't know the price of a token
+var ErrPriceNotFound = errors.New("price not found")
+
+// PriceSource returns the USD price of tokens. Sources can be static files or remote price APIs.
+type PriceSource interface {
+	// PriceUSD returns the price of one whole token at a time, ErrPriceNotFound if the price is unknown
+	PriceUSD(ctx context.Context, token *models.Token, at time.Time) (*big.Rat, error)
+}
+
+// PriceSources queries the sources in order, e.g. a remote API then a static file as fallback
+type PriceSources []PriceSource
+
+// PriceUSD implements PriceSource, it returns the price of the first source knowing it
+func (s PriceSources) PriceUSD(ctx context.Context, token *models.Token, at time.Time) (*big.Rat, error) {
+	var errs []error
+	for _, source := range s {
+		price, err := source.PriceUSD(ctx, token, at)
+		if err == nil {
+			return price, nil
+		}
+		if !errors.Is(err, ErrPriceNotFound) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return nil, ErrPriceNotFound
+}
+
+// pricePoint is a price valid from a time until the next price point of the token
+type pricePoint struct {
+	from  time.Time
+	price *big.Rat
+}
+
+// StaticPriceSource serves fixed prices, optionally changing over time.
+// Prices are keyed by the asset ID of the token, e.g. "usdc", or by "<chain ID>:<address>"
+// for the tokens without asset ID.
+type StaticPriceSource struct {
+	prices map[string][]pricePoint
+}
+
+// NewStaticPriceSource creates a new StaticPriceSource instance with the prices of its keys
+func NewStaticPriceSource(prices map[string]*big.Rat) *StaticPriceSource {
+	s := &StaticPriceSource{prices: make(map[string][]pricePoint, len(prices))}
+	for key, price := range prices {
+		s.AddPrice(key, time.Time{}, price)
+	}
+	return s
+}
+
+// AddPrice sets the price of a token from a time, the zero time sets the price of the token before its first price
+func (s *StaticPriceSource) AddPrice(key string, from time.Time, price *big.Rat) {
+	key = strings.ToLower(key)
+	points := append(s.prices[key], pricePoint{from: from, price: price})
+	sort.SliceStable(points, func(i, j int) bool { return points[i].from.Before(points[j].from) })
+	s.prices[key] = points
+}
+
+// PriceUSD implements PriceSource, the price of a token at a time is its last price from before that time
+func (s *StaticPriceSource) PriceUSD(_ context.Context, token *models.Token, at time.Time) (*big.Rat, error) {
+	for _, key := range []string{fmt.Sprintf("%d:%s", token.ChainID, token.Address), token.AssetID} {
+		points := s.prices[strings.ToLower(key)]
+		if key == "" || len(points) == 0 {
+			continue
+		}
+
+		// the earliest price applies to the times before it
+		price := points[0].price
+		for _, point := range points[1:] {
+			if point.from.After(at) {
+				break
+			}
+			price = point.price
+		}
+
+		return price, nil
+	}
+
+	return nil, ErrPriceNotFound
+}
+
+// staticPriceFile is the YAML or JSON price file format
+type staticPriceFile struct {
+	Prices map[string]string `yaml:"prices"`
+}
+
+// LoadPriceFile loads a StaticPriceSource from a file.
+// CSV files have an "asset,price_usd,from" row per price, from being an optional RFC3339 time.
+// Other files are YAML or JSON objects with the USD price of each asset, e.g. {"prices": {"usdc": "1"}}.
+func LoadPriceFile(path string) (*StaticPriceSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price file: %v", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return parsePriceCSV(data)
+	}
+
+	var file staticPriceFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse price file: %v", err)
+	}
+
+	source := NewStaticPriceSource(nil)
+	for key, value := range file.Prices {
+		price, err := parsePrice(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price of %s: %v", key, err)
+		}
+		source.AddPrice(key, time.Time{}, price)
+	}
+
+	return source, nil
+}
+
+func parsePriceCSV(data []byte) (*StaticPriceSource, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	source := NewStaticPriceSource(nil)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price file: %v", err)
+		}
+
+		// the header is optional
+		if line == 1 && strings.EqualFold(record[0], "asset") {
+			continue
+		}
+
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected asset, price_usd and optional from columns", line)
+		}
+
+		price, err := parsePrice(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		var from time.Time
+		if len(record) == 3 && record[2] != "" {
+			if from, err = time.Parse(time.RFC3339, record[2]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid time %q", line, record[2])
+			}
+		}
+
+		source.AddPrice(record[0], from, price)
+	}
+
+	return source, nil
+}
+
+func parsePrice(value string) (*big.Rat, error) {
+	price, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || price.Sign() < 0 {
+		return nil, fmt.Errorf("invalid price %q", value)
+	}
+	return price, nil
+}
+
+// valueUSD returns the USD value of an amount of base units of a token at a price, with usdDecimals decimals
+func valueUSD(amount string, decimals int, price *big.Rat) (string, error) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return "", fmt.Errorf("invalid amount: %q", amount)
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	usd := new(big.Rat).Mul(new(big.Rat).SetFrac(value, unit), price)
+
+	formatted := usd.FloatString(usdDecimals)
+	formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+
+	return formatted, nil
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticPriceSource(t *testing.T) {
+	usdc := &models.Token{ChainID: 1, Address: tokensTestUSDC, AssetID: "usdc"}
+	weth := &models.Token{ChainID: 1, Address: tokensTestWETH}
+
+	source := NewStaticPriceSource(map[string]*big.Rat{"USDC": big.NewRat(1, 1)})
+	source.AddPrice("1:"+tokensTestWETH, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), big.NewRat(3300, 1))
+	source.AddPrice("1:"+tokensTestWETH, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), big.NewRat(2400, 1))
+
+	tests := []struct {
+		name     string
+		token    *models.Token
+		at       time.Time
+		expected *big.Rat
+	}{
+		{name: "asset", token: usdc, at: time.Now(), expected: big.NewRat(1, 1)},
+		{name: "before the first price", token: weth, at: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), expected: big.NewRat(2400, 1)},
+		{name: "between prices", token: weth, at: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), expected: big.NewRat(2400, 1)},
+		{name: "after the last price", token: weth, at: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), expected: big.NewRat(3300, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := source.PriceUSD(context.Background(), tt.token, tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, 0, tt.expected.Cmp(price), price.String())
+		})
+	}
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := source.PriceUSD(context.Background(), &models.Token{ChainID: 1, Address: tokensTestMKR}, time.Now())
+		assert.ErrorIs(t, err, ErrPriceNotFound)
+	})
+}
+
+func TestPriceSources(t *testing.T) {
+	token := &models.Token{ChainID: 1, Address: tokensTestUSDC, AssetID: "usdc"}
+	failing := priceSourceFunc(func(*models.Token) (*big.Rat, error) { return nil, errors.New("rate limited") })
+	static := NewStaticPriceSource(map[string]*big.Rat{"usdc": big.NewRat(1, 1)})
+
+	t.Run("falls back to the next source", func(t *testing.T) {
+		price, err := PriceSources{failing, static}.PriceUSD(context.Background(), token, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, "1", price.RatString())
+	})
+
+	t.Run("errors of the sources", func(t *testing.T) {
+		_, err := PriceSources{failing, NewStaticPriceSource(nil)}.PriceUSD(context.Background(), token, time.Now())
+		require.ErrorContains(t, err, "rate limited")
+		assert.NotErrorIs(t, err, ErrPriceNotFound)
+	})
+
+	t.Run("unknown price", func(t *testing.T) {
+		_, err := PriceSources{NewStaticPriceSource(nil)}.PriceUSD(context.Background(), token, time.Now())
+		assert.ErrorIs(t, err, ErrPriceNotFound)
+	})
+}
+
+func TestLoadPriceFile(t *testing.T) {
+	weth := &models.Token{ChainID: 8453, Address: "0x4200000000000000000000000000000000000006", AssetID: "weth"}
+
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "csv",
+			file:     "prices.csv",
+			content:  "asset,price_usd,from\nweth,2400.5\nweth,3300,2025-06-01T00:00:00Z\n",
+			expected: "3300",
+		},
+		{
+			name:     "csv without header",
+			file:     "prices.csv",
+			content:  "# prices\nWETH, 2400.5\n",
+			expected: "4801/2",
+		},
+		{
+			name:     "yaml",
+			file:     "prices.yaml",
+			content:  "prices:\n  \"8453:0x4200000000000000000000000000000000000006\": \"3300\"\n",
+			expected: "3300",
+		},
+		{
+			name:     "json",
+			file:     "prices.json",
+			content:  `{"prices": {"weth": "2400.5"}}`,
+			expected: "4801/2",
+		},
+		{
+			name:        "invalid price",
+			file:        "prices.csv",
+			content:     "weth,-1\n",
+			expectedErr: "line 1: invalid price",
+		},
+		{
+			name:        "invalid time",
+			file:        "prices.csv",
+			content:     "weth,1,2025-06-01\n",
+			expectedErr: "line 1: invalid time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			source, err := LoadPriceFile(path)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			price, err := source.PriceUSD(context.Background(), weth, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, price.RatString())
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadPriceFile(filepath.Join(t.TempDir(), "prices.csv"))
+		require.ErrorContains(t, err, "failed to read price file")
+	})
+}
+
+func TestIntentService_StoresUSDValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		prices       PriceSource
+		amountUSD    string
+		intentFeeUSD string
+	}{
+		{
+			name:         "known price",
+			prices:       NewStaticPriceSource(map[string]*big.Rat{"usdc": big.NewRat(9999, 10000)}),
+			amountUSD:    "1.49985",
+			intentFeeUSD: "0.0005",
+		},
+		{
+			name:   "unknown price",
+			prices: NewStaticPriceSource(nil),
+		},
+		{
+			name:   "failing price source",
+			prices: priceSourceFunc(func(*models.Token) (*big.Rat, error) { return nil, errors.New("rate limited") }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(mocks.DatabaseMock)
+			intentService := &IntentService{db: mockDB, chainID: 1}
+			intentService.SetTokenRegistry(newTestTokenRegistry(t, &erc20Client{}))
+			intentService.SetPriceSource(tt.prices)
+
+			expectTx(mockDB)
+			mockDB.On("EnqueueOutboxMessage", mock.Anything, mock.Anything).Return(nil).Once()
+			mockDB.On("CreateIntent", mock.Anything, mock.MatchedBy(func(i *models.Intent) bool {
+				return i.AmountUSD == tt.amountUSD && i.IntentFeeUSD == tt.intentFeeUSD
+			})).Return(nil).Once()
+
+			intent, err := intentService.CreateIntent(
+				context.Background(),
+				"0x1234567890123456789012345678901234567890123456789012345678901234",
+				1,
+				2,
+				tokensTestUSDC,
+				"1500000",
+				"0x9876543210987654321098765432109876543210",
+				"0x5678901234567890123456789012345678901234",
+				"500",
+			)
+			require.NoError(t, err)
+			assert.Equal(t, tt.amountUSD, intent.AmountUSD)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIntentService_BackfillUSDValues(t *testing.T) {
+	mockDB := mocks.NewDatabaseMock(t)
+	intentService := &IntentService{db: mockDB, chainID: 1, logger: logging.NewTesting(t)}
+	intentService.SetTokenRegistry(newTestTokenRegistry(t, &erc20Client{}))
+
+	t.Run("requires a price source", func(t *testing.T) {
+		_, _, err := intentService.BackfillUSDValues(context.Background(), 2)
+		require.ErrorContains(t, err, "no price source")
+	})
+
+	intentService.SetPriceSource(NewStaticPriceSource(map[string]*big.Rat{"usdc": big.NewRat(1, 1)}))
+
+	// the intents are valued at their creation time, the intent without price is left without value
+	valued := &models.Intent{ID: "0x01", SourceChain: 1, Token: tokensTestUSDC, Amount: "1500000", IntentFee: "500"}
+	unpriced := &models.Intent{ID: "0x02", SourceChain: 1, Token: tokensTestWETH, Amount: "1", IntentFee: "1"}
+	mockDB.On("ListUnvaluedIntents", mock.Anything, uint64(1), "", 2).
+		Return([]*models.Intent{valued, unpriced}, nil).
+		Once()
+	mockDB.On("ListUnvaluedIntents", mock.Anything, uint64(1), "0x02", 2).
+		Return(nil, nil).
+		Once()
+	mockDB.On("UpdateIntentUSDValue", mock.Anything, "0x01", "1.5", "0.0005").
+		Return(nil).
+		Once()
+
+	scanned, count, err := intentService.BackfillUSDValues(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, scanned)
+	assert.Equal(t, 1, count)
+}
+
+func TestValueUSD(t *testing.T) {
+	value, err := valueUSD("2500000000000000000", 18, big.NewRat(33001, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "8250.25", value)
+
+	value, err = valueUSD("1", 18, big.NewRat(3300, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "0", value)
+
+	_, err = valueUSD("1.5", 6, big.NewRat(1, 1))
+	require.ErrorContains(t, err, "invalid amount")
+}
+
+// priceSourceFunc is a PriceSource answering with a function
+type priceSourceFunc func(token *models.Token) (*big.Rat, error)
+
+func (f priceSourceFunc) PriceUSD(_ context.Context, token *models.Token, _ time.Time) (*big.Rat, error) {
+	return f(token)
+}
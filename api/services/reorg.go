@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+// ReorgTrackingDepth is the number of recent blocks per chain whose hashes are kept
+// and verified against the canonical chain
+const ReorgTrackingDepth = uint64(256)
+
+// HeaderFetcher fetches block headers; satisfied by evm.Client
+type HeaderFetcher interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// ReorgDetector records the hashes of processed blocks and rolls back indexed
+// intents, fulfillments and settlements when a chain reorganization orphans them
+type ReorgDetector struct {
+	db     db.Database
+	depth  uint64
+	logger zerolog.Logger
+}
+
+// NewReorgDetector creates a new reorg detector
+func NewReorgDetector(database db.Database, logger zerolog.Logger) *ReorgDetector {
+	return &ReorgDetector{
+		db:     database,
+		depth:  ReorgTrackingDepth,
+		logger: logger.With().Str(logging.FieldModule, "reorg").Logger(),
+	}
+}
+
+// RecordLog records the block of a successfully processed log
+func (d *ReorgDetector) RecordLog(ctx context.Context, chainID uint64, vLog types.Log) {
+	if vLog.BlockNumber == 0 || vLog.BlockHash == (common.Hash{}) {
+		return
+	}
+
+	if err := d.db.RecordBlockHash(ctx, chainID, vLog.BlockNumber, vLog.BlockHash.Hex()); err != nil {
+		d.logger.Warn().
+			Err(err).
+			Uint64(logging.FieldChain, chainID).
+			Uint64(logging.FieldBlock, vLog.BlockNumber).
+			Msg("Failed to record block hash")
+	}
+}
+
+// RecordHead records the hash of the given block as seen by the client,
+// so that polled ranges without events can be verified later
+func (d *ReorgDetector) RecordHead(ctx context.Context, chainID uint64, client HeaderFetcher, blockNumber uint64) error {
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to get header for block %d: %v", blockNumber, err)
+	}
+
+	return d.db.RecordBlockHash(ctx, chainID, blockNumber, header.Hash().Hex())
+}
+
+// HandleRemovedLog rolls back everything indexed from the block of a log that
+// a subscription reported as removed
+func (d *ReorgDetector) HandleRemovedLog(ctx context.Context, chainID uint64, vLog types.Log) error {
+	d.logger.Warn().
+		Uint64(logging.FieldChain, chainID).
+		Uint64(logging.FieldBlock, vLog.BlockNumber).
+		Str("block_hash", vLog.BlockHash.Hex()).
+		Str("tx_hash", vLog.TxHash.Hex()).
+		Msg("Received removed log, chain reorganization detected")
+
+	_, err := d.rollback(ctx, chainID, vLog.BlockNumber, []string{vLog.BlockHash.Hex()})
+	return err
+}
+
+// CheckCanonical compares the recorded block hashes of a chain with the client's view
+// and rolls back from the first orphaned block. Returns nil if no reorg was detected.
+func (d *ReorgDetector) CheckCanonical(
+	ctx context.Context,
+	chainID uint64,
+	client HeaderFetcher,
+) (*models.RollbackResult, error) {
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %v", err)
+	}
+
+	head := latest.Number.Uint64()
+
+	var from uint64
+	if head > d.depth {
+		from = head - d.depth
+	}
+
+	// newest first
+	blocks, err := d.db.ListBlockHashes(ctx, chainID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var forkBlock uint64
+	for _, block := range blocks {
+		if block.BlockNumber > head {
+			// the chain is now shorter than what we indexed
+			forkBlock = block.BlockNumber
+			continue
+		}
+
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.BlockNumber))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get header for block %d: %v", block.BlockNumber, err)
+		}
+
+		// found the common ancestor
+		if header.Hash().Hex() == block.BlockHash {
+			break
+		}
+
+		forkBlock = block.BlockNumber
+	}
+
+	if from > 0 {
+		if err := d.db.PruneBlockHashes(ctx, chainID, from); err != nil {
+			d.logger.Warn().Err(err).Uint64(logging.FieldChain, chainID).Msg("Failed to prune block hashes")
+		}
+	}
+
+	if forkBlock == 0 {
+		return nil, nil
+	}
+
+	d.logger.Warn().
+		Uint64(logging.FieldChain, chainID).
+		Uint64("fork_block", forkBlock).
+		Uint64("head_block", head).
+		Msg("Block hash mismatch, chain reorganization detected")
+
+	return d.rollback(ctx, chainID, forkBlock, nil)
+}
+
+func (d *ReorgDetector) rollback(
+	ctx context.Context,
+	chainID, fromBlock uint64,
+	orphanedHashes []string,
+) (*models.RollbackResult, error) {
+	result, err := d.db.RollbackFromBlock(ctx, chainID, fromBlock, orphanedHashes)
+	if err != nil {
+		d.logger.Error().
+			Err(err).
+			Uint64(logging.FieldChain, chainID).
+			Uint64("from_block", fromBlock).
+			Msg("Failed to roll back orphaned blocks")
+		return nil, fmt.Errorf("failed to roll back from block %d: %v", fromBlock, err)
+	}
+
+	d.logger.Info().
+		Uint64(logging.FieldChain, chainID).
+		Uint64("from_block", fromBlock).
+		Int64("intents", result.Intents).
+		Int64("fulfillments", result.Fulfillments).
+		Int64("settlements", result.Settlements).
+		Int64("rederived_intents", result.RederivedIntents).
+		Msg("Rolled back orphaned blocks")
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeaderFetcher serves headers of a chain from a map of block number to header
+type fakeHeaderFetcher struct {
+	head    uint64
+	headers map[uint64]*types.Header
+	tags    map[int64]uint64 // block tag (e.g. rpc.FinalizedBlockNumber) -> block number
+}
+
+func newFakeHeaderFetcher(head uint64) *fakeHeaderFetcher {
+	f := &fakeHeaderFetcher{head: head, headers: make(map[uint64]*types.Header)}
+	for n := uint64(1); n <= head; n++ {
+		f.headers[n] = &types.Header{Number: new(big.Int).SetUint64(n), Extra: []byte("canonical")}
+	}
+	return f
+}
+
+func (f *fakeHeaderFetcher) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return f.headers[f.head], nil
+	}
+	if number.Sign() < 0 {
+		n, ok := f.tags[number.Int64()]
+		if !ok {
+			return nil, errors.New("unsupported block tag")
+		}
+		return f.headers[n], nil
+	}
+	return f.headers[number.Uint64()], nil
+}
+
+func (f *fakeHeaderFetcher) hash(n uint64) string {
+	return f.headers[n].Hash().Hex()
+}
+
+func TestReorgDetector_CheckCanonical(t *testing.T) {
+	const chainID = uint64(8453)
+
+	t.Run("no reorg", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		detector := NewReorgDetector(mockDB, logging.NewTesting(t))
+		client := newFakeHeaderFetcher(10)
+
+		mockDB.On("ListBlockHashes", mock.Anything, chainID, uint64(0)).Return([]*models.ProcessedBlock{
+			{ChainID: chainID, BlockNumber: 10, BlockHash: client.hash(10)},
+			{ChainID: chainID, BlockNumber: 9, BlockHash: client.hash(9)},
+		}, nil)
+
+		result, err := detector.CheckCanonical(context.Background(), chainID, client)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("rolls back from fork block", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		detector := NewReorgDetector(mockDB, logging.NewTesting(t))
+		client := newFakeHeaderFetcher(10)
+
+		orphaned := &types.Header{Number: big.NewInt(9), Extra: []byte("orphaned")}
+
+		mockDB.On("ListBlockHashes", mock.Anything, chainID, uint64(0)).Return([]*models.ProcessedBlock{
+			{ChainID: chainID, BlockNumber: 12, BlockHash: "0x12"},
+			{ChainID: chainID, BlockNumber: 9, BlockHash: orphaned.Hash().Hex()},
+			{ChainID: chainID, BlockNumber: 8, BlockHash: client.hash(8)},
+			{ChainID: chainID, BlockNumber: 7, BlockHash: "0x07"},
+		}, nil)
+
+		expected := &models.RollbackResult{ChainID: chainID, FromBlock: 9, Intents: 1}
+		mockDB.On("RollbackFromBlock", mock.Anything, chainID, uint64(9), []string(nil)).Return(expected, nil)
+
+		result, err := detector.CheckCanonical(context.Background(), chainID, client)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("prunes hashes beyond tracking depth", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		detector := NewReorgDetector(mockDB, logging.NewTesting(t))
+		detector.depth = 5
+		client := newFakeHeaderFetcher(10)
+
+		mockDB.On("ListBlockHashes", mock.Anything, chainID, uint64(5)).Return([]*models.ProcessedBlock{
+			{ChainID: chainID, BlockNumber: 10, BlockHash: client.hash(10)},
+		}, nil)
+		mockDB.On("PruneBlockHashes", mock.Anything, chainID, uint64(5)).Return(nil)
+
+		result, err := detector.CheckCanonical(context.Background(), chainID, client)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestReorgDetector_HandleRemovedLog(t *testing.T) {
+	const chainID = uint64(7000)
+
+	mockDB := mocks.NewDatabaseMock(t)
+	detector := NewReorgDetector(mockDB, logging.NewTesting(t))
+
+	blockHash := common.HexToHash("0xabc")
+	vLog := types.Log{BlockNumber: 42, BlockHash: blockHash, Removed: true}
+
+	mockDB.On("RollbackFromBlock", mock.Anything, chainID, uint64(42), []string{blockHash.Hex()}).
+		Return(&models.RollbackResult{ChainID: chainID, FromBlock: 42, Fulfillments: 1}, nil)
+
+	require.NoError(t, detector.HandleRemovedLog(context.Background(), chainID, vLog))
+}
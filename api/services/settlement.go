@@ -6,17 +6,15 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync"
-	"sync/atomic"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
 	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
 	"github.com/speedrun-hq/speedrun/api/logging"
 	"github.com/speedrun-hq/speedrun/api/models"
 )
@@ -41,29 +39,70 @@ const (
 
 // SettlementService handles monitoring and processing of settlement events
 type SettlementService struct {
-	client         *ethclient.Client
+	client         evm.Client
 	clientResolver ClientResolver
 	db             db.Database
+	reorg          *ReorgDetector
+	confirmations  *ConfirmationTracker
+	logRange       *LogRange
+	events         EventNotifier
 	abi            abi.ABI
 	chainID        uint64
-	subs           map[string]ethereum.Subscription
-	mu             sync.Mutex
+	backfill       bool // re-indexing a range, checkpoints are left untouched
 	logger         zerolog.Logger
+}
+
+// SetEventNotifier sets the notifier called after an intent event is persisted
+func (s *SettlementService) SetEventNotifier(notifier EventNotifier) {
+	s.events = notifier
+}
+
+// SetLogRange sets the adaptive block range of the FilterLogs requests made while catching up and polling
+func (s *SettlementService) SetLogRange(logRange *LogRange) {
+	s.logRange = logRange
+}
+
+// SetBackfill makes the service re-index logs without moving the live checkpoints
+func (s *SettlementService) SetBackfill() {
+	s.backfill = true
+}
 
-	// Goroutine tracking
-	activeGoroutines int32 // Counter for active goroutines
+// SetConfirmationTracker makes the service stage logs until their block is confirmed
+func (s *SettlementService) SetConfirmationTracker(tracker *ConfirmationTracker) {
+	s.confirmations = tracker
+	tracker.Register(eventTypeSettlement, s.persistLog)
+}
 
-	// Goroutine cleanup management
-	cleanupCtx    context.Context    // Context for cleanup operations
-	cleanupCancel context.CancelFunc // Cancel function for cleanup context
-	goroutineWg   sync.WaitGroup     // WaitGroup to track all goroutines
-	isShutdown    bool               // Flag to prevent new goroutines after shutdown
-	shutdownMu    sync.RWMutex       // Mutex for shutdown operations
+// SetOrphanReconciler makes the reconciler replay the settlements queued until their intent is indexed
+func (s *SettlementService) SetOrphanReconciler(reconciler *OrphanReconciler) {
+	reconciler.Register(s.chainID, eventTypeSettlement, s.storeLog)
+}
+
+// EventHandler returns the handler of the settlement events for an IngestionEngine
+func (s *SettlementService) EventHandler() EventHandler {
+	return EventHandler{
+		EventType: eventTypeSettlement,
+		Topics: []common.Hash{
+			s.abi.Events[IntentSettledEventName].ID,
+			s.abi.Events[IntentSettledWithCallEventName].ID,
+		},
+		Handle: s.processLog,
+	}
+}
+
+// chainHandler returns the event handler of the service with the client and log range of its chain
+func (s *SettlementService) chainHandler() chainHandler {
+	return chainHandler{
+		EventHandler: s.EventHandler(),
+		chainID:      s.chainID,
+		client:       s.client,
+		logRange:     s.logRange,
+	}
 }
 
 // NewSettlementService creates a new SettlementService instance
 func NewSettlementService(
-	client *ethclient.Client,
+	client evm.Client,
 	clientResolver ClientResolver,
 	db db.Database,
 	intentSettledEventABI string,
@@ -78,206 +117,44 @@ func NewSettlementService(
 		return nil, fmt.Errorf("failed to parse contract ABI: %v", err)
 	}
 
-	// Create cleanup context
-	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-
 	return &SettlementService{
 		client:         client,
 		clientResolver: clientResolver,
 		db:             db,
+		reorg:          NewReorgDetector(db, logger),
+		logRange:       NewLogRange(DefaultLogRange, DefaultMinLogRange, DefaultMaxLogRange),
 		abi:            parsedABI,
 		chainID:        chainID,
-		subs:           make(map[string]ethereum.Subscription),
 		logger:         logger,
-		cleanupCtx:     cleanupCtx,
-		cleanupCancel:  cleanupCancel,
 	}, nil
 }
 
-func (s *SettlementService) StartListening(ctx context.Context, contractAddress common.Address) error {
-	// Check if service is shutdown
-	if s.IsShutdown() {
-		return fmt.Errorf("cannot start listening: service is shutdown")
-	}
-
-	// Check if service is already running - prevent multiple starts
-	activeGoroutines := atomic.LoadInt32(&s.activeGoroutines)
-	if activeGoroutines > 0 {
-		s.logger.Info().
-			Int32("active_goroutines", activeGoroutines).
-			Msg("Service already running, skipping start")
-		return nil
-	}
-
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{contractAddress},
-		Topics: [][]common.Hash{
-			{
-				s.abi.Events[IntentSettledEventName].ID,
-				s.abi.Events[IntentSettledWithCallEventName].ID,
-			},
-		},
+func (s *SettlementService) processLog(ctx context.Context, vLog types.Log) error {
+	// The log was part of a block that is no longer canonical
+	if vLog.Removed {
+		return s.reorg.HandleRemovedLog(ctx, s.chainID, vLog)
 	}
 
-	logs := make(chan types.Log)
-	sub, err := s.client.SubscribeFilterLogs(ctx, query, logs)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to logs: %v", err)
+	// Hold the log back until its block is confirmed
+	if staged, err := s.confirmations.Stage(ctx, eventTypeSettlement, vLog); err != nil || staged {
+		return err
 	}
 
-	// Store the subscription with a unique ID
-	subID := contractAddress.Hex()
-	s.mu.Lock()
-	s.subs[subID] = sub
-	s.mu.Unlock()
-
-	s.logger.Info().
-		Str("contract", contractAddress.Hex()).
-		Msg("Successfully subscribed to settlement events")
-
-	s.startGoroutine("settlement-processor", func() {
-		s.processEventLogs(s.cleanupCtx, sub, logs, subID, contractAddress)
-	})
-	return nil
+	return s.persistLog(ctx, vLog)
 }
 
-func (s *SettlementService) processEventLogs(
-	ctx context.Context,
-	sub ethereum.Subscription,
-	logs chan types.Log,
-	subID string,
-	contractAddress common.Address,
-) {
-	defer func() {
-		sub.Unsubscribe()
-		// Remove the subscription from the map when done
-		s.mu.Lock()
-		delete(s.subs, subID)
-		s.mu.Unlock()
-		s.logger.Info().
-			Str("subscription_id", subID).
-			Msg("Ended settlement event log processing")
-	}()
-
-	s.logger.Info().
-		Str("subscription_id", subID).
-		Msg("Starting settlement event log processing")
-
-	// Add a ticker for debugging to periodically log subscription status
-	debugTicker := time.NewTicker(30 * time.Second)
-	defer debugTicker.Stop()
-
-	for {
-		select {
-		case err := <-sub.Err():
-			if err != nil {
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Err(err).
-					Msg("Settlement subscription error")
-				// Try to resubscribe
-				newSub, err := s.handleSubscriptionError(ctx, sub, logs, subID, contractAddress)
-				if err != nil {
-					s.logger.Error().Err(err).Msg("CRITICAL: Failed to resubscribe settlement service")
-					return
-				}
-				// Update the subscription and continue the loop
-				sub = newSub
-			}
-		case vLog, ok := <-logs:
-			if !ok {
-				s.logger.Error().
-					Str("subscription_id", subID).
-					Msg("Settlement log channel closed unexpectedly")
-				return
-			}
-
-			s.logger.Info().
-				Uint64(logging.FieldBlock, vLog.BlockNumber).
-				Str("tx_hash", vLog.TxHash.Hex()).
-				Msg("SETTLEMENT EVENT RECEIVED")
-
-			if err := s.processLog(ctx, vLog); err != nil {
-				s.logger.Error().Err(err).Msg("Error processing settlement log")
-				continue
-			}
-		case <-debugTicker.C:
-			// Extra debugging info
-			s.logger.Debug().
-				Str("subscription_id", subID).
-				Msg("Settlement subscription still active")
-		case <-ctx.Done():
-			s.logger.Debug().Msg("Context cancelled, stopping settlement event processing")
-			return
-		}
+// persistLog stores the settlement of a confirmed log,
+// logs whose intent isn't indexed yet are queued until it is
+func (s *SettlementService) persistLog(ctx context.Context, vLog types.Log) error {
+	err := s.storeLog(ctx, vLog)
+	if errors.Is(err, errIntentNotIndexed) {
+		return enqueueOrphan(ctx, s.db, s.chainID, eventTypeSettlement, vLog, !s.backfill, s.logger)
 	}
+	return err
 }
 
-func (s *SettlementService) handleSubscriptionError(
-	ctx context.Context,
-	oldSub ethereum.Subscription,
-	logs chan types.Log,
-	subID string,
-	contractAddress common.Address,
-) (ethereum.Subscription, error) {
-	oldSub.Unsubscribe()
-	s.mu.Lock()
-	delete(s.subs, subID)
-	s.mu.Unlock()
-
-	// Implement exponential backoff for retry
-	maxRetries := 5
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		// Check if context is cancelled
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
-		}
-
-		query := ethereum.FilterQuery{
-			Addresses: []common.Address{contractAddress},
-			Topics: [][]common.Hash{
-				{
-					s.abi.Events[IntentSettledEventName].ID,
-					s.abi.Events[IntentSettledWithCallEventName].ID,
-				},
-			},
-		}
-
-		// Try to resubscribe
-		newSub, err := s.client.SubscribeFilterLogs(ctx, query, logs)
-		if err == nil {
-			// Store the new subscription
-			s.mu.Lock()
-			s.subs[subID] = newSub
-			s.mu.Unlock()
-			s.logger.Debug().Msg("Successfully resubscribed to settlement events")
-			return newSub, nil
-		}
-
-		// If we reach here, resubscription failed
-		backoffTime := time.Duration(1<<attempt) * time.Second
-		if backoffTime > 30*time.Second {
-			backoffTime = 30 * time.Second
-		}
-		s.logger.Debug().
-			Int("attempt", attempt+1).
-			Int("max_attempts", maxRetries).
-			Err(err).
-			Dur("backoff_time", backoffTime).
-			Msg("Settlement service resubscription attempt failed")
-
-		select {
-		case <-time.After(backoffTime):
-			// Continue with next retry
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		}
-	}
-
-	return nil, fmt.Errorf("failed to resubscribe to settlement events after %d attempts", maxRetries)
-}
-
-func (s *SettlementService) processLog(ctx context.Context, vLog types.Log) error {
+// storeLog stores the settlement of a confirmed log, it fails with errIntentNotIndexed if its intent isn't indexed
+func (s *SettlementService) storeLog(ctx context.Context, vLog types.Log) error {
 	if err := s.validateLog(vLog); err != nil {
 		return err
 	}
@@ -289,13 +166,16 @@ func (s *SettlementService) processLog(ctx context.Context, vLog types.Log) erro
 
 	// Get related intent to associate with settlement
 	intent, err := s.db.GetIntent(ctx, event.IntentID)
+	if errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("%w: %s", errIntentNotIndexed, event.IntentID)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get intent: %v", err)
+		return fmt.Errorf("failed to get intent: %w", err)
 	}
 
 	// Important: Use the destination chain client for settlement events
 	// Settlement events happen on the destination chain
-	var client *ethclient.Client
+	var client evm.Client
 	if s.clientResolver != nil && intent.DestinationChain != 0 {
 		// Try to get the destination chain client
 		destClient, err := s.clientResolver.GetClient(intent.DestinationChain)
@@ -323,8 +203,31 @@ func (s *SettlementService) processLog(ctx context.Context, vLog types.Log) erro
 			Msg("Using client for different chain to fetch timestamp for settlement event")
 	}
 
-	// Process the event
-	return s.CreateSettlement(ctx, settlement)
+	// Save the settlement with the intent status change, its outbox message and the checkpoint atomically
+	err = s.db.WithTx(ctx, func(tx db.Database) error {
+		if err := s.createSettlement(ctx, tx, settlement); err != nil {
+			return err
+		}
+		if s.backfill {
+			return nil
+		}
+		return advanceCheckpoint(ctx, tx, s.chainID, eventTypeSettlement, vLog)
+	})
+	if errors.Is(err, db.ErrDuplicate) {
+		s.logger.Debug().
+			Str(logging.FieldIntent, settlement.ID).
+			Msg("Skipping duplicate settlement")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	notifyEvents(s.events)
+
+	s.reorg.RecordLog(ctx, s.chainID, vLog)
+
+	return nil
 }
 
 func (s *SettlementService) validateLog(vLog types.Log) error {
@@ -402,8 +305,12 @@ func (s *SettlementService) extractEventData(vLog types.Log) (*models.IntentSett
 		Fulfiller:    fulfiller,
 		ActualAmount: actualAmount,
 		PaidTip:      paidTip,
+		ChainID:      s.chainID,
 		BlockNumber:  vLog.BlockNumber,
+		BlockHash:    vLog.BlockHash.Hex(),
 		TxHash:       vLog.TxHash.Hex(),
+		LogIndex:     vLog.Index,
+		Contract:     vLog.Address.Hex(),
 		IsCall:       isCallSettlement,
 	}
 
@@ -425,7 +332,7 @@ func (s *SettlementService) extractEventData(vLog types.Log) (*models.IntentSett
 func (s *SettlementService) GetSettlement(ctx context.Context, id string) (*models.Settlement, error) {
 	settlement, err := s.db.GetSettlement(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get settlement: %v", err)
+		return nil, fmt.Errorf("failed to get settlement: %w", err)
 	}
 
 	return settlement, nil
@@ -447,7 +354,7 @@ func (s *SettlementService) CreateSettlement(ctx context.Context, settlement *mo
 	existingSettlement, err := s.db.GetSettlement(ctx, settlement.ID)
 	if err != nil {
 		if !errors.Is(err, db.ErrNotFound) {
-			return fmt.Errorf("failed to check for existing settlement: %v", err)
+			return fmt.Errorf("failed to check for existing settlement: %w", err)
 		}
 	} else if existingSettlement != nil {
 		s.logger.Debug().
@@ -456,81 +363,35 @@ func (s *SettlementService) CreateSettlement(ctx context.Context, settlement *mo
 		return nil
 	}
 
-	// Create the settlement
-	if err := s.db.CreateSettlement(ctx, settlement); err != nil {
-		return fmt.Errorf("failed to create settlement: %v", err)
+	// Create the settlement with the intent status change
+	err = s.db.WithTx(ctx, func(tx db.Database) error {
+		return s.createSettlement(ctx, tx, settlement)
+	})
+	if err != nil {
+		return err
 	}
 
-	// Update intent status
-	if err := s.db.UpdateIntentStatus(ctx, settlement.ID, models.IntentStatusSettled); err != nil {
-		return fmt.Errorf("failed to update intent status: %v", err)
-	}
+	notifyEvents(s.events)
 
 	return nil
 }
 
-// GetSubscriptionCount returns the number of active subscriptions
-func (s *SettlementService) GetSubscriptionCount() int {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	return len(s.subs)
-}
-
-// Restart properly restarts the service by shutting down existing goroutines and starting new ones
-func (s *SettlementService) Restart(ctx context.Context, contractAddress common.Address) error {
-	s.logger.Info().Msg("Restarting settlement service...")
-
-	// Check if service is shutdown
-	if s.IsShutdown() {
-		return fmt.Errorf("cannot restart: service is shutdown")
+// createSettlement stores a settlement with the status change of its intent and its outbox message in a transaction
+func (s *SettlementService) createSettlement(ctx context.Context, tx db.Database, settlement *models.Settlement) error {
+	if err := tx.CreateSettlement(ctx, settlement); err != nil {
+		return fmt.Errorf("failed to create settlement: %w", err)
 	}
 
-	// Cancel the cleanup context to signal all existing goroutines to stop
-	s.cleanupCancel()
-
-	// Wait for existing goroutines to complete with a short timeout
-	done := make(chan struct{})
-	go func() {
-		s.goroutineWg.Wait()
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		s.logger.Debug().Msg("Existing goroutines stopped successfully")
-	case <-time.After(5 * time.Second):
-		s.logger.Warn().Msg("Timeout waiting for existing goroutines to stop")
+	transition := settlement.StatusTransition(s.chainID)
+	if err := updateIntentStatus(ctx, tx, transition, s.logger); err != nil {
+		return err
 	}
-
-	// Unsubscribe from all subscriptions
-	s.UnsubscribeAll()
-
-	// Create a new cleanup context
-	s.cleanupCtx, s.cleanupCancel = context.WithCancel(context.Background())
-
-	// Reset goroutine counter
-	atomic.StoreInt32(&s.activeGoroutines, 0)
-
-	// Start the service again
-	return s.StartListening(ctx, contractAddress)
-}
-
-// UnsubscribeAll unsubscribes from all active subscriptions
-func (s *SettlementService) UnsubscribeAll() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	s.logger.Debug().
-		Int("active_subscriptions", len(s.subs)).
-		Msg("Unsubscribing from all settlement subscriptions")
-
-	for id, sub := range s.subs {
-		sub.Unsubscribe()
-		s.logger.Debug().
-			Str("subscription_id", id).
-			Msg("Unsubscribed from settlement subscription")
-		delete(s.subs, id)
+	if transition.FromStatus == "" {
+		// the intent status didn't change, there is no event to publish
+		return nil
 	}
+
+	return enqueueIntentEvent(ctx, tx, models.OutboxTopicIntentSettled, transition)
 }
 
 // CreateCallSettlement creates a new settlement with call data
@@ -551,20 +412,20 @@ func (s *SettlementService) CreateCallSettlement(
 	intent, err := s.db.GetIntent(ctx, intentID)
 	if err != nil {
 		if errors.Is(err, db.ErrNotFound) {
-			return fmt.Errorf("intent not found: %s", intentID)
+			return errs.NotFound("intent not found: %s", intentID)
 		}
-		return fmt.Errorf("failed to get intent: %v", err)
+		return fmt.Errorf("failed to get intent: %w", err)
 	}
 
 	// Verify this is a call intent
 	if !intent.IsCall {
-		return fmt.Errorf("intent is not a call intent: %s", intentID)
+		return errs.Validation("intent is not a call intent: %s", intentID)
 	}
 
 	// Get block timestamp if available
 	var timestamp time.Time
 	if txHash != "" && strings.HasPrefix(txHash, "0x") {
-		var client *ethclient.Client
+		var client evm.Client
 		if s.clientResolver != nil && intent.DestinationChain != 0 {
 			destClient, err := s.clientResolver.GetClient(intent.DestinationChain)
 			if err == nil {
@@ -607,6 +468,7 @@ func (s *SettlementService) CreateCallSettlement(
 		Fulfiller:    fulfiller,
 		ActualAmount: actualAmount,
 		PaidTip:      paidTip,
+		ChainID:      intent.DestinationChain,
 		TxHash:       txHash,
 		CreatedAt:    timestamp,
 		UpdatedAt:    timestamp,
@@ -616,85 +478,3 @@ func (s *SettlementService) CreateCallSettlement(
 
 	return s.CreateSettlement(ctx, settlement)
 }
-
-// Shutdown gracefully shuts down the service and waits for all goroutines to complete
-func (s *SettlementService) Shutdown(timeout time.Duration) error {
-	s.shutdownMu.Lock()
-	if s.isShutdown {
-		s.shutdownMu.Unlock()
-		return nil // Already shutdown
-	}
-	s.isShutdown = true
-	s.shutdownMu.Unlock()
-
-	s.logger.Info().Msg("Shutting down SettlementService...")
-
-	// Cancel the cleanup context to signal all goroutines to stop
-	s.cleanupCancel()
-
-	// Unsubscribe from all subscriptions
-	s.UnsubscribeAll()
-
-	// Wait for all goroutines to complete with timeout
-	done := make(chan struct{})
-	go func() {
-		s.goroutineWg.Wait()
-		close(done)
-	}()
-
-	select {
-	case <-done:
-		s.logger.Info().Msg("SettlementService shutdown completed successfully")
-		return nil
-	case <-time.After(timeout):
-		s.logger.Error().
-			Dur("timeout", timeout).
-			Msg("SettlementService shutdown timed out")
-		return fmt.Errorf("shutdown timed out after %v", timeout)
-	}
-}
-
-// IsShutdown returns whether the service is in shutdown state
-func (s *SettlementService) IsShutdown() bool {
-	s.shutdownMu.RLock()
-	defer s.shutdownMu.RUnlock()
-	return s.isShutdown
-}
-
-// startGoroutine safely starts a goroutine with proper cleanup tracking
-func (s *SettlementService) startGoroutine(name string, fn func()) {
-	s.shutdownMu.RLock()
-	if s.isShutdown {
-		s.shutdownMu.RUnlock()
-		s.logger.Debug().
-			Str("goroutine_name", name).
-			Msg("Cannot start goroutine: service is shutdown")
-		return
-	}
-	s.shutdownMu.RUnlock()
-
-	s.goroutineWg.Add(1)
-	atomic.AddInt32(&s.activeGoroutines, 1)
-
-	go func() {
-		defer func() {
-			s.goroutineWg.Done()
-			atomic.AddInt32(&s.activeGoroutines, -1)
-
-			// Recover from panics
-			if r := recover(); r != nil {
-				s.logger.Error().
-					Str("goroutine_name", name).
-					Any("panic", r).
-					Msg("Panic in goroutine")
-			}
-		}()
-
-		fn()
-	}()
-}
-
-// ActiveGoroutines returns the current count of active goroutines
-func (s *SettlementService) ActiveGoroutines() int32 {
-	return atomic.LoadInt32(&s.activeGoroutines)
-}
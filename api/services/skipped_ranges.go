@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// skipProbeChunks is the number of adaptive ranges probed for events at once by the catchup
+	skipProbeChunks = 16
+
+	// auditConcurrency is the number of block headers fetched concurrently by an audit
+	auditConcurrency = 8
+
+	// DefaultAuditLimit is the number of skipped ranges of a chain audited by a run
+	DefaultAuditLimit = 100
+)
+
+// emptyRangeEnd returns the last block of the empty start of the blocks after from up to to:
+// the block before the first log matching the query, or to if there is none.
+// Ranges rejected as too large are split in halves, so no block is ever assumed empty without being queried.
+func emptyRangeEnd(
+	ctx context.Context,
+	client evm.Client,
+	query ethereum.FilterQuery,
+	from, to uint64,
+) (uint64, error) {
+	if to <= from {
+		return from, nil
+	}
+
+	query.FromBlock = big.NewInt(int64(from + 1))
+	query.ToBlock = big.NewInt(int64(to))
+
+	filterCtx, cancel := context.WithTimeout(ctx, FilterLogsTimeout)
+	logs, err := client.FilterLogs(filterCtx, query)
+	cancel()
+
+	switch {
+	case err == nil:
+		end := to
+		for _, l := range logs {
+			end = min(end, l.BlockNumber-1)
+		}
+		return max(end, from), nil
+	case evm.IsRangeTooLarge(err) && to-from > 1:
+		mid := from + (to-from)/2
+
+		end, err := emptyRangeEnd(ctx, client, query, from, mid)
+		if err != nil || end < mid {
+			return end, err
+		}
+
+		return emptyRangeEnd(ctx, client, query, mid, to)
+	default:
+		return from, err
+	}
+}
+
+// SkipAuditor audits the block ranges the catchup skipped as empty. It checks the logs bloom of
+// every block header in a range, independently of the FilterLogs queries that skipped it,
+// and fetches the logs of the blocks matching the bloom. Blocks with events are reported as misses
+// to re-index with a backfill.
+type SkipAuditor struct {
+	intentServices map[uint64]*IntentService
+	db             db.Database
+	logger         zerolog.Logger
+}
+
+// NewSkipAuditor creates a new SkipAuditor instance
+func NewSkipAuditor(
+	intentServices map[uint64]*IntentService,
+	database db.Database,
+	logger zerolog.Logger,
+) *SkipAuditor {
+	return &SkipAuditor{
+		intentServices: intentServices,
+		db:             database,
+		logger:         logger.With().Str(logging.FieldModule, "skip_audit").Logger(),
+	}
+}
+
+// Audit verifies up to limit skipped ranges of a chain not audited yet.
+// Returns the audited ranges with their missed blocks.
+func (a *SkipAuditor) Audit(ctx context.Context, chainID uint64, limit int) ([]*models.SkippedRange, error) {
+	ranges, err := a.db.ListUnverifiedSkippedRanges(ctx, chainID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skipped ranges: %w", err)
+	}
+
+	audited := make([]*models.SkippedRange, 0, len(ranges))
+	for _, skipped := range ranges {
+		missed, err := a.auditRange(ctx, skipped)
+		if err != nil {
+			return audited, fmt.Errorf("failed to audit skipped range %d-%d: %w", skipped.FromBlock, skipped.ToBlock, err)
+		}
+
+		if err := a.db.MarkSkippedRangeVerified(ctx, skipped.ID, missed); err != nil {
+			return audited, fmt.Errorf("failed to record audit of skipped range %d-%d: %w",
+				skipped.FromBlock, skipped.ToBlock, err)
+		}
+		skipped.MissedBlocks = missed
+
+		logger := a.logger.With().
+			Uint64(logging.FieldChain, chainID).
+			Str("event_type", skipped.EventType).
+			Uint64("from_block", skipped.FromBlock).
+			Uint64("to_block", skipped.ToBlock).
+			Logger()
+
+		if len(missed) > 0 {
+			logger.Error().
+				Interface("missed_blocks", missed).
+				Msg("Skipped block range has events, backfill the missed blocks")
+		} else {
+			logger.Debug().Msg("Skipped block range verified empty")
+		}
+
+		audited = append(audited, skipped)
+	}
+
+	return audited, nil
+}
+
+// auditRange returns the blocks of a skipped range with events matching its query
+func (a *SkipAuditor) auditRange(ctx context.Context, skipped *models.SkippedRange) ([]uint64, error) {
+	client, query, err := a.rangeQuery(skipped)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		missed []uint64
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(auditConcurrency)
+
+	for block := skipped.FromBlock; block <= skipped.ToBlock; block++ {
+		g.Go(func() error {
+			hasEvents, err := blockHasEvents(gctx, client, query, block)
+			if err != nil {
+				return err
+			}
+
+			if hasEvents {
+				mu.Lock()
+				missed = append(missed, block)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(missed)
+
+	return missed, nil
+}
+
+// rangeQuery returns the client and the query of the events of a skipped range
+func (a *SkipAuditor) rangeQuery(skipped *models.SkippedRange) (evm.Client, ethereum.FilterQuery, error) {
+	switch skipped.EventType {
+	case eventTypeIntent:
+		service, ok := a.intentServices[skipped.ChainID]
+		if !ok {
+			return nil, ethereum.FilterQuery{}, fmt.Errorf("no intent service for chain %d", skipped.ChainID)
+		}
+		return service.client, service.filterQuery(common.HexToAddress(skipped.ContractAddress)), nil
+	default:
+		return nil, ethereum.FilterQuery{}, fmt.Errorf("skipped %s ranges can't be audited", skipped.EventType)
+	}
+}
+
+// blockHasEvents checks the logs bloom of a block header and confirms a match with the logs of the block,
+// blooms have false positives but no false negatives
+func blockHasEvents(ctx context.Context, client evm.Client, query ethereum.FilterQuery, block uint64) (bool, error) {
+	number := new(big.Int).SetUint64(block)
+
+	headerCtx, cancel := context.WithTimeout(ctx, FilterLogsTimeout)
+	header, err := client.HeaderByNumber(headerCtx, number)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("failed to get header of block %d: %w", block, err)
+	}
+
+	if !bloomMatches(header.Bloom, query) {
+		return false, nil
+	}
+
+	query.FromBlock = number
+	query.ToBlock = number
+
+	filterCtx, cancel := context.WithTimeout(ctx, FilterLogsTimeout)
+	logs, err := client.FilterLogs(filterCtx, query)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("failed to get logs of block %d: %w", block, err)
+	}
+
+	return len(logs) > 0, nil
+}
+
+// bloomMatches returns whether a logs bloom may contain a log matching the query
+func bloomMatches(bloom types.Bloom, query ethereum.FilterQuery) bool {
+	if len(query.Addresses) > 0 && !slices.ContainsFunc(query.Addresses, func(address common.Address) bool {
+		return types.BloomLookup(bloom, address)
+	}) {
+		return false
+	}
+
+	for _, topics := range query.Topics {
+		if len(topics) > 0 && !slices.ContainsFunc(topics, func(topic common.Hash) bool {
+			return types.BloomLookup(bloom, topic)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var skipTestContract = common.HexToAddress("0x951AB2A5417a51eB5810aC44BC1fC716995C1CAB")
+
+func TestEmptyRangeEnd(t *testing.T) {
+	t.Run("whole range is empty", func(t *testing.T) {
+		client := &eventBlocksClient{}
+
+		end, err := emptyRangeEnd(context.Background(), client, ethereum.FilterQuery{}, 100, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1000), end)
+	})
+
+	t.Run("ends before the first event", func(t *testing.T) {
+		client := &eventBlocksClient{events: []uint64{700, 350}}
+
+		end, err := emptyRangeEnd(context.Background(), client, ethereum.FilterQuery{}, 100, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(349), end)
+	})
+
+	t.Run("event right after the start", func(t *testing.T) {
+		client := &eventBlocksClient{events: []uint64{101}}
+
+		end, err := emptyRangeEnd(context.Background(), client, ethereum.FilterQuery{}, 100, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), end)
+	})
+
+	t.Run("splits ranges rejected as too large", func(t *testing.T) {
+		// the sampling of a few blocks used to miss events like this one
+		client := &eventBlocksClient{events: []uint64{613}, maxRange: 300}
+
+		end, err := emptyRangeEnd(context.Background(), client, ethereum.FilterQuery{}, 0, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(612), end)
+	})
+
+	t.Run("returns other errors", func(t *testing.T) {
+		client := &eventBlocksClient{err: errors.New("connection refused")}
+
+		end, err := emptyRangeEnd(context.Background(), client, ethereum.FilterQuery{}, 100, 1000)
+		require.Error(t, err)
+		assert.Equal(t, uint64(100), end)
+	})
+}
+
+func TestCatchUpOnEvents_SkipsEmptyRanges(t *testing.T) {
+	client := &eventBlocksClient{maxRange: 400}
+	intentService := newSkipTestIntentService(t, client)
+	intentService.SetLogRange(NewLogRange(100, 10, 100))
+	intentService.SetSkipEmptyRanges(true)
+
+	database := mocks.NewDatabaseMock(t)
+	database.On("RecordSkippedRange", mock.Anything, &models.SkippedRange{
+		ChainID:         1,
+		EventType:       eventTypeIntent,
+		ContractAddress: skipTestContract.Hex(),
+		FromBlock:       1,
+		ToBlock:         1000,
+	}).Return(nil).Once()
+
+	backfill := NewBackfillService(
+		map[uint64]*IntentService{1: intentService},
+		map[uint64]*FulfillmentService{},
+		map[uint64]*SettlementService{},
+		database,
+		logging.NewTesting(t),
+	)
+
+	err := backfill.catchup.catchUpOnEvents(context.Background(), intentService.chainHandler(), skipTestContract, 0, 1000, "test")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), backfill.catchup.intentProgress[1])
+}
+
+func TestSkipAuditor_Audit(t *testing.T) {
+	client := &eventBlocksClient{events: []uint64{15}}
+	intentService := newSkipTestIntentService(t, client)
+	query := intentService.filterQuery(skipTestContract)
+
+	// block 12 matches the bloom without having events, block 15 has a missed event
+	client.blooms = map[uint64]types.Bloom{
+		12: newBloom(skipTestContract.Bytes(), query.Topics[0][0].Bytes()),
+		15: newBloom(skipTestContract.Bytes(), query.Topics[0][1].Bytes()),
+		17: newBloom(skipTestContract.Bytes()),
+	}
+
+	database := mocks.NewDatabaseMock(t)
+	database.On("ListUnverifiedSkippedRanges", mock.Anything, uint64(1), 10).Return([]*models.SkippedRange{
+		{ID: 1, ChainID: 1, EventType: eventTypeIntent, ContractAddress: skipTestContract.Hex(), FromBlock: 10, ToBlock: 20},
+		{ID: 2, ChainID: 1, EventType: eventTypeIntent, ContractAddress: skipTestContract.Hex(), FromBlock: 21, ToBlock: 30},
+	}, nil)
+	database.On("MarkSkippedRangeVerified", mock.Anything, int64(1), []uint64{15}).Return(nil).Once()
+	database.On("MarkSkippedRangeVerified", mock.Anything, int64(2), []uint64(nil)).Return(nil).Once()
+
+	auditor := NewSkipAuditor(map[uint64]*IntentService{1: intentService}, database, logging.NewTesting(t))
+
+	audited, err := auditor.Audit(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, audited, 2)
+	assert.Equal(t, []uint64{15}, audited[0].MissedBlocks)
+	assert.Empty(t, audited[1].MissedBlocks)
+
+	// only the blocks matching the bloom are queried for logs
+	assert.Equal(t, []uint64{1, 1}, client.ranges)
+}
+
+func TestBloomMatches(t *testing.T) {
+	topic := common.HexToHash("0x01")
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{skipTestContract},
+		Topics:    [][]common.Hash{{topic}},
+	}
+
+	assert.True(t, bloomMatches(newBloom(skipTestContract.Bytes(), topic.Bytes()), query))
+	assert.False(t, bloomMatches(newBloom(skipTestContract.Bytes()), query))
+	assert.False(t, bloomMatches(newBloom(topic.Bytes()), query))
+	assert.True(t, bloomMatches(types.Bloom{}, ethereum.FilterQuery{}))
+}
+
+func newSkipTestIntentService(t *testing.T, client evm.Client) *IntentService {
+	intentService, err := NewIntentService(
+		client,
+		mocks.NewClientResolverMock(t),
+		mocks.NewDatabaseMock(t),
+		config.IntentInitiatedEventABI,
+		1,
+		logging.NewTesting(t),
+	)
+	require.NoError(t, err)
+
+	return intentService
+}
+
+func newBloom(values ...[]byte) types.Bloom {
+	var bloom types.Bloom
+	for _, value := range values {
+		bloom.Add(value)
+	}
+	return bloom
+}
+
+// eventBlocksClient answers FilterLogs with a log for each event block in the range
+// and rejects queries over maxRange blocks like hosted providers do
+type eventBlocksClient struct {
+	evm.Client
+
+	mu       sync.Mutex
+	events   []uint64
+	blooms   map[uint64]types.Bloom
+	maxRange uint64
+	err      error
+	ranges   []uint64
+}
+
+func (c *eventBlocksClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	c.ranges = append(c.ranges, to-from+1)
+
+	switch {
+	case c.err != nil:
+		return nil, c.err
+	case c.maxRange > 0 && to-from+1 > c.maxRange:
+		return nil, errors.New("block range too large")
+	}
+
+	var logs []types.Log
+	for _, block := range c.events {
+		if block >= from && block <= to {
+			logs = append(logs, types.Log{BlockNumber: block})
+		}
+	}
+	return logs, nil
+}
+
+func (c *eventBlocksClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Bloom: c.blooms[number.Uint64()]}, nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+)
+
+const (
+	// StreamPollInterval is how often new intent events are fetched when no service notified the broker,
+	// e.g. for events persisted by another instance
+	StreamPollInterval = 2 * time.Second
+
+	// StreamBatchSize is the maximum number of intent events fetched from the database at once
+	StreamBatchSize = 500
+
+	// StreamSubscriberBuffer is the number of events buffered per subscriber,
+	// subscribers falling further behind are dropped and have to resume from their cursor
+	StreamSubscriberBuffer = 256
+)
+
+// ErrStreamNotReady is returned when subscribing before the broker loaded its initial cursor
+var ErrStreamNotReady = errs.New(errs.KindUnavailable, "intent event stream is not ready")
+
+// EventNotifier is notified when intent events were persisted
+type EventNotifier interface {
+	Notify()
+}
+
+// EventNotifiers notifies every notifier of the list
+type EventNotifiers []EventNotifier
+
+// Notify implements EventNotifier
+func (n EventNotifiers) Notify() {
+	for _, notifier := range n {
+		notifier.Notify()
+	}
+}
+
+func notifyEvents(notifier EventNotifier) {
+	if notifier != nil {
+		notifier.Notify()
+	}
+}
+
+// IntentEventBroker publishes the intent status changes recorded in the database to stream subscribers.
+// Services notify the broker after persisting an event, the broker also polls to catch changes made elsewhere.
+type IntentEventBroker struct {
+	db     db.Database
+	notify chan struct{}
+	logger zerolog.Logger
+
+	mu          sync.Mutex
+	ready       bool
+	cursor      int64 // cursor of the last published event
+	subscribers map[*IntentEventSubscription]struct{}
+}
+
+// IntentEventSubscription receives the intent events matching its filter
+type IntentEventSubscription struct {
+	broker *IntentEventBroker
+	filter models.IntentEventFilter
+	start  int64 // broker cursor when subscribing, live events are after it
+	events chan *models.IntentEvent
+	once   sync.Once
+}
+
+// NewIntentEventBroker creates a new IntentEventBroker instance
+func NewIntentEventBroker(database db.Database, logger zerolog.Logger) *IntentEventBroker {
+	return &IntentEventBroker{
+		db:          database,
+		notify:      make(chan struct{}, 1),
+		subscribers: make(map[*IntentEventSubscription]struct{}),
+		logger:      logger.With().Str(logging.FieldModule, "stream").Logger(),
+	}
+}
+
+// Notify wakes the broker up to publish newly persisted events
+func (b *IntentEventBroker) Notify() {
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes new intent events until the context is done
+func (b *IntentEventBroker) Run(ctx context.Context) {
+	ticker := time.NewTicker(StreamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.publish(ctx); err != nil {
+			b.logger.Error().Err(err).Msg("Failed to publish intent events")
+		}
+
+		select {
+		case <-ctx.Done():
+			b.closeAll()
+			return
+		case <-b.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish fans the events dispatched from the outbox after the broker cursor out to the subscribers
+func (b *IntentEventBroker) publish(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.ready {
+		// only events recorded from now on are published live
+		cursor, err := b.db.GetLatestIntentEventCursor(ctx)
+		if err != nil {
+			return err
+		}
+		b.cursor = cursor
+		b.ready = true
+		return nil
+	}
+
+	for {
+		events, err := b.db.ListIntentEvents(ctx, b.cursor, StreamBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			for sub := range b.subscribers {
+				if !sub.filter.Matches(event) {
+					continue
+				}
+
+				select {
+				case sub.events <- event:
+				default:
+					b.logger.Warn().Int64("cursor", event.Cursor).Msg("Dropping slow stream subscriber")
+					b.remove(sub)
+				}
+			}
+			b.cursor = event.Cursor
+		}
+
+		if len(events) < StreamBatchSize {
+			return nil
+		}
+	}
+}
+
+// Subscribe registers a subscriber receiving the events published from now on
+func (b *IntentEventBroker) Subscribe(filter models.IntentEventFilter) (*IntentEventSubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.ready {
+		return nil, ErrStreamNotReady
+	}
+
+	sub := &IntentEventSubscription{
+		broker: b,
+		filter: filter,
+		start:  b.cursor,
+		events: make(chan *models.IntentEvent, StreamSubscriberBuffer),
+	}
+	b.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// remove unregisters a subscriber and closes its channel, the caller must hold the lock
+func (b *IntentEventBroker) remove(sub *IntentEventSubscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+func (b *IntentEventBroker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// Events returns the channel of live events, it's closed when the subscriber is dropped
+func (s *IntentEventSubscription) Events() <-chan *models.IntentEvent {
+	return s.events
+}
+
+// Replay calls fn for every matching event dispatched after the cursor that was published
+// before the subscription started, so that a resumed stream has no gap with the live events
+func (s *IntentEventSubscription) Replay(
+	ctx context.Context,
+	cursor int64,
+	fn func(event *models.IntentEvent) error,
+) error {
+	for cursor < s.start {
+		events, err := s.broker.db.ListIntentEvents(ctx, cursor, StreamBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, event := range events {
+			if event.Cursor > s.start {
+				return nil
+			}
+
+			cursor = event.Cursor
+
+			if !s.filter.Matches(event) {
+				continue
+			}
+
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close unregisters the subscription
+func (s *IntentEventSubscription) Close() {
+	s.once.Do(func() {
+		s.broker.mu.Lock()
+		defer s.broker.mu.Unlock()
+		s.broker.remove(s)
+	})
+}
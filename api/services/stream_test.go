@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntentEventBroker(t *testing.T) {
+	const (
+		alice = "0x1111111111111111111111111111111111111111"
+		bob   = "0x2222222222222222222222222222222222222222"
+	)
+
+	event := func(cursor int64, sender string) *models.IntentEvent {
+		return &models.IntentEvent{
+			Cursor: cursor,
+			Type:   models.IntentEventCreated,
+			Status: models.IntentStatusPending,
+			Sender: sender,
+		}
+	}
+
+	newReadyBroker := func(t *testing.T, cursor int64) (*IntentEventBroker, *mocks.DatabaseMock) {
+		mockDB := mocks.NewDatabaseMock(t)
+		broker := NewIntentEventBroker(mockDB, logging.NewTesting(t))
+
+		mockDB.On("GetLatestIntentEventCursor", mock.Anything).Return(cursor, nil).Once()
+		require.NoError(t, broker.publish(context.Background()))
+
+		return broker, mockDB
+	}
+
+	t.Run("not ready before the first publish", func(t *testing.T) {
+		broker := NewIntentEventBroker(mocks.NewDatabaseMock(t), logging.NewTesting(t))
+
+		_, err := broker.Subscribe(models.IntentEventFilter{})
+		require.ErrorIs(t, err, ErrStreamNotReady)
+	})
+
+	t.Run("publishes matching events", func(t *testing.T) {
+		broker, mockDB := newReadyBroker(t, 10)
+
+		sub, err := broker.Subscribe(models.IntentEventFilter{Sender: alice})
+		require.NoError(t, err)
+		defer sub.Close()
+
+		mockDB.On("ListIntentEvents", mock.Anything, int64(10), StreamBatchSize).
+			Return([]*models.IntentEvent{event(11, bob), event(12, alice)}, nil).
+			Once()
+
+		require.NoError(t, broker.publish(context.Background()))
+
+		require.Len(t, sub.Events(), 1)
+		assert.Equal(t, int64(12), (<-sub.Events()).Cursor)
+		assert.Equal(t, int64(12), broker.cursor)
+	})
+
+	t.Run("replays events up to the subscription start", func(t *testing.T) {
+		broker, mockDB := newReadyBroker(t, 3)
+
+		sub, err := broker.Subscribe(models.IntentEventFilter{Sender: alice})
+		require.NoError(t, err)
+		defer sub.Close()
+
+		mockDB.On("ListIntentEvents", mock.Anything, int64(1), StreamBatchSize).
+			Return([]*models.IntentEvent{event(2, alice), event(3, bob), event(4, alice)}, nil).
+			Once()
+
+		var replayed []int64
+		err = sub.Replay(context.Background(), 1, func(e *models.IntentEvent) error {
+			replayed = append(replayed, e.Cursor)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2}, replayed)
+	})
+
+	t.Run("drops slow subscribers", func(t *testing.T) {
+		broker, mockDB := newReadyBroker(t, 0)
+
+		sub, err := broker.Subscribe(models.IntentEventFilter{})
+		require.NoError(t, err)
+
+		events := make([]*models.IntentEvent, StreamSubscriberBuffer+1)
+		for i := range events {
+			events[i] = event(int64(i+1), alice)
+		}
+
+		mockDB.On("ListIntentEvents", mock.Anything, int64(0), StreamBatchSize).Return(events, nil).Once()
+
+		require.NoError(t, broker.publish(context.Background()))
+
+		received := 0
+		for range sub.Events() {
+			received++
+		}
+		assert.Equal(t, StreamSubscriberBuffer, received)
+		assert.Empty(t, broker.subscribers)
+
+		// closing a dropped subscription is a no-op
+		sub.Close()
+	})
+}
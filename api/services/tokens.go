@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/utils"
+)
+
+const (
+	// TokenLookupTimeout bounds the ERC-20 calls looking up a token
+	TokenLookupTimeout = 5 * time.Second
+
+	// TokenLookupRetryInterval is how long a token whose lookup failed is left unknown before it's looked up again
+	TokenLookupRetryInterval = 10 * time.Minute
+)
+
+type tokenKey struct {
+	chainID uint64
+	address string
+}
+
+// TokenRegistry knows the symbol and decimals of the tokens of every chain. Tokens are loaded from the chain
+// registry, the others are looked up with the ERC-20 symbol() and decimals() calls when first seen.
+type TokenRegistry struct {
+	clients map[uint64]evm.Client
+	abi     abi.ABI
+	logger  zerolog.Logger
+
+	mu       sync.RWMutex
+	tokens   map[tokenKey]*models.Token
+	failures map[tokenKey]time.Time
+}
+
+// NewTokenRegistry creates a new TokenRegistry instance with the tokens of the chain configs
+func NewTokenRegistry(
+	chains map[uint64]*config.ChainConfig,
+	clients map[uint64]evm.Client,
+	logger zerolog.Logger,
+) (*TokenRegistry, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(config.ERC20MetadataABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-20 ABI: %v", err)
+	}
+
+	r := &TokenRegistry{
+		clients:  clients,
+		abi:      parsedABI,
+		logger:   logger.With().Str(logging.FieldModule, "tokens").Logger(),
+		tokens:   make(map[tokenKey]*models.Token),
+		failures: make(map[tokenKey]time.Time),
+	}
+
+	for chainID, chain := range chains {
+		for _, entry := range chain.Tokens {
+			maxAmount := utils.MaxAmount(int(entry.Decimals))
+			if entry.MaxAmount != "" {
+				if maxAmount, err = utils.ParseUnits(entry.MaxAmount, int(entry.Decimals)); err != nil {
+					return nil, fmt.Errorf("invalid max amount of token %s of chain %d: %v", entry.Address, chainID, err)
+				}
+			}
+
+			r.tokens[newTokenKey(chainID, entry.Address)] = &models.Token{
+				Address:   common.HexToAddress(entry.Address).Hex(),
+				Symbol:    entry.Symbol,
+				Decimals:  int(entry.Decimals),
+				ChainID:   chainID,
+				LogoURL:   entry.LogoURL,
+				AssetID:   entry.Asset,
+				MaxAmount: maxAmount,
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func newTokenKey(chainID uint64, address string) tokenKey {
+	return tokenKey{chainID: chainID, address: strings.ToLower(address)}
+}
+
+// Lookup returns a token of a chain, looking it up on the chain if it isn't known yet
+func (r *TokenRegistry) Lookup(ctx context.Context, chainID uint64, address string) (*models.Token, error) {
+	key := newTokenKey(chainID, address)
+
+	r.mu.RLock()
+	token, ok := r.tokens[key]
+	failedAt, failed := r.failures[key]
+	r.mu.RUnlock()
+
+	switch {
+	case ok:
+		return token, nil
+	case failed && time.Since(failedAt) < TokenLookupRetryInterval:
+		return nil, fmt.Errorf("token %s of chain %d is unknown", address, chainID)
+	case !common.IsHexAddress(address):
+		return nil, fmt.Errorf("invalid token address: %s", address)
+	}
+
+	token, err := r.fetch(ctx, chainID, common.HexToAddress(address))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		// the lookups failing because the request was canceled are retried on the next request
+		if ctx.Err() == nil {
+			r.failures[key] = time.Now()
+		}
+		return nil, fmt.Errorf("failed to look up token %s of chain %d: %v", address, chainID, err)
+	}
+
+	delete(r.failures, key)
+	r.tokens[key] = token
+
+	r.logger.Info().
+		Uint64(logging.FieldChain, chainID).
+		Str("token", token.Address).
+		Str("symbol", token.Symbol).
+		Int("decimals", token.Decimals).
+		Msg("Looked up token")
+
+	return token, nil
+}
+
+// fetch calls the ERC-20 symbol and decimals functions of a token
+func (r *TokenRegistry) fetch(ctx context.Context, chainID uint64, address common.Address) (*models.Token, error) {
+	client, ok := r.clients[chainID]
+	if !ok {
+		return nil, fmt.Errorf("no client for chain %d", chainID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, TokenLookupTimeout)
+	defer cancel()
+
+	symbolData, err := r.call(ctx, client, address, "symbol")
+	if err != nil {
+		return nil, err
+	}
+
+	var symbol string
+	if err := r.abi.UnpackIntoInterface(&symbol, "symbol", symbolData); err != nil {
+		// some early tokens return their symbol as bytes32
+		if len(symbolData) != 32 {
+			return nil, fmt.Errorf("failed to decode symbol: %v", err)
+		}
+		symbol = strings.TrimRight(string(symbolData), "\x00")
+	}
+
+	decimalsData, err := r.call(ctx, client, address, "decimals")
+	if err != nil {
+		return nil, err
+	}
+
+	var decimals uint8
+	if err := r.abi.UnpackIntoInterface(&decimals, "decimals", decimalsData); err != nil {
+		return nil, fmt.Errorf("failed to decode decimals: %v", err)
+	}
+
+	return &models.Token{
+		Address:   address.Hex(),
+		Symbol:    symbol,
+		Decimals:  int(decimals),
+		ChainID:   chainID,
+		MaxAmount: utils.MaxAmount(int(decimals)),
+	}, nil
+}
+
+func (r *TokenRegistry) call(ctx context.Context, client evm.Client, address common.Address, method string) ([]byte, error) {
+	data, err := r.abi.Pack(method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %v", method, err)
+	}
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %v", method, err)
+	}
+
+	return result, nil
+}
+
+// MaxAmount returns the largest amount of an intent of a token in base units,
+// nil if the token can't be looked up or the registry is nil
+func (r *TokenRegistry) MaxAmount(ctx context.Context, chainID uint64, address string) *big.Int {
+	token := r.find(ctx, chainID, address)
+	if token == nil {
+		return nil
+	}
+
+	return token.MaxAmount
+}
+
+// find returns a token of a chain, nil if it can't be looked up
+func (r *TokenRegistry) find(ctx context.Context, chainID uint64, address string) *models.Token {
+	if r == nil || address == "" {
+		return nil
+	}
+
+	token, err := r.Lookup(ctx, chainID, address)
+	if err != nil {
+		r.logger.Debug().Err(err).Uint64(logging.FieldChain, chainID).Str("token", address).Msg("Unknown token")
+		return nil
+	}
+
+	return token
+}
+
+// AnnotateIntents sets the token and formatted amounts of the intents and their fills
+func (r *TokenRegistry) AnnotateIntents(ctx context.Context, intents ...*models.Intent) {
+	for _, intent := range intents {
+		if token := r.find(ctx, intent.SourceChain, intent.Token); token != nil {
+			intent.TokenInfo = token
+			intent.AmountFormatted = formatAmount(intent.Amount, token)
+			intent.IntentFeeFormatted = formatAmount(intent.IntentFee, token)
+		}
+
+		r.AnnotateFulfillments(ctx, intent.Fulfillments...)
+	}
+}
+
+// AnnotateFulfillments sets the asset and formatted amount of the fulfillments
+func (r *TokenRegistry) AnnotateFulfillments(ctx context.Context, fulfillments ...*models.Fulfillment) {
+	for _, f := range fulfillments {
+		if token := r.find(ctx, f.ChainID, f.Asset); token != nil {
+			f.AssetInfo = token
+			f.AmountFormatted = formatAmount(f.Amount, token)
+		}
+	}
+}
+
+// AnnotateSettlements sets the asset and formatted amounts of the settlements
+func (r *TokenRegistry) AnnotateSettlements(ctx context.Context, settlements ...*models.Settlement) {
+	for _, settlement := range settlements {
+		if token := r.find(ctx, settlement.ChainID, settlement.Asset); token != nil {
+			settlement.AssetInfo = token
+			settlement.AmountFormatted = formatAmount(settlement.Amount, token)
+			settlement.ActualAmountFormatted = formatAmount(settlement.ActualAmount, token)
+			settlement.PaidTipFormatted = formatAmount(settlement.PaidTip, token)
+		}
+	}
+}
+
+// formatAmount formats an amount of base units in whole tokens, empty if the amount isn't an integer
+func formatAmount(amount string, token *models.Token) string {
+	formatted, err := utils.FormatUnits(amount, token.Decimals)
+	if err != nil {
+		return ""
+	}
+
+	return formatted
+}
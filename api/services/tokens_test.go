@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
+	"github.com/speedrun-hq/speedrun/api/config"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokensTestUSDC = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	tokensTestWETH = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	tokensTestMKR  = "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"
+)
+
+func newTestTokenRegistry(t *testing.T, client *erc20Client) *TokenRegistry {
+	registry, err := NewTokenRegistry(map[uint64]*config.ChainConfig{
+		1: {Tokens: []config.TokenEntry{
+			{Address: tokensTestUSDC, Symbol: "USDC", Decimals: 6, Asset: "usdc", MaxAmount: "1000000"},
+		}},
+	}, map[uint64]evm.Client{1: client}, logging.NewTesting(t))
+	require.NoError(t, err)
+
+	return registry
+}
+
+func TestTokenRegistry_Lookup(t *testing.T) {
+	t.Run("configured token", func(t *testing.T) {
+		client := &erc20Client{}
+		registry := newTestTokenRegistry(t, client)
+
+		token, err := registry.Lookup(context.Background(), 1, "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+		require.NoError(t, err)
+		assert.Equal(t, &models.Token{
+			Address:   tokensTestUSDC,
+			Symbol:    "USDC",
+			Decimals:  6,
+			ChainID:   1,
+			AssetID:   "usdc",
+			MaxAmount: big.NewInt(1_000_000_000_000),
+		}, token)
+		assert.Zero(t, client.callCount())
+	})
+
+	t.Run("looked up on the chain", func(t *testing.T) {
+		client := &erc20Client{symbol: "WETH", decimals: 18}
+		registry := newTestTokenRegistry(t, client)
+
+		token, err := registry.Lookup(context.Background(), 1, tokensTestWETH)
+		require.NoError(t, err)
+		assert.Equal(t, "WETH", token.Symbol)
+		assert.Equal(t, 18, token.Decimals)
+		assert.Equal(t, tokensTestWETH, token.Address)
+		assert.Equal(t, 0, token.MaxAmount.Cmp(new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)))
+
+		// the token is cached
+		_, err = registry.Lookup(context.Background(), 1, tokensTestWETH)
+		require.NoError(t, err)
+		assert.Equal(t, 2, client.callCount())
+	})
+
+	t.Run("bytes32 symbol", func(t *testing.T) {
+		client := &erc20Client{symbol: "MKR", decimals: 18, bytes32Symbol: true}
+		registry := newTestTokenRegistry(t, client)
+
+		token, err := registry.Lookup(context.Background(), 1, tokensTestMKR)
+		require.NoError(t, err)
+		assert.Equal(t, "MKR", token.Symbol)
+	})
+
+	t.Run("failed lookups aren't retried right away", func(t *testing.T) {
+		client := &erc20Client{err: errors.New("execution reverted")}
+		registry := newTestTokenRegistry(t, client)
+
+		_, err := registry.Lookup(context.Background(), 1, tokensTestWETH)
+		require.ErrorContains(t, err, "execution reverted")
+
+		_, err = registry.Lookup(context.Background(), 1, tokensTestWETH)
+		require.ErrorContains(t, err, "is unknown")
+		assert.Equal(t, 1, client.callCount())
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		registry := newTestTokenRegistry(t, &erc20Client{})
+
+		_, err := registry.Lookup(context.Background(), 10, tokensTestWETH)
+		require.ErrorContains(t, err, "no client for chain 10")
+	})
+}
+
+func TestTokenRegistry_Annotate(t *testing.T) {
+	registry := newTestTokenRegistry(t, &erc20Client{err: errors.New("execution reverted")})
+	ctx := context.Background()
+
+	t.Run("intents", func(t *testing.T) {
+		intent := &models.Intent{
+			SourceChain: 1,
+			Token:       tokensTestUSDC,
+			Amount:      "1500000",
+			IntentFee:   "500",
+			Fulfillments: []*models.Fulfillment{
+				{ChainID: 1, Asset: tokensTestUSDC, Amount: "1000000"},
+			},
+		}
+
+		registry.AnnotateIntents(ctx, intent)
+
+		assert.Equal(t, "USDC", intent.TokenInfo.Symbol)
+		assert.Equal(t, "1.5", intent.AmountFormatted)
+		assert.Equal(t, "0.0005", intent.IntentFeeFormatted)
+		assert.Equal(t, "1", intent.Fulfillments[0].AmountFormatted)
+	})
+
+	t.Run("settlements", func(t *testing.T) {
+		settlement := &models.Settlement{
+			ChainID:      1,
+			Asset:        tokensTestUSDC,
+			Amount:       "2000000",
+			ActualAmount: "1999990",
+			PaidTip:      "10",
+		}
+
+		registry.AnnotateSettlements(ctx, settlement)
+
+		assert.Equal(t, "2", settlement.AmountFormatted)
+		assert.Equal(t, "1.99999", settlement.ActualAmountFormatted)
+		assert.Equal(t, "0.00001", settlement.PaidTipFormatted)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		fulfillment := &models.Fulfillment{ChainID: 1, Asset: tokensTestWETH, Amount: "1"}
+
+		registry.AnnotateFulfillments(ctx, fulfillment)
+
+		assert.Nil(t, fulfillment.AssetInfo)
+		assert.Empty(t, fulfillment.AmountFormatted)
+	})
+}
+
+func TestIntentService_ValidatesTokenLimit(t *testing.T) {
+	intentService := &IntentService{chainID: 1}
+	intentService.SetTokenRegistry(newTestTokenRegistry(t, &erc20Client{}))
+
+	// above the limit of 1 million USDC
+	_, err := intentService.CreateIntent(
+		context.Background(),
+		"0x1234567890123456789012345678901234567890123456789012345678901234",
+		1,
+		2,
+		tokensTestUSDC,
+		"1000000000001",
+		"0x9876543210987654321098765432109876543210",
+		"0x5678901234567890123456789012345678901234",
+		"1000",
+	)
+	require.ErrorContains(t, err, "amount exceeds maximum limit")
+	assert.Equal(t, errs.KindValidation, errs.KindOf(err))
+}
+
+// erc20Client answers the symbol and decimals calls of any token
+type erc20Client struct {
+	evm.Client
+
+	symbol        string
+	bytes32Symbol bool
+	decimals      uint8
+	err           error
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *erc20Client) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	erc20, err := abi.JSON(strings.NewReader(config.ERC20MetadataABI))
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := erc20.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case method.Name == "symbol" && c.bytes32Symbol:
+		return common.RightPadBytes([]byte(c.symbol), 32), nil
+	case method.Name == "symbol":
+		return method.Outputs.Pack(c.symbol)
+	default:
+		return method.Outputs.Pack(c.decimals)
+	}
+}
+
+func (c *erc20Client) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
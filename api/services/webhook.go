@@ -0,0 +1,451 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/speedrun-hq/speedrun/api/db"
+	"github.com/speedrun-hq/speedrun/api/errs"
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/utils"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// WebhookPollInterval is how often pending deliveries are checked when no service notified new events
+	WebhookPollInterval = 5 * time.Second
+
+	// WebhookBatchSize is the maximum number of deliveries claimed at once
+	WebhookBatchSize = 100
+
+	// WebhookConcurrency is the number of deliveries attempted in parallel
+	WebhookConcurrency = 8
+
+	// WebhookRequestTimeout bounds a single delivery attempt
+	WebhookRequestTimeout = 10 * time.Second
+
+	// WebhookClaimLease is how long a claimed delivery is reserved to this instance
+	WebhookClaimLease = time.Minute
+
+	// WebhookBaseBackoff is the delay before the first retry, doubled on every attempt up to WebhookMaxBackoff
+	WebhookBaseBackoff = 30 * time.Second
+	WebhookMaxBackoff  = 6 * time.Hour
+
+	// Headers of a delivery request
+	WebhookEventHeader     = "X-Speedrun-Event"
+	WebhookDeliveryHeader  = "X-Speedrun-Delivery"
+	WebhookSignatureHeader = "X-Speedrun-Signature"
+)
+
+// ErrInvalidWebhook is returned when registering a webhook subscription with invalid parameters
+var ErrInvalidWebhook = errs.Validation("invalid webhook")
+
+// errWebhookTargetNotAllowed is the error of a delivery to an address that isn't public
+var errWebhookTargetNotAllowed = errors.New("webhook target address not allowed")
+
+// HostResolver resolves the hosts of the webhook URLs, net.DefaultResolver by default
+type HostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// WebhookService notifies the webhook subscriptions of intent events.
+// Deliveries are queued by the outbox dispatcher along with the dispatch of the events,
+// they're retried with exponential backoff until they exhaust their attempts.
+type WebhookService struct {
+	db          db.Database
+	client      *http.Client
+	resolver    HostResolver
+	allowTarget func(ip net.IP) bool // whether requests can be sent to an address, only public ones by default
+	maxAttempts int
+	notify      chan struct{}
+	logger      zerolog.Logger
+}
+
+// NewWebhookService creates a new WebhookService instance
+func NewWebhookService(database db.Database, maxAttempts int, logger zerolog.Logger) *WebhookService {
+	s := &WebhookService{
+		db:          database,
+		resolver:    net.DefaultResolver,
+		allowTarget: isPublicIP,
+		maxAttempts: maxAttempts,
+		notify:      make(chan struct{}, 1),
+		logger:      logger.With().Str(logging.FieldModule, "webhooks").Logger(),
+	}
+
+	// The address is checked when connecting, after the host is resolved again, so that a host
+	// resolving to a private address since its registration isn't reached. Proxies would hide it.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: WebhookRequestTimeout, Control: s.checkDial}).DialContext
+
+	s.client = &http.Client{
+		Timeout:   WebhookRequestTimeout,
+		Transport: transport,
+		// redirects could lead to private addresses, they're failed deliveries
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return s
+}
+
+// SetResolver sets the resolver of the hosts of the subscription URLs
+func (s *WebhookService) SetResolver(resolver HostResolver) {
+	s.resolver = resolver
+}
+
+// Notify wakes the service up to deliver newly queued deliveries
+func (s *WebhookService) Notify() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers the queued deliveries until the context is done
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(WebhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverPending(ctx); err != nil {
+			s.logger.Error().Err(err).Msg("Failed to deliver webhooks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// CreateSubscription registers a webhook subscription with a generated signing secret
+func (s *WebhookService) CreateSubscription(
+	ctx context.Context,
+	req models.CreateWebhookRequest,
+) (*models.WebhookSubscription, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTarget(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:              req.URL,
+		Secret:           secret,
+		Sender:           req.Sender,
+		Recipient:        req.Recipient,
+		Token:            req.Token,
+		SourceChain:      req.SourceChain,
+		DestinationChain: req.DestinationChain,
+	}
+
+	if err := s.db.CreateWebhookSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Int64("subscription_id", sub.ID).Str("url", sub.URL).Msg("Registered webhook subscription")
+
+	return sub, nil
+}
+
+// ConsumeIntentEvents implements OutboxConsumer, it queues the deliveries of dispatched intent events
+// to the matching subscriptions in the transaction dispatching the events
+func (s *WebhookService) ConsumeIntentEvents(ctx context.Context, tx db.Database, events []*models.IntentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	subs, err := tx.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for _, event := range events {
+		var payload []byte
+		for _, sub := range subs {
+			if !sub.Filter().Matches(event) {
+				continue
+			}
+
+			if payload == nil {
+				if payload, err = json.Marshal(event); err != nil {
+					return fmt.Errorf("failed to marshal intent event: %v", err)
+				}
+			}
+
+			deliveries = append(deliveries, &models.WebhookDelivery{
+				SubscriptionID: sub.ID,
+				EventCursor:    event.Cursor,
+				EventType:      event.Type,
+				IntentID:       event.IntentID,
+				Payload:        payload,
+			})
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.CreateWebhookDeliveries(ctx, deliveries)
+}
+
+// DeliverPending attempts the deliveries that are due. Returns the number of attempts made.
+func (s *WebhookService) DeliverPending(ctx context.Context) (int, error) {
+	deliveries, err := s.db.ClaimWebhookDeliveries(ctx, WebhookBatchSize, WebhookClaimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(WebhookConcurrency)
+
+	for _, delivery := range deliveries {
+		g.Go(func() error {
+			return s.attempt(gctx, delivery)
+		})
+	}
+
+	return len(deliveries), g.Wait()
+}
+
+// attempt sends a delivery once and records the outcome
+func (s *WebhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	start := time.Now()
+	statusCode, sendErr := s.send(ctx, delivery, start)
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+
+	attempt := &models.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		Duration:   time.Since(start),
+		CreatedAt:  start,
+	}
+
+	logger := s.logger.With().
+		Int64("delivery_id", delivery.ID).
+		Int64("subscription_id", delivery.SubscriptionID).
+		Int("attempt", delivery.Attempts).
+		Logger()
+
+	switch {
+	case sendErr == nil:
+		now := time.Now()
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		logger.Debug().Int("status_code", statusCode).Msg("Delivered webhook")
+	case delivery.Attempts >= s.maxAttempts:
+		attempt.Error = sendErr.Error()
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.LastError = sendErr.Error()
+		logger.Warn().Err(sendErr).Msg("Webhook delivery exhausted its attempts, moving it to dead letters")
+	default:
+		attempt.Error = sendErr.Error()
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = time.Now().Add(webhookBackoff(delivery.Attempts))
+		logger.Debug().Err(sendErr).Time("next_attempt_at", delivery.NextAttemptAt).Msg("Webhook delivery failed")
+	}
+
+	// record the outcome even if the context is done, otherwise the attempt is lost until the lease expires
+	if err := s.db.RecordWebhookAttempt(context.WithoutCancel(ctx), delivery, attempt); err != nil {
+		return fmt.Errorf("failed to record webhook attempt of delivery %d: %v", delivery.ID, err)
+	}
+
+	return nil
+}
+
+// send posts the signed payload of a delivery, any non-2xx response is an error
+func (s *WebhookService) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "speedrun-webhooks")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(delivery.Secret, now.Unix(), delivery.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		// drain a bounded part of the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+		if err := res.Body.Close(); err != nil {
+			s.logger.Debug().Err(err).Msg("Failed to close webhook response body")
+		}
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value of a payload sent at the given unix time:
+// "t=<timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with the subscription secret>".
+// Receivers recompute the HMAC and reject stale timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// webhookBackoff returns the delay before retrying a delivery that failed the given number of times
+func webhookBackoff(attempts int) time.Duration {
+	backoff := WebhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= WebhookMaxBackoff {
+			return WebhookMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// checkTarget resolves the host of a subscription URL, which must only have public addresses
+func (s *WebhookService) checkTarget(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: invalid url", ErrInvalidWebhook)
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		addrs, err := s.resolver.LookupIPAddr(ctx, u.Hostname())
+		if err != nil || len(addrs) == 0 {
+			return fmt.Errorf("%w: url host doesn't resolve", ErrInvalidWebhook)
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if !s.allowTarget(ip) {
+			return fmt.Errorf("%w: url must not target a private address", ErrInvalidWebhook)
+		}
+	}
+
+	return nil
+}
+
+// checkDial is the Control of the delivery connections, it rejects the addresses that aren't allowed
+func (s *WebhookService) checkDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !s.allowTarget(ip) {
+		return fmt.Errorf("%w: %s", errWebhookTargetNotAllowed, host)
+	}
+	return nil
+}
+
+// nonPublicPrefixes are the special-purpose ranges that aren't publicly routable and aren't covered
+// by the predicates of net.IP, e.g. the carrier-grade NAT range used by cloud and overlay networks
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use IPv4/IPv6 translation
+	netip.MustParsePrefix("100::/64"),       // discard-only
+}
+
+// isPublicIP reports whether an address is publicly routable, i.e. not loopback, private,
+// link-local (e.g. cloud metadata endpoints), multicast, unspecified or another special-purpose range
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return false
+	}
+
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateWebhookRequest(req models.CreateWebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) url", ErrInvalidWebhook)
+	}
+
+	for key, address := range map[string]string{
+		"sender":    req.Sender,
+		"recipient": req.Recipient,
+		"token":     req.Token,
+	} {
+		if address != "" && !utils.IsValidAddress(address) {
+			return fmt.Errorf("%w: invalid %s address", ErrInvalidWebhook, key)
+		}
+	}
+
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %v", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
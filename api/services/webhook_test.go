@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/speedrun-hq/speedrun/api/logging"
+	"github.com/speedrun-hq/speedrun/api/models"
+	"github.com/speedrun-hq/speedrun/api/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_ConsumeIntentEvents(t *testing.T) {
+	const (
+		alice = "0x1111111111111111111111111111111111111111"
+		bob   = "0x2222222222222222222222222222222222222222"
+	)
+
+	events := []*models.IntentEvent{
+		{Cursor: 11, Type: models.IntentEventCreated, IntentID: "0x01", Sender: alice, SourceChain: 8453},
+		{Cursor: 12, Type: string(models.IntentStatusFulfilled), IntentID: "0x02", Sender: bob, SourceChain: 8453},
+	}
+
+	t.Run("queues the deliveries of matching subscriptions", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+
+		mockDB.On("ListWebhookSubscriptions", mock.Anything).Return([]*models.WebhookSubscription{
+			{ID: 1, Sender: alice},
+			{ID: 2, SourceChain: 8453},
+			{ID: 3, SourceChain: 1},
+		}, nil).Once()
+		mockDB.On("CreateWebhookDeliveries", mock.Anything, mock.MatchedBy(
+			func(deliveries []*models.WebhookDelivery) bool {
+				var keys []string
+				for _, d := range deliveries {
+					keys = append(keys, strconv.FormatInt(d.SubscriptionID, 10)+"@"+strconv.FormatInt(d.EventCursor, 10))
+				}
+				return assert.ObjectsAreEqual([]string{"1@11", "2@11", "2@12"}, keys)
+			},
+		)).Return(nil).Once()
+
+		require.NoError(t, service.ConsumeIntentEvents(context.Background(), mockDB, events))
+	})
+
+	t.Run("fails the dispatch when the deliveries aren't queued", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+
+		mockDB.On("ListWebhookSubscriptions", mock.Anything).
+			Return([]*models.WebhookSubscription{{ID: 2, SourceChain: 8453}}, nil).
+			Once()
+		mockDB.On("CreateWebhookDeliveries", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+
+		err := service.ConsumeIntentEvents(context.Background(), mockDB, events)
+		require.ErrorContains(t, err, "connection reset")
+	})
+}
+
+func TestWebhookService_DeliverPending(t *testing.T) {
+	const secret = "topsecret"
+
+	newDelivery := func(url string, attempts int) *models.WebhookDelivery {
+		return &models.WebhookDelivery{
+			ID:             42,
+			SubscriptionID: 1,
+			EventCursor:    11,
+			EventType:      models.IntentEventCreated,
+			Payload:        []byte(`{"cursor":11}`),
+			Status:         models.WebhookDeliveryPending,
+			Attempts:       attempts,
+			URL:            url,
+			Secret:         secret,
+		}
+	}
+
+	for _, tt := range []struct {
+		name            string
+		statusCode      int
+		attempts        int
+		expectedStatus  models.WebhookDeliveryStatus
+		expectsNextTime bool
+	}{
+		{name: "delivered", statusCode: http.StatusOK, expectedStatus: models.WebhookDeliveryDelivered},
+		{
+			name:            "retried with backoff",
+			statusCode:      http.StatusServiceUnavailable,
+			expectedStatus:  models.WebhookDeliveryPending,
+			expectsNextTime: true,
+		},
+		{
+			name:           "dead after the last attempt",
+			statusCode:     http.StatusInternalServerError,
+			attempts:       2,
+			expectedStatus: models.WebhookDeliveryDead,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				// the signature is verifiable with the subscription secret
+				signature := r.Header.Get(WebhookSignatureHeader)
+				timestamp, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+				require.NoError(t, err)
+				assert.Equal(t, SignWebhookPayload(secret, timestamp, body), signature)
+				assert.Equal(t, "42", r.Header.Get(WebhookDeliveryHeader))
+				assert.Equal(t, models.IntentEventCreated, r.Header.Get(WebhookEventHeader))
+
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			mockDB := mocks.NewDatabaseMock(t)
+			service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+			service.allowTarget = func(net.IP) bool { return true } // the test server listens on loopback
+
+			delivery := newDelivery(server.URL, tt.attempts)
+			mockDB.On("ClaimWebhookDeliveries", mock.Anything, WebhookBatchSize, WebhookClaimLease).
+				Return([]*models.WebhookDelivery{delivery}, nil)
+			mockDB.On("RecordWebhookAttempt", mock.Anything, delivery, mock.MatchedBy(
+				func(attempt *models.WebhookDeliveryAttempt) bool {
+					return attempt.Attempt == tt.attempts+1 && attempt.StatusCode == tt.statusCode
+				},
+			)).Return(nil)
+
+			attempted, err := service.DeliverPending(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, attempted)
+
+			assert.Equal(t, tt.expectedStatus, delivery.Status)
+			assert.Equal(t, tt.attempts+1, delivery.Attempts)
+			if tt.expectsNextTime {
+				assert.WithinDuration(t, time.Now().Add(WebhookBaseBackoff), delivery.NextAttemptAt, 5*time.Second)
+			}
+			if tt.expectedStatus == models.WebhookDeliveryDelivered {
+				assert.NotNil(t, delivery.DeliveredAt)
+				assert.Empty(t, delivery.LastError)
+			} else {
+				assert.Contains(t, delivery.LastError, strconv.Itoa(tt.statusCode))
+			}
+		})
+	}
+
+	t.Run("rejects private addresses", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+		}))
+		defer server.Close()
+
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+
+		delivery := newDelivery(server.URL, 0)
+		mockDB.On("ClaimWebhookDeliveries", mock.Anything, WebhookBatchSize, WebhookClaimLease).
+			Return([]*models.WebhookDelivery{delivery}, nil)
+		mockDB.On("RecordWebhookAttempt", mock.Anything, delivery, mock.Anything).Return(nil)
+
+		_, err := service.DeliverPending(context.Background())
+		require.NoError(t, err)
+
+		assert.Zero(t, requests)
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Contains(t, delivery.LastError, "not allowed")
+	})
+
+	t.Run("doesn't follow redirects", func(t *testing.T) {
+		var redirected bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/internal" {
+				redirected = true
+				return
+			}
+			http.Redirect(w, r, "/internal", http.StatusFound)
+		}))
+		defer server.Close()
+
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+		service.allowTarget = func(net.IP) bool { return true }
+
+		delivery := newDelivery(server.URL, 0)
+		mockDB.On("ClaimWebhookDeliveries", mock.Anything, WebhookBatchSize, WebhookClaimLease).
+			Return([]*models.WebhookDelivery{delivery}, nil)
+		mockDB.On("RecordWebhookAttempt", mock.Anything, delivery, mock.MatchedBy(
+			func(attempt *models.WebhookDeliveryAttempt) bool {
+				return attempt.StatusCode == http.StatusFound
+			},
+		)).Return(nil)
+
+		_, err := service.DeliverPending(context.Background())
+		require.NoError(t, err)
+
+		assert.False(t, redirected)
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	})
+
+	t.Run("returns database error", func(t *testing.T) {
+		mockDB := mocks.NewDatabaseMock(t)
+		service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+
+		mockDB.On("ClaimWebhookDeliveries", mock.Anything, WebhookBatchSize, WebhookClaimLease).
+			Return(nil, errors.New("connection refused"))
+
+		_, err := service.DeliverPending(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	resolver := staticResolver{
+		"partner.example.com":  {"203.0.113.10"},
+		"internal.example.com": {"203.0.113.10", "10.0.0.5"},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		url           string
+		errorContains string
+	}{
+		{name: "public host", url: "https://partner.example.com/hooks"},
+		{name: "public address", url: "https://203.0.113.10/hooks"},
+		{name: "host with a private address", url: "https://internal.example.com", errorContains: "private address"},
+		{name: "unresolved host", url: "https://unknown.example.com", errorContains: "doesn't resolve"},
+		{name: "loopback", url: "http://127.0.0.1:8080", errorContains: "private address"},
+		{name: "ipv6 loopback", url: "http://[::1]/hooks", errorContains: "private address"},
+		{name: "private network", url: "http://192.168.1.1", errorContains: "private address"},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data", errorContains: "private address"},
+		{name: "unspecified", url: "http://0.0.0.0", errorContains: "private address"},
+		{name: "carrier-grade nat", url: "http://100.100.100.100/hooks", errorContains: "private address"},
+		{name: "ipv4-mapped carrier-grade nat", url: "http://[::ffff:100.64.0.1]/hooks", errorContains: "private address"},
+		{name: "reserved", url: "http://240.0.0.1", errorContains: "private address"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := mocks.NewDatabaseMock(t)
+			service := NewWebhookService(mockDB, 3, logging.NewTesting(t))
+			service.SetResolver(resolver)
+
+			if tt.errorContains == "" {
+				mockDB.On("CreateWebhookSubscription", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			_, err := service.CreateSubscription(context.Background(), models.CreateWebhookRequest{URL: tt.url})
+			if tt.errorContains != "" {
+				require.ErrorIs(t, err, ErrInvalidWebhook)
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, WebhookBaseBackoff, webhookBackoff(1))
+	assert.Equal(t, 2*WebhookBaseBackoff, webhookBackoff(2))
+	assert.Equal(t, 8*WebhookBaseBackoff, webhookBackoff(4))
+	assert.Equal(t, WebhookMaxBackoff, webhookBackoff(20))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(
+		t,
+		"t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163",
+		SignWebhookPayload("secret", 1700000000, []byte("{}")),
+	)
+}
+
+// staticResolver resolves the hosts of a map
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	addrs := make([]net.IPAddr, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs, nil
+}
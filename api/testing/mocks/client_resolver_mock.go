@@ -5,7 +5,7 @@
 package mocks
 
 import (
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/speedrun-hq/speedrun/api/clients/evm"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -37,23 +37,23 @@ func (_m *ClientResolverMock) EXPECT() *ClientResolverMock_Expecter {
 }
 
 // GetClient provides a mock function for the type ClientResolverMock
-func (_mock *ClientResolverMock) GetClient(chainID uint64) (*ethclient.Client, error) {
+func (_mock *ClientResolverMock) GetClient(chainID uint64) (evm.Client, error) {
 	ret := _mock.Called(chainID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetClient")
 	}
 
-	var r0 *ethclient.Client
+	var r0 evm.Client
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(uint64) (*ethclient.Client, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(uint64) (evm.Client, error)); ok {
 		return returnFunc(chainID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(uint64) *ethclient.Client); ok {
+	if returnFunc, ok := ret.Get(0).(func(uint64) evm.Client); ok {
 		r0 = returnFunc(chainID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ethclient.Client)
+			r0 = ret.Get(0).(evm.Client)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(uint64) error); ok {
@@ -88,12 +88,12 @@ func (_c *ClientResolverMock_GetClient_Call) Run(run func(chainID uint64)) *Clie
 	return _c
 }
 
-func (_c *ClientResolverMock_GetClient_Call) Return(client *ethclient.Client, err error) *ClientResolverMock_GetClient_Call {
+func (_c *ClientResolverMock_GetClient_Call) Return(client evm.Client, err error) *ClientResolverMock_GetClient_Call {
 	_c.Call.Return(client, err)
 	return _c
 }
 
-func (_c *ClientResolverMock_GetClient_Call) RunAndReturn(run func(chainID uint64) (*ethclient.Client, error)) *ClientResolverMock_GetClient_Call {
+func (_c *ClientResolverMock_GetClient_Call) RunAndReturn(run func(chainID uint64) (evm.Client, error)) *ClientResolverMock_GetClient_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/speedrun-hq/speedrun/api/db"
 	"github.com/speedrun-hq/speedrun/api/models"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -40,6 +41,155 @@ func (_m *DatabaseMock) EXPECT() *DatabaseMock_Expecter {
 	return &DatabaseMock_Expecter{mock: &_m.Mock}
 }
 
+// AdvanceEventCheckpoint provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) AdvanceEventCheckpoint(ctx context.Context, chainID uint64, eventType string, contractAddress string, blockNumber uint64) error {
+	ret := _mock.Called(ctx, chainID, eventType, contractAddress, blockNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvanceEventCheckpoint")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, string, uint64) error); ok {
+		r0 = returnFunc(ctx, chainID, eventType, contractAddress, blockNumber)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_AdvanceEventCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AdvanceEventCheckpoint'
+type DatabaseMock_AdvanceEventCheckpoint_Call struct {
+	*mock.Call
+}
+
+// AdvanceEventCheckpoint is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - eventType string
+//   - contractAddress string
+//   - blockNumber uint64
+func (_e *DatabaseMock_Expecter) AdvanceEventCheckpoint(ctx interface{}, chainID interface{}, eventType interface{}, contractAddress interface{}, blockNumber interface{}) *DatabaseMock_AdvanceEventCheckpoint_Call {
+	return &DatabaseMock_AdvanceEventCheckpoint_Call{Call: _e.mock.On("AdvanceEventCheckpoint", ctx, chainID, eventType, contractAddress, blockNumber)}
+}
+
+func (_c *DatabaseMock_AdvanceEventCheckpoint_Call) Run(run func(ctx context.Context, chainID uint64, eventType string, contractAddress string, blockNumber uint64)) *DatabaseMock_AdvanceEventCheckpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 uint64
+		if args[4] != nil {
+			arg4 = args[4].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_AdvanceEventCheckpoint_Call) Return(err error) *DatabaseMock_AdvanceEventCheckpoint_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_AdvanceEventCheckpoint_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, eventType string, contractAddress string, blockNumber uint64) error) *DatabaseMock_AdvanceEventCheckpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimWebhookDeliveries provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	ret := _mock.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimWebhookDeliveries")
+	}
+
+	var r0 []*models.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]*models.WebhookDelivery, error)); ok {
+		return returnFunc(ctx, limit, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Duration) []*models.WebhookDelivery); ok {
+		r0 = returnFunc(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = returnFunc(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ClaimWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimWebhookDeliveries'
+type DatabaseMock_ClaimWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// ClaimWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - lease time.Duration
+func (_e *DatabaseMock_Expecter) ClaimWebhookDeliveries(ctx interface{}, limit interface{}, lease interface{}) *DatabaseMock_ClaimWebhookDeliveries_Call {
+	return &DatabaseMock_ClaimWebhookDeliveries_Call{Call: _e.mock.On("ClaimWebhookDeliveries", ctx, limit, lease)}
+}
+
+func (_c *DatabaseMock_ClaimWebhookDeliveries_Call) Run(run func(ctx context.Context, limit int, lease time.Duration)) *DatabaseMock_ClaimWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ClaimWebhookDeliveries_Call) Return(webhookDeliverys []*models.WebhookDelivery, err error) *DatabaseMock_ClaimWebhookDeliveries_Call {
+	_c.Call.Return(webhookDeliverys, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ClaimWebhookDeliveries_Call) RunAndReturn(run func(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)) *DatabaseMock_ClaimWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function for the type DatabaseMock
 func (_mock *DatabaseMock) Close() error {
 	ret := _mock.Called()
@@ -84,6 +234,80 @@ func (_c *DatabaseMock_Close_Call) RunAndReturn(run func() error) *DatabaseMock_
 	return _c
 }
 
+// CountStuckUnconfirmedEvents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) CountStuckUnconfirmedEvents(ctx context.Context, chainID uint64, maxAttempts int) (map[string]int, error) {
+	ret := _mock.Called(ctx, chainID, maxAttempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountStuckUnconfirmedEvents")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, int) (map[string]int, error)); ok {
+		return returnFunc(ctx, chainID, maxAttempts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, int) map[string]int); ok {
+		r0 = returnFunc(ctx, chainID, maxAttempts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = returnFunc(ctx, chainID, maxAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_CountStuckUnconfirmedEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountStuckUnconfirmedEvents'
+type DatabaseMock_CountStuckUnconfirmedEvents_Call struct {
+	*mock.Call
+}
+
+// CountStuckUnconfirmedEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - maxAttempts int
+func (_e *DatabaseMock_Expecter) CountStuckUnconfirmedEvents(ctx interface{}, chainID interface{}, maxAttempts interface{}) *DatabaseMock_CountStuckUnconfirmedEvents_Call {
+	return &DatabaseMock_CountStuckUnconfirmedEvents_Call{Call: _e.mock.On("CountStuckUnconfirmedEvents", ctx, chainID, maxAttempts)}
+}
+
+func (_c *DatabaseMock_CountStuckUnconfirmedEvents_Call) Run(run func(ctx context.Context, chainID uint64, maxAttempts int)) *DatabaseMock_CountStuckUnconfirmedEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_CountStuckUnconfirmedEvents_Call) Return(stringToInt map[string]int, err error) *DatabaseMock_CountStuckUnconfirmedEvents_Call {
+	_c.Call.Return(stringToInt, err)
+	return _c
+}
+
+func (_c *DatabaseMock_CountStuckUnconfirmedEvents_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, maxAttempts int) (map[string]int, error)) *DatabaseMock_CountStuckUnconfirmedEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateFulfillment provides a mock function for the type DatabaseMock
 func (_mock *DatabaseMock) CreateFulfillment(ctx context.Context, fulfillment *models.Fulfillment) error {
 	ret := _mock.Called(ctx, fulfillment)
@@ -255,138 +479,101 @@ func (_c *DatabaseMock_CreateSettlement_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
-// Exec provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	var tmpRet mock.Arguments
-	if len(args) > 0 {
-		tmpRet = _mock.Called(ctx, query, args)
-	} else {
-		tmpRet = _mock.Called(ctx, query)
-	}
-	ret := tmpRet
+// CreateWebhookDeliveries provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) CreateWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	ret := _mock.Called(ctx, deliveries)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Exec")
+		panic("no return value specified for CreateWebhookDeliveries")
 	}
 
-	var r0 sql.Result
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) (sql.Result, error)); ok {
-		return returnFunc(ctx, query, args...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) sql.Result); ok {
-		r0 = returnFunc(ctx, query, args...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(sql.Result)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
-		r1 = returnFunc(ctx, query, args...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*models.WebhookDelivery) error); ok {
+		r0 = returnFunc(ctx, deliveries)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_Exec_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exec'
-type DatabaseMock_Exec_Call struct {
+// DatabaseMock_CreateWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookDeliveries'
+type DatabaseMock_CreateWebhookDeliveries_Call struct {
 	*mock.Call
 }
 
-// Exec is a helper method to define mock.On call
+// CreateWebhookDeliveries is a helper method to define mock.On call
 //   - ctx context.Context
-//   - query string
-//   - args ...interface{}
-func (_e *DatabaseMock_Expecter) Exec(ctx interface{}, query interface{}, args ...interface{}) *DatabaseMock_Exec_Call {
-	return &DatabaseMock_Exec_Call{Call: _e.mock.On("Exec",
-		append([]interface{}{ctx, query}, args...)...)}
+//   - deliveries []*models.WebhookDelivery
+func (_e *DatabaseMock_Expecter) CreateWebhookDeliveries(ctx interface{}, deliveries interface{}) *DatabaseMock_CreateWebhookDeliveries_Call {
+	return &DatabaseMock_CreateWebhookDeliveries_Call{Call: _e.mock.On("CreateWebhookDeliveries", ctx, deliveries)}
 }
 
-func (_c *DatabaseMock_Exec_Call) Run(run func(ctx context.Context, query string, args ...interface{})) *DatabaseMock_Exec_Call {
+func (_c *DatabaseMock_CreateWebhookDeliveries_Call) Run(run func(ctx context.Context, deliveries []*models.WebhookDelivery)) *DatabaseMock_CreateWebhookDeliveries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 []*models.WebhookDelivery
 		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 []interface{}
-		var variadicArgs []interface{}
-		if len(args) > 2 {
-			variadicArgs = args[2].([]interface{})
+			arg1 = args[1].([]*models.WebhookDelivery)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_Exec_Call) Return(result sql.Result, err error) *DatabaseMock_Exec_Call {
-	_c.Call.Return(result, err)
+func (_c *DatabaseMock_CreateWebhookDeliveries_Call) Return(err error) *DatabaseMock_CreateWebhookDeliveries_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_Exec_Call) RunAndReturn(run func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)) *DatabaseMock_Exec_Call {
+func (_c *DatabaseMock_CreateWebhookDeliveries_Call) RunAndReturn(run func(ctx context.Context, deliveries []*models.WebhookDelivery) error) *DatabaseMock_CreateWebhookDeliveries_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFulfillment provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) GetFulfillment(ctx context.Context, id string) (*models.Fulfillment, error) {
-	ret := _mock.Called(ctx, id)
+// CreateWebhookSubscription provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	ret := _mock.Called(ctx, sub)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetFulfillment")
+		panic("no return value specified for CreateWebhookSubscription")
 	}
 
-	var r0 *models.Fulfillment
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Fulfillment, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Fulfillment); ok {
-		r0 = returnFunc(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Fulfillment)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WebhookSubscription) error); ok {
+		r0 = returnFunc(ctx, sub)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_GetFulfillment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFulfillment'
-type DatabaseMock_GetFulfillment_Call struct {
+// DatabaseMock_CreateWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhookSubscription'
+type DatabaseMock_CreateWebhookSubscription_Call struct {
 	*mock.Call
 }
 
-// GetFulfillment is a helper method to define mock.On call
+// CreateWebhookSubscription is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-func (_e *DatabaseMock_Expecter) GetFulfillment(ctx interface{}, id interface{}) *DatabaseMock_GetFulfillment_Call {
-	return &DatabaseMock_GetFulfillment_Call{Call: _e.mock.On("GetFulfillment", ctx, id)}
+//   - sub *models.WebhookSubscription
+func (_e *DatabaseMock_Expecter) CreateWebhookSubscription(ctx interface{}, sub interface{}) *DatabaseMock_CreateWebhookSubscription_Call {
+	return &DatabaseMock_CreateWebhookSubscription_Call{Call: _e.mock.On("CreateWebhookSubscription", ctx, sub)}
 }
 
-func (_c *DatabaseMock_GetFulfillment_Call) Run(run func(ctx context.Context, id string)) *DatabaseMock_GetFulfillment_Call {
+func (_c *DatabaseMock_CreateWebhookSubscription_Call) Run(run func(ctx context.Context, sub *models.WebhookSubscription)) *DatabaseMock_CreateWebhookSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *models.WebhookSubscription
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(*models.WebhookSubscription)
 		}
 		run(
 			arg0,
@@ -396,65 +583,54 @@ func (_c *DatabaseMock_GetFulfillment_Call) Run(run func(ctx context.Context, id
 	return _c
 }
 
-func (_c *DatabaseMock_GetFulfillment_Call) Return(fulfillment *models.Fulfillment, err error) *DatabaseMock_GetFulfillment_Call {
-	_c.Call.Return(fulfillment, err)
+func (_c *DatabaseMock_CreateWebhookSubscription_Call) Return(err error) *DatabaseMock_CreateWebhookSubscription_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_GetFulfillment_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Fulfillment, error)) *DatabaseMock_GetFulfillment_Call {
+func (_c *DatabaseMock_CreateWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, sub *models.WebhookSubscription) error) *DatabaseMock_CreateWebhookSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetIntent provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) GetIntent(ctx context.Context, id string) (*models.Intent, error) {
+// DeleteOrphanEvent provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) DeleteOrphanEvent(ctx context.Context, id int64) error {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetIntent")
+		panic("no return value specified for DeleteOrphanEvent")
 	}
 
-	var r0 *models.Intent
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Intent, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Intent); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) error); ok {
 		r0 = returnFunc(ctx, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Intent)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
-	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_GetIntent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIntent'
-type DatabaseMock_GetIntent_Call struct {
+// DatabaseMock_DeleteOrphanEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteOrphanEvent'
+type DatabaseMock_DeleteOrphanEvent_Call struct {
 	*mock.Call
 }
 
-// GetIntent is a helper method to define mock.On call
+// DeleteOrphanEvent is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-func (_e *DatabaseMock_Expecter) GetIntent(ctx interface{}, id interface{}) *DatabaseMock_GetIntent_Call {
-	return &DatabaseMock_GetIntent_Call{Call: _e.mock.On("GetIntent", ctx, id)}
+//   - id int64
+func (_e *DatabaseMock_Expecter) DeleteOrphanEvent(ctx interface{}, id interface{}) *DatabaseMock_DeleteOrphanEvent_Call {
+	return &DatabaseMock_DeleteOrphanEvent_Call{Call: _e.mock.On("DeleteOrphanEvent", ctx, id)}
 }
 
-func (_c *DatabaseMock_GetIntent_Call) Run(run func(ctx context.Context, id string)) *DatabaseMock_GetIntent_Call {
+func (_c *DatabaseMock_DeleteOrphanEvent_Call) Run(run func(ctx context.Context, id int64)) *DatabaseMock_DeleteOrphanEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 int64
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(int64)
 		}
 		run(
 			arg0,
@@ -464,55 +640,48 @@ func (_c *DatabaseMock_GetIntent_Call) Run(run func(ctx context.Context, id stri
 	return _c
 }
 
-func (_c *DatabaseMock_GetIntent_Call) Return(intent *models.Intent, err error) *DatabaseMock_GetIntent_Call {
-	_c.Call.Return(intent, err)
+func (_c *DatabaseMock_DeleteOrphanEvent_Call) Return(err error) *DatabaseMock_DeleteOrphanEvent_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_GetIntent_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Intent, error)) *DatabaseMock_GetIntent_Call {
+func (_c *DatabaseMock_DeleteOrphanEvent_Call) RunAndReturn(run func(ctx context.Context, id int64) error) *DatabaseMock_DeleteOrphanEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetLastProcessedBlock provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) GetLastProcessedBlock(ctx context.Context, chainID uint64) (uint64, error) {
-	ret := _mock.Called(ctx, chainID)
+// DeleteUnconfirmedEvent provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) DeleteUnconfirmedEvent(ctx context.Context, chainID uint64, blockHash string, logIndex uint) error {
+	ret := _mock.Called(ctx, chainID, blockHash, logIndex)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLastProcessedBlock")
+		panic("no return value specified for DeleteUnconfirmedEvent")
 	}
 
-	var r0 uint64
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) (uint64, error)); ok {
-		return returnFunc(ctx, chainID)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) uint64); ok {
-		r0 = returnFunc(ctx, chainID)
-	} else {
-		r0 = ret.Get(0).(uint64)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
-		r1 = returnFunc(ctx, chainID)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, uint) error); ok {
+		r0 = returnFunc(ctx, chainID, blockHash, logIndex)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_GetLastProcessedBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastProcessedBlock'
-type DatabaseMock_GetLastProcessedBlock_Call struct {
+// DatabaseMock_DeleteUnconfirmedEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUnconfirmedEvent'
+type DatabaseMock_DeleteUnconfirmedEvent_Call struct {
 	*mock.Call
 }
 
-// GetLastProcessedBlock is a helper method to define mock.On call
+// DeleteUnconfirmedEvent is a helper method to define mock.On call
 //   - ctx context.Context
 //   - chainID uint64
-func (_e *DatabaseMock_Expecter) GetLastProcessedBlock(ctx interface{}, chainID interface{}) *DatabaseMock_GetLastProcessedBlock_Call {
-	return &DatabaseMock_GetLastProcessedBlock_Call{Call: _e.mock.On("GetLastProcessedBlock", ctx, chainID)}
+//   - blockHash string
+//   - logIndex uint
+func (_e *DatabaseMock_Expecter) DeleteUnconfirmedEvent(ctx interface{}, chainID interface{}, blockHash interface{}, logIndex interface{}) *DatabaseMock_DeleteUnconfirmedEvent_Call {
+	return &DatabaseMock_DeleteUnconfirmedEvent_Call{Call: _e.mock.On("DeleteUnconfirmedEvent", ctx, chainID, blockHash, logIndex)}
 }
 
-func (_c *DatabaseMock_GetLastProcessedBlock_Call) Run(run func(ctx context.Context, chainID uint64)) *DatabaseMock_GetLastProcessedBlock_Call {
+func (_c *DatabaseMock_DeleteUnconfirmedEvent_Call) Run(run func(ctx context.Context, chainID uint64, blockHash string, logIndex uint)) *DatabaseMock_DeleteUnconfirmedEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -522,73 +691,72 @@ func (_c *DatabaseMock_GetLastProcessedBlock_Call) Run(run func(ctx context.Cont
 		if args[1] != nil {
 			arg1 = args[1].(uint64)
 		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 uint
+		if args[3] != nil {
+			arg3 = args[3].(uint)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_GetLastProcessedBlock_Call) Return(v uint64, err error) *DatabaseMock_GetLastProcessedBlock_Call {
-	_c.Call.Return(v, err)
+func (_c *DatabaseMock_DeleteUnconfirmedEvent_Call) Return(err error) *DatabaseMock_DeleteUnconfirmedEvent_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_GetLastProcessedBlock_Call) RunAndReturn(run func(ctx context.Context, chainID uint64) (uint64, error)) *DatabaseMock_GetLastProcessedBlock_Call {
+func (_c *DatabaseMock_DeleteUnconfirmedEvent_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, blockHash string, logIndex uint) error) *DatabaseMock_DeleteUnconfirmedEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSettlement provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) GetSettlement(ctx context.Context, id string) (*models.Settlement, error) {
+// DeleteWebhookSubscription provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) DeleteWebhookSubscription(ctx context.Context, id int64) error {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSettlement")
+		panic("no return value specified for DeleteWebhookSubscription")
 	}
 
-	var r0 *models.Settlement
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Settlement, error)); ok {
-		return returnFunc(ctx, id)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Settlement); ok {
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) error); ok {
 		r0 = returnFunc(ctx, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Settlement)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
-	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_GetSettlement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSettlement'
-type DatabaseMock_GetSettlement_Call struct {
+// DatabaseMock_DeleteWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhookSubscription'
+type DatabaseMock_DeleteWebhookSubscription_Call struct {
 	*mock.Call
 }
 
-// GetSettlement is a helper method to define mock.On call
+// DeleteWebhookSubscription is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id string
-func (_e *DatabaseMock_Expecter) GetSettlement(ctx interface{}, id interface{}) *DatabaseMock_GetSettlement_Call {
-	return &DatabaseMock_GetSettlement_Call{Call: _e.mock.On("GetSettlement", ctx, id)}
+//   - id int64
+func (_e *DatabaseMock_Expecter) DeleteWebhookSubscription(ctx interface{}, id interface{}) *DatabaseMock_DeleteWebhookSubscription_Call {
+	return &DatabaseMock_DeleteWebhookSubscription_Call{Call: _e.mock.On("DeleteWebhookSubscription", ctx, id)}
 }
 
-func (_c *DatabaseMock_GetSettlement_Call) Run(run func(ctx context.Context, id string)) *DatabaseMock_GetSettlement_Call {
+func (_c *DatabaseMock_DeleteWebhookSubscription_Call) Run(run func(ctx context.Context, id int64)) *DatabaseMock_DeleteWebhookSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 int64
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(int64)
 		}
 		run(
 			arg0,
@@ -598,63 +766,71 @@ func (_c *DatabaseMock_GetSettlement_Call) Run(run func(ctx context.Context, id
 	return _c
 }
 
-func (_c *DatabaseMock_GetSettlement_Call) Return(settlement *models.Settlement, err error) *DatabaseMock_GetSettlement_Call {
-	_c.Call.Return(settlement, err)
+func (_c *DatabaseMock_DeleteWebhookSubscription_Call) Return(err error) *DatabaseMock_DeleteWebhookSubscription_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_GetSettlement_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Settlement, error)) *DatabaseMock_GetSettlement_Call {
+func (_c *DatabaseMock_DeleteWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, id int64) error) *DatabaseMock_DeleteWebhookSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTotalFulfilledAmount provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) GetTotalFulfilledAmount(ctx context.Context, intentID string) (string, error) {
-	ret := _mock.Called(ctx, intentID)
+// DispatchOutboxMessages provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) DispatchOutboxMessages(ctx context.Context, limit int) ([]*models.IntentEvent, int, error) {
+	ret := _mock.Called(ctx, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTotalFulfilledAmount")
+		panic("no return value specified for DispatchOutboxMessages")
 	}
 
-	var r0 string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return returnFunc(ctx, intentID)
+	var r0 []*models.IntentEvent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]*models.IntentEvent, int, error)); ok {
+		return returnFunc(ctx, limit)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = returnFunc(ctx, intentID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []*models.IntentEvent); ok {
+		r0 = returnFunc(ctx, limit)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.IntentEvent)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, intentID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) int); ok {
+		r1 = returnFunc(ctx, limit)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int)
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int) error); ok {
+		r2 = returnFunc(ctx, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// DatabaseMock_GetTotalFulfilledAmount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTotalFulfilledAmount'
-type DatabaseMock_GetTotalFulfilledAmount_Call struct {
+// DatabaseMock_DispatchOutboxMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DispatchOutboxMessages'
+type DatabaseMock_DispatchOutboxMessages_Call struct {
 	*mock.Call
 }
 
-// GetTotalFulfilledAmount is a helper method to define mock.On call
+// DispatchOutboxMessages is a helper method to define mock.On call
 //   - ctx context.Context
-//   - intentID string
-func (_e *DatabaseMock_Expecter) GetTotalFulfilledAmount(ctx interface{}, intentID interface{}) *DatabaseMock_GetTotalFulfilledAmount_Call {
-	return &DatabaseMock_GetTotalFulfilledAmount_Call{Call: _e.mock.On("GetTotalFulfilledAmount", ctx, intentID)}
+//   - limit int
+func (_e *DatabaseMock_Expecter) DispatchOutboxMessages(ctx interface{}, limit interface{}) *DatabaseMock_DispatchOutboxMessages_Call {
+	return &DatabaseMock_DispatchOutboxMessages_Call{Call: _e.mock.On("DispatchOutboxMessages", ctx, limit)}
 }
 
-func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) Run(run func(ctx context.Context, intentID string)) *DatabaseMock_GetTotalFulfilledAmount_Call {
+func (_c *DatabaseMock_DispatchOutboxMessages_Call) Run(run func(ctx context.Context, limit int)) *DatabaseMock_DispatchOutboxMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 int
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(int)
 		}
 		run(
 			arg0,
@@ -664,392 +840,398 @@ func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) Run(run func(ctx context.Co
 	return _c
 }
 
-func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) Return(s string, err error) *DatabaseMock_GetTotalFulfilledAmount_Call {
-	_c.Call.Return(s, err)
+func (_c *DatabaseMock_DispatchOutboxMessages_Call) Return(intentEvents []*models.IntentEvent, n int, err error) *DatabaseMock_DispatchOutboxMessages_Call {
+	_c.Call.Return(intentEvents, n, err)
 	return _c
 }
 
-func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) RunAndReturn(run func(ctx context.Context, intentID string) (string, error)) *DatabaseMock_GetTotalFulfilledAmount_Call {
+func (_c *DatabaseMock_DispatchOutboxMessages_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]*models.IntentEvent, int, error)) *DatabaseMock_DispatchOutboxMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InitDB provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) InitDB(ctx context.Context) error {
-	ret := _mock.Called(ctx)
+// EnqueueOrphanEvent provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) EnqueueOrphanEvent(ctx context.Context, event *models.OrphanEvent) error {
+	ret := _mock.Called(ctx, event)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InitDB")
+		panic("no return value specified for EnqueueOrphanEvent")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.OrphanEvent) error); ok {
+		r0 = returnFunc(ctx, event)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// DatabaseMock_InitDB_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InitDB'
-type DatabaseMock_InitDB_Call struct {
+// DatabaseMock_EnqueueOrphanEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueOrphanEvent'
+type DatabaseMock_EnqueueOrphanEvent_Call struct {
 	*mock.Call
 }
 
-// InitDB is a helper method to define mock.On call
+// EnqueueOrphanEvent is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *DatabaseMock_Expecter) InitDB(ctx interface{}) *DatabaseMock_InitDB_Call {
-	return &DatabaseMock_InitDB_Call{Call: _e.mock.On("InitDB", ctx)}
+//   - event *models.OrphanEvent
+func (_e *DatabaseMock_Expecter) EnqueueOrphanEvent(ctx interface{}, event interface{}) *DatabaseMock_EnqueueOrphanEvent_Call {
+	return &DatabaseMock_EnqueueOrphanEvent_Call{Call: _e.mock.On("EnqueueOrphanEvent", ctx, event)}
 }
 
-func (_c *DatabaseMock_InitDB_Call) Run(run func(ctx context.Context)) *DatabaseMock_InitDB_Call {
+func (_c *DatabaseMock_EnqueueOrphanEvent_Call) Run(run func(ctx context.Context, event *models.OrphanEvent)) *DatabaseMock_EnqueueOrphanEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 *models.OrphanEvent
+		if args[1] != nil {
+			arg1 = args[1].(*models.OrphanEvent)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_InitDB_Call) Return(err error) *DatabaseMock_InitDB_Call {
+func (_c *DatabaseMock_EnqueueOrphanEvent_Call) Return(err error) *DatabaseMock_EnqueueOrphanEvent_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_InitDB_Call) RunAndReturn(run func(ctx context.Context) error) *DatabaseMock_InitDB_Call {
+func (_c *DatabaseMock_EnqueueOrphanEvent_Call) RunAndReturn(run func(ctx context.Context, event *models.OrphanEvent) error) *DatabaseMock_EnqueueOrphanEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListFulfillments provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListFulfillments(ctx context.Context) ([]*models.Fulfillment, error) {
-	ret := _mock.Called(ctx)
+// EnqueueOutboxMessage provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) EnqueueOutboxMessage(ctx context.Context, message *models.OutboxMessage) error {
+	ret := _mock.Called(ctx, message)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListFulfillments")
+		panic("no return value specified for EnqueueOutboxMessage")
 	}
 
-	var r0 []*models.Fulfillment
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.Fulfillment, error)); ok {
-		return returnFunc(ctx)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.Fulfillment); ok {
-		r0 = returnFunc(ctx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Fulfillment)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.OutboxMessage) error); ok {
+		r0 = returnFunc(ctx, message)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_ListFulfillments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFulfillments'
-type DatabaseMock_ListFulfillments_Call struct {
+// DatabaseMock_EnqueueOutboxMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueOutboxMessage'
+type DatabaseMock_EnqueueOutboxMessage_Call struct {
 	*mock.Call
 }
 
-// ListFulfillments is a helper method to define mock.On call
+// EnqueueOutboxMessage is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *DatabaseMock_Expecter) ListFulfillments(ctx interface{}) *DatabaseMock_ListFulfillments_Call {
-	return &DatabaseMock_ListFulfillments_Call{Call: _e.mock.On("ListFulfillments", ctx)}
+//   - message *models.OutboxMessage
+func (_e *DatabaseMock_Expecter) EnqueueOutboxMessage(ctx interface{}, message interface{}) *DatabaseMock_EnqueueOutboxMessage_Call {
+	return &DatabaseMock_EnqueueOutboxMessage_Call{Call: _e.mock.On("EnqueueOutboxMessage", ctx, message)}
 }
 
-func (_c *DatabaseMock_ListFulfillments_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListFulfillments_Call {
+func (_c *DatabaseMock_EnqueueOutboxMessage_Call) Run(run func(ctx context.Context, message *models.OutboxMessage)) *DatabaseMock_EnqueueOutboxMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 *models.OutboxMessage
+		if args[1] != nil {
+			arg1 = args[1].(*models.OutboxMessage)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListFulfillments_Call) Return(fulfillments []*models.Fulfillment, err error) *DatabaseMock_ListFulfillments_Call {
-	_c.Call.Return(fulfillments, err)
+func (_c *DatabaseMock_EnqueueOutboxMessage_Call) Return(err error) *DatabaseMock_EnqueueOutboxMessage_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListFulfillments_Call) RunAndReturn(run func(ctx context.Context) ([]*models.Fulfillment, error)) *DatabaseMock_ListFulfillments_Call {
+func (_c *DatabaseMock_EnqueueOutboxMessage_Call) RunAndReturn(run func(ctx context.Context, message *models.OutboxMessage) error) *DatabaseMock_EnqueueOutboxMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListFulfillmentsPaginated provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListFulfillmentsPaginated(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error) {
-	ret := _mock.Called(ctx, page, pageSize)
+// Exec provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, query, args)
+	} else {
+		tmpRet = _mock.Called(ctx, query)
+	}
+	ret := tmpRet
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListFulfillmentsPaginated")
+		panic("no return value specified for Exec")
 	}
 
-	var r0 []*models.Fulfillment
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Fulfillment, int, error)); ok {
-		return returnFunc(ctx, page, pageSize)
+	var r0 sql.Result
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) (sql.Result, error)); ok {
+		return returnFunc(ctx, query, args...)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Fulfillment); ok {
-		r0 = returnFunc(ctx, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) sql.Result); ok {
+		r0 = returnFunc(ctx, query, args...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Fulfillment)
+			r0 = ret.Get(0).(sql.Result)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = returnFunc(ctx, page, pageSize)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = returnFunc(ctx, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = returnFunc(ctx, query, args...)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListFulfillmentsPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFulfillmentsPaginated'
-type DatabaseMock_ListFulfillmentsPaginated_Call struct {
+// DatabaseMock_Exec_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exec'
+type DatabaseMock_Exec_Call struct {
 	*mock.Call
 }
 
-// ListFulfillmentsPaginated is a helper method to define mock.On call
+// Exec is a helper method to define mock.On call
 //   - ctx context.Context
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListFulfillmentsPaginated(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListFulfillmentsPaginated_Call {
-	return &DatabaseMock_ListFulfillmentsPaginated_Call{Call: _e.mock.On("ListFulfillmentsPaginated", ctx, page, pageSize)}
+//   - query string
+//   - args ...interface{}
+func (_e *DatabaseMock_Expecter) Exec(ctx interface{}, query interface{}, args ...interface{}) *DatabaseMock_Exec_Call {
+	return &DatabaseMock_Exec_Call{Call: _e.mock.On("Exec",
+		append([]interface{}{ctx, query}, args...)...)}
 }
 
-func (_c *DatabaseMock_ListFulfillmentsPaginated_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListFulfillmentsPaginated_Call {
+func (_c *DatabaseMock_Exec_Call) Run(run func(ctx context.Context, query string, args ...interface{})) *DatabaseMock_Exec_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(string)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
+		var arg2 []interface{}
+		var variadicArgs []interface{}
+		if len(args) > 2 {
+			variadicArgs = args[2].([]interface{})
 		}
+		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2,
+			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListFulfillmentsPaginated_Call) Return(fulfillments []*models.Fulfillment, n int, err error) *DatabaseMock_ListFulfillmentsPaginated_Call {
-	_c.Call.Return(fulfillments, n, err)
+func (_c *DatabaseMock_Exec_Call) Return(result sql.Result, err error) *DatabaseMock_Exec_Call {
+	_c.Call.Return(result, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListFulfillmentsPaginated_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error)) *DatabaseMock_ListFulfillmentsPaginated_Call {
+func (_c *DatabaseMock_Exec_Call) RunAndReturn(run func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)) *DatabaseMock_Exec_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListFulfillmentsPaginatedOptimized provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListFulfillmentsPaginatedOptimized(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error) {
-	ret := _mock.Called(ctx, page, pageSize)
+// ExpireIntents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ExpireIntents(ctx context.Context, createdBefore time.Time) (int64, error) {
+	ret := _mock.Called(ctx, createdBefore)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListFulfillmentsPaginatedOptimized")
+		panic("no return value specified for ExpireIntents")
 	}
 
-	var r0 []*models.Fulfillment
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Fulfillment, int, error)); ok {
-		return returnFunc(ctx, page, pageSize)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Fulfillment); ok {
-		r0 = returnFunc(ctx, page, pageSize)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Fulfillment)
-		}
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, createdBefore)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = returnFunc(ctx, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, createdBefore)
 	} else {
-		r1 = ret.Get(1).(int)
+		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = returnFunc(ctx, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, createdBefore)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListFulfillmentsPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFulfillmentsPaginatedOptimized'
-type DatabaseMock_ListFulfillmentsPaginatedOptimized_Call struct {
+// DatabaseMock_ExpireIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpireIntents'
+type DatabaseMock_ExpireIntents_Call struct {
 	*mock.Call
 }
 
-// ListFulfillmentsPaginatedOptimized is a helper method to define mock.On call
+// ExpireIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListFulfillmentsPaginatedOptimized(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
-	return &DatabaseMock_ListFulfillmentsPaginatedOptimized_Call{Call: _e.mock.On("ListFulfillmentsPaginatedOptimized", ctx, page, pageSize)}
+//   - createdBefore time.Time
+func (_e *DatabaseMock_Expecter) ExpireIntents(ctx interface{}, createdBefore interface{}) *DatabaseMock_ExpireIntents_Call {
+	return &DatabaseMock_ExpireIntents_Call{Call: _e.mock.On("ExpireIntents", ctx, createdBefore)}
 }
 
-func (_c *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
+func (_c *DatabaseMock_ExpireIntents_Call) Run(run func(ctx context.Context, createdBefore time.Time)) *DatabaseMock_ExpireIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg1 = args[1].(time.Time)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call) Return(fulfillments []*models.Fulfillment, n int, err error) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
-	_c.Call.Return(fulfillments, n, err)
+func (_c *DatabaseMock_ExpireIntents_Call) Return(n int64, err error) *DatabaseMock_ExpireIntents_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error)) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
+func (_c *DatabaseMock_ExpireIntents_Call) RunAndReturn(run func(ctx context.Context, createdBefore time.Time) (int64, error)) *DatabaseMock_ExpireIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntents provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntents(ctx context.Context) ([]*models.Intent, error) {
-	ret := _mock.Called(ctx)
+// GetEventCheckpoint provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetEventCheckpoint(ctx context.Context, chainID uint64, eventType string, contractAddress string) (uint64, error) {
+	ret := _mock.Called(ctx, chainID, eventType, contractAddress)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntents")
+		panic("no return value specified for GetEventCheckpoint")
 	}
 
-	var r0 []*models.Intent
+	var r0 uint64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.Intent, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, string) (uint64, error)); ok {
+		return returnFunc(ctx, chainID, eventType, contractAddress)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.Intent); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, string) uint64); ok {
+		r0 = returnFunc(ctx, chainID, eventType, contractAddress)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
-		}
+		r0 = ret.Get(0).(uint64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, string, string) error); ok {
+		r1 = returnFunc(ctx, chainID, eventType, contractAddress)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// DatabaseMock_ListIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntents'
-type DatabaseMock_ListIntents_Call struct {
+// DatabaseMock_GetEventCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEventCheckpoint'
+type DatabaseMock_GetEventCheckpoint_Call struct {
 	*mock.Call
 }
 
-// ListIntents is a helper method to define mock.On call
+// GetEventCheckpoint is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *DatabaseMock_Expecter) ListIntents(ctx interface{}) *DatabaseMock_ListIntents_Call {
-	return &DatabaseMock_ListIntents_Call{Call: _e.mock.On("ListIntents", ctx)}
+//   - chainID uint64
+//   - eventType string
+//   - contractAddress string
+func (_e *DatabaseMock_Expecter) GetEventCheckpoint(ctx interface{}, chainID interface{}, eventType interface{}, contractAddress interface{}) *DatabaseMock_GetEventCheckpoint_Call {
+	return &DatabaseMock_GetEventCheckpoint_Call{Call: _e.mock.On("GetEventCheckpoint", ctx, chainID, eventType, contractAddress)}
 }
 
-func (_c *DatabaseMock_ListIntents_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListIntents_Call {
+func (_c *DatabaseMock_GetEventCheckpoint_Call) Run(run func(ctx context.Context, chainID uint64, eventType string, contractAddress string)) *DatabaseMock_GetEventCheckpoint_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntents_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListIntents_Call {
-	_c.Call.Return(intents, err)
+func (_c *DatabaseMock_GetEventCheckpoint_Call) Return(v uint64, err error) *DatabaseMock_GetEventCheckpoint_Call {
+	_c.Call.Return(v, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntents_Call) RunAndReturn(run func(ctx context.Context) ([]*models.Intent, error)) *DatabaseMock_ListIntents_Call {
+func (_c *DatabaseMock_GetEventCheckpoint_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, eventType string, contractAddress string) (uint64, error)) *DatabaseMock_GetEventCheckpoint_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsByRecipient provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsByRecipient(ctx context.Context, recipient string) ([]*models.Intent, error) {
-	ret := _mock.Called(ctx, recipient)
+// GetFulfillment provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetFulfillment(ctx context.Context, id string) (*models.Fulfillment, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsByRecipient")
+		panic("no return value specified for GetFulfillment")
 	}
 
-	var r0 []*models.Intent
+	var r0 *models.Fulfillment
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*models.Intent, error)); ok {
-		return returnFunc(ctx, recipient)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Fulfillment, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*models.Intent); ok {
-		r0 = returnFunc(ctx, recipient)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Fulfillment); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
+			r0 = ret.Get(0).(*models.Fulfillment)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, recipient)
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// DatabaseMock_ListIntentsByRecipient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsByRecipient'
-type DatabaseMock_ListIntentsByRecipient_Call struct {
+// DatabaseMock_GetFulfillment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFulfillment'
+type DatabaseMock_GetFulfillment_Call struct {
 	*mock.Call
 }
 
-// ListIntentsByRecipient is a helper method to define mock.On call
+// GetFulfillment is a helper method to define mock.On call
 //   - ctx context.Context
-//   - recipient string
-func (_e *DatabaseMock_Expecter) ListIntentsByRecipient(ctx interface{}, recipient interface{}) *DatabaseMock_ListIntentsByRecipient_Call {
-	return &DatabaseMock_ListIntentsByRecipient_Call{Call: _e.mock.On("ListIntentsByRecipient", ctx, recipient)}
+//   - id string
+func (_e *DatabaseMock_Expecter) GetFulfillment(ctx interface{}, id interface{}) *DatabaseMock_GetFulfillment_Call {
+	return &DatabaseMock_GetFulfillment_Call{Call: _e.mock.On("GetFulfillment", ctx, id)}
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipient_Call) Run(run func(ctx context.Context, recipient string)) *DatabaseMock_ListIntentsByRecipient_Call {
+func (_c *DatabaseMock_GetFulfillment_Call) Run(run func(ctx context.Context, id string)) *DatabaseMock_GetFulfillment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1067,65 +1249,57 @@ func (_c *DatabaseMock_ListIntentsByRecipient_Call) Run(run func(ctx context.Con
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipient_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListIntentsByRecipient_Call {
-	_c.Call.Return(intents, err)
+func (_c *DatabaseMock_GetFulfillment_Call) Return(fulfillment *models.Fulfillment, err error) *DatabaseMock_GetFulfillment_Call {
+	_c.Call.Return(fulfillment, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipient_Call) RunAndReturn(run func(ctx context.Context, recipient string) ([]*models.Intent, error)) *DatabaseMock_ListIntentsByRecipient_Call {
+func (_c *DatabaseMock_GetFulfillment_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Fulfillment, error)) *DatabaseMock_GetFulfillment_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsByRecipientPaginated provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsByRecipientPaginated(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error) {
-	ret := _mock.Called(ctx, recipient, page, pageSize)
+// GetIntent provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetIntent(ctx context.Context, id string) (*models.Intent, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsByRecipientPaginated")
+		panic("no return value specified for GetIntent")
 	}
 
-	var r0 []*models.Intent
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
-		return returnFunc(ctx, recipient, page, pageSize)
+	var r0 *models.Intent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Intent, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
-		r0 = returnFunc(ctx, recipient, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Intent); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
+			r0 = ret.Get(0).(*models.Intent)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
-		r1 = returnFunc(ctx, recipient, page, pageSize)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
-		r2 = returnFunc(ctx, recipient, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListIntentsByRecipientPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsByRecipientPaginated'
-type DatabaseMock_ListIntentsByRecipientPaginated_Call struct {
+// DatabaseMock_GetIntent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIntent'
+type DatabaseMock_GetIntent_Call struct {
 	*mock.Call
 }
 
-// ListIntentsByRecipientPaginated is a helper method to define mock.On call
+// GetIntent is a helper method to define mock.On call
 //   - ctx context.Context
-//   - recipient string
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListIntentsByRecipientPaginated(ctx interface{}, recipient interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
-	return &DatabaseMock_ListIntentsByRecipientPaginated_Call{Call: _e.mock.On("ListIntentsByRecipientPaginated", ctx, recipient, page, pageSize)}
+//   - id string
+func (_e *DatabaseMock_Expecter) GetIntent(ctx interface{}, id interface{}) *DatabaseMock_GetIntent_Call {
+	return &DatabaseMock_GetIntent_Call{Call: _e.mock.On("GetIntent", ctx, id)}
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) Run(run func(ctx context.Context, recipient string, page int, pageSize int)) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
+func (_c *DatabaseMock_GetIntent_Call) Run(run func(ctx context.Context, id string)) *DatabaseMock_GetIntent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1135,237 +1309,187 @@ func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) Run(run func(ctx co
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
-	_c.Call.Return(intents, n, err)
+func (_c *DatabaseMock_GetIntent_Call) Return(intent *models.Intent, err error) *DatabaseMock_GetIntent_Call {
+	_c.Call.Return(intent, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) RunAndReturn(run func(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
+func (_c *DatabaseMock_GetIntent_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Intent, error)) *DatabaseMock_GetIntent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsByRecipientPaginatedOptimized provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsByRecipientPaginatedOptimized(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error) {
-	ret := _mock.Called(ctx, recipient, page, pageSize)
+// GetLatestIntentEventCursor provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetLatestIntentEventCursor(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsByRecipientPaginatedOptimized")
+		panic("no return value specified for GetLatestIntentEventCursor")
 	}
 
-	var r0 []*models.Intent
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
-		return returnFunc(ctx, recipient, page, pageSize)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
-		r0 = returnFunc(ctx, recipient, page, pageSize)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
-		}
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
-		r1 = returnFunc(ctx, recipient, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
 	} else {
-		r1 = ret.Get(1).(int)
+		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
-		r2 = returnFunc(ctx, recipient, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsByRecipientPaginatedOptimized'
-type DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call struct {
+// DatabaseMock_GetLatestIntentEventCursor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestIntentEventCursor'
+type DatabaseMock_GetLatestIntentEventCursor_Call struct {
 	*mock.Call
 }
 
-// ListIntentsByRecipientPaginatedOptimized is a helper method to define mock.On call
+// GetLatestIntentEventCursor is a helper method to define mock.On call
 //   - ctx context.Context
-//   - recipient string
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListIntentsByRecipientPaginatedOptimized(ctx interface{}, recipient interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
-	return &DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call{Call: _e.mock.On("ListIntentsByRecipientPaginatedOptimized", ctx, recipient, page, pageSize)}
+func (_e *DatabaseMock_Expecter) GetLatestIntentEventCursor(ctx interface{}) *DatabaseMock_GetLatestIntentEventCursor_Call {
+	return &DatabaseMock_GetLatestIntentEventCursor_Call{Call: _e.mock.On("GetLatestIntentEventCursor", ctx)}
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call) Run(run func(ctx context.Context, recipient string, page int, pageSize int)) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
+func (_c *DatabaseMock_GetLatestIntentEventCursor_Call) Run(run func(ctx context.Context)) *DatabaseMock_GetLatestIntentEventCursor_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
-		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
 		run(
 			arg0,
-			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
-	_c.Call.Return(intents, n, err)
+func (_c *DatabaseMock_GetLatestIntentEventCursor_Call) Return(n int64, err error) *DatabaseMock_GetLatestIntentEventCursor_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
+func (_c *DatabaseMock_GetLatestIntentEventCursor_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *DatabaseMock_GetLatestIntentEventCursor_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsBySender provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsBySender(ctx context.Context, sender string) ([]*models.Intent, error) {
-	ret := _mock.Called(ctx, sender)
+// GetOrphanEventStats provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetOrphanEventStats(ctx context.Context) ([]*models.OrphanEventStats, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsBySender")
+		panic("no return value specified for GetOrphanEventStats")
 	}
 
-	var r0 []*models.Intent
+	var r0 []*models.OrphanEventStats
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*models.Intent, error)); ok {
-		return returnFunc(ctx, sender)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.OrphanEventStats, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*models.Intent); ok {
-		r0 = returnFunc(ctx, sender)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.OrphanEventStats); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
+			r0 = ret.Get(0).([]*models.OrphanEventStats)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, sender)
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// DatabaseMock_ListIntentsBySender_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsBySender'
-type DatabaseMock_ListIntentsBySender_Call struct {
+// DatabaseMock_GetOrphanEventStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrphanEventStats'
+type DatabaseMock_GetOrphanEventStats_Call struct {
 	*mock.Call
 }
 
-// ListIntentsBySender is a helper method to define mock.On call
+// GetOrphanEventStats is a helper method to define mock.On call
 //   - ctx context.Context
-//   - sender string
-func (_e *DatabaseMock_Expecter) ListIntentsBySender(ctx interface{}, sender interface{}) *DatabaseMock_ListIntentsBySender_Call {
-	return &DatabaseMock_ListIntentsBySender_Call{Call: _e.mock.On("ListIntentsBySender", ctx, sender)}
+func (_e *DatabaseMock_Expecter) GetOrphanEventStats(ctx interface{}) *DatabaseMock_GetOrphanEventStats_Call {
+	return &DatabaseMock_GetOrphanEventStats_Call{Call: _e.mock.On("GetOrphanEventStats", ctx)}
 }
 
-func (_c *DatabaseMock_ListIntentsBySender_Call) Run(run func(ctx context.Context, sender string)) *DatabaseMock_ListIntentsBySender_Call {
+func (_c *DatabaseMock_GetOrphanEventStats_Call) Run(run func(ctx context.Context)) *DatabaseMock_GetOrphanEventStats_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
-		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsBySender_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListIntentsBySender_Call {
-	_c.Call.Return(intents, err)
+func (_c *DatabaseMock_GetOrphanEventStats_Call) Return(orphanEventStatss []*models.OrphanEventStats, err error) *DatabaseMock_GetOrphanEventStats_Call {
+	_c.Call.Return(orphanEventStatss, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsBySender_Call) RunAndReturn(run func(ctx context.Context, sender string) ([]*models.Intent, error)) *DatabaseMock_ListIntentsBySender_Call {
+func (_c *DatabaseMock_GetOrphanEventStats_Call) RunAndReturn(run func(ctx context.Context) ([]*models.OrphanEventStats, error)) *DatabaseMock_GetOrphanEventStats_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsBySenderPaginated provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsBySenderPaginated(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error) {
-	ret := _mock.Called(ctx, sender, page, pageSize)
+// GetSettlement provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetSettlement(ctx context.Context, id string) (*models.Settlement, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsBySenderPaginated")
+		panic("no return value specified for GetSettlement")
 	}
 
-	var r0 []*models.Intent
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
-		return returnFunc(ctx, sender, page, pageSize)
+	var r0 *models.Settlement
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*models.Settlement, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
-		r0 = returnFunc(ctx, sender, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *models.Settlement); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
+			r0 = ret.Get(0).(*models.Settlement)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
-		r1 = returnFunc(ctx, sender, page, pageSize)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
-		r2 = returnFunc(ctx, sender, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListIntentsBySenderPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsBySenderPaginated'
-type DatabaseMock_ListIntentsBySenderPaginated_Call struct {
+// DatabaseMock_GetSettlement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSettlement'
+type DatabaseMock_GetSettlement_Call struct {
 	*mock.Call
 }
 
-// ListIntentsBySenderPaginated is a helper method to define mock.On call
+// GetSettlement is a helper method to define mock.On call
 //   - ctx context.Context
-//   - sender string
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListIntentsBySenderPaginated(ctx interface{}, sender interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsBySenderPaginated_Call {
-	return &DatabaseMock_ListIntentsBySenderPaginated_Call{Call: _e.mock.On("ListIntentsBySenderPaginated", ctx, sender, page, pageSize)}
+//   - id string
+func (_e *DatabaseMock_Expecter) GetSettlement(ctx interface{}, id interface{}) *DatabaseMock_GetSettlement_Call {
+	return &DatabaseMock_GetSettlement_Call{Call: _e.mock.On("GetSettlement", ctx, id)}
 }
 
-func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) Run(run func(ctx context.Context, sender string, page int, pageSize int)) *DatabaseMock_ListIntentsBySenderPaginated_Call {
+func (_c *DatabaseMock_GetSettlement_Call) Run(run func(ctx context.Context, id string)) *DatabaseMock_GetSettlement_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1375,83 +1499,63 @@ func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) Run(run func(ctx conte
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsBySenderPaginated_Call {
-	_c.Call.Return(intents, n, err)
+func (_c *DatabaseMock_GetSettlement_Call) Return(settlement *models.Settlement, err error) *DatabaseMock_GetSettlement_Call {
+	_c.Call.Return(settlement, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) RunAndReturn(run func(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsBySenderPaginated_Call {
+func (_c *DatabaseMock_GetSettlement_Call) RunAndReturn(run func(ctx context.Context, id string) (*models.Settlement, error)) *DatabaseMock_GetSettlement_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsBySenderPaginatedOptimized provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsBySenderPaginatedOptimized(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error) {
-	ret := _mock.Called(ctx, sender, page, pageSize)
+// GetTotalFulfilledAmount provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetTotalFulfilledAmount(ctx context.Context, intentID string) (string, error) {
+	ret := _mock.Called(ctx, intentID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsBySenderPaginatedOptimized")
+		panic("no return value specified for GetTotalFulfilledAmount")
 	}
 
-	var r0 []*models.Intent
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
-		return returnFunc(ctx, sender, page, pageSize)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
-		r0 = returnFunc(ctx, sender, page, pageSize)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
-		}
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, intentID)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
-		r1 = returnFunc(ctx, sender, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, intentID)
 	} else {
-		r1 = ret.Get(1).(int)
+		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
-		r2 = returnFunc(ctx, sender, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, intentID)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsBySenderPaginatedOptimized'
-type DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call struct {
+// DatabaseMock_GetTotalFulfilledAmount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTotalFulfilledAmount'
+type DatabaseMock_GetTotalFulfilledAmount_Call struct {
 	*mock.Call
 }
 
-// ListIntentsBySenderPaginatedOptimized is a helper method to define mock.On call
+// GetTotalFulfilledAmount is a helper method to define mock.On call
 //   - ctx context.Context
-//   - sender string
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListIntentsBySenderPaginatedOptimized(ctx interface{}, sender interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
-	return &DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call{Call: _e.mock.On("ListIntentsBySenderPaginatedOptimized", ctx, sender, page, pageSize)}
+//   - intentID string
+func (_e *DatabaseMock_Expecter) GetTotalFulfilledAmount(ctx interface{}, intentID interface{}) *DatabaseMock_GetTotalFulfilledAmount_Call {
+	return &DatabaseMock_GetTotalFulfilledAmount_Call{Call: _e.mock.On("GetTotalFulfilledAmount", ctx, intentID)}
 }
 
-func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) Run(run func(ctx context.Context, sender string, page int, pageSize int)) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
+func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) Run(run func(ctx context.Context, intentID string)) *DatabaseMock_GetTotalFulfilledAmount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1461,316 +1565,235 @@ func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) Run(run func(
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
-	_c.Call.Return(intents, n, err)
+func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) Return(s string, err error) *DatabaseMock_GetTotalFulfilledAmount_Call {
+	_c.Call.Return(s, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
+func (_c *DatabaseMock_GetTotalFulfilledAmount_Call) RunAndReturn(run func(ctx context.Context, intentID string) (string, error)) *DatabaseMock_GetTotalFulfilledAmount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsKeysetPaginated provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsKeysetPaginated(ctx context.Context, lastTimestamp time.Time, lastID string, pageSize int, status string) ([]*models.Intent, bool, error) {
-	ret := _mock.Called(ctx, lastTimestamp, lastID, pageSize, status)
+// GetWebhookSubscription provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) GetWebhookSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsKeysetPaginated")
+		panic("no return value specified for GetWebhookSubscription")
 	}
 
-	var r0 []*models.Intent
-	var r1 bool
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, string, int, string) ([]*models.Intent, bool, error)); ok {
-		return returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	var r0 *models.WebhookSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) (*models.WebhookSubscription, error)); ok {
+		return returnFunc(ctx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, string, int, string) []*models.Intent); ok {
-		r0 = returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) *models.WebhookSubscription); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
+			r0 = ret.Get(0).(*models.WebhookSubscription)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, string, int, string) bool); ok {
-		r1 = returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
-	} else {
-		r1 = ret.Get(1).(bool)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, time.Time, string, int, string) error); ok {
-		r2 = returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListIntentsKeysetPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsKeysetPaginated'
-type DatabaseMock_ListIntentsKeysetPaginated_Call struct {
+// DatabaseMock_GetWebhookSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookSubscription'
+type DatabaseMock_GetWebhookSubscription_Call struct {
 	*mock.Call
 }
 
-// ListIntentsKeysetPaginated is a helper method to define mock.On call
+// GetWebhookSubscription is a helper method to define mock.On call
 //   - ctx context.Context
-//   - lastTimestamp time.Time
-//   - lastID string
-//   - pageSize int
-//   - status string
-func (_e *DatabaseMock_Expecter) ListIntentsKeysetPaginated(ctx interface{}, lastTimestamp interface{}, lastID interface{}, pageSize interface{}, status interface{}) *DatabaseMock_ListIntentsKeysetPaginated_Call {
-	return &DatabaseMock_ListIntentsKeysetPaginated_Call{Call: _e.mock.On("ListIntentsKeysetPaginated", ctx, lastTimestamp, lastID, pageSize, status)}
+//   - id int64
+func (_e *DatabaseMock_Expecter) GetWebhookSubscription(ctx interface{}, id interface{}) *DatabaseMock_GetWebhookSubscription_Call {
+	return &DatabaseMock_GetWebhookSubscription_Call{Call: _e.mock.On("GetWebhookSubscription", ctx, id)}
 }
 
-func (_c *DatabaseMock_ListIntentsKeysetPaginated_Call) Run(run func(ctx context.Context, lastTimestamp time.Time, lastID string, pageSize int, status string)) *DatabaseMock_ListIntentsKeysetPaginated_Call {
+func (_c *DatabaseMock_GetWebhookSubscription_Call) Run(run func(ctx context.Context, id int64)) *DatabaseMock_GetWebhookSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 time.Time
+		var arg1 int64
 		if args[1] != nil {
-			arg1 = args[1].(time.Time)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
-		var arg4 string
-		if args[4] != nil {
-			arg4 = args[4].(string)
+			arg1 = args[1].(int64)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
-			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsKeysetPaginated_Call) Return(intents []*models.Intent, b bool, err error) *DatabaseMock_ListIntentsKeysetPaginated_Call {
-	_c.Call.Return(intents, b, err)
+func (_c *DatabaseMock_GetWebhookSubscription_Call) Return(webhookSubscription *models.WebhookSubscription, err error) *DatabaseMock_GetWebhookSubscription_Call {
+	_c.Call.Return(webhookSubscription, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsKeysetPaginated_Call) RunAndReturn(run func(ctx context.Context, lastTimestamp time.Time, lastID string, pageSize int, status string) ([]*models.Intent, bool, error)) *DatabaseMock_ListIntentsKeysetPaginated_Call {
+func (_c *DatabaseMock_GetWebhookSubscription_Call) RunAndReturn(run func(ctx context.Context, id int64) (*models.WebhookSubscription, error)) *DatabaseMock_GetWebhookSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsPaginated provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsPaginated(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error) {
-	ret := _mock.Called(ctx, page, pageSize, status)
+// InitDB provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) InitDB(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsPaginated")
+		panic("no return value specified for InitDB")
 	}
 
-	var r0 []*models.Intent
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) ([]*models.Intent, int, error)); ok {
-		return returnFunc(ctx, page, pageSize, status)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) []*models.Intent); ok {
-		r0 = returnFunc(ctx, page, pageSize, status)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) int); ok {
-		r1 = returnFunc(ctx, page, pageSize, status)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int, string) error); ok {
-		r2 = returnFunc(ctx, page, pageSize, status)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Error(0)
 	}
-	return r0, r1, r2
+	return r0
 }
 
-// DatabaseMock_ListIntentsPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsPaginated'
-type DatabaseMock_ListIntentsPaginated_Call struct {
+// DatabaseMock_InitDB_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InitDB'
+type DatabaseMock_InitDB_Call struct {
 	*mock.Call
 }
 
-// ListIntentsPaginated is a helper method to define mock.On call
+// InitDB is a helper method to define mock.On call
 //   - ctx context.Context
-//   - page int
-//   - pageSize int
-//   - status string
-func (_e *DatabaseMock_Expecter) ListIntentsPaginated(ctx interface{}, page interface{}, pageSize interface{}, status interface{}) *DatabaseMock_ListIntentsPaginated_Call {
-	return &DatabaseMock_ListIntentsPaginated_Call{Call: _e.mock.On("ListIntentsPaginated", ctx, page, pageSize, status)}
+func (_e *DatabaseMock_Expecter) InitDB(ctx interface{}) *DatabaseMock_InitDB_Call {
+	return &DatabaseMock_InitDB_Call{Call: _e.mock.On("InitDB", ctx)}
 }
 
-func (_c *DatabaseMock_ListIntentsPaginated_Call) Run(run func(ctx context.Context, page int, pageSize int, status string)) *DatabaseMock_ListIntentsPaginated_Call {
+func (_c *DatabaseMock_InitDB_Call) Run(run func(ctx context.Context)) *DatabaseMock_InitDB_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
-		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
-		}
 		run(
 			arg0,
-			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsPaginated_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsPaginated_Call {
-	_c.Call.Return(intents, n, err)
+func (_c *DatabaseMock_InitDB_Call) Return(err error) *DatabaseMock_InitDB_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsPaginated_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsPaginated_Call {
+func (_c *DatabaseMock_InitDB_Call) RunAndReturn(run func(ctx context.Context) error) *DatabaseMock_InitDB_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListIntentsPaginatedOptimized provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListIntentsPaginatedOptimized(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error) {
-	ret := _mock.Called(ctx, page, pageSize, status)
+// ListBlockHashes provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListBlockHashes(ctx context.Context, chainID uint64, fromBlock uint64) ([]*models.ProcessedBlock, error) {
+	ret := _mock.Called(ctx, chainID, fromBlock)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListIntentsPaginatedOptimized")
+		panic("no return value specified for ListBlockHashes")
 	}
 
-	var r0 []*models.Intent
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) ([]*models.Intent, int, error)); ok {
-		return returnFunc(ctx, page, pageSize, status)
+	var r0 []*models.ProcessedBlock
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64) ([]*models.ProcessedBlock, error)); ok {
+		return returnFunc(ctx, chainID, fromBlock)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) []*models.Intent); ok {
-		r0 = returnFunc(ctx, page, pageSize, status)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64) []*models.ProcessedBlock); ok {
+		r0 = returnFunc(ctx, chainID, fromBlock)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Intent)
+			r0 = ret.Get(0).([]*models.ProcessedBlock)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) int); ok {
-		r1 = returnFunc(ctx, page, pageSize, status)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int, string) error); ok {
-		r2 = returnFunc(ctx, page, pageSize, status)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = returnFunc(ctx, chainID, fromBlock)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListIntentsPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsPaginatedOptimized'
-type DatabaseMock_ListIntentsPaginatedOptimized_Call struct {
+// DatabaseMock_ListBlockHashes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBlockHashes'
+type DatabaseMock_ListBlockHashes_Call struct {
 	*mock.Call
 }
 
-// ListIntentsPaginatedOptimized is a helper method to define mock.On call
+// ListBlockHashes is a helper method to define mock.On call
 //   - ctx context.Context
-//   - page int
-//   - pageSize int
-//   - status string
-func (_e *DatabaseMock_Expecter) ListIntentsPaginatedOptimized(ctx interface{}, page interface{}, pageSize interface{}, status interface{}) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
-	return &DatabaseMock_ListIntentsPaginatedOptimized_Call{Call: _e.mock.On("ListIntentsPaginatedOptimized", ctx, page, pageSize, status)}
+//   - chainID uint64
+//   - fromBlock uint64
+func (_e *DatabaseMock_Expecter) ListBlockHashes(ctx interface{}, chainID interface{}, fromBlock interface{}) *DatabaseMock_ListBlockHashes_Call {
+	return &DatabaseMock_ListBlockHashes_Call{Call: _e.mock.On("ListBlockHashes", ctx, chainID, fromBlock)}
 }
 
-func (_c *DatabaseMock_ListIntentsPaginatedOptimized_Call) Run(run func(ctx context.Context, page int, pageSize int, status string)) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
+func (_c *DatabaseMock_ListBlockHashes_Call) Run(run func(ctx context.Context, chainID uint64, fromBlock uint64)) *DatabaseMock_ListBlockHashes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 uint64
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(uint64)
 		}
-		var arg2 int
+		var arg2 uint64
 		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg2 = args[2].(uint64)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsPaginatedOptimized_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
-	_c.Call.Return(intents, n, err)
+func (_c *DatabaseMock_ListBlockHashes_Call) Return(processedBlocks []*models.ProcessedBlock, err error) *DatabaseMock_ListBlockHashes_Call {
+	_c.Call.Return(processedBlocks, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListIntentsPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
+func (_c *DatabaseMock_ListBlockHashes_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, fromBlock uint64) ([]*models.ProcessedBlock, error)) *DatabaseMock_ListBlockHashes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListSettlements provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListSettlements(ctx context.Context) ([]*models.Settlement, error) {
+// ListEventCheckpoints provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListEventCheckpoints(ctx context.Context) ([]*models.EventCheckpoint, error) {
 	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListSettlements")
+		panic("no return value specified for ListEventCheckpoints")
 	}
 
-	var r0 []*models.Settlement
+	var r0 []*models.EventCheckpoint
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.Settlement, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.EventCheckpoint, error)); ok {
 		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.Settlement); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.EventCheckpoint); ok {
 		r0 = returnFunc(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Settlement)
+			r0 = ret.Get(0).([]*models.EventCheckpoint)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
@@ -1781,18 +1804,18 @@ func (_mock *DatabaseMock) ListSettlements(ctx context.Context) ([]*models.Settl
 	return r0, r1
 }
 
-// DatabaseMock_ListSettlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlements'
-type DatabaseMock_ListSettlements_Call struct {
+// DatabaseMock_ListEventCheckpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEventCheckpoints'
+type DatabaseMock_ListEventCheckpoints_Call struct {
 	*mock.Call
 }
 
-// ListSettlements is a helper method to define mock.On call
+// ListEventCheckpoints is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *DatabaseMock_Expecter) ListSettlements(ctx interface{}) *DatabaseMock_ListSettlements_Call {
-	return &DatabaseMock_ListSettlements_Call{Call: _e.mock.On("ListSettlements", ctx)}
+func (_e *DatabaseMock_Expecter) ListEventCheckpoints(ctx interface{}) *DatabaseMock_ListEventCheckpoints_Call {
+	return &DatabaseMock_ListEventCheckpoints_Call{Call: _e.mock.On("ListEventCheckpoints", ctx)}
 }
 
-func (_c *DatabaseMock_ListSettlements_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListSettlements_Call {
+func (_c *DatabaseMock_ListEventCheckpoints_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListEventCheckpoints_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1805,35 +1828,97 @@ func (_c *DatabaseMock_ListSettlements_Call) Run(run func(ctx context.Context))
 	return _c
 }
 
-func (_c *DatabaseMock_ListSettlements_Call) Return(settlements []*models.Settlement, err error) *DatabaseMock_ListSettlements_Call {
-	_c.Call.Return(settlements, err)
+func (_c *DatabaseMock_ListEventCheckpoints_Call) Return(eventCheckpoints []*models.EventCheckpoint, err error) *DatabaseMock_ListEventCheckpoints_Call {
+	_c.Call.Return(eventCheckpoints, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListSettlements_Call) RunAndReturn(run func(ctx context.Context) ([]*models.Settlement, error)) *DatabaseMock_ListSettlements_Call {
+func (_c *DatabaseMock_ListEventCheckpoints_Call) RunAndReturn(run func(ctx context.Context) ([]*models.EventCheckpoint, error)) *DatabaseMock_ListEventCheckpoints_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListSettlementsPaginated provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListSettlementsPaginated(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error) {
+// ListFulfillments provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListFulfillments(ctx context.Context) ([]*models.Fulfillment, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFulfillments")
+	}
+
+	var r0 []*models.Fulfillment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.Fulfillment, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.Fulfillment); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Fulfillment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListFulfillments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFulfillments'
+type DatabaseMock_ListFulfillments_Call struct {
+	*mock.Call
+}
+
+// ListFulfillments is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *DatabaseMock_Expecter) ListFulfillments(ctx interface{}) *DatabaseMock_ListFulfillments_Call {
+	return &DatabaseMock_ListFulfillments_Call{Call: _e.mock.On("ListFulfillments", ctx)}
+}
+
+func (_c *DatabaseMock_ListFulfillments_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListFulfillments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListFulfillments_Call) Return(fulfillments []*models.Fulfillment, err error) *DatabaseMock_ListFulfillments_Call {
+	_c.Call.Return(fulfillments, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListFulfillments_Call) RunAndReturn(run func(ctx context.Context) ([]*models.Fulfillment, error)) *DatabaseMock_ListFulfillments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListFulfillmentsPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListFulfillmentsPaginated(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error) {
 	ret := _mock.Called(ctx, page, pageSize)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListSettlementsPaginated")
+		panic("no return value specified for ListFulfillmentsPaginated")
 	}
 
-	var r0 []*models.Settlement
+	var r0 []*models.Fulfillment
 	var r1 int
 	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Settlement, int, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Fulfillment, int, error)); ok {
 		return returnFunc(ctx, page, pageSize)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Settlement); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Fulfillment); ok {
 		r0 = returnFunc(ctx, page, pageSize)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Settlement)
+			r0 = ret.Get(0).([]*models.Fulfillment)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
@@ -1849,32 +1934,2665 @@ func (_mock *DatabaseMock) ListSettlementsPaginated(ctx context.Context, page in
 	return r0, r1, r2
 }
 
-// DatabaseMock_ListSettlementsPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlementsPaginated'
-type DatabaseMock_ListSettlementsPaginated_Call struct {
+// DatabaseMock_ListFulfillmentsPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFulfillmentsPaginated'
+type DatabaseMock_ListFulfillmentsPaginated_Call struct {
 	*mock.Call
 }
 
-// ListSettlementsPaginated is a helper method to define mock.On call
+// ListFulfillmentsPaginated is a helper method to define mock.On call
 //   - ctx context.Context
 //   - page int
 //   - pageSize int
-func (_e *DatabaseMock_Expecter) ListSettlementsPaginated(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListSettlementsPaginated_Call {
-	return &DatabaseMock_ListSettlementsPaginated_Call{Call: _e.mock.On("ListSettlementsPaginated", ctx, page, pageSize)}
+func (_e *DatabaseMock_Expecter) ListFulfillmentsPaginated(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListFulfillmentsPaginated_Call {
+	return &DatabaseMock_ListFulfillmentsPaginated_Call{Call: _e.mock.On("ListFulfillmentsPaginated", ctx, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListFulfillmentsPaginated_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListFulfillmentsPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListFulfillmentsPaginated_Call) Return(fulfillments []*models.Fulfillment, n int, err error) *DatabaseMock_ListFulfillmentsPaginated_Call {
+	_c.Call.Return(fulfillments, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListFulfillmentsPaginated_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error)) *DatabaseMock_ListFulfillmentsPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListFulfillmentsPaginatedOptimized provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListFulfillmentsPaginatedOptimized(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error) {
+	ret := _mock.Called(ctx, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFulfillmentsPaginatedOptimized")
+	}
+
+	var r0 []*models.Fulfillment
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Fulfillment, int, error)); ok {
+		return returnFunc(ctx, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Fulfillment); ok {
+		r0 = returnFunc(ctx, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Fulfillment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = returnFunc(ctx, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = returnFunc(ctx, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListFulfillmentsPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFulfillmentsPaginatedOptimized'
+type DatabaseMock_ListFulfillmentsPaginatedOptimized_Call struct {
+	*mock.Call
+}
+
+// ListFulfillmentsPaginatedOptimized is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListFulfillmentsPaginatedOptimized(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
+	return &DatabaseMock_ListFulfillmentsPaginatedOptimized_Call{Call: _e.mock.On("ListFulfillmentsPaginatedOptimized", ctx, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call) Return(fulfillments []*models.Fulfillment, n int, err error) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
+	_c.Call.Return(fulfillments, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Fulfillment, int, error)) *DatabaseMock_ListFulfillmentsPaginatedOptimized_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentEvents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentEvents(ctx context.Context, afterCursor int64, limit int) ([]*models.IntentEvent, error) {
+	ret := _mock.Called(ctx, afterCursor, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentEvents")
+	}
+
+	var r0 []*models.IntentEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int) ([]*models.IntentEvent, error)); ok {
+		return returnFunc(ctx, afterCursor, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int) []*models.IntentEvent); ok {
+		r0 = returnFunc(ctx, afterCursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.IntentEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = returnFunc(ctx, afterCursor, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListIntentEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentEvents'
+type DatabaseMock_ListIntentEvents_Call struct {
+	*mock.Call
+}
+
+// ListIntentEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterCursor int64
+//   - limit int
+func (_e *DatabaseMock_Expecter) ListIntentEvents(ctx interface{}, afterCursor interface{}, limit interface{}) *DatabaseMock_ListIntentEvents_Call {
+	return &DatabaseMock_ListIntentEvents_Call{Call: _e.mock.On("ListIntentEvents", ctx, afterCursor, limit)}
+}
+
+func (_c *DatabaseMock_ListIntentEvents_Call) Run(run func(ctx context.Context, afterCursor int64, limit int)) *DatabaseMock_ListIntentEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentEvents_Call) Return(intentEvents []*models.IntentEvent, err error) *DatabaseMock_ListIntentEvents_Call {
+	_c.Call.Return(intentEvents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentEvents_Call) RunAndReturn(run func(ctx context.Context, afterCursor int64, limit int) ([]*models.IntentEvent, error)) *DatabaseMock_ListIntentEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentFulfillments provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentFulfillments(ctx context.Context, intentID string) ([]*models.Fulfillment, error) {
+	ret := _mock.Called(ctx, intentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentFulfillments")
+	}
+
+	var r0 []*models.Fulfillment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*models.Fulfillment, error)); ok {
+		return returnFunc(ctx, intentID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*models.Fulfillment); ok {
+		r0 = returnFunc(ctx, intentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Fulfillment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, intentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListIntentFulfillments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentFulfillments'
+type DatabaseMock_ListIntentFulfillments_Call struct {
+	*mock.Call
+}
+
+// ListIntentFulfillments is a helper method to define mock.On call
+//   - ctx context.Context
+//   - intentID string
+func (_e *DatabaseMock_Expecter) ListIntentFulfillments(ctx interface{}, intentID interface{}) *DatabaseMock_ListIntentFulfillments_Call {
+	return &DatabaseMock_ListIntentFulfillments_Call{Call: _e.mock.On("ListIntentFulfillments", ctx, intentID)}
+}
+
+func (_c *DatabaseMock_ListIntentFulfillments_Call) Run(run func(ctx context.Context, intentID string)) *DatabaseMock_ListIntentFulfillments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentFulfillments_Call) Return(fulfillments []*models.Fulfillment, err error) *DatabaseMock_ListIntentFulfillments_Call {
+	_c.Call.Return(fulfillments, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentFulfillments_Call) RunAndReturn(run func(ctx context.Context, intentID string) ([]*models.Fulfillment, error)) *DatabaseMock_ListIntentFulfillments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentStatusHistory provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentStatusHistory(ctx context.Context, intentID string) ([]*models.IntentStatusTransition, error) {
+	ret := _mock.Called(ctx, intentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentStatusHistory")
+	}
+
+	var r0 []*models.IntentStatusTransition
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*models.IntentStatusTransition, error)); ok {
+		return returnFunc(ctx, intentID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*models.IntentStatusTransition); ok {
+		r0 = returnFunc(ctx, intentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.IntentStatusTransition)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, intentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListIntentStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentStatusHistory'
+type DatabaseMock_ListIntentStatusHistory_Call struct {
+	*mock.Call
+}
+
+// ListIntentStatusHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - intentID string
+func (_e *DatabaseMock_Expecter) ListIntentStatusHistory(ctx interface{}, intentID interface{}) *DatabaseMock_ListIntentStatusHistory_Call {
+	return &DatabaseMock_ListIntentStatusHistory_Call{Call: _e.mock.On("ListIntentStatusHistory", ctx, intentID)}
+}
+
+func (_c *DatabaseMock_ListIntentStatusHistory_Call) Run(run func(ctx context.Context, intentID string)) *DatabaseMock_ListIntentStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentStatusHistory_Call) Return(intentStatusTransitions []*models.IntentStatusTransition, err error) *DatabaseMock_ListIntentStatusHistory_Call {
+	_c.Call.Return(intentStatusTransitions, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentStatusHistory_Call) RunAndReturn(run func(ctx context.Context, intentID string) ([]*models.IntentStatusTransition, error)) *DatabaseMock_ListIntentStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntents(ctx context.Context) ([]*models.Intent, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntents")
+	}
+
+	var r0 []*models.Intent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.Intent, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.Intent); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntents'
+type DatabaseMock_ListIntents_Call struct {
+	*mock.Call
+}
+
+// ListIntents is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *DatabaseMock_Expecter) ListIntents(ctx interface{}) *DatabaseMock_ListIntents_Call {
+	return &DatabaseMock_ListIntents_Call{Call: _e.mock.On("ListIntents", ctx)}
+}
+
+func (_c *DatabaseMock_ListIntents_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntents_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListIntents_Call {
+	_c.Call.Return(intents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntents_Call) RunAndReturn(run func(ctx context.Context) ([]*models.Intent, error)) *DatabaseMock_ListIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsByRecipient provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsByRecipient(ctx context.Context, recipient string) ([]*models.Intent, error) {
+	ret := _mock.Called(ctx, recipient)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsByRecipient")
+	}
+
+	var r0 []*models.Intent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*models.Intent, error)); ok {
+		return returnFunc(ctx, recipient)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*models.Intent); ok {
+		r0 = returnFunc(ctx, recipient)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, recipient)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListIntentsByRecipient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsByRecipient'
+type DatabaseMock_ListIntentsByRecipient_Call struct {
+	*mock.Call
+}
+
+// ListIntentsByRecipient is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipient string
+func (_e *DatabaseMock_Expecter) ListIntentsByRecipient(ctx interface{}, recipient interface{}) *DatabaseMock_ListIntentsByRecipient_Call {
+	return &DatabaseMock_ListIntentsByRecipient_Call{Call: _e.mock.On("ListIntentsByRecipient", ctx, recipient)}
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipient_Call) Run(run func(ctx context.Context, recipient string)) *DatabaseMock_ListIntentsByRecipient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipient_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListIntentsByRecipient_Call {
+	_c.Call.Return(intents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipient_Call) RunAndReturn(run func(ctx context.Context, recipient string) ([]*models.Intent, error)) *DatabaseMock_ListIntentsByRecipient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsByRecipientPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsByRecipientPaginated(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error) {
+	ret := _mock.Called(ctx, recipient, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsByRecipientPaginated")
+	}
+
+	var r0 []*models.Intent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
+		return returnFunc(ctx, recipient, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
+		r0 = returnFunc(ctx, recipient, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = returnFunc(ctx, recipient, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = returnFunc(ctx, recipient, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsByRecipientPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsByRecipientPaginated'
+type DatabaseMock_ListIntentsByRecipientPaginated_Call struct {
+	*mock.Call
+}
+
+// ListIntentsByRecipientPaginated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipient string
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListIntentsByRecipientPaginated(ctx interface{}, recipient interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
+	return &DatabaseMock_ListIntentsByRecipientPaginated_Call{Call: _e.mock.On("ListIntentsByRecipientPaginated", ctx, recipient, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) Run(run func(ctx context.Context, recipient string, page int, pageSize int)) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
+	_c.Call.Return(intents, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipientPaginated_Call) RunAndReturn(run func(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsByRecipientPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsByRecipientPaginatedOptimized provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsByRecipientPaginatedOptimized(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error) {
+	ret := _mock.Called(ctx, recipient, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsByRecipientPaginatedOptimized")
+	}
+
+	var r0 []*models.Intent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
+		return returnFunc(ctx, recipient, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
+		r0 = returnFunc(ctx, recipient, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = returnFunc(ctx, recipient, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = returnFunc(ctx, recipient, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsByRecipientPaginatedOptimized'
+type DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call struct {
+	*mock.Call
+}
+
+// ListIntentsByRecipientPaginatedOptimized is a helper method to define mock.On call
+//   - ctx context.Context
+//   - recipient string
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListIntentsByRecipientPaginatedOptimized(ctx interface{}, recipient interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
+	return &DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call{Call: _e.mock.On("ListIntentsByRecipientPaginatedOptimized", ctx, recipient, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call) Run(run func(ctx context.Context, recipient string, page int, pageSize int)) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
+	_c.Call.Return(intents, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, recipient string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsByRecipientPaginatedOptimized_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsBySender provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsBySender(ctx context.Context, sender string) ([]*models.Intent, error) {
+	ret := _mock.Called(ctx, sender)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsBySender")
+	}
+
+	var r0 []*models.Intent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*models.Intent, error)); ok {
+		return returnFunc(ctx, sender)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*models.Intent); ok {
+		r0 = returnFunc(ctx, sender)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, sender)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListIntentsBySender_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsBySender'
+type DatabaseMock_ListIntentsBySender_Call struct {
+	*mock.Call
+}
+
+// ListIntentsBySender is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sender string
+func (_e *DatabaseMock_Expecter) ListIntentsBySender(ctx interface{}, sender interface{}) *DatabaseMock_ListIntentsBySender_Call {
+	return &DatabaseMock_ListIntentsBySender_Call{Call: _e.mock.On("ListIntentsBySender", ctx, sender)}
+}
+
+func (_c *DatabaseMock_ListIntentsBySender_Call) Run(run func(ctx context.Context, sender string)) *DatabaseMock_ListIntentsBySender_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsBySender_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListIntentsBySender_Call {
+	_c.Call.Return(intents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsBySender_Call) RunAndReturn(run func(ctx context.Context, sender string) ([]*models.Intent, error)) *DatabaseMock_ListIntentsBySender_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsBySenderPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsBySenderPaginated(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error) {
+	ret := _mock.Called(ctx, sender, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsBySenderPaginated")
+	}
+
+	var r0 []*models.Intent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
+		return returnFunc(ctx, sender, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
+		r0 = returnFunc(ctx, sender, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = returnFunc(ctx, sender, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = returnFunc(ctx, sender, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsBySenderPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsBySenderPaginated'
+type DatabaseMock_ListIntentsBySenderPaginated_Call struct {
+	*mock.Call
+}
+
+// ListIntentsBySenderPaginated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sender string
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListIntentsBySenderPaginated(ctx interface{}, sender interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsBySenderPaginated_Call {
+	return &DatabaseMock_ListIntentsBySenderPaginated_Call{Call: _e.mock.On("ListIntentsBySenderPaginated", ctx, sender, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) Run(run func(ctx context.Context, sender string, page int, pageSize int)) *DatabaseMock_ListIntentsBySenderPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsBySenderPaginated_Call {
+	_c.Call.Return(intents, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsBySenderPaginated_Call) RunAndReturn(run func(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsBySenderPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsBySenderPaginatedOptimized provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsBySenderPaginatedOptimized(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error) {
+	ret := _mock.Called(ctx, sender, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsBySenderPaginatedOptimized")
+	}
+
+	var r0 []*models.Intent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Intent, int, error)); ok {
+		return returnFunc(ctx, sender, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Intent); ok {
+		r0 = returnFunc(ctx, sender, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = returnFunc(ctx, sender, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = returnFunc(ctx, sender, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsBySenderPaginatedOptimized'
+type DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call struct {
+	*mock.Call
+}
+
+// ListIntentsBySenderPaginatedOptimized is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sender string
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListIntentsBySenderPaginatedOptimized(ctx interface{}, sender interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
+	return &DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call{Call: _e.mock.On("ListIntentsBySenderPaginatedOptimized", ctx, sender, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) Run(run func(ctx context.Context, sender string, page int, pageSize int)) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
+	_c.Call.Return(intents, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, sender string, page int, pageSize int) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsBySenderPaginatedOptimized_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsKeysetPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsKeysetPaginated(ctx context.Context, lastTimestamp time.Time, lastID string, pageSize int, status string) ([]*models.Intent, bool, error) {
+	ret := _mock.Called(ctx, lastTimestamp, lastID, pageSize, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsKeysetPaginated")
+	}
+
+	var r0 []*models.Intent
+	var r1 bool
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, string, int, string) ([]*models.Intent, bool, error)); ok {
+		return returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, string, int, string) []*models.Intent); ok {
+		r0 = returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, string, int, string) bool); ok {
+		r1 = returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, time.Time, string, int, string) error); ok {
+		r2 = returnFunc(ctx, lastTimestamp, lastID, pageSize, status)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsKeysetPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsKeysetPaginated'
+type DatabaseMock_ListIntentsKeysetPaginated_Call struct {
+	*mock.Call
+}
+
+// ListIntentsKeysetPaginated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lastTimestamp time.Time
+//   - lastID string
+//   - pageSize int
+//   - status string
+func (_e *DatabaseMock_Expecter) ListIntentsKeysetPaginated(ctx interface{}, lastTimestamp interface{}, lastID interface{}, pageSize interface{}, status interface{}) *DatabaseMock_ListIntentsKeysetPaginated_Call {
+	return &DatabaseMock_ListIntentsKeysetPaginated_Call{Call: _e.mock.On("ListIntentsKeysetPaginated", ctx, lastTimestamp, lastID, pageSize, status)}
+}
+
+func (_c *DatabaseMock_ListIntentsKeysetPaginated_Call) Run(run func(ctx context.Context, lastTimestamp time.Time, lastID string, pageSize int, status string)) *DatabaseMock_ListIntentsKeysetPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsKeysetPaginated_Call) Return(intents []*models.Intent, b bool, err error) *DatabaseMock_ListIntentsKeysetPaginated_Call {
+	_c.Call.Return(intents, b, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsKeysetPaginated_Call) RunAndReturn(run func(ctx context.Context, lastTimestamp time.Time, lastID string, pageSize int, status string) ([]*models.Intent, bool, error)) *DatabaseMock_ListIntentsKeysetPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsPaginated(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error) {
+	ret := _mock.Called(ctx, page, pageSize, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsPaginated")
+	}
+
+	var r0 []*models.Intent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) ([]*models.Intent, int, error)); ok {
+		return returnFunc(ctx, page, pageSize, status)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) []*models.Intent); ok {
+		r0 = returnFunc(ctx, page, pageSize, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) int); ok {
+		r1 = returnFunc(ctx, page, pageSize, status)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int, string) error); ok {
+		r2 = returnFunc(ctx, page, pageSize, status)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsPaginated'
+type DatabaseMock_ListIntentsPaginated_Call struct {
+	*mock.Call
+}
+
+// ListIntentsPaginated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - pageSize int
+//   - status string
+func (_e *DatabaseMock_Expecter) ListIntentsPaginated(ctx interface{}, page interface{}, pageSize interface{}, status interface{}) *DatabaseMock_ListIntentsPaginated_Call {
+	return &DatabaseMock_ListIntentsPaginated_Call{Call: _e.mock.On("ListIntentsPaginated", ctx, page, pageSize, status)}
+}
+
+func (_c *DatabaseMock_ListIntentsPaginated_Call) Run(run func(ctx context.Context, page int, pageSize int, status string)) *DatabaseMock_ListIntentsPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsPaginated_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsPaginated_Call {
+	_c.Call.Return(intents, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsPaginated_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListIntentsPaginatedOptimized provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListIntentsPaginatedOptimized(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error) {
+	ret := _mock.Called(ctx, page, pageSize, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIntentsPaginatedOptimized")
+	}
+
+	var r0 []*models.Intent
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) ([]*models.Intent, int, error)); ok {
+		return returnFunc(ctx, page, pageSize, status)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) []*models.Intent); ok {
+		r0 = returnFunc(ctx, page, pageSize, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, string) int); ok {
+		r1 = returnFunc(ctx, page, pageSize, status)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int, string) error); ok {
+		r2 = returnFunc(ctx, page, pageSize, status)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListIntentsPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListIntentsPaginatedOptimized'
+type DatabaseMock_ListIntentsPaginatedOptimized_Call struct {
+	*mock.Call
+}
+
+// ListIntentsPaginatedOptimized is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - pageSize int
+//   - status string
+func (_e *DatabaseMock_Expecter) ListIntentsPaginatedOptimized(ctx interface{}, page interface{}, pageSize interface{}, status interface{}) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
+	return &DatabaseMock_ListIntentsPaginatedOptimized_Call{Call: _e.mock.On("ListIntentsPaginatedOptimized", ctx, page, pageSize, status)}
+}
+
+func (_c *DatabaseMock_ListIntentsPaginatedOptimized_Call) Run(run func(ctx context.Context, page int, pageSize int, status string)) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsPaginatedOptimized_Call) Return(intents []*models.Intent, n int, err error) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
+	_c.Call.Return(intents, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListIntentsPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int, status string) ([]*models.Intent, int, error)) *DatabaseMock_ListIntentsPaginatedOptimized_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLeaderboardPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListLeaderboardPaginated(ctx context.Context, query models.LeaderboardQuery) ([]*models.LeaderboardEntry, int, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLeaderboardPaginated")
+	}
+
+	var r0 []*models.LeaderboardEntry
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.LeaderboardQuery) ([]*models.LeaderboardEntry, int, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.LeaderboardQuery) []*models.LeaderboardEntry); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.LeaderboardEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.LeaderboardQuery) int); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, models.LeaderboardQuery) error); ok {
+		r2 = returnFunc(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListLeaderboardPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLeaderboardPaginated'
+type DatabaseMock_ListLeaderboardPaginated_Call struct {
+	*mock.Call
+}
+
+// ListLeaderboardPaginated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query models.LeaderboardQuery
+func (_e *DatabaseMock_Expecter) ListLeaderboardPaginated(ctx interface{}, query interface{}) *DatabaseMock_ListLeaderboardPaginated_Call {
+	return &DatabaseMock_ListLeaderboardPaginated_Call{Call: _e.mock.On("ListLeaderboardPaginated", ctx, query)}
+}
+
+func (_c *DatabaseMock_ListLeaderboardPaginated_Call) Run(run func(ctx context.Context, query models.LeaderboardQuery)) *DatabaseMock_ListLeaderboardPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.LeaderboardQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.LeaderboardQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListLeaderboardPaginated_Call) Return(leaderboardEntrys []*models.LeaderboardEntry, n int, err error) *DatabaseMock_ListLeaderboardPaginated_Call {
+	_c.Call.Return(leaderboardEntrys, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListLeaderboardPaginated_Call) RunAndReturn(run func(ctx context.Context, query models.LeaderboardQuery) ([]*models.LeaderboardEntry, int, error)) *DatabaseMock_ListLeaderboardPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListReconcilableOrphanEvents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListReconcilableOrphanEvents(ctx context.Context, maxAttempts int, limit int) ([]*models.OrphanEvent, error) {
+	ret := _mock.Called(ctx, maxAttempts, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListReconcilableOrphanEvents")
+	}
+
+	var r0 []*models.OrphanEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.OrphanEvent, error)); ok {
+		return returnFunc(ctx, maxAttempts, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.OrphanEvent); ok {
+		r0 = returnFunc(ctx, maxAttempts, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.OrphanEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, maxAttempts, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListReconcilableOrphanEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListReconcilableOrphanEvents'
+type DatabaseMock_ListReconcilableOrphanEvents_Call struct {
+	*mock.Call
+}
+
+// ListReconcilableOrphanEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - maxAttempts int
+//   - limit int
+func (_e *DatabaseMock_Expecter) ListReconcilableOrphanEvents(ctx interface{}, maxAttempts interface{}, limit interface{}) *DatabaseMock_ListReconcilableOrphanEvents_Call {
+	return &DatabaseMock_ListReconcilableOrphanEvents_Call{Call: _e.mock.On("ListReconcilableOrphanEvents", ctx, maxAttempts, limit)}
+}
+
+func (_c *DatabaseMock_ListReconcilableOrphanEvents_Call) Run(run func(ctx context.Context, maxAttempts int, limit int)) *DatabaseMock_ListReconcilableOrphanEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListReconcilableOrphanEvents_Call) Return(orphanEvents []*models.OrphanEvent, err error) *DatabaseMock_ListReconcilableOrphanEvents_Call {
+	_c.Call.Return(orphanEvents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListReconcilableOrphanEvents_Call) RunAndReturn(run func(ctx context.Context, maxAttempts int, limit int) ([]*models.OrphanEvent, error)) *DatabaseMock_ListReconcilableOrphanEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSettlements provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListSettlements(ctx context.Context) ([]*models.Settlement, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSettlements")
+	}
+
+	var r0 []*models.Settlement
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.Settlement, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.Settlement); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Settlement)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListSettlements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlements'
+type DatabaseMock_ListSettlements_Call struct {
+	*mock.Call
+}
+
+// ListSettlements is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *DatabaseMock_Expecter) ListSettlements(ctx interface{}) *DatabaseMock_ListSettlements_Call {
+	return &DatabaseMock_ListSettlements_Call{Call: _e.mock.On("ListSettlements", ctx)}
+}
+
+func (_c *DatabaseMock_ListSettlements_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListSettlements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlements_Call) Return(settlements []*models.Settlement, err error) *DatabaseMock_ListSettlements_Call {
+	_c.Call.Return(settlements, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlements_Call) RunAndReturn(run func(ctx context.Context) ([]*models.Settlement, error)) *DatabaseMock_ListSettlements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSettlementsByFulfillerPaginatedOptimized provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListSettlementsByFulfillerPaginatedOptimized(ctx context.Context, fulfiller string, page int, pageSize int) ([]*models.Settlement, int, error) {
+	ret := _mock.Called(ctx, fulfiller, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSettlementsByFulfillerPaginatedOptimized")
+	}
+
+	var r0 []*models.Settlement
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*models.Settlement, int, error)); ok {
+		return returnFunc(ctx, fulfiller, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int) []*models.Settlement); ok {
+		r0 = returnFunc(ctx, fulfiller, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Settlement)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int) int); ok {
+		r1 = returnFunc(ctx, fulfiller, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = returnFunc(ctx, fulfiller, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlementsByFulfillerPaginatedOptimized'
+type DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call struct {
+	*mock.Call
+}
+
+// ListSettlementsByFulfillerPaginatedOptimized is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fulfiller string
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListSettlementsByFulfillerPaginatedOptimized(ctx interface{}, fulfiller interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call {
+	return &DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call{Call: _e.mock.On("ListSettlementsByFulfillerPaginatedOptimized", ctx, fulfiller, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call) Run(run func(ctx context.Context, fulfiller string, page int, pageSize int)) *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call) Return(settlements []*models.Settlement, n int, err error) *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call {
+	_c.Call.Return(settlements, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, fulfiller string, page int, pageSize int) ([]*models.Settlement, int, error)) *DatabaseMock_ListSettlementsByFulfillerPaginatedOptimized_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSettlementsPaginated provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListSettlementsPaginated(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error) {
+	ret := _mock.Called(ctx, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSettlementsPaginated")
+	}
+
+	var r0 []*models.Settlement
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Settlement, int, error)); ok {
+		return returnFunc(ctx, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Settlement); ok {
+		r0 = returnFunc(ctx, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Settlement)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = returnFunc(ctx, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = returnFunc(ctx, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListSettlementsPaginated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlementsPaginated'
+type DatabaseMock_ListSettlementsPaginated_Call struct {
+	*mock.Call
+}
+
+// ListSettlementsPaginated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListSettlementsPaginated(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListSettlementsPaginated_Call {
+	return &DatabaseMock_ListSettlementsPaginated_Call{Call: _e.mock.On("ListSettlementsPaginated", ctx, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListSettlementsPaginated_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListSettlementsPaginated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlementsPaginated_Call) Return(settlements []*models.Settlement, n int, err error) *DatabaseMock_ListSettlementsPaginated_Call {
+	_c.Call.Return(settlements, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlementsPaginated_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error)) *DatabaseMock_ListSettlementsPaginated_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSettlementsPaginatedOptimized provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListSettlementsPaginatedOptimized(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error) {
+	ret := _mock.Called(ctx, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSettlementsPaginatedOptimized")
+	}
+
+	var r0 []*models.Settlement
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Settlement, int, error)); ok {
+		return returnFunc(ctx, page, pageSize)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Settlement); ok {
+		r0 = returnFunc(ctx, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Settlement)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
+		r1 = returnFunc(ctx, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = returnFunc(ctx, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// DatabaseMock_ListSettlementsPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlementsPaginatedOptimized'
+type DatabaseMock_ListSettlementsPaginatedOptimized_Call struct {
+	*mock.Call
+}
+
+// ListSettlementsPaginatedOptimized is a helper method to define mock.On call
+//   - ctx context.Context
+//   - page int
+//   - pageSize int
+func (_e *DatabaseMock_Expecter) ListSettlementsPaginatedOptimized(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
+	return &DatabaseMock_ListSettlementsPaginatedOptimized_Call{Call: _e.mock.On("ListSettlementsPaginatedOptimized", ctx, page, pageSize)}
+}
+
+func (_c *DatabaseMock_ListSettlementsPaginatedOptimized_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlementsPaginatedOptimized_Call) Return(settlements []*models.Settlement, n int, err error) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
+	_c.Call.Return(settlements, n, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListSettlementsPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error)) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUnconfirmedEvents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListUnconfirmedEvents(ctx context.Context, chainID uint64, upToBlock uint64, maxAttempts int) ([]*models.UnconfirmedEvent, error) {
+	ret := _mock.Called(ctx, chainID, upToBlock, maxAttempts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnconfirmedEvents")
+	}
+
+	var r0 []*models.UnconfirmedEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) ([]*models.UnconfirmedEvent, error)); ok {
+		return returnFunc(ctx, chainID, upToBlock, maxAttempts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64, int) []*models.UnconfirmedEvent); ok {
+		r0 = returnFunc(ctx, chainID, upToBlock, maxAttempts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.UnconfirmedEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, uint64, int) error); ok {
+		r1 = returnFunc(ctx, chainID, upToBlock, maxAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListUnconfirmedEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUnconfirmedEvents'
+type DatabaseMock_ListUnconfirmedEvents_Call struct {
+	*mock.Call
+}
+
+// ListUnconfirmedEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - upToBlock uint64
+//   - maxAttempts int
+func (_e *DatabaseMock_Expecter) ListUnconfirmedEvents(ctx interface{}, chainID interface{}, upToBlock interface{}, maxAttempts interface{}) *DatabaseMock_ListUnconfirmedEvents_Call {
+	return &DatabaseMock_ListUnconfirmedEvents_Call{Call: _e.mock.On("ListUnconfirmedEvents", ctx, chainID, upToBlock, maxAttempts)}
+}
+
+func (_c *DatabaseMock_ListUnconfirmedEvents_Call) Run(run func(ctx context.Context, chainID uint64, upToBlock uint64, maxAttempts int)) *DatabaseMock_ListUnconfirmedEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListUnconfirmedEvents_Call) Return(unconfirmedEvents []*models.UnconfirmedEvent, err error) *DatabaseMock_ListUnconfirmedEvents_Call {
+	_c.Call.Return(unconfirmedEvents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListUnconfirmedEvents_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, upToBlock uint64, maxAttempts int) ([]*models.UnconfirmedEvent, error)) *DatabaseMock_ListUnconfirmedEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUnvaluedIntents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListUnvaluedIntents(ctx context.Context, sourceChain uint64, afterID string, limit int) ([]*models.Intent, error) {
+	ret := _mock.Called(ctx, sourceChain, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnvaluedIntents")
+	}
+
+	var r0 []*models.Intent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, int) ([]*models.Intent, error)); ok {
+		return returnFunc(ctx, sourceChain, afterID, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, int) []*models.Intent); ok {
+		r0 = returnFunc(ctx, sourceChain, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, string, int) error); ok {
+		r1 = returnFunc(ctx, sourceChain, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListUnvaluedIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUnvaluedIntents'
+type DatabaseMock_ListUnvaluedIntents_Call struct {
+	*mock.Call
+}
+
+// ListUnvaluedIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sourceChain uint64
+//   - afterID string
+//   - limit int
+func (_e *DatabaseMock_Expecter) ListUnvaluedIntents(ctx interface{}, sourceChain interface{}, afterID interface{}, limit interface{}) *DatabaseMock_ListUnvaluedIntents_Call {
+	return &DatabaseMock_ListUnvaluedIntents_Call{Call: _e.mock.On("ListUnvaluedIntents", ctx, sourceChain, afterID, limit)}
+}
+
+func (_c *DatabaseMock_ListUnvaluedIntents_Call) Run(run func(ctx context.Context, sourceChain uint64, afterID string, limit int)) *DatabaseMock_ListUnvaluedIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListUnvaluedIntents_Call) Return(intents []*models.Intent, err error) *DatabaseMock_ListUnvaluedIntents_Call {
+	_c.Call.Return(intents, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListUnvaluedIntents_Call) RunAndReturn(run func(ctx context.Context, sourceChain uint64, afterID string, limit int) ([]*models.Intent, error)) *DatabaseMock_ListUnvaluedIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUnverifiedSkippedRanges provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListUnverifiedSkippedRanges(ctx context.Context, chainID uint64, limit int) ([]*models.SkippedRange, error) {
+	ret := _mock.Called(ctx, chainID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnverifiedSkippedRanges")
+	}
+
+	var r0 []*models.SkippedRange
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, int) ([]*models.SkippedRange, error)); ok {
+		return returnFunc(ctx, chainID, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, int) []*models.SkippedRange); ok {
+		r0 = returnFunc(ctx, chainID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SkippedRange)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = returnFunc(ctx, chainID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListUnverifiedSkippedRanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUnverifiedSkippedRanges'
+type DatabaseMock_ListUnverifiedSkippedRanges_Call struct {
+	*mock.Call
+}
+
+// ListUnverifiedSkippedRanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - limit int
+func (_e *DatabaseMock_Expecter) ListUnverifiedSkippedRanges(ctx interface{}, chainID interface{}, limit interface{}) *DatabaseMock_ListUnverifiedSkippedRanges_Call {
+	return &DatabaseMock_ListUnverifiedSkippedRanges_Call{Call: _e.mock.On("ListUnverifiedSkippedRanges", ctx, chainID, limit)}
+}
+
+func (_c *DatabaseMock_ListUnverifiedSkippedRanges_Call) Run(run func(ctx context.Context, chainID uint64, limit int)) *DatabaseMock_ListUnverifiedSkippedRanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListUnverifiedSkippedRanges_Call) Return(skippedRanges []*models.SkippedRange, err error) *DatabaseMock_ListUnverifiedSkippedRanges_Call {
+	_c.Call.Return(skippedRanges, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListUnverifiedSkippedRanges_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, limit int) ([]*models.SkippedRange, error)) *DatabaseMock_ListUnverifiedSkippedRanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWebhookDeliveries provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status models.WebhookDeliveryStatus, limit int) ([]*models.WebhookDelivery, error) {
+	ret := _mock.Called(ctx, subscriptionID, status, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhookDeliveries")
+	}
+
+	var r0 []*models.WebhookDelivery
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, models.WebhookDeliveryStatus, int) ([]*models.WebhookDelivery, error)); ok {
+		return returnFunc(ctx, subscriptionID, status, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, models.WebhookDeliveryStatus, int) []*models.WebhookDelivery); ok {
+		r0 = returnFunc(ctx, subscriptionID, status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookDelivery)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, models.WebhookDeliveryStatus, int) error); ok {
+		r1 = returnFunc(ctx, subscriptionID, status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebhookDeliveries'
+type DatabaseMock_ListWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// ListWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subscriptionID int64
+//   - status models.WebhookDeliveryStatus
+//   - limit int
+func (_e *DatabaseMock_Expecter) ListWebhookDeliveries(ctx interface{}, subscriptionID interface{}, status interface{}, limit interface{}) *DatabaseMock_ListWebhookDeliveries_Call {
+	return &DatabaseMock_ListWebhookDeliveries_Call{Call: _e.mock.On("ListWebhookDeliveries", ctx, subscriptionID, status, limit)}
+}
+
+func (_c *DatabaseMock_ListWebhookDeliveries_Call) Run(run func(ctx context.Context, subscriptionID int64, status models.WebhookDeliveryStatus, limit int)) *DatabaseMock_ListWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 models.WebhookDeliveryStatus
+		if args[2] != nil {
+			arg2 = args[2].(models.WebhookDeliveryStatus)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListWebhookDeliveries_Call) Return(webhookDeliverys []*models.WebhookDelivery, err error) *DatabaseMock_ListWebhookDeliveries_Call {
+	_c.Call.Return(webhookDeliverys, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListWebhookDeliveries_Call) RunAndReturn(run func(ctx context.Context, subscriptionID int64, status models.WebhookDeliveryStatus, limit int) ([]*models.WebhookDelivery, error)) *DatabaseMock_ListWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWebhookSubscriptions provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) ListWebhookSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhookSubscriptions")
+	}
+
+	var r0 []*models.WebhookSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]*models.WebhookSubscription, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []*models.WebhookSubscription); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.WebhookSubscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_ListWebhookSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWebhookSubscriptions'
+type DatabaseMock_ListWebhookSubscriptions_Call struct {
+	*mock.Call
+}
+
+// ListWebhookSubscriptions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *DatabaseMock_Expecter) ListWebhookSubscriptions(ctx interface{}) *DatabaseMock_ListWebhookSubscriptions_Call {
+	return &DatabaseMock_ListWebhookSubscriptions_Call{Call: _e.mock.On("ListWebhookSubscriptions", ctx)}
+}
+
+func (_c *DatabaseMock_ListWebhookSubscriptions_Call) Run(run func(ctx context.Context)) *DatabaseMock_ListWebhookSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_ListWebhookSubscriptions_Call) Return(webhookSubscriptions []*models.WebhookSubscription, err error) *DatabaseMock_ListWebhookSubscriptions_Call {
+	_c.Call.Return(webhookSubscriptions, err)
+	return _c
+}
+
+func (_c *DatabaseMock_ListWebhookSubscriptions_Call) RunAndReturn(run func(ctx context.Context) ([]*models.WebhookSubscription, error)) *DatabaseMock_ListWebhookSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkSkippedRangeVerified provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) MarkSkippedRangeVerified(ctx context.Context, id int64, missedBlocks []uint64) error {
+	ret := _mock.Called(ctx, id, missedBlocks)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSkippedRangeVerified")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, []uint64) error); ok {
+		r0 = returnFunc(ctx, id, missedBlocks)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_MarkSkippedRangeVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkSkippedRangeVerified'
+type DatabaseMock_MarkSkippedRangeVerified_Call struct {
+	*mock.Call
+}
+
+// MarkSkippedRangeVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - missedBlocks []uint64
+func (_e *DatabaseMock_Expecter) MarkSkippedRangeVerified(ctx interface{}, id interface{}, missedBlocks interface{}) *DatabaseMock_MarkSkippedRangeVerified_Call {
+	return &DatabaseMock_MarkSkippedRangeVerified_Call{Call: _e.mock.On("MarkSkippedRangeVerified", ctx, id, missedBlocks)}
+}
+
+func (_c *DatabaseMock_MarkSkippedRangeVerified_Call) Run(run func(ctx context.Context, id int64, missedBlocks []uint64)) *DatabaseMock_MarkSkippedRangeVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 []uint64
+		if args[2] != nil {
+			arg2 = args[2].([]uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_MarkSkippedRangeVerified_Call) Return(err error) *DatabaseMock_MarkSkippedRangeVerified_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_MarkSkippedRangeVerified_Call) RunAndReturn(run func(ctx context.Context, id int64, missedBlocks []uint64) error) *DatabaseMock_MarkSkippedRangeVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) Ping() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type DatabaseMock_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+func (_e *DatabaseMock_Expecter) Ping() *DatabaseMock_Ping_Call {
+	return &DatabaseMock_Ping_Call{Call: _e.mock.On("Ping")}
+}
+
+func (_c *DatabaseMock_Ping_Call) Run(run func()) *DatabaseMock_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_Ping_Call) Return(err error) *DatabaseMock_Ping_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_Ping_Call) RunAndReturn(run func() error) *DatabaseMock_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PrepareStatements provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) PrepareStatements(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrepareStatements")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_PrepareStatements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrepareStatements'
+type DatabaseMock_PrepareStatements_Call struct {
+	*mock.Call
+}
+
+// PrepareStatements is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *DatabaseMock_Expecter) PrepareStatements(ctx interface{}) *DatabaseMock_PrepareStatements_Call {
+	return &DatabaseMock_PrepareStatements_Call{Call: _e.mock.On("PrepareStatements", ctx)}
+}
+
+func (_c *DatabaseMock_PrepareStatements_Call) Run(run func(ctx context.Context)) *DatabaseMock_PrepareStatements_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_PrepareStatements_Call) Return(err error) *DatabaseMock_PrepareStatements_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_PrepareStatements_Call) RunAndReturn(run func(ctx context.Context) error) *DatabaseMock_PrepareStatements_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PruneBlockHashes provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) PruneBlockHashes(ctx context.Context, chainID uint64, belowBlock uint64) error {
+	ret := _mock.Called(ctx, chainID, belowBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneBlockHashes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = returnFunc(ctx, chainID, belowBlock)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_PruneBlockHashes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PruneBlockHashes'
+type DatabaseMock_PruneBlockHashes_Call struct {
+	*mock.Call
+}
+
+// PruneBlockHashes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - belowBlock uint64
+func (_e *DatabaseMock_Expecter) PruneBlockHashes(ctx interface{}, chainID interface{}, belowBlock interface{}) *DatabaseMock_PruneBlockHashes_Call {
+	return &DatabaseMock_PruneBlockHashes_Call{Call: _e.mock.On("PruneBlockHashes", ctx, chainID, belowBlock)}
+}
+
+func (_c *DatabaseMock_PruneBlockHashes_Call) Run(run func(ctx context.Context, chainID uint64, belowBlock uint64)) *DatabaseMock_PruneBlockHashes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_PruneBlockHashes_Call) Return(err error) *DatabaseMock_PruneBlockHashes_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_PruneBlockHashes_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, belowBlock uint64) error) *DatabaseMock_PruneBlockHashes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Query provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, query, args)
+	} else {
+		tmpRet = _mock.Called(ctx, query)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 *sql.Rows
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) (*sql.Rows, error)); ok {
+		return returnFunc(ctx, query, args...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Rows); ok {
+		r0 = returnFunc(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Rows)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = returnFunc(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// DatabaseMock_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type DatabaseMock_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - args ...interface{}
+func (_e *DatabaseMock_Expecter) Query(ctx interface{}, query interface{}, args ...interface{}) *DatabaseMock_Query_Call {
+	return &DatabaseMock_Query_Call{Call: _e.mock.On("Query",
+		append([]interface{}{ctx, query}, args...)...)}
+}
+
+func (_c *DatabaseMock_Query_Call) Run(run func(ctx context.Context, query string, args ...interface{})) *DatabaseMock_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []interface{}
+		var variadicArgs []interface{}
+		if len(args) > 2 {
+			variadicArgs = args[2].([]interface{})
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_Query_Call) Return(rows *sql.Rows, err error) *DatabaseMock_Query_Call {
+	_c.Call.Return(rows, err)
+	return _c
+}
+
+func (_c *DatabaseMock_Query_Call) RunAndReturn(run func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)) *DatabaseMock_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryRow provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, query, args)
+	} else {
+		tmpRet = _mock.Called(ctx, query)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryRow")
+	}
+
+	var r0 *sql.Row
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Row); ok {
+		r0 = returnFunc(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Row)
+		}
+	}
+	return r0
+}
+
+// DatabaseMock_QueryRow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRow'
+type DatabaseMock_QueryRow_Call struct {
+	*mock.Call
+}
+
+// QueryRow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - args ...interface{}
+func (_e *DatabaseMock_Expecter) QueryRow(ctx interface{}, query interface{}, args ...interface{}) *DatabaseMock_QueryRow_Call {
+	return &DatabaseMock_QueryRow_Call{Call: _e.mock.On("QueryRow",
+		append([]interface{}{ctx, query}, args...)...)}
+}
+
+func (_c *DatabaseMock_QueryRow_Call) Run(run func(ctx context.Context, query string, args ...interface{})) *DatabaseMock_QueryRow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []interface{}
+		var variadicArgs []interface{}
+		if len(args) > 2 {
+			variadicArgs = args[2].([]interface{})
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_QueryRow_Call) Return(row *sql.Row) *DatabaseMock_QueryRow_Call {
+	_c.Call.Return(row)
+	return _c
+}
+
+func (_c *DatabaseMock_QueryRow_Call) RunAndReturn(run func(ctx context.Context, query string, args ...interface{}) *sql.Row) *DatabaseMock_QueryRow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordBlockHash provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) RecordBlockHash(ctx context.Context, chainID uint64, blockNumber uint64, blockHash string) error {
+	ret := _mock.Called(ctx, chainID, blockNumber, blockHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordBlockHash")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64, string) error); ok {
+		r0 = returnFunc(ctx, chainID, blockNumber, blockHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_RecordBlockHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordBlockHash'
+type DatabaseMock_RecordBlockHash_Call struct {
+	*mock.Call
+}
+
+// RecordBlockHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - blockNumber uint64
+//   - blockHash string
+func (_e *DatabaseMock_Expecter) RecordBlockHash(ctx interface{}, chainID interface{}, blockNumber interface{}, blockHash interface{}) *DatabaseMock_RecordBlockHash_Call {
+	return &DatabaseMock_RecordBlockHash_Call{Call: _e.mock.On("RecordBlockHash", ctx, chainID, blockNumber, blockHash)}
+}
+
+func (_c *DatabaseMock_RecordBlockHash_Call) Run(run func(ctx context.Context, chainID uint64, blockNumber uint64, blockHash string)) *DatabaseMock_RecordBlockHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_RecordBlockHash_Call) Return(err error) *DatabaseMock_RecordBlockHash_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_RecordBlockHash_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, blockNumber uint64, blockHash string) error) *DatabaseMock_RecordBlockHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordOrphanEventFailure provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) RecordOrphanEventFailure(ctx context.Context, id int64, reason string) error {
+	ret := _mock.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordOrphanEventFailure")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = returnFunc(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_RecordOrphanEventFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordOrphanEventFailure'
+type DatabaseMock_RecordOrphanEventFailure_Call struct {
+	*mock.Call
+}
+
+// RecordOrphanEventFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - reason string
+func (_e *DatabaseMock_Expecter) RecordOrphanEventFailure(ctx interface{}, id interface{}, reason interface{}) *DatabaseMock_RecordOrphanEventFailure_Call {
+	return &DatabaseMock_RecordOrphanEventFailure_Call{Call: _e.mock.On("RecordOrphanEventFailure", ctx, id, reason)}
+}
+
+func (_c *DatabaseMock_RecordOrphanEventFailure_Call) Run(run func(ctx context.Context, id int64, reason string)) *DatabaseMock_RecordOrphanEventFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_RecordOrphanEventFailure_Call) Return(err error) *DatabaseMock_RecordOrphanEventFailure_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_RecordOrphanEventFailure_Call) RunAndReturn(run func(ctx context.Context, id int64, reason string) error) *DatabaseMock_RecordOrphanEventFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordSkippedRange provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) RecordSkippedRange(ctx context.Context, skipped *models.SkippedRange) error {
+	ret := _mock.Called(ctx, skipped)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordSkippedRange")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.SkippedRange) error); ok {
+		r0 = returnFunc(ctx, skipped)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_RecordSkippedRange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordSkippedRange'
+type DatabaseMock_RecordSkippedRange_Call struct {
+	*mock.Call
+}
+
+// RecordSkippedRange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - skipped *models.SkippedRange
+func (_e *DatabaseMock_Expecter) RecordSkippedRange(ctx interface{}, skipped interface{}) *DatabaseMock_RecordSkippedRange_Call {
+	return &DatabaseMock_RecordSkippedRange_Call{Call: _e.mock.On("RecordSkippedRange", ctx, skipped)}
+}
+
+func (_c *DatabaseMock_RecordSkippedRange_Call) Run(run func(ctx context.Context, skipped *models.SkippedRange)) *DatabaseMock_RecordSkippedRange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.SkippedRange
+		if args[1] != nil {
+			arg1 = args[1].(*models.SkippedRange)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_RecordSkippedRange_Call) Return(err error) *DatabaseMock_RecordSkippedRange_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_RecordSkippedRange_Call) RunAndReturn(run func(ctx context.Context, skipped *models.SkippedRange) error) *DatabaseMock_RecordSkippedRange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordUnconfirmedEventFailure provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) RecordUnconfirmedEventFailure(ctx context.Context, chainID uint64, blockHash string, logIndex uint, reason string) error {
+	ret := _mock.Called(ctx, chainID, blockHash, logIndex, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordUnconfirmedEventFailure")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, uint, string) error); ok {
+		r0 = returnFunc(ctx, chainID, blockHash, logIndex, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_RecordUnconfirmedEventFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordUnconfirmedEventFailure'
+type DatabaseMock_RecordUnconfirmedEventFailure_Call struct {
+	*mock.Call
+}
+
+// RecordUnconfirmedEventFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID uint64
+//   - blockHash string
+//   - logIndex uint
+//   - reason string
+func (_e *DatabaseMock_Expecter) RecordUnconfirmedEventFailure(ctx interface{}, chainID interface{}, blockHash interface{}, logIndex interface{}, reason interface{}) *DatabaseMock_RecordUnconfirmedEventFailure_Call {
+	return &DatabaseMock_RecordUnconfirmedEventFailure_Call{Call: _e.mock.On("RecordUnconfirmedEventFailure", ctx, chainID, blockHash, logIndex, reason)}
+}
+
+func (_c *DatabaseMock_RecordUnconfirmedEventFailure_Call) Run(run func(ctx context.Context, chainID uint64, blockHash string, logIndex uint, reason string)) *DatabaseMock_RecordUnconfirmedEventFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 uint
+		if args[3] != nil {
+			arg3 = args[3].(uint)
+		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *DatabaseMock_RecordUnconfirmedEventFailure_Call) Return(err error) *DatabaseMock_RecordUnconfirmedEventFailure_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *DatabaseMock_RecordUnconfirmedEventFailure_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, blockHash string, logIndex uint, reason string) error) *DatabaseMock_RecordUnconfirmedEventFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordWebhookAttempt provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) RecordWebhookAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	ret := _mock.Called(ctx, delivery, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordWebhookAttempt")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WebhookDelivery, *models.WebhookDeliveryAttempt) error); ok {
+		r0 = returnFunc(ctx, delivery, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// DatabaseMock_RecordWebhookAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordWebhookAttempt'
+type DatabaseMock_RecordWebhookAttempt_Call struct {
+	*mock.Call
 }
 
-func (_c *DatabaseMock_ListSettlementsPaginated_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListSettlementsPaginated_Call {
+// RecordWebhookAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - delivery *models.WebhookDelivery
+//   - attempt *models.WebhookDeliveryAttempt
+func (_e *DatabaseMock_Expecter) RecordWebhookAttempt(ctx interface{}, delivery interface{}, attempt interface{}) *DatabaseMock_RecordWebhookAttempt_Call {
+	return &DatabaseMock_RecordWebhookAttempt_Call{Call: _e.mock.On("RecordWebhookAttempt", ctx, delivery, attempt)}
+}
+
+func (_c *DatabaseMock_RecordWebhookAttempt_Call) Run(run func(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt)) *DatabaseMock_RecordWebhookAttempt_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 *models.WebhookDelivery
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(*models.WebhookDelivery)
 		}
-		var arg2 int
+		var arg2 *models.WebhookDeliveryAttempt
 		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg2 = args[2].(*models.WebhookDeliveryAttempt)
 		}
 		run(
 			arg0,
@@ -1885,379 +4603,391 @@ func (_c *DatabaseMock_ListSettlementsPaginated_Call) Run(run func(ctx context.C
 	return _c
 }
 
-func (_c *DatabaseMock_ListSettlementsPaginated_Call) Return(settlements []*models.Settlement, n int, err error) *DatabaseMock_ListSettlementsPaginated_Call {
-	_c.Call.Return(settlements, n, err)
+func (_c *DatabaseMock_RecordWebhookAttempt_Call) Return(err error) *DatabaseMock_RecordWebhookAttempt_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListSettlementsPaginated_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error)) *DatabaseMock_ListSettlementsPaginated_Call {
+func (_c *DatabaseMock_RecordWebhookAttempt_Call) RunAndReturn(run func(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error) *DatabaseMock_RecordWebhookAttempt_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListSettlementsPaginatedOptimized provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) ListSettlementsPaginatedOptimized(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error) {
-	ret := _mock.Called(ctx, page, pageSize)
+// RollbackFromBlock provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) RollbackFromBlock(ctx context.Context, chainID uint64, fromBlock uint64, orphanedHashes []string) (*models.RollbackResult, error) {
+	ret := _mock.Called(ctx, chainID, fromBlock, orphanedHashes)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListSettlementsPaginatedOptimized")
+		panic("no return value specified for RollbackFromBlock")
 	}
 
-	var r0 []*models.Settlement
-	var r1 int
-	var r2 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.Settlement, int, error)); ok {
-		return returnFunc(ctx, page, pageSize)
+	var r0 *models.RollbackResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64, []string) (*models.RollbackResult, error)); ok {
+		return returnFunc(ctx, chainID, fromBlock, orphanedHashes)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []*models.Settlement); ok {
-		r0 = returnFunc(ctx, page, pageSize)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64, []string) *models.RollbackResult); ok {
+		r0 = returnFunc(ctx, chainID, fromBlock, orphanedHashes)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Settlement)
+			r0 = ret.Get(0).(*models.RollbackResult)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = returnFunc(ctx, page, pageSize)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = returnFunc(ctx, page, pageSize)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, uint64, []string) error); ok {
+		r1 = returnFunc(ctx, chainID, fromBlock, orphanedHashes)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2
+	return r0, r1
 }
 
-// DatabaseMock_ListSettlementsPaginatedOptimized_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSettlementsPaginatedOptimized'
-type DatabaseMock_ListSettlementsPaginatedOptimized_Call struct {
+// DatabaseMock_RollbackFromBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackFromBlock'
+type DatabaseMock_RollbackFromBlock_Call struct {
 	*mock.Call
 }
 
-// ListSettlementsPaginatedOptimized is a helper method to define mock.On call
+// RollbackFromBlock is a helper method to define mock.On call
 //   - ctx context.Context
-//   - page int
-//   - pageSize int
-func (_e *DatabaseMock_Expecter) ListSettlementsPaginatedOptimized(ctx interface{}, page interface{}, pageSize interface{}) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
-	return &DatabaseMock_ListSettlementsPaginatedOptimized_Call{Call: _e.mock.On("ListSettlementsPaginatedOptimized", ctx, page, pageSize)}
+//   - chainID uint64
+//   - fromBlock uint64
+//   - orphanedHashes []string
+func (_e *DatabaseMock_Expecter) RollbackFromBlock(ctx interface{}, chainID interface{}, fromBlock interface{}, orphanedHashes interface{}) *DatabaseMock_RollbackFromBlock_Call {
+	return &DatabaseMock_RollbackFromBlock_Call{Call: _e.mock.On("RollbackFromBlock", ctx, chainID, fromBlock, orphanedHashes)}
 }
 
-func (_c *DatabaseMock_ListSettlementsPaginatedOptimized_Call) Run(run func(ctx context.Context, page int, pageSize int)) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
+func (_c *DatabaseMock_RollbackFromBlock_Call) Run(run func(ctx context.Context, chainID uint64, fromBlock uint64, orphanedHashes []string)) *DatabaseMock_RollbackFromBlock_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 uint64
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(uint64)
 		}
-		var arg2 int
+		var arg2 uint64
 		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg2 = args[2].(uint64)
+		}
+		var arg3 []string
+		if args[3] != nil {
+			arg3 = args[3].([]string)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_ListSettlementsPaginatedOptimized_Call) Return(settlements []*models.Settlement, n int, err error) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
-	_c.Call.Return(settlements, n, err)
+func (_c *DatabaseMock_RollbackFromBlock_Call) Return(rollbackResult *models.RollbackResult, err error) *DatabaseMock_RollbackFromBlock_Call {
+	_c.Call.Return(rollbackResult, err)
 	return _c
 }
 
-func (_c *DatabaseMock_ListSettlementsPaginatedOptimized_Call) RunAndReturn(run func(ctx context.Context, page int, pageSize int) ([]*models.Settlement, int, error)) *DatabaseMock_ListSettlementsPaginatedOptimized_Call {
+func (_c *DatabaseMock_RollbackFromBlock_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, fromBlock uint64, orphanedHashes []string) (*models.RollbackResult, error)) *DatabaseMock_RollbackFromBlock_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Ping provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) Ping() error {
-	ret := _mock.Called()
+// SearchIntents provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) SearchIntents(ctx context.Context, query models.IntentQuery) ([]*models.Intent, bool, error) {
+	ret := _mock.Called(ctx, query)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Ping")
+		panic("no return value specified for SearchIntents")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func() error); ok {
-		r0 = returnFunc()
+	var r0 []*models.Intent
+	var r1 bool
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.IntentQuery) ([]*models.Intent, bool, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.IntentQuery) []*models.Intent); ok {
+		r0 = returnFunc(ctx, query)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Intent)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.IntentQuery) bool); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, models.IntentQuery) error); ok {
+		r2 = returnFunc(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
-// DatabaseMock_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
-type DatabaseMock_Ping_Call struct {
+// DatabaseMock_SearchIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchIntents'
+type DatabaseMock_SearchIntents_Call struct {
 	*mock.Call
 }
 
-// Ping is a helper method to define mock.On call
-func (_e *DatabaseMock_Expecter) Ping() *DatabaseMock_Ping_Call {
-	return &DatabaseMock_Ping_Call{Call: _e.mock.On("Ping")}
+// SearchIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query models.IntentQuery
+func (_e *DatabaseMock_Expecter) SearchIntents(ctx interface{}, query interface{}) *DatabaseMock_SearchIntents_Call {
+	return &DatabaseMock_SearchIntents_Call{Call: _e.mock.On("SearchIntents", ctx, query)}
 }
 
-func (_c *DatabaseMock_Ping_Call) Run(run func()) *DatabaseMock_Ping_Call {
+func (_c *DatabaseMock_SearchIntents_Call) Run(run func(ctx context.Context, query models.IntentQuery)) *DatabaseMock_SearchIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.IntentQuery
+		if args[1] != nil {
+			arg1 = args[1].(models.IntentQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_Ping_Call) Return(err error) *DatabaseMock_Ping_Call {
-	_c.Call.Return(err)
+func (_c *DatabaseMock_SearchIntents_Call) Return(intents []*models.Intent, b bool, err error) *DatabaseMock_SearchIntents_Call {
+	_c.Call.Return(intents, b, err)
 	return _c
 }
 
-func (_c *DatabaseMock_Ping_Call) RunAndReturn(run func() error) *DatabaseMock_Ping_Call {
+func (_c *DatabaseMock_SearchIntents_Call) RunAndReturn(run func(ctx context.Context, query models.IntentQuery) ([]*models.Intent, bool, error)) *DatabaseMock_SearchIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PrepareStatements provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) PrepareStatements(ctx context.Context) error {
-	ret := _mock.Called(ctx)
+// StageUnconfirmedEvent provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) StageUnconfirmedEvent(ctx context.Context, event *models.UnconfirmedEvent) error {
+	ret := _mock.Called(ctx, event)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PrepareStatements")
+		panic("no return value specified for StageUnconfirmedEvent")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.UnconfirmedEvent) error); ok {
+		r0 = returnFunc(ctx, event)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// DatabaseMock_PrepareStatements_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrepareStatements'
-type DatabaseMock_PrepareStatements_Call struct {
+// DatabaseMock_StageUnconfirmedEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StageUnconfirmedEvent'
+type DatabaseMock_StageUnconfirmedEvent_Call struct {
 	*mock.Call
 }
 
-// PrepareStatements is a helper method to define mock.On call
+// StageUnconfirmedEvent is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *DatabaseMock_Expecter) PrepareStatements(ctx interface{}) *DatabaseMock_PrepareStatements_Call {
-	return &DatabaseMock_PrepareStatements_Call{Call: _e.mock.On("PrepareStatements", ctx)}
+//   - event *models.UnconfirmedEvent
+func (_e *DatabaseMock_Expecter) StageUnconfirmedEvent(ctx interface{}, event interface{}) *DatabaseMock_StageUnconfirmedEvent_Call {
+	return &DatabaseMock_StageUnconfirmedEvent_Call{Call: _e.mock.On("StageUnconfirmedEvent", ctx, event)}
 }
 
-func (_c *DatabaseMock_PrepareStatements_Call) Run(run func(ctx context.Context)) *DatabaseMock_PrepareStatements_Call {
+func (_c *DatabaseMock_StageUnconfirmedEvent_Call) Run(run func(ctx context.Context, event *models.UnconfirmedEvent)) *DatabaseMock_StageUnconfirmedEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 *models.UnconfirmedEvent
+		if args[1] != nil {
+			arg1 = args[1].(*models.UnconfirmedEvent)
+		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_PrepareStatements_Call) Return(err error) *DatabaseMock_PrepareStatements_Call {
+func (_c *DatabaseMock_StageUnconfirmedEvent_Call) Return(err error) *DatabaseMock_StageUnconfirmedEvent_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_PrepareStatements_Call) RunAndReturn(run func(ctx context.Context) error) *DatabaseMock_PrepareStatements_Call {
+func (_c *DatabaseMock_StageUnconfirmedEvent_Call) RunAndReturn(run func(ctx context.Context, event *models.UnconfirmedEvent) error) *DatabaseMock_StageUnconfirmedEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Query provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	var tmpRet mock.Arguments
-	if len(args) > 0 {
-		tmpRet = _mock.Called(ctx, query, args)
-	} else {
-		tmpRet = _mock.Called(ctx, query)
-	}
-	ret := tmpRet
+// UpdateEventCheckpoint provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) UpdateEventCheckpoint(ctx context.Context, chainID uint64, eventType string, contractAddress string, blockNumber uint64) error {
+	ret := _mock.Called(ctx, chainID, eventType, contractAddress, blockNumber)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Query")
+		panic("no return value specified for UpdateEventCheckpoint")
 	}
 
-	var r0 *sql.Rows
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) (*sql.Rows, error)); ok {
-		return returnFunc(ctx, query, args...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Rows); ok {
-		r0 = returnFunc(ctx, query, args...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*sql.Rows)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
-		r1 = returnFunc(ctx, query, args...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string, string, uint64) error); ok {
+		r0 = returnFunc(ctx, chainID, eventType, contractAddress, blockNumber)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// DatabaseMock_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
-type DatabaseMock_Query_Call struct {
+// DatabaseMock_UpdateEventCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEventCheckpoint'
+type DatabaseMock_UpdateEventCheckpoint_Call struct {
 	*mock.Call
 }
 
-// Query is a helper method to define mock.On call
+// UpdateEventCheckpoint is a helper method to define mock.On call
 //   - ctx context.Context
-//   - query string
-//   - args ...interface{}
-func (_e *DatabaseMock_Expecter) Query(ctx interface{}, query interface{}, args ...interface{}) *DatabaseMock_Query_Call {
-	return &DatabaseMock_Query_Call{Call: _e.mock.On("Query",
-		append([]interface{}{ctx, query}, args...)...)}
+//   - chainID uint64
+//   - eventType string
+//   - contractAddress string
+//   - blockNumber uint64
+func (_e *DatabaseMock_Expecter) UpdateEventCheckpoint(ctx interface{}, chainID interface{}, eventType interface{}, contractAddress interface{}, blockNumber interface{}) *DatabaseMock_UpdateEventCheckpoint_Call {
+	return &DatabaseMock_UpdateEventCheckpoint_Call{Call: _e.mock.On("UpdateEventCheckpoint", ctx, chainID, eventType, contractAddress, blockNumber)}
 }
 
-func (_c *DatabaseMock_Query_Call) Run(run func(ctx context.Context, query string, args ...interface{})) *DatabaseMock_Query_Call {
+func (_c *DatabaseMock_UpdateEventCheckpoint_Call) Run(run func(ctx context.Context, chainID uint64, eventType string, contractAddress string, blockNumber uint64)) *DatabaseMock_UpdateEventCheckpoint_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 uint64
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(uint64)
 		}
-		var arg2 []interface{}
-		var variadicArgs []interface{}
-		if len(args) > 2 {
-			variadicArgs = args[2].([]interface{})
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 uint64
+		if args[4] != nil {
+			arg4 = args[4].(uint64)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
+			arg2,
+			arg3,
+			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_Query_Call) Return(rows *sql.Rows, err error) *DatabaseMock_Query_Call {
-	_c.Call.Return(rows, err)
+func (_c *DatabaseMock_UpdateEventCheckpoint_Call) Return(err error) *DatabaseMock_UpdateEventCheckpoint_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_Query_Call) RunAndReturn(run func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)) *DatabaseMock_Query_Call {
+func (_c *DatabaseMock_UpdateEventCheckpoint_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, eventType string, contractAddress string, blockNumber uint64) error) *DatabaseMock_UpdateEventCheckpoint_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryRow provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) QueryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	var tmpRet mock.Arguments
-	if len(args) > 0 {
-		tmpRet = _mock.Called(ctx, query, args)
-	} else {
-		tmpRet = _mock.Called(ctx, query)
-	}
-	ret := tmpRet
+// UpdateIntentStatus provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) UpdateIntentStatus(ctx context.Context, transition *models.IntentStatusTransition) error {
+	ret := _mock.Called(ctx, transition)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryRow")
+		panic("no return value specified for UpdateIntentStatus")
 	}
 
-	var r0 *sql.Row
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Row); ok {
-		r0 = returnFunc(ctx, query, args...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.IntentStatusTransition) error); ok {
+		r0 = returnFunc(ctx, transition)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*sql.Row)
-		}
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// DatabaseMock_QueryRow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRow'
-type DatabaseMock_QueryRow_Call struct {
+// DatabaseMock_UpdateIntentStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIntentStatus'
+type DatabaseMock_UpdateIntentStatus_Call struct {
 	*mock.Call
 }
 
-// QueryRow is a helper method to define mock.On call
+// UpdateIntentStatus is a helper method to define mock.On call
 //   - ctx context.Context
-//   - query string
-//   - args ...interface{}
-func (_e *DatabaseMock_Expecter) QueryRow(ctx interface{}, query interface{}, args ...interface{}) *DatabaseMock_QueryRow_Call {
-	return &DatabaseMock_QueryRow_Call{Call: _e.mock.On("QueryRow",
-		append([]interface{}{ctx, query}, args...)...)}
+//   - transition *models.IntentStatusTransition
+func (_e *DatabaseMock_Expecter) UpdateIntentStatus(ctx interface{}, transition interface{}) *DatabaseMock_UpdateIntentStatus_Call {
+	return &DatabaseMock_UpdateIntentStatus_Call{Call: _e.mock.On("UpdateIntentStatus", ctx, transition)}
 }
 
-func (_c *DatabaseMock_QueryRow_Call) Run(run func(ctx context.Context, query string, args ...interface{})) *DatabaseMock_QueryRow_Call {
+func (_c *DatabaseMock_UpdateIntentStatus_Call) Run(run func(ctx context.Context, transition *models.IntentStatusTransition)) *DatabaseMock_UpdateIntentStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *models.IntentStatusTransition
 		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 []interface{}
-		var variadicArgs []interface{}
-		if len(args) > 2 {
-			variadicArgs = args[2].([]interface{})
+			arg1 = args[1].(*models.IntentStatusTransition)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_QueryRow_Call) Return(row *sql.Row) *DatabaseMock_QueryRow_Call {
-	_c.Call.Return(row)
+func (_c *DatabaseMock_UpdateIntentStatus_Call) Return(err error) *DatabaseMock_UpdateIntentStatus_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_QueryRow_Call) RunAndReturn(run func(ctx context.Context, query string, args ...interface{}) *sql.Row) *DatabaseMock_QueryRow_Call {
+func (_c *DatabaseMock_UpdateIntentStatus_Call) RunAndReturn(run func(ctx context.Context, transition *models.IntentStatusTransition) error) *DatabaseMock_UpdateIntentStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateIntentStatus provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) UpdateIntentStatus(ctx context.Context, id string, status models.IntentStatus) error {
-	ret := _mock.Called(ctx, id, status)
+// UpdateIntentUSDValue provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) UpdateIntentUSDValue(ctx context.Context, id string, amountUSD string, intentFeeUSD string) error {
+	ret := _mock.Called(ctx, id, amountUSD, intentFeeUSD)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateIntentStatus")
+		panic("no return value specified for UpdateIntentUSDValue")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.IntentStatus) error); ok {
-		r0 = returnFunc(ctx, id, status)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = returnFunc(ctx, id, amountUSD, intentFeeUSD)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// DatabaseMock_UpdateIntentStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIntentStatus'
-type DatabaseMock_UpdateIntentStatus_Call struct {
+// DatabaseMock_UpdateIntentUSDValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIntentUSDValue'
+type DatabaseMock_UpdateIntentUSDValue_Call struct {
 	*mock.Call
 }
 
-// UpdateIntentStatus is a helper method to define mock.On call
+// UpdateIntentUSDValue is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-//   - status models.IntentStatus
-func (_e *DatabaseMock_Expecter) UpdateIntentStatus(ctx interface{}, id interface{}, status interface{}) *DatabaseMock_UpdateIntentStatus_Call {
-	return &DatabaseMock_UpdateIntentStatus_Call{Call: _e.mock.On("UpdateIntentStatus", ctx, id, status)}
+//   - amountUSD string
+//   - intentFeeUSD string
+func (_e *DatabaseMock_Expecter) UpdateIntentUSDValue(ctx interface{}, id interface{}, amountUSD interface{}, intentFeeUSD interface{}) *DatabaseMock_UpdateIntentUSDValue_Call {
+	return &DatabaseMock_UpdateIntentUSDValue_Call{Call: _e.mock.On("UpdateIntentUSDValue", ctx, id, amountUSD, intentFeeUSD)}
 }
 
-func (_c *DatabaseMock_UpdateIntentStatus_Call) Run(run func(ctx context.Context, id string, status models.IntentStatus)) *DatabaseMock_UpdateIntentStatus_Call {
+func (_c *DatabaseMock_UpdateIntentUSDValue_Call) Run(run func(ctx context.Context, id string, amountUSD string, intentFeeUSD string)) *DatabaseMock_UpdateIntentUSDValue_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -2267,88 +4997,87 @@ func (_c *DatabaseMock_UpdateIntentStatus_Call) Run(run func(ctx context.Context
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 models.IntentStatus
+		var arg2 string
 		if args[2] != nil {
-			arg2 = args[2].(models.IntentStatus)
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_UpdateIntentStatus_Call) Return(err error) *DatabaseMock_UpdateIntentStatus_Call {
+func (_c *DatabaseMock_UpdateIntentUSDValue_Call) Return(err error) *DatabaseMock_UpdateIntentUSDValue_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_UpdateIntentStatus_Call) RunAndReturn(run func(ctx context.Context, id string, status models.IntentStatus) error) *DatabaseMock_UpdateIntentStatus_Call {
+func (_c *DatabaseMock_UpdateIntentUSDValue_Call) RunAndReturn(run func(ctx context.Context, id string, amountUSD string, intentFeeUSD string) error) *DatabaseMock_UpdateIntentUSDValue_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateLastProcessedBlock provides a mock function for the type DatabaseMock
-func (_mock *DatabaseMock) UpdateLastProcessedBlock(ctx context.Context, chainID uint64, blockNumber uint64) error {
-	ret := _mock.Called(ctx, chainID, blockNumber)
+// WithTx provides a mock function for the type DatabaseMock
+func (_mock *DatabaseMock) WithTx(ctx context.Context, fn func(tx db.Database) error) error {
+	ret := _mock.Called(ctx, fn)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateLastProcessedBlock")
+		panic("no return value specified for WithTx")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
-		r0 = returnFunc(ctx, chainID, blockNumber)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(tx db.Database) error) error); ok {
+		r0 = returnFunc(ctx, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// DatabaseMock_UpdateLastProcessedBlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateLastProcessedBlock'
-type DatabaseMock_UpdateLastProcessedBlock_Call struct {
+// DatabaseMock_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type DatabaseMock_WithTx_Call struct {
 	*mock.Call
 }
 
-// UpdateLastProcessedBlock is a helper method to define mock.On call
+// WithTx is a helper method to define mock.On call
 //   - ctx context.Context
-//   - chainID uint64
-//   - blockNumber uint64
-func (_e *DatabaseMock_Expecter) UpdateLastProcessedBlock(ctx interface{}, chainID interface{}, blockNumber interface{}) *DatabaseMock_UpdateLastProcessedBlock_Call {
-	return &DatabaseMock_UpdateLastProcessedBlock_Call{Call: _e.mock.On("UpdateLastProcessedBlock", ctx, chainID, blockNumber)}
+//   - fn func(tx db.Database) error
+func (_e *DatabaseMock_Expecter) WithTx(ctx interface{}, fn interface{}) *DatabaseMock_WithTx_Call {
+	return &DatabaseMock_WithTx_Call{Call: _e.mock.On("WithTx", ctx, fn)}
 }
 
-func (_c *DatabaseMock_UpdateLastProcessedBlock_Call) Run(run func(ctx context.Context, chainID uint64, blockNumber uint64)) *DatabaseMock_UpdateLastProcessedBlock_Call {
+func (_c *DatabaseMock_WithTx_Call) Run(run func(ctx context.Context, fn func(tx db.Database) error)) *DatabaseMock_WithTx_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint64
+		var arg1 func(tx db.Database) error
 		if args[1] != nil {
-			arg1 = args[1].(uint64)
-		}
-		var arg2 uint64
-		if args[2] != nil {
-			arg2 = args[2].(uint64)
+			arg1 = args[1].(func(tx db.Database) error)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *DatabaseMock_UpdateLastProcessedBlock_Call) Return(err error) *DatabaseMock_UpdateLastProcessedBlock_Call {
+func (_c *DatabaseMock_WithTx_Call) Return(err error) *DatabaseMock_WithTx_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *DatabaseMock_UpdateLastProcessedBlock_Call) RunAndReturn(run func(ctx context.Context, chainID uint64, blockNumber uint64) error) *DatabaseMock_UpdateLastProcessedBlock_Call {
+func (_c *DatabaseMock_WithTx_Call) RunAndReturn(run func(ctx context.Context, fn func(tx db.Database) error) error) *DatabaseMock_WithTx_Call {
 	_c.Call.Return(run)
 	return _c
 }
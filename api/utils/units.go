@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits formats an integer amount of base units, e.g. wei, as a decimal amount of tokens
+// with the given decimals, without trailing zeros, e.g. "1500000" with 6 decimals is "1.5"
+func FormatUnits(amount string, decimals int) (string, error) {
+	value, ok := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+	if !ok {
+		return "", fmt.Errorf("invalid amount: %q", amount)
+	}
+	if decimals < 0 {
+		return "", errors.New("decimals must be positive")
+	}
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+		value.Abs(value)
+	}
+
+	digits := value.String()
+	if decimals == 0 {
+		return sign + digits, nil
+	}
+
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return sign + whole, nil
+	}
+
+	return sign + whole + "." + fraction, nil
+}
+
+// ParseUnits parses a decimal amount of tokens with the given decimals into base units,
+// e.g. "1.5" with 6 decimals is 1500000
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	if !amountRegex.MatchString(amount) {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+	if decimals < 0 {
+		return nil, errors.New("decimals must be positive")
+	}
+
+	whole, fraction, _ := strings.Cut(amount, ".")
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", amount, decimals)
+	}
+
+	value, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+
+	return value, nil
+}
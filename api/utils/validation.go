@@ -68,8 +68,24 @@ func ValidateChain(chainID uint64) error {
 	return fmt.Errorf("unsupported chain ID: %d", chainID)
 }
 
-// ValidateAmount checks if the amount is valid and within limits
+// DefaultMaxAmount is the largest amount of tokens without configured limit, in whole tokens
+const DefaultMaxAmount = 1_000_000_000
+
+// ValidateAmount checks if the amount is valid and within the limit of an 18 decimals token
 func ValidateAmount(amount string) error {
+	return ValidateAmountLimit(amount, MaxAmount(18))
+}
+
+// MaxAmount returns DefaultMaxAmount in base units of a token with the given decimals
+func MaxAmount(decimals int) *big.Int {
+	return new(big.Int).Mul(
+		big.NewInt(DefaultMaxAmount),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil),
+	)
+}
+
+// ValidateAmountLimit checks if the amount is valid and doesn't exceed the limit, in base units
+func ValidateAmountLimit(amount string, maxAmount *big.Int) error {
 	if amount == "" {
 		return errors.New("amount cannot be empty")
 	}
@@ -104,11 +120,7 @@ func ValidateAmount(amount string) error {
 		return errors.New("amount must be positive")
 	}
 
-	// Check maximum amount (e.g., 1 billion ETH in wei)
-	maxAmount := new(big.Int).Mul(
-		new(big.Int).SetInt64(1_000_000_000),
-		new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
-	)
+	// Check maximum amount
 	if value.Cmp(maxAmount) > 0 {
 		return errors.New("amount exceeds maximum limit")
 	}
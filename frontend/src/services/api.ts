@@ -176,10 +176,18 @@ class ApiService {
   }
 
   // Leaderboard endpoints
-  async getLeaderboard(chainId: number): Promise<Runner[]> {
-    return this.fetchApi<Runner[]>(`/leaderboard/${chainId}`, {
-      method: "GET",
-    });
+  async getLeaderboard(
+    chainId: number,
+    pagination?: PaginationParams,
+  ): Promise<Runner[]> {
+    const queryString = this.getPaginationQueryString(pagination);
+    const response = await this.fetchApi<PaginatedResponse<Runner>>(
+      `/leaderboard/${chainId}${queryString}`,
+      {
+        method: "GET",
+      },
+    );
+    return response.data ?? [];
   }
 
   // Helper to build query string from pagination params